@@ -17,3 +17,14 @@ type ErrMapNotFound struct {
 func (e ErrMapNotFound) Error() string {
 	return fmt.Sprintf("atlas: map (%v) not found", e.Name)
 }
+
+// ErrUnsupportedContentEncoding is returned when an MVT provider reports a
+// content encoding for its tiles which tegola can not serve
+type ErrUnsupportedContentEncoding struct {
+	Provider string
+	Encoding string
+}
+
+func (e ErrUnsupportedContentEncoding) Error() string {
+	return fmt.Sprintf("atlas: provider (%v) content encoding (%v) not supported", e.Provider, e.Encoding)
+}
@@ -22,6 +22,9 @@ type Layer struct {
 	// DontClip indicates wheather feature clipping should be applied.
 	// We use a negative in the name so the default is to clip
 	DontClip bool
+	// TileExtent is the MVT extent the layer is encoded with. 0 uses the
+	// map's extent, see Map.LayerMVTExtent
+	TileExtent uint64
 }
 
 // MVTName will return the value that will be encoded in the Name field when the layer is encoded as MVT
@@ -32,3 +35,24 @@ func (l *Layer) MVTName() string {
 
 	return l.ProviderLayerName
 }
+
+// GeometryType returns the layer's GeomType. If it is not known, i.e. as the
+// layer's provider is lazy and was not initialized when the layer was
+// registered, it is looked up from the provider's layers. nil is returned
+// if the provider's layers can not be read.
+func (l *Layer) GeometryType() geom.Geometry {
+	if l.GeomType != nil || l.Provider == nil {
+		return l.GeomType
+	}
+
+	infos, err := l.Provider.Layers()
+	if err != nil {
+		return nil
+	}
+	for i := range infos {
+		if infos[i].Name() == l.ProviderLayerName {
+			return infos[i].GeomType()
+		}
+	}
+	return nil
+}
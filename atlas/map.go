@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"crypto/sha1"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"log"
@@ -62,6 +64,25 @@ type Map struct {
 	mvtProvider     mvtprovider.Tiler
 }
 
+// MVTExtent returns the MVT extent the map's tiles are encoded with,
+// TileExtent or tegola.DefaultExtent if it is not set
+func (m Map) MVTExtent() uint32 {
+	if m.TileExtent == 0 {
+		return tegola.DefaultExtent
+	}
+	return uint32(m.TileExtent)
+}
+
+// LayerMVTExtent returns the MVT extent the layer is encoded with, the
+// layer's TileExtent or the map's MVTExtent if it is not set. The layers of a
+// tile may be encoded with different extents.
+func (m Map) LayerMVTExtent(l Layer) uint32 {
+	if l.TileExtent == 0 {
+		return m.MVTExtent()
+	}
+	return uint32(l.TileExtent)
+}
+
 // HasMVTProvider indicates if map is a mvt provider based map
 func (m Map) HasMVTProvider() bool { return m.mvtProvider != nil }
 
@@ -115,6 +136,19 @@ func (m Map) AddDebugLayers() Map {
 	return m
 }
 
+// CombinedAttribution returns the map's attribution combined with the
+// attribution reported by the providers of the map's layers.
+func (m Map) CombinedAttribution() string {
+	attributions := []string{m.Attribution}
+	for i := range m.Layers {
+		if m.Layers[i].Provider == nil {
+			continue
+		}
+		attributions = append(attributions, provider.Attribution(m.Layers[i].Provider, m.Layers[i].ProviderLayerName))
+	}
+	return provider.JoinAttributions(attributions...)
+}
+
 // FilterLayersByZoom returns a copy of a Map with a subset of layers that match the given zoom
 func (m Map) FilterLayersByZoom(zoom uint) Map {
 	var layers []Layer
@@ -159,12 +193,23 @@ func (m Map) encodeMVTProviderTile(ctx context.Context, tile *slippy.Tile) ([]by
 	ptile := provider.NewTile(tile.Z, tile.X, tile.Y, uint(m.TileBuffer), uint(m.SRID))
 
 	layers := make([]mvtprovider.Layer, len(m.Layers))
+	names := make([]string, len(m.Layers))
 	for i := range m.Layers {
 		layers[i] = mvtprovider.Layer{
 			Name:    m.Layers[i].ProviderLayerName,
 			MVTName: m.Layers[i].MVTName(),
+			Extent:  m.LayerMVTExtent(m.Layers[i]),
 		}
+		names[i] = m.Layers[i].MVTName()
 	}
+
+	ctx = provider.WithQueryHint(ctx, provider.QueryHint{
+		Map:   m.Name,
+		Layer: strings.Join(names, ","),
+		Z:     tile.Z,
+		X:     tile.X,
+		Y:     tile.Y,
+	})
 	return m.mvtProvider.MVTForLayers(ctx, ptile, layers)
 
 }
@@ -189,9 +234,11 @@ func (m Map) encodeMVTTile(ctx context.Context, tile *slippy.Tile) ([]byte, erro
 
 		// go routine for fetching the layer concurrently
 		go func(i int, l Layer) {
+			extent := m.LayerMVTExtent(l)
 			mvtLayer := mvt.Layer{
 				Name: l.MVTName(),
 			}
+			mvtLayer.SetExtent(int(extent))
 
 			// on completion let the wait group know
 			defer wg.Done()
@@ -199,8 +246,16 @@ func (m Map) encodeMVTTile(ctx context.Context, tile *slippy.Tile) ([]byte, erro
 			ptile := provider.NewTile(tile.Z, tile.X, tile.Y,
 				uint(m.TileBuffer), uint(m.SRID))
 
+			hctx := provider.WithQueryHint(ctx, provider.QueryHint{
+				Map:   m.Name,
+				Layer: l.MVTName(),
+				Z:     tile.Z,
+				X:     tile.X,
+				Y:     tile.Y,
+			})
+
 			// fetch layer from data provider
-			err := l.Provider.TileFeatures(ctx, l.ProviderLayerName, ptile, func(f *provider.Feature) error {
+			err := l.Provider.TileFeatures(hctx, l.ProviderLayerName, ptile, func(f *provider.Feature) error {
 				// skip row if geometry collection empty.
 				g, ok := f.Geometry.(geom.Collection)
 				if ok && len(g.Geometries()) == 0 {
@@ -234,6 +289,12 @@ func (m Map) encodeMVTTile(ctx context.Context, tile *slippy.Tile) ([]byte, erro
 
 				// TODO (arolek): change out the tile type for VTile. tegola.Tile will be deprecated
 				tegolaTile := tegola.NewTile(tile.ZXY())
+				if extent != tegola.DefaultExtent {
+					// the clip buffer is in pixels of the default extent
+					tegolaTile.Buffer *= float64(extent) / tegola.DefaultExtent
+					tegolaTile.Extent = float64(extent)
+					tegolaTile.Init()
+				}
 
 				sg := tegolaGeo
 				// multiple ways to turn off simplification. check the atlas init() function
@@ -267,7 +328,7 @@ func (m Map) encodeMVTTile(ctx context.Context, tile *slippy.Tile) ([]byte, erro
 				// with the adoption of the new make valid routine. once implemented, the clipRegion
 				// calculation will need to be in the same coordinate space as the geometry the
 				// make valid function will be operating on.
-				geo = mvt.PrepareGeo(geo, tile.Extent3857(), float64(mvt.DefaultExtent))
+				geo = mvt.PrepareGeo(geo, tile.Extent3857(), float64(extent))
 
 				// TODO: remove this geom conversion step once the validate function uses geom types
 				sg, err = convert.ToTegola(geo)
@@ -295,6 +356,9 @@ func (m Map) encodeMVTTile(ctx context.Context, tile *slippy.Tile) ([]byte, erro
 			})
 			if err != nil {
 				switch {
+				case errors.Is(err, provider.ErrNoFeatures):
+					// the provider reported the tile as empty, encode the empty layer
+					mvtLayers[i] = &mvtLayer
 				case errors.Is(err, context.Canceled):
 					// Do nothing if we were cancelled.
 
@@ -335,14 +399,74 @@ func (m Map) encodeMVTTile(ctx context.Context, tile *slippy.Tile) ([]byte, erro
 	return proto.Marshal(vtile)
 }
 
-// Encode will encode the given tile into mvt format
+// TileETag returns a tag which changes whenever the encoded tile would change,
+// derived from the version tags reported by the providers of the map's
+// layers. If any of the providers does not implement provider.TileETagger
+// provider.ErrUnsupported is returned and the tile must be encoded instead.
+func (m Map) TileETag(ctx context.Context, tile *slippy.Tile) (string, error) {
+	ptile := provider.NewTile(tile.Z, tile.X, tile.Y, uint(m.TileBuffer), uint(m.SRID))
+
+	h := sha1.New()
+	fmt.Fprintf(h, "%v/%v/%v:%v:%v\n", tile.Z, tile.X, tile.Y, m.TileExtent, m.TileBuffer)
+
+	for i := range m.Layers {
+		var (
+			tag string
+			err error
+		)
+		if m.HasMVTProvider() {
+			te, ok := m.mvtProvider.(provider.TileETagger)
+			if !ok {
+				return "", provider.ErrUnsupported
+			}
+			tag, err = te.TileETag(ctx, m.Layers[i].ProviderLayerName, ptile)
+		} else {
+			tag, err = provider.TileETag(ctx, m.Layers[i].Provider, m.Layers[i].ProviderLayerName, ptile)
+		}
+		if err != nil {
+			return "", err
+		}
+
+		fmt.Fprintf(h, "%v:%v:%v\n", m.Layers[i].MVTName(), m.LayerMVTExtent(m.Layers[i]), tag)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Encode will encode the given tile into mvt format, compressed with gzip.
+// Tiles from an MVT provider reporting a gzip content encoding are returned
+// as is, other encodings are not supported.
 func (m Map) Encode(ctx context.Context, tile *slippy.Tile) ([]byte, error) {
 	var (
 		tileBytes []byte
 		err       error
 	)
+
+	// guard the providers against a map requesting too many layers
+	n, err := provider.LayerLimit(len(m.Layers))
+	if err != nil {
+		return nil, fmt.Errorf("map (%v): %w", m.Name, err)
+	}
+	if n < len(m.Layers) {
+		log.Printf("map (%v) tile (z: %v, x: %v, y: %v) requested with %v layers, only rendering the first %v", m.Name, tile.Z, tile.X, tile.Y, len(m.Layers), n)
+		m.Layers = m.Layers[:n]
+	}
+
 	if m.HasMVTProvider() {
 		tileBytes, err = m.encodeMVTProviderTile(ctx, tile)
+		if err != nil {
+			return nil, err
+		}
+
+		// the provider may return already compressed tiles,
+		// which must not be compressed again
+		switch enc := provider.ContentEncoding(m.mvtProvider); enc {
+		case "":
+		case "gzip":
+			return tileBytes, nil
+		default:
+			return nil, ErrUnsupportedContentEncoding{Provider: m.mvtProviderName, Encoding: enc}
+		}
 	} else {
 		tileBytes, err = m.encodeMVTTile(ctx, tile)
 	}
@@ -14,6 +14,7 @@ import (
 	"github.com/go-spatial/geom/slippy"
 	"github.com/go-spatial/tegola/atlas"
 	"github.com/go-spatial/tegola/internal/p"
+	"github.com/go-spatial/tegola/provider"
 	"github.com/go-spatial/tegola/provider/test"
 	"github.com/go-spatial/tegola/provider/test/emptycollection"
 )
@@ -371,3 +372,79 @@ func TestEncode(t *testing.T) {
 		t.Run(name, fn(tc))
 	}
 }
+
+// encodedMVTProvider returns the same tile for every request,
+// reporting the given content encoding
+type encodedMVTProvider struct {
+	encoding string
+	tile     []byte
+}
+
+func (ep encodedMVTProvider) Layers() ([]provider.LayerInfo, error) { return nil, nil }
+func (ep encodedMVTProvider) ContentEncoding() string               { return ep.encoding }
+func (ep encodedMVTProvider) MVTForLayers(ctx context.Context, tile provider.Tile, layers []provider.Layer) ([]byte, error) {
+	return ep.tile, nil
+}
+
+func TestEncodeContentEncoding(t *testing.T) {
+	plain := []byte("mvt tile")
+
+	var gzipped bytes.Buffer
+	w := gzip.NewWriter(&gzipped)
+	w.Write(plain)
+	w.Close()
+
+	type tcase struct {
+		provider encodedMVTProvider
+		err      error
+	}
+
+	fn := func(tc tcase) func(t *testing.T) {
+		return func(t *testing.T) {
+			var m atlas.Map
+			m.SetMVTProvider("mvt_test", tc.provider)
+			m.Layers = []atlas.Layer{{Name: "layer1", ProviderLayerName: "layer1"}}
+
+			out, err := m.Encode(context.Background(), slippy.NewTile(0, 0, 0))
+			if tc.err != nil {
+				if !reflect.DeepEqual(err, tc.err) {
+					t.Errorf("error, expected %v got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error, expected nil got %v", err)
+			}
+
+			// the output is compressed exactly once
+			r, err := gzip.NewReader(bytes.NewReader(out))
+			if err != nil {
+				t.Fatalf("unexpected error, expected nil got %v", err)
+			}
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("unexpected error, expected nil got %v", err)
+			}
+			if !bytes.Equal(got, plain) {
+				t.Errorf("tile, expected %q got %q", plain, got)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"plain": {
+			provider: encodedMVTProvider{tile: plain},
+		},
+		"gzip": {
+			provider: encodedMVTProvider{encoding: "gzip", tile: gzipped.Bytes()},
+		},
+		"unsupported": {
+			provider: encodedMVTProvider{encoding: "br", tile: plain},
+			err:      atlas.ErrUnsupportedContentEncoding{Provider: "mvt_test", Encoding: "br"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
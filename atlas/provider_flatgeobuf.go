@@ -0,0 +1,12 @@
+// +build !noFlatgeobufProvider
+
+package atlas
+
+// The point of this file is to load and register the FlatGeobuf provider.
+// the FlatGeobuf provider can be excluded during the build with the `noFlatgeobufProvider` build flag
+// for example from the cmd/tegola directory:
+//
+// go build -tags 'noFlatgeobufProvider'
+import (
+	_ "github.com/go-spatial/tegola/provider/flatgeobuf"
+)
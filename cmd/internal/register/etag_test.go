@@ -0,0 +1,114 @@
+package register_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/atlas"
+	"github.com/go-spatial/tegola/cmd/internal/register"
+	"github.com/go-spatial/tegola/config"
+	"github.com/go-spatial/tegola/dict"
+	"github.com/go-spatial/tegola/provider"
+	"github.com/go-spatial/tegola/server"
+)
+
+// etagLayer is the only layer of etagProvider
+type etagLayer struct{}
+
+func (etagLayer) Name() string            { return "points" }
+func (etagLayer) GeomType() geom.Geometry { return geom.Point{} }
+func (etagLayer) SRID() uint64            { return 3857 }
+
+// etagProvider has no features and reports its config's version as the
+// ETag of every tile
+type etagProvider struct {
+	version uint
+}
+
+func (etagProvider) Layers() ([]provider.LayerInfo, error) {
+	return []provider.LayerInfo{etagLayer{}}, nil
+}
+
+func (etagProvider) TileFeatures(ctx context.Context, layer string, t provider.Tile, fn func(f *provider.Feature) error) error {
+	return nil
+}
+
+func (p etagProvider) TileETag(ctx context.Context, layer string, t provider.Tile) (string, error) {
+	return strconv.FormatUint(uint64(p.version), 10), nil
+}
+
+// TestETag checks tile ETags work for providers registered from the config,
+// which are wrapped so they can be reloaded and given query timeouts
+func TestETag(t *testing.T) {
+	err := provider.Register("etag_test", func(d dict.Dicter) (provider.Tiler, error) {
+		version, err := d.Uint("version", nil)
+		if err != nil {
+			return nil, err
+		}
+		return etagProvider{version: version}, nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("register, expected nil got %v", err)
+	}
+
+	providers, err := register.Providers([]dict.Dicter{
+		dict.Dict{"name": "etag", "type": "etag_test", "version": uint(1), "query_timeout": "5s"},
+	})
+	if err != nil {
+		t.Fatalf("providers, expected nil got %v", err)
+	}
+
+	a := &atlas.Atlas{}
+	err = register.Maps(a, []config.Map{
+		{
+			Name:   "etag-map",
+			Layers: []config.MapLayer{{ProviderLayer: "etag.points"}},
+		},
+	}, providers, nil)
+	if err != nil {
+		t.Fatalf("maps, expected nil got %v", err)
+	}
+	router := server.NewRouter(a)
+
+	request := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		r, err := http.NewRequest("GET", "/maps/etag-map/0/0/0.pbf", nil)
+		if err != nil {
+			t.Fatalf("request error, expected nil got %v", err)
+		}
+		if ifNoneMatch != "" {
+			r.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	w := request("")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status code, expected %v got %v", http.StatusOK, w.Code)
+	}
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Fatalf("etag, expected a value got none")
+	}
+
+	if w = request(etag); w.Code != http.StatusNotModified {
+		t.Errorf("matching status code, expected %v got %v", http.StatusNotModified, w.Code)
+	}
+
+	// the reloaded provider reports a new version
+	if err = provider.Reload("etag", dict.Dict{"type": "etag_test", "version": uint(2)}); err != nil {
+		t.Fatalf("reload, expected nil got %v", err)
+	}
+	w = request(etag)
+	if w.Code != http.StatusOK {
+		t.Errorf("changed status code, expected %v got %v", http.StatusOK, w.Code)
+	}
+	if got := w.Header().Get("ETag"); got == "" || got == etag {
+		t.Errorf("changed etag, expected a new value got %q", got)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/go-spatial/geom"
 	"github.com/go-spatial/tegola/atlas"
 	"github.com/go-spatial/tegola/config"
+	"github.com/go-spatial/tegola/internal/log"
 	"github.com/go-spatial/tegola/mvtprovider"
 	"github.com/go-spatial/tegola/provider"
 )
@@ -29,6 +30,9 @@ func webMercatorMapFromConfigMap(cfg config.Map) (newMap atlas.Map) {
 	if cfg.TileBuffer != nil {
 		newMap.TileBuffer = uint64(*cfg.TileBuffer)
 	}
+	if cfg.TileExtent != nil {
+		newMap.TileExtent = uint64(*cfg.TileExtent)
+	}
 	return newMap
 
 }
@@ -54,22 +58,29 @@ func atlasLayerFromConfigLayer(cfg *config.MapLayer, mapName string, layerProvid
 	// read the provider's layer names
 	// don't care about the error.
 	providerName, layerName, _ := cfg.ProviderLayerName()
-	layerInfos, err := layerProvider.Layers()
-	if err != nil {
-		return layer, ErrFetchingLayerInfo{
-			Provider: providerName,
-			Err:      err,
+	if tiler, ok := layerProvider.(provider.Tiler); ok && provider.LazyPending(tiler) {
+		// reading the layers would initialize the lazy provider. the layer is
+		// validated when the provider is first used, and its geometry type
+		// is looked up when needed, see atlas.Layer.GeometryType
+		log.Infof("provider layer (%v) for map (%v) not validated until the lazy provider is used", providerLayer, mapName)
+	} else {
+		layerInfos, err := layerProvider.Layers()
+		if err != nil {
+			return layer, ErrFetchingLayerInfo{
+				Provider: providerName,
+				Err:      err,
+			}
 		}
-	}
-	layerInfo := layerInfosFindByName(layerInfos, layerName)
-	if layerInfo == nil {
-		return layer, ErrProviderLayerNotRegistered{
-			MapName:       mapName,
-			ProviderLayer: providerLayer,
-			Provider:      providerName,
+		layerInfo := layerInfosFindByName(layerInfos, layerName)
+		if layerInfo == nil {
+			return layer, ErrProviderLayerNotRegistered{
+				MapName:       mapName,
+				ProviderLayer: providerLayer,
+				Provider:      providerName,
+			}
 		}
+		layer.GeomType = layerInfo.GeomType()
 	}
-	layer.GeomType = layerInfo.GeomType()
 
 	if cfg.DefaultTags != nil {
 		if layer.DefaultTags, ok = cfg.DefaultTags.(map[string]interface{}); !ok {
@@ -94,6 +105,9 @@ func atlasLayerFromConfigLayer(cfg *config.MapLayer, mapName string, layerProvid
 	if cfg.MaxZoom != nil {
 		layer.MaxZoom = uint(*cfg.MaxZoom)
 	}
+	if cfg.TileExtent != nil {
+		layer.TileExtent = uint64(*cfg.TileExtent)
+	}
 	return layer, nil
 }
 
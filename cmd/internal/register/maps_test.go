@@ -4,10 +4,12 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/go-spatial/geom"
 	"github.com/go-spatial/tegola/atlas"
 	"github.com/go-spatial/tegola/cmd/internal/register"
 	"github.com/go-spatial/tegola/config"
 	"github.com/go-spatial/tegola/dict"
+	"github.com/go-spatial/tegola/provider"
 )
 
 func TestMaps(t *testing.T) {
@@ -137,6 +139,25 @@ func TestMaps(t *testing.T) {
 				ProviderLayer: "test.debug-tile-outline",
 			},
 		},
+		"lazy provider layer not validated": {
+			maps: []config.Map{
+				{
+					Name: "foo",
+					Layers: []config.MapLayer{
+						{
+							ProviderLayer: "test.bar",
+						},
+					},
+				},
+			},
+			providers: []dict.Dict{
+				{
+					"name": "test",
+					"type": "debug",
+					"lazy": true,
+				},
+			},
+		},
 		"success": {
 			maps: []config.Map{},
 			providers: []dict.Dict{
@@ -153,3 +174,38 @@ func TestMaps(t *testing.T) {
 		t.Run(name, func(t *testing.T) { fn(t, tc) })
 	}
 }
+
+func TestMapsLazy(t *testing.T) {
+	providers, err := register.Providers([]dict.Dicter{
+		dict.Dict{"name": "test", "type": "debug", "lazy": true},
+	})
+	if err != nil {
+		t.Fatalf("providers, expected nil got %v", err)
+	}
+
+	a := &atlas.Atlas{}
+	err = register.Maps(a, []config.Map{
+		{
+			Name:   "foo",
+			Layers: []config.MapLayer{{ProviderLayer: "test.debug-tile-outline"}},
+		},
+	}, providers, nil)
+	if err != nil {
+		t.Fatalf("maps, expected nil got %v", err)
+	}
+	if !provider.LazyPending(providers["test"]) {
+		t.Errorf("pending, expected the provider not to be initialized by registering maps")
+	}
+
+	m, err := a.Map("foo")
+	if err != nil {
+		t.Fatalf("map, expected nil got %v", err)
+	}
+	if m.Layers[0].GeomType != nil {
+		t.Errorf("geom type, expected nil got %T", m.Layers[0].GeomType)
+	}
+	// the geometry type is looked up once the provider is used
+	if _, ok := m.Layers[0].GeometryType().(geom.Line); !ok {
+		t.Errorf("geometry type, expected geom.Line got %T", m.Layers[0].GeometryType())
+	}
+}
@@ -71,8 +71,19 @@ func Providers(providers []dict.Dicter) (map[string]provider.Tiler, error) {
 			}
 		}
 
-		// register the provider
-		prov, err := provider.For(ptype, p)
+		// lazy providers are not initialized until they are first used
+		lazy, err := p.Bool("lazy", new(bool))
+		if err != nil {
+			return registeredProviders, err
+		}
+
+		// register the provider, named so it can be reloaded
+		var prov provider.Tiler
+		if lazy {
+			prov, err = provider.ForNamedLazy(pname, ptype, p)
+		} else {
+			prov, err = provider.ForNamed(pname, ptype, p)
+		}
 		if err != nil {
 			return registeredProviders, err
 		}
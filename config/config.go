@@ -50,6 +50,9 @@ type Map struct {
 	Center      [3]env.Float `toml:"center"`
 	Layers      []MapLayer   `toml:"layers"`
 	TileBuffer  *env.Int     `toml:"tile_buffer"`
+	// TileExtent is the MVT extent the map's tiles are encoded with.
+	// Defaults to 4096
+	TileExtent *env.Uint `toml:"tile_extent"`
 }
 
 type MapLayer struct {
@@ -66,6 +69,9 @@ type MapLayer struct {
 	// DontClip indicates wheather feature clipping should be applied.
 	// We use a negative in the name so the default is to clipping
 	DontClip env.Bool `toml:"dont_clip"`
+	// TileExtent is the MVT extent the layer is encoded with, overriding
+	// the map's tile_extent, i.e. a lower extent for label layers
+	TileExtent *env.Uint `toml:"tile_extent"`
 }
 
 // ProviderLayerName returns the names of the layer and provider or an error
@@ -107,6 +113,10 @@ func (c *Config) Validate() error {
 	// map of layers to providers
 	mapLayers := map[string]map[string]MapLayer{}
 	for mapKey, m := range c.Maps {
+		if m.TileExtent != nil && *m.TileExtent == 0 {
+			return ErrInvalidTileExtent{MapName: string(m.Name)}
+		}
+
 		if _, ok := mapLayers[string(m.Name)]; !ok {
 			mapLayers[string(m.Name)] = map[string]MapLayer{}
 		}
@@ -123,6 +133,10 @@ func (c *Config) Validate() error {
 				return err
 			}
 
+			if l.TileExtent != nil && *l.TileExtent == 0 {
+				return ErrInvalidTileExtent{MapName: string(m.Name), ProviderLayer: string(l.ProviderLayer)}
+			}
+
 			if provider == "" {
 				// This is the first provider we found.
 				// For MVTProviders all others need to be the same, so store it
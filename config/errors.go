@@ -91,3 +91,17 @@ type ErrInvalidURIPrefix string
 func (e ErrInvalidURIPrefix) Error() string {
 	return fmt.Sprintf("config: invalid uri_prefix (%v). uri_prefix must start with a forward slash '/' ", string(e))
 }
+
+// ErrInvalidTileExtent is returned when a map's or map layer's tile_extent is 0
+type ErrInvalidTileExtent struct {
+	MapName string
+	// ProviderLayer is set when the tile_extent is a layer's
+	ProviderLayer string
+}
+
+func (e ErrInvalidTileExtent) Error() string {
+	if e.ProviderLayer != "" {
+		return fmt.Sprintf("config: map (%v) layer (%v) tile_extent must be greater than 0", e.MapName, e.ProviderLayer)
+	}
+	return fmt.Sprintf("config: map (%v) tile_extent must be greater than 0", e.MapName)
+}
@@ -0,0 +1,226 @@
+package dict
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var dicterType = reflect.TypeOf((*Dicter)(nil)).Elem()
+
+// ErrDecodeTarget is returned when the value passed to Decode
+// is not a pointer to a struct or contains an unsupported field type
+type ErrDecodeTarget struct {
+	T      reflect.Type
+	Reason string
+}
+
+func (err ErrDecodeTarget) Error() string {
+	return fmt.Sprintf("config: unable to decode into %v: %v", err.T, err.Reason)
+}
+
+// ErrDecodeOverflow is returned when a value does not fit the size of the
+// field it is decoded into, i.e. 300 into an int8
+type ErrDecodeOverflow struct {
+	Key   string
+	Value interface{}
+	T     reflect.Type
+}
+
+func (err ErrDecodeOverflow) Error() string {
+	return fmt.Sprintf("config: value %v mapped to %q overflows %v", err.Value, err.Key, err.T)
+}
+
+// Decode populates the struct pointed to by v from the Dicter. Fields are
+// matched to keys using the "dict" struct tag, fields without a tag are
+// skipped. The tag may be followed by ",required" in which case a missing key
+// results in an ErrKeyRequired error; otherwise a missing key leaves the field
+// untouched, so defaults can be set on v before calling Decode.
+//
+//	type Config struct {
+//		Host string `dict:"host,required"`
+//		Port uint   `dict:"port"`
+//	}
+//
+// Supported field types are string, bool, the int, uint and float kinds,
+// slices of those, Dicter, and nested structs (and slices of structs) which
+// are decoded from maps. Values are read using the Dicter's typed methods so
+// any conversion done by the Dicter (i.e. environment variables) is applied.
+// A value which does not fit a sized field, i.e. an int8, results in an
+// ErrDecodeOverflow error.
+func Decode(d Dicter, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return ErrDecodeTarget{T: reflect.TypeOf(v), Reason: "expected a non nil pointer to a struct"}
+	}
+	if d == nil {
+		d = Dict{}
+	}
+	return decodeStruct(d, rv.Elem())
+}
+
+func decodeStruct(d Dicter, rv reflect.Value) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag, ok := field.Tag.Lookup("dict")
+		if !ok || tag == "-" || field.PkgPath != "" {
+			continue
+		}
+
+		opts := strings.Split(tag, ",")
+		key := opts[0]
+		if key == "" {
+			key = field.Name
+		}
+		var required bool
+		for _, opt := range opts[1:] {
+			if opt == "required" {
+				required = true
+			}
+		}
+
+		if _, ok := d.Interface(key); !ok {
+			if required {
+				return ErrKeyRequired(key)
+			}
+			continue
+		}
+
+		if err := decodeField(d, key, rv.Field(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeField(d Dicter, key string, fv reflect.Value) error {
+	ft := fv.Type()
+
+	if ft == dicterType {
+		m, err := d.Map(key)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(m))
+		return nil
+	}
+
+	switch ft.Kind() {
+	case reflect.String:
+		s, err := d.String(key, nil)
+		if err != nil {
+			return err
+		}
+		fv.SetString(s)
+
+	case reflect.Bool:
+		b, err := d.Bool(key, nil)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := d.Int(key, nil)
+		if err != nil {
+			return err
+		}
+		if fv.OverflowInt(int64(n)) {
+			return ErrDecodeOverflow{Key: key, Value: n, T: ft}
+		}
+		fv.SetInt(int64(n))
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := d.Uint(key, nil)
+		if err != nil {
+			return err
+		}
+		if fv.OverflowUint(uint64(n)) {
+			return ErrDecodeOverflow{Key: key, Value: n, T: ft}
+		}
+		fv.SetUint(uint64(n))
+
+	case reflect.Float32, reflect.Float64:
+		f, err := d.Float(key, nil)
+		if err != nil {
+			return err
+		}
+		if fv.OverflowFloat(f) {
+			return ErrDecodeOverflow{Key: key, Value: f, T: ft}
+		}
+		fv.SetFloat(f)
+
+	case reflect.Interface:
+		val, _ := d.Interface(key)
+		if val != nil {
+			if !reflect.TypeOf(val).AssignableTo(ft) {
+				return ErrKeyType{Key: key, Value: val, T: ft}
+			}
+			fv.Set(reflect.ValueOf(val))
+		}
+
+	case reflect.Struct:
+		m, err := d.Map(key)
+		if err != nil {
+			return err
+		}
+		return decodeStruct(m, fv)
+
+	case reflect.Slice:
+		return decodeSlice(d, key, fv)
+
+	default:
+		return ErrDecodeTarget{T: ft, Reason: fmt.Sprintf("unsupported type for key %q", key)}
+	}
+
+	return nil
+}
+
+func decodeSlice(d Dicter, key string, fv reflect.Value) error {
+	var (
+		val interface{}
+		err error
+	)
+
+	ft := fv.Type()
+	switch ft.Elem().Kind() {
+	case reflect.String:
+		val, err = d.StringSlice(key)
+	case reflect.Bool:
+		val, err = d.BoolSlice(key)
+	case reflect.Int:
+		val, err = d.IntSlice(key)
+	case reflect.Uint:
+		val, err = d.UintSlice(key)
+	case reflect.Float64:
+		val, err = d.FloatSlice(key)
+	case reflect.Struct:
+		ms, err := d.MapSlice(key)
+		if err != nil {
+			return err
+		}
+		slice := reflect.MakeSlice(ft, len(ms), len(ms))
+		for i := range ms {
+			if err := decodeStruct(ms[i], slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		fv.Set(slice)
+		return nil
+	case reflect.Interface:
+		if ft.Elem() == dicterType {
+			val, err = d.MapSlice(key)
+			break
+		}
+		fallthrough
+	default:
+		return ErrDecodeTarget{T: ft, Reason: fmt.Sprintf("unsupported type for key %q", key)}
+	}
+	if err != nil {
+		return err
+	}
+
+	fv.Set(reflect.ValueOf(val).Convert(ft))
+	return nil
+}
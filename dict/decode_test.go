@@ -0,0 +1,116 @@
+package dict_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/tegola/dict"
+)
+
+func TestDecode(t *testing.T) {
+	type layer struct {
+		Name string `dict:"name,required"`
+		SQL  string `dict:"sql"`
+	}
+
+	type config struct {
+		Host    string   `dict:"host,required"`
+		Port    uint     `dict:"port"`
+		MaxConn int      `dict:"max_connections"`
+		SSL     bool     `dict:"ssl"`
+		Ratio   float64  `dict:"ratio"`
+		Retries int8     `dict:"retries"`
+		Level   uint8    `dict:"level"`
+		Schemas []string `dict:"schemas"`
+		Layers  []layer  `dict:"layers"`
+		Skipped string
+	}
+
+	type tcase struct {
+		dict        dict.Dict
+		expected    config
+		expectedErr error
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		// defaults
+		got := config{Port: 5432}
+
+		err := dict.Decode(tc.dict, &got)
+		if tc.expectedErr != nil {
+			if err == nil || err.Error() != tc.expectedErr.Error() {
+				t.Errorf("error, expected %v got %v", tc.expectedErr, err)
+			}
+			return
+		}
+		if err != nil {
+			t.Errorf("error, expected nil got %v", err)
+			return
+		}
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("config, expected %+v got %+v", tc.expected, got)
+		}
+	}
+
+	tests := map[string]tcase{
+		"all fields": {
+			dict: dict.Dict{
+				"host":            "localhost",
+				"port":            uint(5433),
+				"max_connections": 10,
+				"ssl":             true,
+				"ratio":           0.5,
+				"retries":         -3,
+				"level":           uint(255),
+				"schemas":         []string{"public", "osm"},
+				"layers": []map[string]interface{}{
+					{"name": "roads", "sql": "SELECT 1"},
+				},
+				"Skipped": "value",
+			},
+			expected: config{
+				Host:    "localhost",
+				Port:    5433,
+				MaxConn: 10,
+				SSL:     true,
+				Ratio:   0.5,
+				Retries: -3,
+				Level:   255,
+				Schemas: []string{"public", "osm"},
+				Layers:  []layer{{Name: "roads", SQL: "SELECT 1"}},
+			},
+		},
+		"defaults": {
+			dict:     dict.Dict{"host": "localhost"},
+			expected: config{Host: "localhost", Port: 5432},
+		},
+		"missing required": {
+			dict:        dict.Dict{"port": uint(5433)},
+			expectedErr: dict.ErrKeyRequired("host"),
+		},
+		"missing nested required": {
+			dict: dict.Dict{
+				"host":   "localhost",
+				"layers": []map[string]interface{}{{"sql": "SELECT 1"}},
+			},
+			expectedErr: dict.ErrKeyRequired("name"),
+		},
+		"wrong type": {
+			dict:        dict.Dict{"host": 1},
+			expectedErr: dict.ErrKeyType{Key: "host", Value: 1, T: reflect.TypeOf("")},
+		},
+		"int overflow": {
+			dict:        dict.Dict{"host": "localhost", "retries": 300},
+			expectedErr: dict.ErrDecodeOverflow{Key: "retries", Value: 300, T: reflect.TypeOf(int8(0))},
+		},
+		"uint overflow": {
+			dict:        dict.Dict{"host": "localhost", "level": uint(256)},
+			expectedErr: dict.ErrDecodeOverflow{Key: "level", Value: uint(256), T: reflect.TypeOf(uint8(0))},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
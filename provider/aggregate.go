@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-spatial/geom"
+)
+
+// AggFunc is an aggregation function of an Aggregation
+type AggFunc uint8
+
+const (
+	// AggCount counts the features in a cell, the Aggregation's Field is ignored
+	AggCount AggFunc = iota
+	// AggSum sums the values of the Field in a cell
+	AggSum
+	// AggAvg averages the values of the Field in a cell
+	AggAvg
+)
+
+func (fn AggFunc) String() string {
+	switch fn {
+	case AggCount:
+		return "count"
+	case AggSum:
+		return "sum"
+	case AggAvg:
+		return "avg"
+	default:
+		return "unknown"
+	}
+}
+
+// Aggregation computes a property of an aggregated feature
+type Aggregation struct {
+	Func AggFunc
+	// Field is the property aggregated, unused by AggCount
+	Field string
+	// As is the name of the computed property, see Name
+	As string
+}
+
+// Name returns the name of the computed property: As if set, otherwise
+// "count" for AggCount and "<field>_<func>", i.e. "population_sum", for the
+// other functions.
+func (agg Aggregation) Name() string {
+	switch {
+	case agg.As != "":
+		return agg.As
+	case agg.Func == AggCount:
+		return agg.Func.String()
+	default:
+		return agg.Field + "_" + agg.Func.String()
+	}
+}
+
+// AggSpec describes how AggregateTile aggregates the features of a tile.
+// The tile is divided into a grid of Cells by Cells cells, and the features
+// whose centroids fall in each cell are aggregated into one feature with a
+// property for each of the Aggregations.
+type AggSpec struct {
+	Cells        uint
+	Aggregations []Aggregation
+}
+
+// Validate reports if the spec can be used to aggregate a tile
+func (spec AggSpec) Validate() error {
+	if spec.Cells == 0 {
+		return fmt.Errorf("aggregation spec must have at least 1 cell")
+	}
+	if len(spec.Aggregations) == 0 {
+		return fmt.Errorf("aggregation spec must have at least 1 aggregation")
+	}
+	names := make(map[string]bool, len(spec.Aggregations))
+	for _, agg := range spec.Aggregations {
+		if agg.Func > AggAvg {
+			return fmt.Errorf("aggregation (%v) has an unknown function %v", agg.Name(), agg.Func)
+		}
+		if agg.Func != AggCount && agg.Field == "" {
+			return fmt.Errorf("aggregation (%v) requires a field", agg.Name())
+		}
+		if names[agg.Name()] {
+			return fmt.Errorf("aggregation (%v) is duplicated", agg.Name())
+		}
+		names[agg.Name()] = true
+	}
+	return nil
+}
+
+// CellPolygon returns the polygon of the cell at column x and row y of the
+// spec's grid over the tile, in the tile's SRID. Row 0 is the top of the tile.
+func (spec AggSpec) CellPolygon(t Tile, x, y uint) geom.Polygon {
+	ext, _ := t.Extent()
+	w, h := ext.XSpan()/float64(spec.Cells), ext.YSpan()/float64(spec.Cells)
+	minx, maxy := ext.MinX()+float64(x)*w, ext.MaxY()-float64(y)*h
+	return geom.Polygon{{
+		{minx, maxy - h},
+		{minx + w, maxy - h},
+		{minx + w, maxy},
+		{minx, maxy},
+	}}
+}
+
+// Aggregator is implemented by providers which are able to aggregate the
+// features of a layer into a grid over the tile, i.e. with a GROUP BY, so
+// low zoom tiles of quantitative data carry statistics rather than every
+// feature. The aggregated features have the cell's polygon, see
+// AggSpec.CellPolygon, as their geometry, in the tile's SRID, and a property
+// for each of the spec's aggregations. Cells without features are omitted.
+type Aggregator interface {
+	AggregateTile(ctx context.Context, layer string, t Tile, spec AggSpec, fn func(f *Feature) error) error
+}
+
+// AggregateTile streams the aggregated features of the layer for the tile,
+// if the Tiler implements Aggregator. Otherwise ErrUnsupported is returned.
+// The spec is validated before it is passed to the provider.
+func AggregateTile(ctx context.Context, t Tiler, layer string, tile Tile, spec AggSpec, fn func(f *Feature) error) error {
+	agg, release, ok := acquireAs[Aggregator](t)
+	if !ok {
+		return ErrUnsupported
+	}
+	defer release()
+	if err := spec.Validate(); err != nil {
+		return err
+	}
+	return agg.AggregateTile(ctx, layer, tile, spec, fn)
+}
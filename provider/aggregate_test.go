@@ -0,0 +1,78 @@
+package provider_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/slippy"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestAggSpecValidate(t *testing.T) {
+	type tcase struct {
+		spec provider.AggSpec
+		err  bool
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		err := tc.spec.Validate()
+		if tc.err != (err != nil) {
+			t.Errorf("error, expected error %v got %v", tc.err, err)
+		}
+	}
+
+	tests := map[string]tcase{
+		"valid": {
+			spec: provider.AggSpec{Cells: 4, Aggregations: []provider.Aggregation{
+				{Func: provider.AggCount},
+				{Func: provider.AggSum, Field: "population"},
+				{Func: provider.AggAvg, Field: "population"},
+			}},
+		},
+		"no cells": {
+			spec: provider.AggSpec{Aggregations: []provider.Aggregation{{Func: provider.AggCount}}},
+			err:  true,
+		},
+		"no aggregations": {
+			spec: provider.AggSpec{Cells: 4},
+			err:  true,
+		},
+		"missing field": {
+			spec: provider.AggSpec{Cells: 4, Aggregations: []provider.Aggregation{{Func: provider.AggSum}}},
+			err:  true,
+		},
+		"duplicate name": {
+			spec: provider.AggSpec{Cells: 4, Aggregations: []provider.Aggregation{
+				{Func: provider.AggCount},
+				{Func: provider.AggSum, Field: "population", As: "count"},
+			}},
+			err: true,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
+
+func TestAggSpecCellPolygon(t *testing.T) {
+	spec := provider.AggSpec{Cells: 2}
+	got := spec.CellPolygon(provider.NewTile(0, 0, 0, 64, 3857), 1, 0)
+
+	max := slippy.WebMercatorMax
+	expected := geom.Polygon{{{0, 0}, {max, 0}, {max, max}, {0, max}}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("cell, expected %v got %v", expected, got)
+	}
+}
+
+func TestAggregateTileUnsupported(t *testing.T) {
+	spec := provider.AggSpec{Cells: 2, Aggregations: []provider.Aggregation{{Func: provider.AggCount}}}
+	err := provider.AggregateTile(context.Background(), featuresTiler{}, "", provider.NewTile(0, 0, 0, 0, 3857), spec, func(f *provider.Feature) error { return nil })
+	if err != provider.ErrUnsupported {
+		t.Errorf("error, expected %v got %v", provider.ErrUnsupported, err)
+	}
+}
@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"context"
+	"errors"
+)
+
+// TileFeaturesAllLayers streams the features of each of the layers for the
+// tile to fn, along with the name of the layer the feature is from, merging
+// the layers into a single stream, i.e. to export a whole map. The layers are
+// streamed one after the other in the order given; if layers is empty every
+// layer of the Tiler is streamed, in the order of Layers.
+//
+// A layer without features (ErrNoFeatures) is skipped. Any other error, from
+// the provider or fn, stops the stream and is returned. The context is
+// checked between layers, so a canceled context stops the stream even if the
+// provider does not check it.
+func TileFeaturesAllLayers(ctx context.Context, t Tiler, layers []string, tile Tile, fn func(layer string, f *Feature) error) error {
+	if len(layers) == 0 {
+		infos, err := t.Layers()
+		if err != nil {
+			return err
+		}
+		for _, info := range infos {
+			layers = append(layers, info.Name())
+		}
+	}
+
+	for _, layer := range layers {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		layer := layer
+		err := t.TileFeatures(ctx, layer, tile, func(f *Feature) error {
+			return fn(layer, f)
+		})
+		if err != nil && !errors.Is(err, ErrNoFeatures) {
+			return err
+		}
+	}
+	return nil
+}
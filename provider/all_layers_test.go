@@ -0,0 +1,84 @@
+package provider_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestTileFeaturesAllLayers(t *testing.T) {
+	tiler := layersTiler{
+		"roads": {
+			{ID: 1, Geometry: geom.LineString{{0, 0}, {1, 1}}},
+			{ID: 2, Geometry: geom.LineString{{1, 1}, {2, 2}}},
+		},
+		"places": {
+			{ID: 3, Geometry: geom.Point{0, 0}},
+		},
+	}
+	errStop := errors.New("stop")
+
+	type tcase struct {
+		layers   []string
+		cancel   string
+		stop     uint64
+		expected []string
+		err      error
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var got []string
+		err := provider.TileFeaturesAllLayers(ctx, tiler, tc.layers, provider.NewTile(0, 0, 0, 0, 3857), func(layer string, f *provider.Feature) error {
+			got = append(got, fmt.Sprintf("%v/%v", layer, f.ID))
+			if layer == tc.cancel {
+				cancel()
+			}
+			if f.ID == tc.stop {
+				return errStop
+			}
+			return nil
+		})
+		if !errors.Is(err, tc.err) {
+			t.Errorf("error, expected %v got %v", tc.err, err)
+		}
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("features, expected %v got %v", tc.expected, got)
+		}
+	}
+
+	tests := map[string]tcase{
+		"merged": {
+			layers:   []string{"roads", "empty", "places"},
+			expected: []string{"roads/1", "roads/2", "places/3"},
+		},
+		"order": {
+			layers:   []string{"places", "roads"},
+			expected: []string{"places/3", "roads/1", "roads/2"},
+		},
+		"callback error": {
+			layers:   []string{"roads", "places"},
+			stop:     1,
+			expected: []string{"roads/1"},
+			err:      errStop,
+		},
+		"canceled between layers": {
+			layers:   []string{"roads", "places"},
+			cancel:   "roads",
+			expected: []string{"roads/1", "roads/2"},
+			err:      context.Canceled,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/slippy"
+	"github.com/go-spatial/tegola"
+	"github.com/go-spatial/tegola/basic"
+)
+
+// CrossesAntimeridian reports if the extent crosses ±180° longitude. The
+// extent may either use continuous coordinates extending past ±180°, i.e.
+// 170° to 190°, or be wrapped, with a MinX east of its MaxX, i.e. 170° to
+// -170°. WebMercator extents are reprojected to WGS84 before being checked.
+// false is returned for a nil extent or an SRID other than WebMercator or
+// WGS84.
+func CrossesAntimeridian(ext *geom.Extent, srid uint64) bool {
+	if ext == nil {
+		return false
+	}
+
+	minx, maxx := ext[0], ext[2]
+	switch srid {
+	case tegola.WGS84:
+	case tegola.WebMercator:
+		g, err := basic.FromWebMercator(tegola.WGS84, geom.MultiPoint{{ext[0], ext[1]}, {ext[2], ext[3]}})
+		if err != nil {
+			return false
+		}
+		pts := g.(geom.MultiPoint)
+		minx, maxx = pts[0][0], pts[1][0]
+	default:
+		return false
+	}
+
+	return minx > maxx || minx < -180 || maxx > 180
+}
+
+// TilesForExtent returns the tiles, from minZoom to maxZoom inclusive, whose
+// extent intersects the extent, ordered by zoom. The extent must be in
+// either WebMercator or WGS84. Extents crossing the antimeridian, see
+// CrossesAntimeridian, are split and their tiles wrapped into the valid
+// range, so an extent from 170° to -170° covers the tiles either side of
+// ±180° rather than the whole world.
+//
+// The returned tiles have no buffer and are in WebMercator.
+func TilesForExtent(ext *geom.Extent, srid uint64, minZoom, maxZoom uint) ([]Tile, error) {
+	if minZoom > maxZoom {
+		return nil, fmt.Errorf("min zoom (%v) is greater than max zoom (%v)", minZoom, maxZoom)
+	}
+	if maxZoom > tegola.MaxZ {
+		return nil, fmt.Errorf("max zoom (%v) is greater than %v", maxZoom, tegola.MaxZ)
+	}
+	if ext == nil {
+		return nil, nil
+	}
+
+	g, err := basic.ToWebMercator(srid, geom.MultiPoint{{ext[0], ext[1]}, {ext[2], ext[3]}})
+	if err != nil {
+		return nil, err
+	}
+	pts := g.(geom.MultiPoint)
+
+	// unwrap the extent, so it extends east past the antimeridian
+	merc := geom.Extent{pts[0][0], pts[0][1], pts[1][0], pts[1][1]}
+	if merc[0] > merc[2] {
+		merc[2] += 2 * slippy.WebMercatorMax
+	}
+
+	var tiles []Tile
+	for z := minZoom; z <= maxZoom; z++ {
+		minx, miny, maxx, maxy := tileRangeForExtent(z, &merc)
+		// an extent spanning more than the world covers each column once
+		if n := 1 << z; maxx-minx >= n {
+			minx, maxx = 0, n-1
+		}
+		for y := miny; y <= maxy; y++ {
+			for x := minx; x <= maxx; x++ {
+				tiles = append(tiles, NewTile(z, wrapTileX(z, x), uint(y), 0, tegola.WebMercator))
+			}
+		}
+	}
+	return tiles, nil
+}
+
+// Neighbors returns the tiles surrounding the tile at the same zoom, with the
+// same buffer and SRID, ordered by row and column. Tiles on the east and west
+// edges of the world wrap across the antimeridian. There are no tiles beyond
+// the poles, so tiles on the top and bottom rows have fewer neighbors, and
+// at zooms with less than three columns each neighbor is returned once.
+func Neighbors(t Tile) []Tile {
+	var (
+		z, x, y = t.ZXY()
+		_, srid = t.Extent()
+		buf     = tileBuffer(t)
+		n       = 1 << z
+		tiles   []Tile
+		seen    = make(map[[2]uint]struct{})
+	)
+	for dy := -1; dy <= 1; dy++ {
+		ty := int(y) + dy
+		if ty < 0 || ty >= n {
+			continue
+		}
+		for dx := -1; dx <= 1; dx++ {
+			tx := wrapTileX(z, int(x)+dx)
+			key := [2]uint{tx, uint(ty)}
+			if _, ok := seen[key]; ok || (tx == x && uint(ty) == y) {
+				continue
+			}
+			seen[key] = struct{}{}
+			tiles = append(tiles, NewTile(z, tx, uint(ty), buf, uint(srid)))
+		}
+	}
+	return tiles
+}
@@ -0,0 +1,181 @@
+package provider_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/slippy"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestCrossesAntimeridian(t *testing.T) {
+	const max = slippy.WebMercatorMax
+
+	type tcase struct {
+		ext      *geom.Extent
+		srid     uint64
+		expected bool
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		if got := provider.CrossesAntimeridian(tc.ext, tc.srid); got != tc.expected {
+			t.Errorf("crosses, expected %v got %v", tc.expected, got)
+		}
+	}
+
+	tests := map[string]tcase{
+		"pacific wrapped": {
+			ext:      &geom.Extent{170, -10, -170, 10},
+			srid:     4326,
+			expected: true,
+		},
+		"pacific continuous": {
+			ext:      &geom.Extent{170, -10, 190, 10},
+			srid:     4326,
+			expected: true,
+		},
+		"pacific webmercator": {
+			ext:      &geom.Extent{max * 17 / 18, -max / 10, -max * 17 / 18, max / 10},
+			srid:     3857,
+			expected: true,
+		},
+		"pacific webmercator continuous": {
+			ext:      &geom.Extent{max * 17 / 18, -max / 10, max * 19 / 18, max / 10},
+			srid:     3857,
+			expected: true,
+		},
+		"eastern hemisphere": {
+			ext:      &geom.Extent{10, -10, 170, 10},
+			srid:     4326,
+			expected: false,
+		},
+		"western hemisphere webmercator": {
+			ext:      &geom.Extent{-max * 17 / 18, -max / 10, -max / 18, max / 10},
+			srid:     3857,
+			expected: false,
+		},
+		"world": {
+			ext:      &geom.Extent{-180, -85, 180, 85},
+			srid:     4326,
+			expected: false,
+		},
+		"nil": {
+			srid:     4326,
+			expected: false,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
+
+func TestTilesForExtent(t *testing.T) {
+	type tcase struct {
+		ext      *geom.Extent
+		srid     uint64
+		minZoom  uint
+		maxZoom  uint
+		expected [][3]uint
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		tiles, err := provider.TilesForExtent(tc.ext, tc.srid, tc.minZoom, tc.maxZoom)
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		if got := zxys(tiles); !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("tiles, expected %v got %v", tc.expected, got)
+		}
+	}
+
+	tests := map[string]tcase{
+		"pacific wrapped": {
+			ext:      &geom.Extent{170, 10, -170, 20},
+			srid:     4326,
+			minZoom:  1,
+			maxZoom:  2,
+			expected: [][3]uint{{1, 0, 0}, {1, 1, 0}, {2, 0, 1}, {2, 3, 1}},
+		},
+		"pacific continuous": {
+			ext:      &geom.Extent{170, 10, 190, 20},
+			srid:     4326,
+			minZoom:  2,
+			maxZoom:  2,
+			expected: [][3]uint{{2, 0, 1}, {2, 3, 1}},
+		},
+		"eastern hemisphere": {
+			ext:      &geom.Extent{100, 10, 170, 20},
+			srid:     4326,
+			minZoom:  1,
+			maxZoom:  2,
+			expected: [][3]uint{{1, 1, 0}, {2, 3, 1}},
+		},
+		"wider than the world": {
+			ext:      &geom.Extent{-200, -10, 200, 10},
+			srid:     4326,
+			minZoom:  1,
+			maxZoom:  1,
+			expected: [][3]uint{{1, 0, 0}, {1, 0, 1}, {1, 1, 0}, {1, 1, 1}},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
+
+func TestNeighbors(t *testing.T) {
+	type tcase struct {
+		tile     provider.Tile
+		expected [][3]uint
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		if got := zxys(provider.Neighbors(tc.tile)); !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("neighbors, expected %v got %v", tc.expected, got)
+		}
+	}
+
+	tests := map[string]tcase{
+		"interior": {
+			tile: provider.NewTile(3, 4, 4, 0, 3857),
+			expected: [][3]uint{
+				{3, 3, 3}, {3, 3, 4}, {3, 3, 5},
+				{3, 4, 3}, {3, 4, 5},
+				{3, 5, 3}, {3, 5, 4}, {3, 5, 5},
+			},
+		},
+		"west edge wraps": {
+			tile: provider.NewTile(3, 0, 4, 0, 3857),
+			expected: [][3]uint{
+				{3, 0, 3}, {3, 0, 5},
+				{3, 1, 3}, {3, 1, 4}, {3, 1, 5},
+				{3, 7, 3}, {3, 7, 4}, {3, 7, 5},
+			},
+		},
+		"north east corner": {
+			tile: provider.NewTile(3, 7, 0, 0, 3857),
+			expected: [][3]uint{
+				{3, 0, 0}, {3, 0, 1},
+				{3, 6, 0}, {3, 6, 1},
+				{3, 7, 1},
+			},
+		},
+		"z1": {
+			tile:     provider.NewTile(1, 0, 0, 0, 3857),
+			expected: [][3]uint{{1, 0, 1}, {1, 1, 0}, {1, 1, 1}},
+		},
+		"z0": {
+			tile: provider.NewTile(0, 0, 0, 0, 3857),
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
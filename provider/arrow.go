@@ -0,0 +1,385 @@
+package provider
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/wkb"
+)
+
+// ArrowBatchSize is the number of features in each record batch written by
+// EncodeArrow. The schema is inferred from the first batch, so larger
+// batches make it more likely every property is seen.
+var ArrowBatchSize = 1024
+
+// arrowType is the type of an Arrow column
+type arrowType uint8
+
+const (
+	arrowUint64 arrowType = iota
+	arrowInt64
+	arrowFloat64
+	arrowBool
+	arrowUtf8
+	arrowBinary
+)
+
+// arrowColumn is a column of the schema, with the property it is read from
+type arrowColumn struct {
+	name string
+	typ  arrowType
+}
+
+// arrowRow is a feature with its geometry encoded as WKB
+type arrowRow struct {
+	id   uint64
+	wkb  []byte
+	tags map[string]interface{}
+}
+
+// EncodeArrow encodes the features of the layer for the tile as an Apache
+// Arrow IPC stream and writes it to w, so tiles can be loaded by DataFrame
+// libraries, i.e. pandas, polars or DuckDB. The features are written in
+// record batches of ArrowBatchSize features as they are streamed from
+// TileFeatures.
+//
+// The first columns are "id", the feature's ID as an unsigned 64 bit
+// integer, and "geometry", the feature's WGS84 geometry as WKB, tagged with
+// the geoarrow.wkb extension type. A null geometry is encoded as null. They
+// are followed by a column for each property, ordered by name, whose type is
+// inferred from the first batch: integers are int64, floating point numbers
+// float64, booleans bool and anything else a string. Properties mixing
+// integers and floating point numbers are widened to float64, and
+// properties mixing other types are widened to strings.
+//
+// As the schema can not change once written, properties which first appear
+// after the first batch are dropped, and values in later batches which can
+// not be converted to their column's type are written as nulls. Properties
+// named "id" or "geometry" are dropped as well.
+func EncodeArrow(ctx context.Context, t Tiler, layer string, tile Tile, w io.Writer) error {
+	var (
+		enc   = arrowEncoder{w: w}
+		batch = make([]arrowRow, 0, ArrowBatchSize)
+	)
+	err := t.TileFeatures(ctx, layer, tile, func(f *Feature) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		g, err := f.wgs84Geometry()
+		if err != nil {
+			return err
+		}
+		row := arrowRow{id: f.ID, tags: f.Tags}
+		if g != nil && !geom.IsEmpty(g) {
+			if row.wkb, err = wkb.EncodeBytes(g); err != nil {
+				return fmt.Errorf("feature %v: %w", f.ID, err)
+			}
+		}
+
+		if batch = append(batch, row); len(batch) < ArrowBatchSize {
+			return nil
+		}
+		err = enc.write(batch)
+		batch = batch[:0]
+		return err
+	})
+	if err != nil && !errors.Is(err, ErrNoFeatures) {
+		return err
+	}
+
+	// an empty stream still has a schema
+	if len(batch) > 0 || enc.columns == nil {
+		if err = enc.write(batch); err != nil {
+			return err
+		}
+	}
+	return writeArrowEOS(w)
+}
+
+// arrowEncoder writes the schema, inferred from the first batch, followed by
+// the record batches
+type arrowEncoder struct {
+	w       io.Writer
+	columns []arrowColumn
+}
+
+func (enc *arrowEncoder) write(batch []arrowRow) error {
+	if enc.columns == nil {
+		enc.columns = arrowColumns(batch)
+		if err := writeArrowMessage(enc.w, arrowHeaderSchema, arrowSchema(enc.columns), nil); err != nil {
+			return err
+		}
+	}
+	if len(batch) == 0 {
+		return nil
+	}
+
+	var body arrowBody
+	for i, col := range enc.columns {
+		vals := make([]interface{}, len(batch))
+		for j := range batch {
+			switch i {
+			case 0:
+				vals[j] = batch[j].id
+			case 1:
+				if batch[j].wkb != nil {
+					vals[j] = batch[j].wkb
+				}
+			default:
+				vals[j] = batch[j].tags[col.name]
+			}
+		}
+		body.column(col.typ, vals)
+	}
+
+	return writeArrowMessage(enc.w, arrowHeaderRecordBatch, fbTable{
+		int64(len(batch)),
+		body.nodes,
+		body.buffers,
+	}, body.data)
+}
+
+// arrowColumns returns the columns of the schema, inferring the type of each
+// property from the batch
+func arrowColumns(batch []arrowRow) []arrowColumn {
+	types := make(map[string]arrowType)
+	for _, row := range batch {
+		for k, v := range row.tags {
+			if v == nil || k == "id" || k == "geometry" {
+				continue
+			}
+			typ := arrowTypeOf(v)
+			if seen, ok := types[k]; ok && seen != typ {
+				typ = arrowWiden(seen, typ)
+			}
+			types[k] = typ
+		}
+	}
+
+	names := make([]string, 0, len(types))
+	for k := range types {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	columns := []arrowColumn{
+		{name: "id", typ: arrowUint64},
+		{name: "geometry", typ: arrowBinary},
+	}
+	for _, k := range names {
+		columns = append(columns, arrowColumn{name: k, typ: types[k]})
+	}
+	return columns
+}
+
+func arrowTypeOf(v interface{}) arrowType {
+	switch v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return arrowInt64
+	case float32, float64:
+		return arrowFloat64
+	case bool:
+		return arrowBool
+	default:
+		return arrowUtf8
+	}
+}
+
+// arrowWiden returns the type which holds values of both types
+func arrowWiden(a, b arrowType) arrowType {
+	if (a == arrowInt64 && b == arrowFloat64) || (a == arrowFloat64 && b == arrowInt64) {
+		return arrowFloat64
+	}
+	return arrowUtf8
+}
+
+// arrowSchema returns the Schema table of the columns
+func arrowSchema(columns []arrowColumn) fbTable {
+	fields := make([]fbTable, len(columns))
+	for i, col := range columns {
+		var (
+			typeType uint8
+			typ      fbTable
+			meta     []fbTable
+		)
+		switch col.typ {
+		case arrowUint64:
+			typeType, typ = arrowTypeInt, fbTable{int32(64), false}
+		case arrowInt64:
+			typeType, typ = arrowTypeInt, fbTable{int32(64), true}
+		case arrowFloat64:
+			typeType, typ = arrowTypeFloatingPoint, fbTable{arrowPrecisionDouble}
+		case arrowBool:
+			typeType, typ = arrowTypeBool, fbTable{}
+		case arrowUtf8:
+			typeType, typ = arrowTypeUtf8, fbTable{}
+		case arrowBinary:
+			typeType, typ = arrowTypeBinary, fbTable{}
+			meta = []fbTable{
+				{"ARROW:extension:name", "geoarrow.wkb"},
+				{"ARROW:extension:metadata", "{}"},
+			}
+		}
+
+		field := fbTable{
+			col.name,
+			// the id is the only column without nulls
+			i != 0,
+			typeType,
+			typ,
+			nil,
+			[]fbTable{},
+		}
+		if meta != nil {
+			field = append(field, meta)
+		}
+		fields[i] = field
+	}
+
+	return fbTable{
+		// little endian
+		int16(0),
+		fields,
+	}
+}
+
+// arrowBody is the body of a record batch, with the FieldNode of each column
+// and the Buffer of each of the columns' buffers
+type arrowBody struct {
+	data    []byte
+	nodes   fbStructs
+	buffers fbStructs
+}
+
+// buffer adds the buffer to the body, padded to 8 bytes
+func (body *arrowBody) buffer(b []byte) {
+	body.buffers = append(body.buffers, [2]int64{int64(len(body.data)), int64(len(b))})
+	body.data = append(body.data, b...)
+	for len(body.data)%8 != 0 {
+		body.data = append(body.data, 0)
+	}
+}
+
+// column adds the column's buffers to the body, values which can not be
+// converted to the column's type are nulls
+func (body *arrowBody) column(typ arrowType, vals []interface{}) {
+	var (
+		nulls    int64
+		validity = make([]byte, (len(vals)+7)/8)
+		offsets  []byte
+		data     []byte
+	)
+	switch typ {
+	case arrowBool:
+		data = make([]byte, (len(vals)+7)/8)
+	case arrowUtf8, arrowBinary:
+		offsets = make([]byte, 4*(len(vals)+1))
+	default:
+		data = make([]byte, 8*len(vals))
+	}
+
+	for i, v := range vals {
+		ok := v != nil
+		if ok {
+			switch typ {
+			case arrowUint64:
+				binary.LittleEndian.PutUint64(data[8*i:], v.(uint64))
+			case arrowInt64:
+				var n int64
+				if n, ok = arrowInt(v); ok {
+					binary.LittleEndian.PutUint64(data[8*i:], uint64(n))
+				}
+			case arrowFloat64:
+				var n float64
+				if n, ok = arrowFloat(v); ok {
+					binary.LittleEndian.PutUint64(data[8*i:], math.Float64bits(n))
+				}
+			case arrowBool:
+				var b bool
+				if b, ok = v.(bool); ok && b {
+					data[i/8] |= 1 << (i % 8)
+				}
+			case arrowUtf8:
+				if s, isStr := v.(string); isStr {
+					data = append(data, s...)
+				} else {
+					data = append(data, fmt.Sprint(v)...)
+				}
+			case arrowBinary:
+				data = append(data, v.([]byte)...)
+			}
+		}
+
+		if ok {
+			validity[i/8] |= 1 << (i % 8)
+		} else {
+			nulls++
+		}
+		if offsets != nil {
+			binary.LittleEndian.PutUint32(offsets[4*(i+1):], uint32(len(data)))
+		}
+	}
+
+	body.nodes = append(body.nodes, [2]int64{int64(len(vals)), nulls})
+	body.buffer(validity)
+	if offsets != nil {
+		body.buffer(offsets)
+	}
+	body.buffer(data)
+}
+
+// arrowInt converts an integer to an int64, uint64 values beyond the range
+// of an int64 are not converted
+func arrowInt(v interface{}) (int64, bool) {
+	switch v := v.(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint:
+		return int64(v), uint64(v) <= math.MaxInt64
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint64:
+		return int64(v), v <= math.MaxInt64
+	default:
+		return 0, false
+	}
+}
+
+// arrowFloat converts an integer or floating point number to a float64
+func arrowFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	if n, ok := arrowInt(v); ok {
+		return float64(n), true
+	}
+	if n, ok := v.(uint64); ok {
+		return float64(n), true
+	}
+	if n, ok := v.(uint); ok {
+		return float64(n), true
+	}
+	return 0, false
+}
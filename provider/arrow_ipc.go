@@ -0,0 +1,222 @@
+package provider
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// The Arrow IPC stream format frames flatbuffers encoded metadata followed
+// by the message body. Only the small subset of the format EncodeArrow needs
+// is implemented, see https://arrow.apache.org/docs/format/Columnar.html
+
+// arrowMetadataV5 is the MetadataVersion of the messages
+const arrowMetadataV5 int16 = 4
+
+// MessageHeader union types
+const (
+	arrowHeaderSchema      uint8 = 1
+	arrowHeaderRecordBatch uint8 = 3
+)
+
+// Type union types
+const (
+	arrowTypeInt           uint8 = 2
+	arrowTypeFloatingPoint uint8 = 3
+	arrowTypeBinary        uint8 = 4
+	arrowTypeUtf8          uint8 = 5
+	arrowTypeBool          uint8 = 6
+)
+
+// arrowPrecisionDouble is the FloatingPoint precision of float64 columns
+const arrowPrecisionDouble int16 = 2
+
+// arrowContinuation starts every message of the stream
+const arrowContinuation uint32 = 0xFFFFFFFF
+
+// writeArrowMessage writes the message with the header, a Schema or
+// RecordBatch table, and the body. The body must be padded to 8 bytes.
+func writeArrowMessage(w io.Writer, headerType uint8, header fbTable, body []byte) error {
+	meta := fbTable{
+		arrowMetadataV5,
+		headerType,
+		header,
+		int64(len(body)),
+	}.bytes()
+
+	prefix := make([]byte, 8)
+	binary.LittleEndian.PutUint32(prefix, arrowContinuation)
+	binary.LittleEndian.PutUint32(prefix[4:], uint32(len(meta)))
+
+	for _, b := range [][]byte{prefix, meta, body} {
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeArrowEOS writes the end of stream marker
+func writeArrowEOS(w io.Writer) error {
+	eos := make([]byte, 8)
+	binary.LittleEndian.PutUint32(eos, arrowContinuation)
+	_, err := w.Write(eos)
+	return err
+}
+
+// fbTable is a flatbuffers table, its fields are indexed by their id. A
+// field is a scalar (bool, uint8, int16, int32 or int64) or an object
+// (fbTable, string, []fbTable or fbStructs). nil fields are absent.
+type fbTable []interface{}
+
+// fbStructs is a vector of structs made of two int64s, which is the layout
+// of both FieldNode and Buffer
+type fbStructs [][2]int64
+
+// bytes returns the table as the root of a flatbuffer, padded to 8 bytes
+func (t fbTable) bytes() []byte {
+	b := fbBuilder{buf: make([]byte, 4)}
+	b.uoffset(0, b.object(t))
+	b.pad(8)
+	return b.buf
+}
+
+// fbBuilder writes flatbuffers front to back: objects are written after the
+// tables and vectors referencing them, as offsets to objects are unsigned.
+// Positions are aligned relative to the start of the buffer, which must in
+// turn be 8 byte aligned when read.
+type fbBuilder struct {
+	buf []byte
+}
+
+func (b *fbBuilder) pad(align int) {
+	for len(b.buf)%align != 0 {
+		b.buf = append(b.buf, 0)
+	}
+}
+
+// uoffset sets the offset at the position at to reference the object at to
+func (b *fbBuilder) uoffset(at, to int) {
+	binary.LittleEndian.PutUint32(b.buf[at:], uint32(to-at))
+}
+
+func (b *fbBuilder) u16(v uint16) {
+	b.buf = append(b.buf, 0, 0)
+	binary.LittleEndian.PutUint16(b.buf[len(b.buf)-2:], v)
+}
+
+func (b *fbBuilder) u32(v uint32) {
+	b.buf = append(b.buf, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(b.buf[len(b.buf)-4:], v)
+}
+
+func (b *fbBuilder) u64(v uint64) {
+	b.buf = append(b.buf, 0, 0, 0, 0, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint64(b.buf[len(b.buf)-8:], v)
+}
+
+// object writes the object and returns its position
+func (b *fbBuilder) object(o interface{}) int {
+	switch o := o.(type) {
+	case fbTable:
+		return b.table(o)
+
+	case string:
+		b.pad(4)
+		pos := len(b.buf)
+		b.u32(uint32(len(o)))
+		b.buf = append(append(b.buf, o...), 0)
+		return pos
+
+	case []fbTable:
+		b.pad(4)
+		pos := len(b.buf)
+		b.u32(uint32(len(o)))
+		b.buf = append(b.buf, make([]byte, 4*len(o))...)
+		for i := range o {
+			b.uoffset(pos+4+4*i, b.table(o[i]))
+		}
+		return pos
+
+	case fbStructs:
+		// the structs, rather than the length, are 8 byte aligned
+		for (len(b.buf)+4)%8 != 0 {
+			b.buf = append(b.buf, 0)
+		}
+		pos := len(b.buf)
+		b.u32(uint32(len(o)))
+		for _, s := range o {
+			b.u64(uint64(s[0]))
+			b.u64(uint64(s[1]))
+		}
+		return pos
+
+	default:
+		panic("flatbuffers: unsupported object type")
+	}
+}
+
+// table writes the table's vtable, then the table, then the objects the
+// table references, and returns the table's position
+func (b *fbBuilder) table(t fbTable) int {
+	b.pad(2)
+	vt := len(b.buf)
+	vtSize := 4 + 2*len(t)
+	b.buf = append(b.buf, make([]byte, vtSize)...)
+
+	// 8 byte alignment suits every field
+	b.pad(8)
+	tbl := len(b.buf)
+	b.u32(uint32(int32(tbl - vt)))
+
+	type ref struct {
+		at  int
+		obj interface{}
+	}
+	var refs []ref
+
+	for i, v := range t {
+		if v == nil {
+			continue
+		}
+
+		var at int
+		switch v := v.(type) {
+		case bool:
+			at = len(b.buf)
+			if v {
+				b.buf = append(b.buf, 1)
+			} else {
+				b.buf = append(b.buf, 0)
+			}
+		case uint8:
+			at = len(b.buf)
+			b.buf = append(b.buf, v)
+		case int16:
+			b.pad(2)
+			at = len(b.buf)
+			b.u16(uint16(v))
+		case int32:
+			b.pad(4)
+			at = len(b.buf)
+			b.u32(uint32(v))
+		case int64:
+			b.pad(8)
+			at = len(b.buf)
+			b.u64(uint64(v))
+		default:
+			b.pad(4)
+			at = len(b.buf)
+			b.buf = append(b.buf, 0, 0, 0, 0)
+			refs = append(refs, ref{at: at, obj: v})
+		}
+		binary.LittleEndian.PutUint16(b.buf[vt+4+2*i:], uint16(at-tbl))
+	}
+
+	binary.LittleEndian.PutUint16(b.buf[vt:], uint16(vtSize))
+	binary.LittleEndian.PutUint16(b.buf[vt+2:], uint16(len(b.buf)-tbl))
+
+	for _, r := range refs {
+		b.uoffset(r.at, b.object(r.obj))
+	}
+	return tbl
+}
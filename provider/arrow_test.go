@@ -0,0 +1,251 @@
+package provider_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/wkb"
+	"github.com/go-spatial/tegola/provider"
+)
+
+// fbReader reads the flatbuffers tables of the Arrow messages
+type fbReader []byte
+
+func (b fbReader) u32(at int) int { return int(binary.LittleEndian.Uint32(b[at:])) }
+
+// field returns the position of the table's field, 0 if it is absent
+func (b fbReader) field(tbl, i int) int {
+	vt := tbl - int(int32(binary.LittleEndian.Uint32(b[tbl:])))
+	if 4+2*i >= int(binary.LittleEndian.Uint16(b[vt:])) {
+		return 0
+	}
+	if off := int(binary.LittleEndian.Uint16(b[vt+4+2*i:])); off != 0 {
+		return tbl + off
+	}
+	return 0
+}
+
+// ref returns the position of the object referenced by the table's field
+func (b fbReader) ref(tbl, i int) int {
+	at := b.field(tbl, i)
+	if at == 0 {
+		return 0
+	}
+	return at + b.u32(at)
+}
+
+func (b fbReader) str(tbl, i int) string {
+	s := b.ref(tbl, i)
+	return string(b[s+4 : s+4+b.u32(s)])
+}
+
+// arrowField is a column of the schema: its name, Type union type, and
+// whether integers are signed
+type arrowField struct {
+	name     string
+	typeType uint8
+	signed   bool
+}
+
+// readArrow reads the Arrow IPC stream, returning the schema's fields and
+// the values of each column, across all record batches
+func readArrow(r io.Reader) (fields []arrowField, columns [][]interface{}, batches int, err error) {
+	for {
+		prefix := make([]byte, 8)
+		if _, err = io.ReadFull(r, prefix); err != nil {
+			return nil, nil, 0, err
+		}
+		if binary.LittleEndian.Uint32(prefix) != 0xFFFFFFFF {
+			return nil, nil, 0, fmt.Errorf("missing continuation marker")
+		}
+		size := binary.LittleEndian.Uint32(prefix[4:])
+		if size == 0 {
+			return fields, columns, batches, nil
+		}
+
+		meta := make(fbReader, size)
+		if _, err = io.ReadFull(r, meta); err != nil {
+			return nil, nil, 0, err
+		}
+		msg := meta.u32(0)
+		header := meta.ref(msg, 2)
+		body := make([]byte, binary.LittleEndian.Uint64(meta[meta.field(msg, 3):]))
+		if _, err = io.ReadFull(r, body); err != nil {
+			return nil, nil, 0, err
+		}
+
+		switch headerType := meta[meta.field(msg, 1)]; headerType {
+		case 1: // Schema
+			vec := meta.ref(header, 1)
+			for i := 0; i < meta.u32(vec); i++ {
+				at := vec + 4 + 4*i
+				fld := at + meta.u32(at)
+				af := arrowField{name: meta.str(fld, 0), typeType: meta[meta.field(fld, 2)]}
+				if af.typeType == 2 {
+					typ := meta.ref(fld, 3)
+					af.signed = meta[meta.field(typ, 1)] == 1
+				}
+				fields = append(fields, af)
+			}
+			columns = make([][]interface{}, len(fields))
+
+		case 3: // RecordBatch
+			batches++
+			n := int(binary.LittleEndian.Uint64(meta[meta.field(header, 0):]))
+			bufs := meta.ref(header, 2) + 4
+			next := func() []byte {
+				off, l := binary.LittleEndian.Uint64(meta[bufs:]), binary.LittleEndian.Uint64(meta[bufs+8:])
+				bufs += 16
+				return body[off : off+l]
+			}
+			for c, f := range fields {
+				validity := next()
+				var offsets []byte
+				if f.typeType == 4 || f.typeType == 5 {
+					offsets = next()
+				}
+				data := next()
+				for i := 0; i < n; i++ {
+					if validity[i/8]&(1<<(i%8)) == 0 {
+						columns[c] = append(columns[c], nil)
+						continue
+					}
+					var v interface{}
+					switch f.typeType {
+					case 2:
+						if v = binary.LittleEndian.Uint64(data[8*i:]); f.signed {
+							v = int64(v.(uint64))
+						}
+					case 3:
+						v = math.Float64frombits(binary.LittleEndian.Uint64(data[8*i:]))
+					case 6:
+						v = data[i/8]&(1<<(i%8)) != 0
+					case 4, 5:
+						b := data[binary.LittleEndian.Uint32(offsets[4*i:]):binary.LittleEndian.Uint32(offsets[4*(i+1):])]
+						if v = b; f.typeType == 5 {
+							v = string(b)
+						}
+					}
+					columns[c] = append(columns[c], v)
+				}
+			}
+
+		default:
+			return nil, nil, 0, fmt.Errorf("unexpected message header type %v", headerType)
+		}
+	}
+}
+
+func TestEncodeArrow(t *testing.T) {
+	wkbOf := func(g geom.Geometry) []byte {
+		b, err := wkb.EncodeBytes(g)
+		if err != nil {
+			t.Fatalf("wkb, expected nil got %v", err)
+		}
+		return b
+	}
+
+	type tcase struct {
+		features  []provider.Feature
+		batchSize int
+		fields    []arrowField
+		columns   [][]interface{}
+		batches   int
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		if tc.batchSize != 0 {
+			defer func(size int) { provider.ArrowBatchSize = size }(provider.ArrowBatchSize)
+			provider.ArrowBatchSize = tc.batchSize
+		}
+
+		var buf bytes.Buffer
+		tiler := featuresTiler{features: tc.features}
+		if err := provider.EncodeArrow(context.Background(), tiler, "", provider.NewTile(0, 0, 0, 0, 3857), &buf); err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+
+		fields, columns, batches, err := readArrow(&buf)
+		if err != nil {
+			t.Fatalf("read error, expected nil got %v", err)
+		}
+		if !reflect.DeepEqual(fields, tc.fields) {
+			t.Errorf("fields, expected %v got %v", tc.fields, fields)
+		}
+		if !reflect.DeepEqual(columns, tc.columns) {
+			t.Errorf("columns, expected %v got %v", tc.columns, columns)
+		}
+		if batches != tc.batches {
+			t.Errorf("batches, expected %v got %v", tc.batches, batches)
+		}
+	}
+
+	tests := map[string]tcase{
+		"typed properties": {
+			features: []provider.Feature{
+				{ID: 1, Geometry: geom.Point{1, 2}, SRID: 4326, Tags: map[string]interface{}{"name": "a", "pop": 10, "open": true, "area": 1}},
+				{ID: 2, Geometry: geom.LineString{{0, 0}, {1, 1}}, SRID: 4326, Tags: map[string]interface{}{"name": "b", "pop": int64(20), "area": 1.5, "kind": "road"}},
+				{ID: 3, Tags: map[string]interface{}{"name": "c", "kind": 3, "geometry": "dropped"}},
+			},
+			fields: []arrowField{
+				{name: "id", typeType: 2},
+				{name: "geometry", typeType: 4},
+				{name: "area", typeType: 3},
+				{name: "kind", typeType: 5},
+				{name: "name", typeType: 5},
+				{name: "open", typeType: 6},
+				{name: "pop", typeType: 2, signed: true},
+			},
+			columns: [][]interface{}{
+				{uint64(1), uint64(2), uint64(3)},
+				{wkbOf(geom.Point{1, 2}), wkbOf(geom.LineString{{0, 0}, {1, 1}}), nil},
+				// integers and floats are widened to floats
+				{1.0, 1.5, nil},
+				// strings and integers are widened to strings
+				{nil, "road", "3"},
+				{"a", "b", "c"},
+				{true, nil, nil},
+				{int64(10), int64(20), nil},
+			},
+			batches: 1,
+		},
+		"schema from first batch": {
+			features: []provider.Feature{
+				{ID: 1, Tags: map[string]interface{}{"pop": 10}},
+				{ID: 2, Tags: map[string]interface{}{"pop": 20}},
+				{ID: 3, Tags: map[string]interface{}{"pop": "many", "name": "late"}},
+			},
+			batchSize: 2,
+			fields: []arrowField{
+				{name: "id", typeType: 2},
+				{name: "geometry", typeType: 4},
+				{name: "pop", typeType: 2, signed: true},
+			},
+			columns: [][]interface{}{
+				{uint64(1), uint64(2), uint64(3)},
+				{nil, nil, nil},
+				{int64(10), int64(20), nil},
+			},
+			batches: 2,
+		},
+		"no features": {
+			fields: []arrowField{
+				{name: "id", typeType: 2},
+				{name: "geometry", typeType: 4},
+			},
+			columns: [][]interface{}{nil, nil},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
@@ -0,0 +1,24 @@
+package provider
+
+import (
+	"context"
+	"time"
+)
+
+type asOfKey struct{}
+
+// WithAsOf returns a copy of ctx carrying an as of time, requesting the map
+// as it was at that time. Providers of temporal data, i.e. rows with valid
+// time ranges, read it with AsOfFromContext to filter for the rows valid at
+// the time. Providers without temporal data ignore it.
+//
+// Tiles rendered as of a time should not be cached with the current tiles.
+func WithAsOf(ctx context.Context, t time.Time) context.Context {
+	return context.WithValue(ctx, asOfKey{}, t)
+}
+
+// AsOfFromContext returns the as of time set by WithAsOf, if any
+func AsOfFromContext(ctx context.Context) (time.Time, bool) {
+	t, ok := ctx.Value(asOfKey{}).(time.Time)
+	return t, ok
+}
@@ -0,0 +1,44 @@
+package provider
+
+import "strings"
+
+// AttributionSeparator is used to join the attributions of multiple layers
+const AttributionSeparator = "; "
+
+// Attributer is implemented by providers whose layers carry data license
+// attribution requirements.
+type Attributer interface {
+	// Attribution returns the attribution for the layer, or an empty string
+	// if the layer does not require attribution
+	Attribution(layer string) string
+}
+
+// Attribution returns the attribution for the provider's layer. An empty
+// string is returned if the provider does not implement Attributer.
+func Attribution(t Tiler, layer string) string {
+	a, release, ok := acquireAs[Attributer](t)
+	if !ok {
+		return ""
+	}
+	defer release()
+	return a.Attribution(layer)
+}
+
+// JoinAttributions combines the attributions into a single attribution,
+// skipping empty and duplicate values while preserving their order.
+func JoinAttributions(attributions ...string) string {
+	seen := make(map[string]struct{}, len(attributions))
+	var joined []string
+	for _, a := range attributions {
+		a = strings.TrimSpace(a)
+		if a == "" {
+			continue
+		}
+		if _, ok := seen[a]; ok {
+			continue
+		}
+		seen[a] = struct{}{}
+		joined = append(joined, a)
+	}
+	return strings.Join(joined, AttributionSeparator)
+}
@@ -0,0 +1,61 @@
+package provider_test
+
+import (
+	"testing"
+
+	"github.com/go-spatial/tegola/provider"
+)
+
+type attributionTiler struct {
+	featuresTiler
+}
+
+func (attributionTiler) Attribution(layer string) string {
+	if layer == "roads" {
+		return "© OpenStreetMap contributors"
+	}
+	return ""
+}
+
+func TestAttribution(t *testing.T) {
+	type tcase struct {
+		tiler    provider.Tiler
+		layer    string
+		expected string
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		if got := provider.Attribution(tc.tiler, tc.layer); got != tc.expected {
+			t.Errorf("attribution, expected %q got %q", tc.expected, got)
+		}
+	}
+
+	tests := map[string]tcase{
+		"attributer": {
+			tiler:    attributionTiler{},
+			layer:    "roads",
+			expected: "© OpenStreetMap contributors",
+		},
+		"attributer without layer attribution": {
+			tiler: attributionTiler{},
+			layer: "water",
+		},
+		"not an attributer": {
+			tiler: featuresTiler{},
+			layer: "roads",
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
+
+func TestJoinAttributions(t *testing.T) {
+	got := provider.JoinAttributions("tegola", "", "© OpenStreetMap contributors", "tegola")
+	expected := "tegola; © OpenStreetMap contributors"
+	if got != expected {
+		t.Errorf("joined attribution, expected %q got %q", expected, got)
+	}
+}
@@ -0,0 +1,75 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-spatial/geom"
+)
+
+// BBoxMinSize is the minimum width and height, in pixels, of the bounding
+// boxes of a Tiler wrapped with WithBBoxOnly
+var BBoxMinSize = 1.0
+
+// WithBBoxOnly wraps the Tiler so the geometry of every feature is replaced
+// by the polygon of its bounding box, keeping its properties, for compact
+// overview tiles showing where there is data. The bounding boxes are in the
+// tile's SRID, features not in the tile's SRID are reprojected first.
+//
+// Bounding boxes narrower or shorter than BBoxMinSize pixels at the tile's
+// zoom are widened to BBoxMinSize pixels around their center, so points and
+// horizontal or vertical lines become small squares and rectangles. If
+// BBoxMinSize is <= 0, bounding boxes without an area are replaced by their
+// center point instead. Features with a nil or empty geometry are passed to
+// the callback unchanged.
+func WithBBoxOnly(t Tiler) Tiler {
+	return &bboxOnlyTiler{Tiler: t}
+}
+
+type bboxOnlyTiler struct {
+	Tiler
+}
+
+func (bt *bboxOnlyTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	minSize := pixelTolerance(t, BBoxMinSize)
+	_, tileSRID := t.Extent()
+
+	return bt.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		if f.Geometry == nil {
+			return fn(f)
+		}
+
+		if err := reprojectFeatureInPlace(f, tileSRID); err != nil {
+			return err
+		}
+
+		ext, err := geom.NewExtentFromGeometry(f.Geometry)
+		if err != nil {
+			return fmt.Errorf("unable to compute extent of feature %v in layer (%v): %w", f.ID, layer, err)
+		}
+		if ext == nil {
+			// empty geometry
+			return fn(f)
+		}
+
+		minx, miny, maxx, maxy := ext.MinX(), ext.MinY(), ext.MaxX(), ext.MaxY()
+		if w := maxx - minx; w < minSize {
+			minx, maxx = minx-(minSize-w)/2, maxx+(minSize-w)/2
+		}
+		if h := maxy - miny; h < minSize {
+			miny, maxy = miny-(minSize-h)/2, maxy+(minSize-h)/2
+		}
+
+		if minx == maxx || miny == maxy {
+			f.Geometry = geom.Point{(minx + maxx) / 2, (miny + maxy) / 2}
+			return fn(f)
+		}
+		f.Geometry = geom.Polygon{{
+			{minx, miny},
+			{maxx, miny},
+			{maxx, maxy},
+			{minx, maxy},
+		}}
+		return fn(f)
+	})
+}
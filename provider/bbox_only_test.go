@@ -0,0 +1,98 @@
+package provider_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithBBoxOnly(t *testing.T) {
+	// a z20 tile near null island, where a pixel is ~0.15m
+	tile := provider.NewTile(20, 524288, 524287, 0, 3857)
+	// half a meter in pixels
+	halfMeter := 0.5 / tile.Resolution()
+
+	// equal compares the geometries, allowing for the error of the pixel size
+	equal := func(a, b geom.Geometry) bool {
+		pa, ok := a.(geom.Polygon)
+		pb, okb := b.(geom.Polygon)
+		if !ok || !okb || len(pa) != 1 || len(pb) != 1 || len(pa[0]) != len(pb[0]) {
+			return reflect.DeepEqual(a, b)
+		}
+		for i := range pa[0] {
+			if math.Abs(pa[0][i][0]-pb[0][i][0]) > 1e-6 || math.Abs(pa[0][i][1]-pb[0][i][1]) > 1e-6 {
+				return false
+			}
+		}
+		return true
+	}
+
+	type tcase struct {
+		minSize  float64
+		feature  provider.Feature
+		expected geom.Geometry
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		defer func(size float64) { provider.BBoxMinSize = size }(provider.BBoxMinSize)
+		provider.BBoxMinSize = tc.minSize
+
+		tags := map[string]interface{}{"name": "a"}
+		tc.feature.Tags = tags
+		got, err := collect(provider.WithBBoxOnly(featuresTiler{
+			features: []provider.Feature{tc.feature},
+		}), "", tile)
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		if len(got) != 1 {
+			t.Fatalf("features, expected 1 got %v", len(got))
+		}
+		if !equal(got[0].Geometry, tc.expected) {
+			t.Errorf("geometry, expected %v got %v", tc.expected, got[0].Geometry)
+		}
+		if !reflect.DeepEqual(got[0].Tags, tags) {
+			t.Errorf("tags, expected %v got %v", tags, got[0].Tags)
+		}
+	}
+
+	tests := map[string]tcase{
+		"line": {
+			minSize:  1,
+			feature:  provider.Feature{Geometry: geom.LineString{{1, 4}, {3, 2}, {2, 3}}},
+			expected: geom.Polygon{{{1, 2}, {3, 2}, {3, 4}, {1, 4}}},
+		},
+		"polygon": {
+			minSize:  1,
+			feature:  provider.Feature{Geometry: geom.Polygon{{{0, 0}, {4, 1}, {2, 5}}, {{1, 1}, {2, 1}, {2, 2}}}},
+			expected: geom.Polygon{{{0, 0}, {4, 0}, {4, 5}, {0, 5}}},
+		},
+		"point": {
+			minSize:  halfMeter,
+			feature:  provider.Feature{Geometry: geom.Point{1, 1}},
+			expected: geom.Polygon{{{0.75, 0.75}, {1.25, 0.75}, {1.25, 1.25}, {0.75, 1.25}}},
+		},
+		"vertical line": {
+			minSize:  halfMeter,
+			feature:  provider.Feature{Geometry: geom.LineString{{1, 0}, {1, 2}}},
+			expected: geom.Polygon{{{0.75, 0}, {1.25, 0}, {1.25, 2}, {0.75, 2}}},
+		},
+		"point without a minimum size": {
+			feature:  provider.Feature{Geometry: geom.MultiPoint{{1, 1}, {1, 3}}},
+			expected: geom.Point{1, 2},
+		},
+		"empty geometry": {
+			minSize:  1,
+			feature:  provider.Feature{Geometry: geom.MultiPoint{}},
+			expected: geom.MultiPoint{},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
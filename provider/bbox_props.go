@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-spatial/geom"
+)
+
+// WithBBoxProps wraps the Tiler so every feature carries the envelope of its
+// geometry as the properties minx, miny, maxx and maxy, each prefixed with
+// keyPrefix, so clients can cull features without computing their bounding
+// boxes. The coordinates are in the tile's SRID, features not in the tile's
+// SRID are reprojected first. Features with a nil or empty geometry are
+// passed to the callback without the properties.
+func WithBBoxProps(t Tiler, keyPrefix string) Tiler {
+	return &bboxPropsTiler{
+		Tiler:  t,
+		prefix: keyPrefix,
+	}
+}
+
+type bboxPropsTiler struct {
+	Tiler
+	prefix string
+}
+
+func (bt *bboxPropsTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	_, tileSRID := t.Extent()
+	return bt.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		if f.Geometry == nil {
+			return fn(f)
+		}
+
+		if err := reprojectFeatureInPlace(f, tileSRID); err != nil {
+			return err
+		}
+
+		ext, err := geom.NewExtentFromGeometry(f.Geometry)
+		if err != nil {
+			return fmt.Errorf("unable to compute extent of feature %v in layer (%v): %w", f.ID, layer, err)
+		}
+		if ext == nil {
+			// empty geometry
+			return fn(f)
+		}
+
+		if f.Tags == nil {
+			f.Tags = make(map[string]interface{}, 4)
+		}
+		f.Tags[bt.prefix+"minx"] = ext.MinX()
+		f.Tags[bt.prefix+"miny"] = ext.MinY()
+		f.Tags[bt.prefix+"maxx"] = ext.MaxX()
+		f.Tags[bt.prefix+"maxy"] = ext.MaxY()
+		return fn(f)
+	})
+}
@@ -0,0 +1,40 @@
+package provider_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithBBoxProps(t *testing.T) {
+	tiler := featuresTiler{
+		features: []provider.Feature{
+			{ID: 1, Geometry: geom.LineString{{1, 4}, {3, 2}}, Tags: map[string]interface{}{"name": "a"}},
+			{ID: 2, Geometry: geom.Point{5, 6}},
+			{ID: 3},
+			{ID: 4, Geometry: geom.MultiPoint{}},
+		},
+	}
+
+	features, err := collect(provider.WithBBoxProps(tiler, "bbox_"), "", provider.NewTile(0, 0, 0, 0, 3857))
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+
+	expected := []map[string]interface{}{
+		{"name": "a", "bbox_minx": 1.0, "bbox_miny": 2.0, "bbox_maxx": 3.0, "bbox_maxy": 4.0},
+		{"bbox_minx": 5.0, "bbox_miny": 6.0, "bbox_maxx": 5.0, "bbox_maxy": 6.0},
+		nil,
+		nil,
+	}
+	if len(features) != len(expected) {
+		t.Fatalf("features, expected %v got %v", len(expected), len(features))
+	}
+	for i := range features {
+		if !reflect.DeepEqual(features[i].Tags, expected[i]) {
+			t.Errorf("feature %v tags, expected %v got %v", features[i].ID, expected[i], features[i].Tags)
+		}
+	}
+}
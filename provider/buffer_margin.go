@@ -0,0 +1,14 @@
+package provider
+
+// BufferMargin returns how far the tile's buffer expands its extent on each
+// side, along the x and y axes, in the units of the tile's SRID. It is the
+// difference between the tile's BufferedExtent and Extent, i.e. the distance
+// past the tile's edge features are included for clipping.
+func BufferMargin(t Tile) (dx, dy float64) {
+	ext, _ := t.Extent()
+	bext, _ := t.BufferedExtent()
+	if ext == nil || bext == nil {
+		return 0, 0
+	}
+	return (bext.XSpan() - ext.XSpan()) / 2, (bext.YSpan() - ext.YSpan()) / 2
+}
@@ -0,0 +1,44 @@
+package provider_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom/slippy"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestBufferMargin(t *testing.T) {
+	type tcase struct {
+		tile     provider.Tile
+		expected float64
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		dx, dy := provider.BufferMargin(tc.tile)
+		if math.Abs(dx-tc.expected) > 1e-6 || math.Abs(dy-tc.expected) > 1e-6 {
+			t.Errorf("margin, expected (%v, %v) got (%v, %v)", tc.expected, tc.expected, dx, dy)
+		}
+	}
+
+	tests := map[string]tcase{
+		"no buffer": {
+			tile:     provider.NewTile(10, 512, 512, 0, 3857),
+			expected: 0,
+		},
+		"64px z0": {
+			tile: provider.NewTile(0, 0, 0, 64, 3857),
+			// the z0 tile is 2*WebMercatorMax wide over 4096 units
+			expected: 2 * slippy.WebMercatorMax / 4096 * 64,
+		},
+		"64px z10": {
+			tile:     provider.NewTile(10, 512, 512, 64, 3857),
+			expected: 2 * slippy.WebMercatorMax / 1024 / 4096 * 64,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
@@ -0,0 +1,26 @@
+package provider
+
+// Decorator wraps a Tiler, adding behavior to it. Decorators which take
+// options can be adapted with a closure, i.e.
+//
+//	func(t Tiler) Tiler { return WithSlowQueryLog(t, time.Second) }
+type Decorator = func(Tiler) Tiler
+
+// Chain wraps t with the decorators. The first decorator is the outermost: it
+// sees each TileFeatures call first and each feature last, while the last
+// decorator is applied directly to t. So
+//
+//	Chain(t, a, b, c)
+//
+// is equivalent to a(b(c(t))), and reads in the order a request passes
+// through the decorators on its way to the provider. Nil decorators are
+// skipped.
+func Chain(t Tiler, decorators ...Decorator) Tiler {
+	for i := len(decorators) - 1; i >= 0; i-- {
+		if decorators[i] == nil {
+			continue
+		}
+		t = decorators[i](t)
+	}
+	return t
+}
@@ -0,0 +1,63 @@
+package provider_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/tegola/provider"
+)
+
+// orderTiler records the order TileFeatures calls pass through it
+type orderTiler struct {
+	provider.Tiler
+	name  string
+	calls *[]string
+}
+
+func (ot orderTiler) TileFeatures(ctx context.Context, layer string, t provider.Tile, fn func(f *provider.Feature) error) error {
+	*ot.calls = append(*ot.calls, ot.name)
+	return ot.Tiler.TileFeatures(ctx, layer, t, fn)
+}
+
+func TestChain(t *testing.T) {
+	var calls []string
+	named := func(name string) provider.Decorator {
+		return func(t provider.Tiler) provider.Tiler {
+			return orderTiler{Tiler: t, name: name, calls: &calls}
+		}
+	}
+
+	type tcase struct {
+		decorators []provider.Decorator
+		expected   []string
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		calls = nil
+		tiler := provider.Chain(featuresTiler{}, tc.decorators...)
+		if _, err := collect(tiler, "roads", provider.NewTile(0, 0, 0, 0, 3857)); err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		if !reflect.DeepEqual(calls, tc.expected) {
+			t.Errorf("order, expected %v got %v", tc.expected, calls)
+		}
+	}
+
+	tests := map[string]tcase{
+		"none": {},
+		"outermost first": {
+			decorators: []provider.Decorator{named("a"), named("b"), named("c")},
+			expected:   []string{"a", "b", "c"},
+		},
+		"nil skipped": {
+			decorators: []provider.Decorator{named("a"), nil, named("c")},
+			expected:   []string{"a", "c"},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
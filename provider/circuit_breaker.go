@@ -0,0 +1,194 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-spatial/tegola/internal/log"
+)
+
+const (
+	// DefaultBreakerFailureThreshold is used when BreakerOpts.FailureThreshold is not set
+	DefaultBreakerFailureThreshold = 5
+	// DefaultBreakerCooldown is used when BreakerOpts.Cooldown is not set
+	DefaultBreakerCooldown = 30 * time.Second
+)
+
+// BreakerOpts configures a circuit breaker created by WithCircuitBreaker
+type BreakerOpts struct {
+	// FailureThreshold is the number of consecutive failures
+	// that open the circuit. Defaults to DefaultBreakerFailureThreshold
+	FailureThreshold int
+	// Cooldown is how long the circuit stays open before a trial
+	// request is let through. Defaults to DefaultBreakerCooldown
+	Cooldown time.Duration
+	// IsFailure reports if an error returned by the provider should count
+	// as a failure. If nil, all errors count except context cancellations.
+	// ErrNoFeatures and errors returned by the callback never count.
+	IsFailure func(error) bool
+	// Now returns the current time. Defaults to time.Now
+	Now func() time.Time
+}
+
+type breakerState uint8
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerClosed:
+		return "closed"
+	case breakerOpen:
+		return "open"
+	case breakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// WithCircuitBreaker wraps the Tiler with a circuit breaker. The circuit opens
+// after opts.FailureThreshold consecutive failures, while open TileFeatures
+// fails fast with ErrCircuitOpen. After opts.Cooldown the circuit half-opens
+// and a single trial request is passed to the provider, if it succeeds the
+// circuit closes, if it fails the circuit opens again for another cooldown.
+//
+// Only a request the provider completed, returning nil or ErrNoFeatures,
+// counts as a success. A request which is neither a success nor a failure,
+// as it was cancelled or the callback returned an error, leaves the circuit
+// as it was, so after a cancelled trial another trial is let through.
+func WithCircuitBreaker(t Tiler, opts BreakerOpts) Tiler {
+	if opts.FailureThreshold <= 0 {
+		opts.FailureThreshold = DefaultBreakerFailureThreshold
+	}
+	if opts.Cooldown <= 0 {
+		opts.Cooldown = DefaultBreakerCooldown
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &breakerTiler{
+		Tiler: t,
+		opts:  opts,
+	}
+}
+
+type breakerTiler struct {
+	Tiler
+	opts BreakerOpts
+
+	lock     sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+	// trial is set while the trial request of a half-open circuit is in flight
+	trial bool
+}
+
+// allow reports if a request can be made, transitioning an open circuit
+// to half-open once the cooldown has passed
+func (bt *breakerTiler) allow() bool {
+	bt.lock.Lock()
+	defer bt.lock.Unlock()
+
+	switch bt.state {
+	case breakerOpen:
+		if bt.opts.Now().Sub(bt.openedAt) < bt.opts.Cooldown {
+			return false
+		}
+		bt.setState(breakerHalfOpen)
+		bt.trial = true
+		return true
+	case breakerHalfOpen:
+		if bt.trial {
+			// a trial request is in flight
+			return false
+		}
+		bt.trial = true
+		return true
+	default:
+		return true
+	}
+}
+
+// report records the outcome of a request
+func (bt *breakerTiler) report(failed bool) {
+	bt.lock.Lock()
+	defer bt.lock.Unlock()
+
+	bt.trial = false
+	if !failed {
+		bt.failures = 0
+		bt.setState(breakerClosed)
+		return
+	}
+
+	bt.failures++
+	if bt.state == breakerHalfOpen || bt.failures >= bt.opts.FailureThreshold {
+		bt.openedAt = bt.opts.Now()
+		bt.setState(breakerOpen)
+	}
+}
+
+// release records a request which neither succeeded nor failed, letting
+// another trial through if the circuit is half-open
+func (bt *breakerTiler) release() {
+	bt.lock.Lock()
+	defer bt.lock.Unlock()
+
+	bt.trial = false
+}
+
+// setState must be called with the lock held
+func (bt *breakerTiler) setState(s breakerState) {
+	if bt.state == s {
+		return
+	}
+	log.Infof("circuit breaker %v -> %v", bt.state, s)
+	bt.state = s
+}
+
+// isSuccess reports if the provider completed the request
+func isSuccess(err error) bool {
+	return err == nil || errors.Is(err, ErrNoFeatures)
+}
+
+func (bt *breakerTiler) isFailure(err error) bool {
+	if isSuccess(err) {
+		return false
+	}
+	if bt.opts.IsFailure != nil {
+		return bt.opts.IsFailure(err)
+	}
+	return !errors.Is(err, context.Canceled)
+}
+
+func (bt *breakerTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	if !bt.allow() {
+		return ErrCircuitOpen
+	}
+
+	var fnErr error
+	err := bt.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		fnErr = fn(f)
+		return fnErr
+	})
+
+	switch {
+	case fnErr != nil:
+		bt.release()
+	case isSuccess(err):
+		bt.report(false)
+	case bt.isFailure(err):
+		bt.report(true)
+	default:
+		bt.release()
+	}
+	return err
+}
@@ -0,0 +1,78 @@
+package provider_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-spatial/tegola/provider"
+)
+
+// switchTiler returns err from TileFeatures, calling fn with a feature if
+// err is nil
+type switchTiler struct {
+	featuresTiler
+	calls int
+	err   error
+}
+
+func (st *switchTiler) TileFeatures(ctx context.Context, layer string, t provider.Tile, fn func(f *provider.Feature) error) error {
+	st.calls++
+	if st.err != nil {
+		return st.err
+	}
+	return fn(&provider.Feature{ID: 1})
+}
+
+func TestWithCircuitBreaker(t *testing.T) {
+	errDown := errors.New("database down")
+	errFn := errors.New("client gone")
+	now := time.Now()
+	base := &switchTiler{err: errDown}
+	tiler := provider.WithCircuitBreaker(base, provider.BreakerOpts{
+		FailureThreshold: 2,
+		Cooldown:         time.Minute,
+		Now:              func() time.Time { return now },
+	})
+	tile := provider.NewTile(0, 0, 0, 0, 3857)
+
+	steps := []struct {
+		desc        string
+		advance     time.Duration
+		baseErr     error
+		fnErr       error
+		expectedErr error
+		calls       int
+	}{
+		{desc: "closed, first failure", baseErr: errDown, expectedErr: errDown, calls: 1},
+		{desc: "closed, second failure opens", baseErr: errDown, expectedErr: errDown, calls: 2},
+		{desc: "open, fails fast", baseErr: nil, expectedErr: provider.ErrCircuitOpen, calls: 2},
+		{desc: "open, fails fast before cooldown", advance: 59 * time.Second, baseErr: nil, expectedErr: provider.ErrCircuitOpen, calls: 2},
+		{desc: "half-open, trial fails and re-opens", advance: time.Second, baseErr: errDown, expectedErr: errDown, calls: 3},
+		{desc: "re-opened, fails fast", baseErr: nil, expectedErr: provider.ErrCircuitOpen, calls: 3},
+		{desc: "half-open, trial cancelled", advance: time.Minute, baseErr: context.Canceled, expectedErr: context.Canceled, calls: 4},
+		{desc: "half-open, trial callback fails", baseErr: nil, fnErr: errFn, expectedErr: errFn, calls: 5},
+		{desc: "half-open, trial succeeds and closes", baseErr: nil, calls: 6},
+		{desc: "closed", baseErr: nil, calls: 7},
+		{desc: "closed, failure below threshold", baseErr: errDown, expectedErr: errDown, calls: 8},
+		{desc: "closed, cancellation does not reset", baseErr: context.Canceled, expectedErr: context.Canceled, calls: 9},
+		{desc: "closed, second failure opens again", baseErr: errDown, expectedErr: errDown, calls: 10},
+		{desc: "open, fails fast again", baseErr: nil, expectedErr: provider.ErrCircuitOpen, calls: 10},
+	}
+
+	for _, step := range steps {
+		now = now.Add(step.advance)
+		base.err = step.baseErr
+
+		err := tiler.TileFeatures(context.Background(), "", tile, func(f *provider.Feature) error {
+			return step.fnErr
+		})
+		if err != step.expectedErr {
+			t.Fatalf("%v: error, expected %v got %v", step.desc, step.expectedErr, err)
+		}
+		if base.calls != step.calls {
+			t.Fatalf("%v: calls, expected %v got %v", step.desc, step.calls, base.calls)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+type clientIDKey struct{}
+
+// WithClientID returns a copy of ctx carrying the ID of the client making
+// the request, i.e. a tenant or API key, read by WithPerClientRateLimit
+func WithClientID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, clientIDKey{}, id)
+}
+
+// ClientIDFromContext returns the client ID set by WithClientID, if any
+func ClientIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(clientIDKey{}).(string)
+	return id, ok
+}
+
+// rateLimitSweepInterval is how often the buckets of idle clients are
+// discarded
+const rateLimitSweepInterval = time.Minute
+
+// WithPerClientRateLimit wraps the Tiler so each client, identified by the ID
+// set on the context with WithClientID, has its own token bucket limiting
+// its calls to TileFeatures, so a client can not use the provider at the
+// expense of the others. Calls exceeding the client's limit fail fast with
+// ErrRateLimited rather than wait. Each call is one request, so a tile of n
+// layers is n requests.
+//
+// limit returns the rate, in requests per second, and the burst of the
+// client's bucket when the client is first seen, so unknown clients get
+// whatever default limit returns for them. Requests without a client ID are
+// limited as the client "". A rate <= 0 does not limit the client, and a
+// burst < 1 is treated as 1.
+//
+// Every minute the buckets which have refilled, as the client has been idle,
+// are discarded, bounding memory to the clients seen recently. A discarded
+// bucket is indistinguishable from a new one, so discarding it does not
+// change the client's limit.
+func WithPerClientRateLimit(t Tiler, limit func(clientID string) (rps float64, burst int)) Tiler {
+	return &clientRateLimitTiler{
+		Tiler:   t,
+		limit:   limit,
+		now:     time.Now,
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+type clientRateLimitTiler struct {
+	Tiler
+	limit func(clientID string) (rps float64, burst int)
+	// now returns the current time, set by tests
+	now func() time.Time
+
+	lock      sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// tokenBucket holds up to burst tokens, refilled at rps tokens per second
+type tokenBucket struct {
+	rps    float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// refill adds the tokens accrued since the bucket was last refilled
+func (tb *tokenBucket) refill(now time.Time) {
+	tb.tokens = math.Min(tb.burst, tb.tokens+now.Sub(tb.last).Seconds()*tb.rps)
+	tb.last = now
+}
+
+// allow reports if the client may make a request, taking a token from its
+// bucket if so
+func (crl *clientRateLimitTiler) allow(clientID string) bool {
+	crl.lock.Lock()
+	defer crl.lock.Unlock()
+
+	now := crl.now()
+	if now.Sub(crl.lastSweep) >= rateLimitSweepInterval {
+		crl.sweep(now)
+	}
+
+	tb, ok := crl.buckets[clientID]
+	if !ok {
+		rps, burst := crl.limit(clientID)
+		if burst < 1 {
+			burst = 1
+		}
+		tb = &tokenBucket{rps: rps, burst: float64(burst), tokens: float64(burst), last: now}
+		crl.buckets[clientID] = tb
+	}
+	if tb.rps <= 0 {
+		return true
+	}
+
+	tb.refill(now)
+	if tb.tokens < 1 {
+		return false
+	}
+	tb.tokens--
+	return true
+}
+
+// sweep discards the buckets which have refilled, it must be called with the
+// lock held
+func (crl *clientRateLimitTiler) sweep(now time.Time) {
+	for id, tb := range crl.buckets {
+		if tb.rps > 0 {
+			tb.refill(now)
+		}
+		if tb.tokens >= tb.burst {
+			delete(crl.buckets, id)
+		}
+	}
+	crl.lastSweep = now
+}
+
+func (crl *clientRateLimitTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	clientID, _ := ClientIDFromContext(ctx)
+	if !crl.allow(clientID) {
+		return fmt.Errorf("%w: client (%v) layer (%v) tile %v", ErrRateLimited, clientID, layer, TileKey(t))
+	}
+	return crl.Tiler.TileFeatures(ctx, layer, t, fn)
+}
@@ -0,0 +1,37 @@
+package provider
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClientRateLimitSweep(t *testing.T) {
+	now := time.Now()
+	// limit is called each time a client's bucket is created
+	created := make(map[string]int)
+	crl := WithPerClientRateLimit(nil, func(clientID string) (float64, int) {
+		created[clientID]++
+		if clientID == "fast" {
+			return 1, 1
+		}
+		return 0.001, 1
+	}).(*clientRateLimitTiler)
+	crl.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		for _, clientID := range []string{"fast", "slow"} {
+			crl.allow(clientID)
+		}
+		// the fast client's bucket refills and is swept
+		now = now.Add(rateLimitSweepInterval)
+	}
+
+	// the fast client's refilled bucket is discarded between requests, while
+	// the slow client's empty bucket is kept
+	if created["fast"] != 3 {
+		t.Errorf("fast buckets created, expected 3 got %v", created["fast"])
+	}
+	if created["slow"] != 1 {
+		t.Errorf("slow buckets created, expected 1 got %v", created["slow"])
+	}
+}
@@ -0,0 +1,66 @@
+package provider_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithPerClientRateLimit(t *testing.T) {
+	tile := provider.NewTile(0, 0, 0, 0, 3857)
+	request := func(tiler provider.Tiler, clientID string) error {
+		ctx := context.Background()
+		if clientID != "" {
+			ctx = provider.WithClientID(ctx, clientID)
+		}
+		return tiler.TileFeatures(ctx, "roads", tile, func(f *provider.Feature) error { return nil })
+	}
+
+	// a slow refill so the buckets do not refill during the test
+	tiler := provider.WithPerClientRateLimit(featuresTiler{}, func(clientID string) (float64, int) {
+		switch clientID {
+		case "gold":
+			return 0.001, 3
+		case "internal":
+			return 0, 0
+		default:
+			return 0.001, 2
+		}
+	})
+
+	type tcase struct {
+		clientID string
+		allowed  int
+	}
+	// the clients are run in order, sharing the tiler
+	tests := []tcase{
+		{clientID: "a", allowed: 2},
+		{clientID: "b", allowed: 2},
+		{clientID: "gold", allowed: 3},
+		{clientID: "", allowed: 2},
+	}
+	for _, tc := range tests {
+		var allowed int
+		for i := 0; i < 5; i++ {
+			err := request(tiler, tc.clientID)
+			switch {
+			case err == nil:
+				allowed++
+			case !errors.Is(err, provider.ErrRateLimited):
+				t.Fatalf("client (%v) error, expected %v got %v", tc.clientID, provider.ErrRateLimited, err)
+			}
+		}
+		if allowed != tc.allowed {
+			t.Errorf("client (%v) allowed, expected %v got %v", tc.clientID, tc.allowed, allowed)
+		}
+	}
+
+	// a client with no rate is not limited
+	for i := 0; i < 10; i++ {
+		if err := request(tiler, "internal"); err != nil {
+			t.Fatalf("internal client error, expected nil got %v", err)
+		}
+	}
+}
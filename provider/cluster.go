@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/go-spatial/geom"
+)
+
+const (
+	// DefaultClusterGridSize is used when ClusterOpts.GridSize is not set
+	DefaultClusterGridSize = 64
+	// DefaultClusterCountKey is used when ClusterOpts.CountKey is not set
+	DefaultClusterCountKey = "point_count"
+)
+
+// ClusterOpts configures the clustering done by WithClustering
+type ClusterOpts struct {
+	// MaxZoom is the highest zoom at which points are clustered.
+	// Points are passed through unchanged on tiles above it.
+	MaxZoom uint
+	// GridSize is the width of a cluster cell, in pixels of a TileSize tile.
+	// Defaults to DefaultClusterGridSize
+	GridSize float64
+	// CountKey is the property holding the number of points in a cluster.
+	// Defaults to DefaultClusterCountKey
+	CountKey string
+}
+
+// WithClustering wraps the Tiler so that, up to opts.MaxZoom, point features
+// are grouped into clusters using a grid. The size of the grid cells is
+// opts.GridSize pixels, so the cells cover less ground as the zoom increases.
+// The grid is aligned to the tile's SRID origin so points are clustered the
+// same on adjacent tiles.
+//
+// A cluster is a point at the mean of its points, with the ID of its first
+// point and a single property, opts.CountKey, holding the number of points.
+// A cell with a single point passes the original feature through. Clusters
+// are passed to the callback after all other features, as every feature
+// must be read before the clusters are known. Features which are not points
+// are passed through unchanged.
+func WithClustering(t Tiler, opts ClusterOpts) Tiler {
+	if opts.GridSize <= 0 {
+		opts.GridSize = DefaultClusterGridSize
+	}
+	if opts.CountKey == "" {
+		opts.CountKey = DefaultClusterCountKey
+	}
+	return &clusterTiler{
+		Tiler: t,
+		opts:  opts,
+	}
+}
+
+type clusterTiler struct {
+	Tiler
+	opts ClusterOpts
+}
+
+// cluster is the points of a grid cell
+type cluster struct {
+	first  Feature
+	sumX   float64
+	sumY   float64
+	points int
+}
+
+func (ct *clusterTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	z, _, _ := t.ZXY()
+	if z > ct.opts.MaxZoom {
+		return ct.Tiler.TileFeatures(ctx, layer, t, fn)
+	}
+
+	ext, tileSRID := t.Extent()
+	cellSize := ext.XSpan() / TileSize * ct.opts.GridSize
+
+	var (
+		clusters = make(map[[2]int64]*cluster)
+		// cells in the order their first point was seen
+		order []*cluster
+	)
+
+	err := ct.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		if _, ok := f.Geometry.(geom.Point); !ok {
+			return fn(f)
+		}
+
+		if err := reprojectFeatureInPlace(f, tileSRID); err != nil {
+			return err
+		}
+		pt := f.Geometry.(geom.Point)
+
+		cell := [2]int64{
+			int64(math.Floor(pt[0] / cellSize)),
+			int64(math.Floor(pt[1] / cellSize)),
+		}
+		c, ok := clusters[cell]
+		if !ok {
+			c = &cluster{first: *f}
+			clusters[cell] = c
+			order = append(order, c)
+		}
+		c.sumX += pt[0]
+		c.sumY += pt[1]
+		c.points++
+		return nil
+	})
+	if err != nil && !errors.Is(err, ErrNoFeatures) {
+		return err
+	}
+
+	for _, c := range order {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		f := c.first
+		if c.points > 1 {
+			f = Feature{
+				ID:       c.first.ID,
+				Geometry: geom.Point{c.sumX / float64(c.points), c.sumY / float64(c.points)},
+				SRID:     c.first.SRID,
+				Tags:     map[string]interface{}{ct.opts.CountKey: c.points},
+			}
+		}
+		if err := fn(&f); err != nil {
+			return err
+		}
+	}
+
+	// a provider reporting no features had no points to cluster
+	return err
+}
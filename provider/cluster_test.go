@@ -0,0 +1,64 @@
+package provider_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithClustering(t *testing.T) {
+	tiler := featuresTiler{
+		features: []provider.Feature{
+			{ID: 1, SRID: 3857, Geometry: geom.Point{0, 0}},
+			{ID: 2, SRID: 3857, Geometry: geom.Point{100, 200}},
+			{ID: 3, SRID: 3857, Geometry: geom.Point{200, 400}},
+			{ID: 4, SRID: 3857, Geometry: geom.Point{-15000000, 5000000}, Tags: map[string]interface{}{"name": "alone"}},
+			{ID: 5, SRID: 3857, Geometry: geom.LineString{{0, 0}, {10, 10}}},
+		},
+	}
+
+	clustered := []provider.Feature{
+		// non-points are passed through as they are read
+		{ID: 5, SRID: 3857, Geometry: geom.LineString{{0, 0}, {10, 10}}},
+		{ID: 1, SRID: 3857, Geometry: geom.Point{100, 200}, Tags: map[string]interface{}{"point_count": 3}},
+		{ID: 4, SRID: 3857, Geometry: geom.Point{-15000000, 5000000}, Tags: map[string]interface{}{"name": "alone"}},
+	}
+
+	type tcase struct {
+		tile     provider.Tile
+		expected []provider.Feature
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		ct := provider.WithClustering(tiler, provider.ClusterOpts{MaxZoom: 5})
+		features, err := collect(ct, "", tc.tile)
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		if !reflect.DeepEqual(features, tc.expected) {
+			t.Errorf("features, expected %v got %v", tc.expected, features)
+		}
+	}
+
+	tests := map[string]tcase{
+		"z0": {
+			tile:     provider.NewTile(0, 0, 0, 0, 3857),
+			expected: clustered,
+		},
+		"max zoom": {
+			tile:     provider.NewTile(5, 0, 0, 0, 3857),
+			expected: clustered,
+		},
+		"above max zoom": {
+			tile:     provider.NewTile(6, 0, 0, 0, 3857),
+			expected: tiler.features,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
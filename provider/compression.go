@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+)
+
+// Codec compresses encoded tiles. Codecs for encodings not provided here,
+// such as brotli, can be added by implementing the interface.
+type Codec interface {
+	// ContentEncoding is the HTTP Content-Encoding token of the
+	// compressed output, i.e. "gzip", or empty for no compression
+	ContentEncoding() string
+	// Compress returns the compressed data
+	Compress(data []byte) ([]byte, error)
+}
+
+var (
+	// GzipCodec compresses tiles with gzip at the default compression level
+	GzipCodec Codec = gzipCodec{level: gzip.DefaultCompression}
+	// NoCompression returns tiles as is
+	NoCompression Codec = noCompression{}
+)
+
+type gzipCodec struct {
+	level int
+}
+
+func (gzipCodec) ContentEncoding() string { return "gzip" }
+
+func (gc gzipCodec) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, gc.level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = w.Write(data); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+type noCompression struct{}
+
+func (noCompression) ContentEncoding() string              { return "" }
+func (noCompression) Compress(data []byte) ([]byte, error) { return data, nil }
+
+// WithCompression wraps the MVT provider so the tiles returned by
+// MVTForLayers are compressed with the codec, and the codec's encoding is
+// reported through ContentEncoder so the server sets the Content-Encoding
+// header rather than compressing the tile again.
+//
+// A nil codec, NoCompression, or a provider which already reports a content
+// encoding are returned as is.
+func WithCompression(mt MVTTiler, codec Codec) MVTTiler {
+	if codec == nil || codec.ContentEncoding() == "" || ContentEncoding(mt) != "" {
+		return mt
+	}
+	return &compressionTiler{
+		MVTTiler: mt,
+		codec:    codec,
+	}
+}
+
+type compressionTiler struct {
+	MVTTiler
+	codec Codec
+}
+
+func (ct *compressionTiler) ContentEncoding() string { return ct.codec.ContentEncoding() }
+
+func (ct *compressionTiler) MVTForLayers(ctx context.Context, tile Tile, layers []Layer) ([]byte, error) {
+	b, err := ct.MVTTiler.MVTForLayers(ctx, tile, layers)
+	if err != nil {
+		return nil, err
+	}
+	return ct.codec.Compress(b)
+}
@@ -0,0 +1,45 @@
+package provider_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithCompression(t *testing.T) {
+	plain := []byte("mvt tile")
+
+	mt := provider.WithCompression(bytesMVTTiler{b: plain}, provider.GzipCodec)
+	if enc := provider.ContentEncoding(mt); enc != "gzip" {
+		t.Errorf("content encoding, expected gzip got %q", enc)
+	}
+
+	b, err := mt.MVTForLayers(context.Background(), provider.NewTile(0, 0, 0, 0, 3857), nil)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	r, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("gzip reader, expected nil got %v", err)
+	}
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("decompress, expected nil got %v", err)
+	}
+	if !bytes.Equal(got, plain) {
+		t.Errorf("tile, expected %q got %q", plain, got)
+	}
+
+	// already compressed tiles are not compressed again
+	if again := provider.WithCompression(mt, provider.GzipCodec); again != mt {
+		t.Errorf("compressed provider, expected to be returned as is")
+	}
+	// no compression leaves the provider as is
+	if enc := provider.ContentEncoding(provider.WithCompression(bytesMVTTiler{b: plain}, provider.NoCompression)); enc != "" {
+		t.Errorf("no compression content encoding, expected empty got %q", enc)
+	}
+}
@@ -0,0 +1,37 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/go-spatial/tegola/dict"
+)
+
+// ConfigHookFunc is given the name of the provider type and its config map,
+// and returns the config map to initialize the provider with.
+type ConfigHookFunc func(name string, d dict.Dicter) (dict.Dicter, error)
+
+var configHooks []ConfigHookFunc
+
+// RegisterConfigHook registers a hook which is run on the config map of every
+// provider before it is initialized by For or MVTFor. This allows values
+// such as secrets or service endpoints to be injected into the config of any
+// provider. Hooks are run in the order they are registered, each given the
+// config returned by the previous hook. An error from a hook aborts the
+// provider's initialization.
+//
+// Hooks should be registered before any providers are initialized, generally
+// in an init function.
+func RegisterConfigHook(fn ConfigHookFunc) {
+	configHooks = append(configHooks, fn)
+}
+
+// runConfigHooks runs the registered hooks on the config
+func runConfigHooks(name string, config dict.Dicter) (dict.Dicter, error) {
+	for _, hook := range configHooks {
+		var err error
+		if config, err = hook(name, config); err != nil {
+			return nil, fmt.Errorf("config hook for provider %v: %w", name, err)
+		}
+	}
+	return config, nil
+}
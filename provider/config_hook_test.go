@@ -0,0 +1,57 @@
+package provider_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-spatial/tegola/dict"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestRegisterConfigHook(t *testing.T) {
+	var got string
+	err := provider.Register("hook_test", func(d dict.Dicter) (provider.Tiler, error) {
+		var err error
+		got, err = d.String("host", nil)
+		return featuresTiler{}, err
+	}, nil)
+	if err != nil {
+		t.Fatalf("register, expected nil got %v", err)
+	}
+
+	errHook := errors.New("discovery failed")
+	appendHost := func(suffix string) provider.ConfigHookFunc {
+		return func(name string, d dict.Dicter) (dict.Dicter, error) {
+			// only modify the config of this test's provider
+			if name != "hook_test" {
+				return d, nil
+			}
+			host, err := d.String("host", nil)
+			if err != nil {
+				return nil, err
+			}
+			if host == "fail" {
+				return nil, errHook
+			}
+			return dict.Dict{"host": host + suffix}, nil
+		}
+	}
+	provider.RegisterConfigHook(appendHost(".internal"))
+	provider.RegisterConfigHook(appendHost(":5432"))
+
+	if _, err = provider.For("hook_test", dict.Dict{"host": "db"}); err != nil {
+		t.Fatalf("for, expected nil got %v", err)
+	}
+	// hooks run in the order registered
+	if got != "db.internal:5432" {
+		t.Errorf("host, expected db.internal:5432 got %v", got)
+	}
+
+	got = ""
+	if _, err = provider.For("hook_test", dict.Dict{"host": "fail"}); !errors.Is(err, errHook) {
+		t.Errorf("hook error, expected %v got %v", errHook, err)
+	}
+	if got != "" {
+		t.Errorf("init called, expected not to be called after a hook error")
+	}
+}
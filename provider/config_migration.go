@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/go-spatial/tegola/dict"
+)
+
+// ConfigKeySchemaVersion is the provider config key holding the version of
+// the config's schema. Configs without it are version 1.
+const ConfigKeySchemaVersion = "schema_version"
+
+// configMigration upgrades a config from one schema version to another
+type configMigration struct {
+	to int
+	fn func(dict.Dicter) (dict.Dicter, error)
+}
+
+var (
+	configMigrationsLock sync.RWMutex
+	// configMigrations is keyed by provider type, then by the version
+	// migrated from
+	configMigrations = make(map[string]map[int]configMigration)
+)
+
+// RegisterConfigMigration registers fn to upgrade the config of the provider
+// type name from schema version from to version to. The provider's current
+// schema version is the highest to registered. Before For or MVTFor
+// initializes a provider its config's schema_version is read, defaulting to
+// 1, and the migrations are chained until the config is at the current
+// version, so deployments keep working as a provider's config evolves.
+//
+// An ErrConfigSchemaVersion is returned by For when the config's version is
+// newer than the current version, or there is no migration from it.
+// Providers without migrations ignore schema_version.
+//
+// Migrations should be registered alongside the provider, generally in an
+// init function. RegisterConfigMigration panics if to is not greater than
+// from, or a migration from the version is already registered.
+func RegisterConfigMigration(name string, from, to int, fn func(dict.Dicter) (dict.Dicter, error)) {
+	if to <= from {
+		panic(fmt.Sprintf("provider %v config migration from version %v to %v must increase the version", name, from, to))
+	}
+
+	configMigrationsLock.Lock()
+	defer configMigrationsLock.Unlock()
+
+	if configMigrations[name] == nil {
+		configMigrations[name] = make(map[int]configMigration)
+	}
+	if _, ok := configMigrations[name][from]; ok {
+		panic(fmt.Sprintf("provider %v config migration from version %v already registered", name, from))
+	}
+	configMigrations[name][from] = configMigration{to: to, fn: fn}
+}
+
+// migrateConfig upgrades the config to the current schema version of the
+// provider type
+func migrateConfig(name string, config dict.Dicter) (dict.Dicter, error) {
+	// copied under the lock, as migrations may be registered concurrently
+	configMigrationsLock.RLock()
+	migrations := make(map[int]configMigration, len(configMigrations[name]))
+	for from, m := range configMigrations[name] {
+		migrations[from] = m
+	}
+	configMigrationsLock.RUnlock()
+	if len(migrations) == 0 {
+		return config, nil
+	}
+
+	current := 1
+	for _, m := range migrations {
+		if m.to > current {
+			current = m.to
+		}
+	}
+
+	def := 1
+	version, err := config.Int(ConfigKeySchemaVersion, &def)
+	if err != nil {
+		return nil, err
+	}
+	if version > current {
+		return nil, ErrConfigSchemaVersion{Name: name, Version: version, Current: current}
+	}
+
+	for version < current {
+		m, ok := migrations[version]
+		if !ok {
+			return nil, ErrConfigSchemaVersion{Name: name, Version: version, Current: current}
+		}
+		if config, err = m.fn(config); err != nil {
+			return nil, fmt.Errorf("provider %v config migration from version %v to %v: %w", name, version, m.to, err)
+		}
+		version = m.to
+	}
+	return config, nil
+}
@@ -0,0 +1,129 @@
+package provider_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/go-spatial/tegola/dict"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestRegisterConfigMigration(t *testing.T) {
+	var got dict.Dicter
+	err := provider.Register("migration_test", func(d dict.Dicter) (provider.Tiler, error) {
+		got = d
+		return featuresTiler{}, nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("register, expected nil got %v", err)
+	}
+
+	// version 2 renamed host to hostname, version 3 added a port
+	provider.RegisterConfigMigration("migration_test", 1, 2, func(d dict.Dicter) (dict.Dicter, error) {
+		host, err := d.String("host", nil)
+		if err != nil {
+			return nil, err
+		}
+		return dict.Dict{"hostname": host}, nil
+	})
+	provider.RegisterConfigMigration("migration_test", 2, 3, func(d dict.Dicter) (dict.Dicter, error) {
+		host, err := d.String("hostname", nil)
+		if err != nil {
+			return nil, err
+		}
+		return dict.Dict{"hostname": host, "port": 5432}, nil
+	})
+
+	type tcase struct {
+		config   dict.Dict
+		expected dict.Dict
+		err      error
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		got = nil
+		_, err := provider.For("migration_test", tc.config)
+		if tc.err != nil {
+			if !errors.Is(err, tc.err) {
+				t.Errorf("error, expected %v got %v", tc.err, err)
+			}
+			if got != nil {
+				t.Errorf("init called, expected not to be called after a migration error")
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+
+		for k, v := range tc.expected {
+			if val, _ := got.(dict.Dict)[k]; val != v {
+				t.Errorf("config %v, expected %v got %v", k, v, val)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"unversioned": {
+			config:   dict.Dict{"host": "db"},
+			expected: dict.Dict{"hostname": "db", "port": 5432},
+		},
+		"version 2": {
+			config:   dict.Dict{"schema_version": 2, "hostname": "db"},
+			expected: dict.Dict{"hostname": "db", "port": 5432},
+		},
+		"current": {
+			config:   dict.Dict{"schema_version": 3, "hostname": "db", "port": 6432},
+			expected: dict.Dict{"hostname": "db", "port": 6432},
+		},
+		"too new": {
+			config: dict.Dict{"schema_version": 4},
+			err:    provider.ErrConfigSchemaVersion{Name: "migration_test", Version: 4, Current: 3},
+		},
+		"unknown": {
+			config: dict.Dict{"schema_version": 0},
+			err:    provider.ErrConfigSchemaVersion{Name: "migration_test", Version: 0, Current: 3},
+		},
+		"migration error": {
+			config: dict.Dict{"schema_version": 1},
+			err:    dict.ErrKeyRequired("host"),
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
+
+func TestRegisterConfigMigrationConcurrent(t *testing.T) {
+	err := provider.Register("migration_concurrent_test", func(d dict.Dicter) (provider.Tiler, error) {
+		return featuresTiler{}, nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("register, expected nil got %v", err)
+	}
+	provider.RegisterConfigMigration("migration_concurrent_test", 1, 2, func(d dict.Dicter) (dict.Dicter, error) {
+		return d, nil
+	})
+
+	// migrations from versions below 1 are never used, but are registered
+	// while providers are initialized
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for from := -1; from > -100; from-- {
+			provider.RegisterConfigMigration("migration_concurrent_test", from, 0, func(d dict.Dicter) (dict.Dicter, error) {
+				return d, nil
+			})
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		if _, err := provider.For("migration_concurrent_test", dict.Dict{}); err != nil {
+			t.Fatalf("for, expected nil got %v", err)
+		}
+	}
+	wg.Wait()
+}
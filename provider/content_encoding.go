@@ -0,0 +1,21 @@
+package provider
+
+// ContentEncoder is implemented by MVT providers which return already
+// encoded tiles from MVTForLayers, i.e. a proxy passing through gzipped
+// tiles from an upstream server. ContentEncoding reports the encoding of
+// the returned bytes, using the HTTP Content-Encoding token such as "gzip",
+// or an empty string for plain bytes.
+type ContentEncoder interface {
+	ContentEncoding() string
+}
+
+// ContentEncoding returns the encoding of the tiles returned by the MVT
+// provider. Providers which do not implement ContentEncoder are assumed to
+// return plain bytes and an empty string is returned.
+func ContentEncoding(t MVTTiler) string {
+	ce, ok := t.(ContentEncoder)
+	if !ok {
+		return ""
+	}
+	return ce.ContentEncoding()
+}
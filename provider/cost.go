@@ -0,0 +1,27 @@
+package provider
+
+import "context"
+
+// CostEstimator is implemented by providers which are able to estimate the
+// cost of TileFeatures for a layer and tile without running the query. This
+// allows callers, i.e. a seeding scheduler, to order or throttle work so
+// cheap tiles are not stuck behind expensive ones.
+//
+// The cost is relative rather than absolute: estimates are only comparable
+// between tiles and layers of the same provider. A higher cost means the
+// query is expected to take longer.
+type CostEstimator interface {
+	EstimateCost(ctx context.Context, layer string, t Tile) (float64, error)
+}
+
+// EstimateCost returns the provider's cost estimate for TileFeatures of the
+// layer and tile, if the Tiler implements CostEstimator. Otherwise
+// ErrUnsupported is returned.
+func EstimateCost(ctx context.Context, t Tiler, layer string, tile Tile) (float64, error) {
+	ce, release, ok := acquireAs[CostEstimator](t)
+	if !ok {
+		return 0, ErrUnsupported
+	}
+	defer release()
+	return ce.EstimateCost(ctx, layer, tile)
+}
@@ -0,0 +1,109 @@
+package provider
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/wkt"
+)
+
+// csvRow is a feature with its geometry encoded as WKT
+type csvRow struct {
+	id   uint64
+	wkt  string
+	tags map[string]interface{}
+}
+
+// EncodeCSV encodes the features of the layer for the tile as CSV and writes
+// it to w, for spreadsheet users. The first row is the header: "id", the
+// feature's ID, and "geometry", the feature's WGS84 geometry as WKT, followed
+// by a column for each property, ordered by name. A feature is written as a
+// row with an empty cell for each property it does not have, so features
+// with heterogeneous properties share the columns.
+//
+// The header must name the properties of every feature, so unlike
+// EncodeGeoJSONL, which streams, the features of the tile are buffered and
+// written once TileFeatures has returned. Querying the tile twice would not
+// buffer, but doubles the load on the provider, and the features of a tile
+// are bounded, as is the memory buffering them uses. Nothing is written if
+// TileFeatures returns an error.
+//
+// Strings are written as is, numbers and booleans as by fmt.Print, and other
+// values, i.e. maps or slices, as JSON. null values and null geometries are
+// written as empty cells. Properties named "id" or "geometry" are dropped.
+func EncodeCSV(ctx context.Context, t Tiler, layer string, tile Tile, w io.Writer) error {
+	var (
+		rows []csvRow
+		keys = make(map[string]struct{})
+	)
+	err := t.TileFeatures(ctx, layer, tile, func(f *Feature) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		g, err := f.wgs84Geometry()
+		if err != nil {
+			return err
+		}
+		row := csvRow{id: f.ID, tags: f.Tags}
+		if g != nil && !geom.IsEmpty(g) {
+			if row.wkt, err = wkt.Encode(g); err != nil {
+				return fmt.Errorf("feature %v: %w", f.ID, err)
+			}
+		}
+		for k := range f.Tags {
+			keys[k] = struct{}{}
+		}
+		rows = append(rows, row)
+		return nil
+	})
+	if err != nil && !errors.Is(err, ErrNoFeatures) {
+		return err
+	}
+
+	delete(keys, "id")
+	delete(keys, "geometry")
+	names := make([]string, 0, len(keys))
+	for k := range keys {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	cw := csv.NewWriter(w)
+	record := append([]string{"id", "geometry"}, names...)
+	if err := cw.Write(record); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record[0] = strconv.FormatUint(row.id, 10)
+		record[1] = row.wkt
+		for i, k := range names {
+			record[i+2] = csvValue(row.tags[k])
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvValue returns the value as a cell
+func csvValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(v)
+	default:
+		return stringifyValue(v)
+	}
+}
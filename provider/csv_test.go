@@ -0,0 +1,43 @@
+package provider_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestEncodeCSV(t *testing.T) {
+	tiler := featuresTiler{
+		features: []provider.Feature{
+			{ID: 1, Geometry: geom.Point{1, 2}, SRID: 4326, Tags: map[string]interface{}{"name": "Main St, North", "lanes": 2}},
+			{ID: 2, Geometry: geom.LineString{{0, 0}, {1, 1}}, SRID: 4326, Tags: map[string]interface{}{"oneway": true, "ref": []interface{}{"A1", "E5"}}},
+			{ID: 3, Tags: map[string]interface{}{"name": `"Quoted"`, "id": 7}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := provider.EncodeCSV(context.Background(), tiler, "roads", provider.NewTile(0, 0, 0, 0, 3857), &buf); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+
+	expected := `id,geometry,lanes,name,oneway,ref
+1,POINT (1 2),2,"Main St, North",,
+2,"LINESTRING (0 0,1 1)",,,true,"[""A1"",""E5""]"
+3,,,"""Quoted""",,
+`
+	if got := buf.String(); got != expected {
+		t.Errorf("csv, expected\n%v\ngot\n%v", expected, got)
+	}
+
+	// an empty tile still has a header
+	buf.Reset()
+	if err := provider.EncodeCSV(context.Background(), featuresTiler{err: provider.ErrNoFeatures}, "roads", provider.NewTile(0, 0, 0, 0, 3857), &buf); err != nil {
+		t.Fatalf("empty error, expected nil got %v", err)
+	}
+	if got := buf.String(); got != "id,geometry\n" {
+		t.Errorf("empty csv, expected %q got %q", "id,geometry\n", got)
+	}
+}
@@ -0,0 +1,26 @@
+package provider
+
+import "context"
+
+// FeatureDebugger is implemented by providers which are able to pass the raw
+// source row of each feature, as read from the data source, along with the
+// feature constructed from it. This makes it possible to tell if a property
+// is wrong in the source query or in how the provider builds the feature.
+//
+// Providers should only support this when debugging is enabled, returning
+// ErrUnsupported otherwise, as keeping the raw rows is not free.
+type FeatureDebugger interface {
+	DebugTileFeatures(ctx context.Context, layer string, t Tile, fn func(raw map[string]interface{}, f *Feature) error) error
+}
+
+// DebugTileFeatures streams the raw source rows and features of the layer
+// for the tile, if the Tiler implements FeatureDebugger. Otherwise
+// ErrUnsupported is returned.
+func DebugTileFeatures(ctx context.Context, t Tiler, layer string, tile Tile, fn func(raw map[string]interface{}, f *Feature) error) error {
+	fd, release, ok := acquireAs[FeatureDebugger](t)
+	if !ok {
+		return ErrUnsupported
+	}
+	defer release()
+	return fd.DebugTileFeatures(ctx, layer, tile, fn)
+}
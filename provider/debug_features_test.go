@@ -0,0 +1,82 @@
+package provider_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-spatial/tegola/provider"
+)
+
+// debugTiler is a featuresTiler which passes the tags of each feature,
+// along with its id, as the raw row
+type debugTiler struct {
+	featuresTiler
+}
+
+func (dt debugTiler) DebugTileFeatures(ctx context.Context, layer string, t provider.Tile, fn func(raw map[string]interface{}, f *provider.Feature) error) error {
+	return dt.TileFeatures(ctx, layer, t, func(f *provider.Feature) error {
+		raw := map[string]interface{}{"gid": int64(f.ID)}
+		for k, v := range f.Tags {
+			raw[k] = v
+		}
+		return fn(raw, f)
+	})
+}
+
+func TestDebugTileFeatures(t *testing.T) {
+	features := []provider.Feature{
+		{ID: 1, Tags: map[string]interface{}{"name": "a"}},
+		{ID: 2, Tags: map[string]interface{}{"name": "b"}},
+	}
+
+	type tcase struct {
+		tiler       provider.Tiler
+		expectedRaw []map[string]interface{}
+		expectedErr error
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		var raws []map[string]interface{}
+		err := provider.DebugTileFeatures(context.Background(), tc.tiler, "places", provider.NewTile(0, 0, 0, 0, 3857), func(raw map[string]interface{}, f *provider.Feature) error {
+			if raw["gid"] != int64(f.ID) {
+				t.Errorf("raw gid, expected %v got %v", f.ID, raw["gid"])
+			}
+			raws = append(raws, raw)
+			return nil
+		})
+		if !errors.Is(err, tc.expectedErr) {
+			t.Fatalf("error, expected %v got %v", tc.expectedErr, err)
+		}
+		if !reflect.DeepEqual(raws, tc.expectedRaw) {
+			t.Errorf("raw, expected %v got %v", tc.expectedRaw, raws)
+		}
+	}
+
+	expectedRaw := []map[string]interface{}{
+		{"gid": int64(1), "name": "a"},
+		{"gid": int64(2), "name": "b"},
+	}
+
+	tests := map[string]tcase{
+		"unsupported": {
+			tiler:       featuresTiler{features: features},
+			expectedErr: provider.ErrUnsupported,
+		},
+		"debugger": {
+			tiler:       debugTiler{featuresTiler{features: features}},
+			expectedRaw: expectedRaw,
+		},
+		"wrapped debugger": {
+			tiler:       provider.WithQueryTimeouts(debugTiler{featuresTiler{features: features}}, time.Minute, nil),
+			expectedRaw: expectedRaw,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
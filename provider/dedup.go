@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"context"
+	"hash/fnv"
+
+	"github.com/go-spatial/tegola/internal/log"
+)
+
+// DedupMatch determines which features WithDedup considers duplicates
+type DedupMatch uint8
+
+const (
+	// DedupGeometry matches features with the same geometry, regardless of
+	// their properties
+	DedupGeometry DedupMatch = iota
+	// DedupGeometryProps matches features with the same geometry and the
+	// same properties
+	DedupGeometryProps
+)
+
+func (m DedupMatch) String() string {
+	switch m {
+	case DedupGeometry:
+		return "geometry"
+	case DedupGeometryProps:
+		return "geometry+properties"
+	default:
+		return "unknown"
+	}
+}
+
+// WithDedup wraps the Tiler so features whose geometry, and with
+// DedupGeometryProps their properties, hash the same as an earlier feature
+// of the tile are dropped, i.e. features stored more than once by an
+// imperfect import. The first feature seen is kept. The hashes are only kept
+// for the duration of each TileFeatures call, bounding memory to the number
+// of features in a tile. Feature IDs are not compared.
+//
+// Features are compared by a 64 bit hash, so while unlikely, distinct
+// features may be dropped if their hashes collide.
+func WithDedup(t Tiler, match DedupMatch) Tiler {
+	return &dedupTiler{
+		Tiler: t,
+		match: match,
+	}
+}
+
+type dedupTiler struct {
+	Tiler
+	match DedupMatch
+}
+
+func (dt *dedupTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	var (
+		h       = fnv.New64a()
+		keys    []string
+		seen    = make(map[uint64]struct{})
+		dropped int
+	)
+	err := dt.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		h.Reset()
+		hashGeometry(h, f.Geometry)
+		if dt.match == DedupGeometryProps {
+			keys = hashTags(h, f.Tags, keys)
+		}
+
+		sum := h.Sum64()
+		if _, ok := seen[sum]; ok {
+			dropped++
+			return nil
+		}
+		seen[sum] = struct{}{}
+		return fn(f)
+	})
+
+	if dropped > 0 {
+		log.Debugf("layer (%v) tile %v dropped %v duplicate features", layer, TileKey(t), dropped)
+	}
+	return err
+}
@@ -0,0 +1,82 @@
+package provider_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithDedup(t *testing.T) {
+	poly := geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}}
+
+	type tcase struct {
+		features []provider.Feature
+		match    provider.DedupMatch
+		expected []uint64
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		tiler := featuresTiler{features: tc.features}
+
+		var ids []uint64
+		err := provider.WithDedup(tiler, tc.match).TileFeatures(context.Background(), "parcels", provider.NewTile(0, 0, 0, 0, 3857), func(f *provider.Feature) error {
+			ids = append(ids, f.ID)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		if !reflect.DeepEqual(ids, tc.expected) {
+			t.Errorf("features, expected %v got %v", tc.expected, ids)
+		}
+	}
+
+	tests := map[string]tcase{
+		"identical": {
+			features: []provider.Feature{
+				{ID: 1, Geometry: poly, Tags: map[string]interface{}{"use": "park"}},
+				{ID: 2, Geometry: poly, Tags: map[string]interface{}{"use": "park"}},
+			},
+			expected: []uint64{1},
+		},
+		"identical geometry props": {
+			features: []provider.Feature{
+				{ID: 1, Geometry: poly, Tags: map[string]interface{}{"use": "park"}},
+				{ID: 2, Geometry: poly, Tags: map[string]interface{}{"use": "park"}},
+			},
+			match:    provider.DedupGeometryProps,
+			expected: []uint64{1},
+		},
+		"different props": {
+			features: []provider.Feature{
+				{ID: 1, Geometry: poly, Tags: map[string]interface{}{"use": "park"}},
+				{ID: 2, Geometry: poly, Tags: map[string]interface{}{"use": "school"}},
+			},
+			expected: []uint64{1},
+		},
+		"different props geometry props": {
+			features: []provider.Feature{
+				{ID: 1, Geometry: poly, Tags: map[string]interface{}{"use": "park"}},
+				{ID: 2, Geometry: poly, Tags: map[string]interface{}{"use": "school"}},
+			},
+			match:    provider.DedupGeometryProps,
+			expected: []uint64{1, 2},
+		},
+		"different geometry": {
+			features: []provider.Feature{
+				{ID: 1, Geometry: geom.Point{1, 1}},
+				{ID: 2, Geometry: geom.Point{1, 2}},
+				{ID: 3, Geometry: geom.Point{1, 1}},
+			},
+			expected: []uint64{1, 2},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
@@ -0,0 +1,36 @@
+package provider
+
+import "context"
+
+// WithDefaultProps wraps the Tiler so that every feature carries the given
+// properties. Properties already on a feature take precedence, a default is
+// only added when the feature does not have the key.
+func WithDefaultProps(t Tiler, defaults map[string]interface{}) Tiler {
+	if len(defaults) == 0 {
+		return t
+	}
+	return &defaultPropsTiler{
+		Tiler:    t,
+		defaults: defaults,
+	}
+}
+
+type defaultPropsTiler struct {
+	Tiler
+	defaults map[string]interface{}
+}
+
+func (dpt *defaultPropsTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	return dpt.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		if f.Tags == nil {
+			f.Tags = make(map[string]interface{}, len(dpt.defaults))
+		}
+		// existing keys win
+		for k, v := range dpt.defaults {
+			if _, ok := f.Tags[k]; !ok {
+				f.Tags[k] = v
+			}
+		}
+		return fn(f)
+	})
+}
@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola"
+)
+
+// DensityCountKey is the property of a DensityLayer cell holding the number
+// of features in the cell
+const DensityCountKey = "count"
+
+// DensityLayer wraps the Tiler with a synthetic layer, named layer, showing
+// where the features of the tile are. The tile is divided into a grid of
+// gridSize by gridSize cells, see AggSpec.CellPolygon, and the layer has a
+// polygon feature for each cell holding features, tagged with DensityCountKey,
+// the number of features of all of t's layers in the cell. Styling the cells
+// by their count shows the hotspots of a map, which is useful when debugging.
+//
+// A feature is counted in the cell holding the center of its extent. Features
+// in the tile's buffer are counted in the nearest cell, so the counts sum to
+// the number of features of the tile, and features without a geometry are
+// not counted. Features not in the tile's SRID are reprojected first.
+//
+// The other layers are passed through, and the layer is added to those
+// returned by Layers. A gridSize < 1 is treated as 1.
+func DensityLayer(t Tiler, layer string, gridSize int) Tiler {
+	if gridSize < 1 {
+		gridSize = 1
+	}
+	return &densityTiler{
+		Tiler:    t,
+		layer:    layer,
+		gridSize: uint(gridSize),
+	}
+}
+
+type densityTiler struct {
+	Tiler
+	layer    string
+	gridSize uint
+}
+
+// densityLayerInfo is the LayerInfo of the synthetic density layer
+type densityLayerInfo string
+
+func (l densityLayerInfo) Name() string            { return string(l) }
+func (l densityLayerInfo) GeomType() geom.Geometry { return geom.Polygon{} }
+func (l densityLayerInfo) SRID() uint64            { return tegola.WebMercator }
+
+func (dt *densityTiler) Layers() ([]LayerInfo, error) {
+	layers, err := dt.Tiler.Layers()
+	if err != nil {
+		return nil, err
+	}
+	return append(layers, densityLayerInfo(dt.layer)), nil
+}
+
+func (dt *densityTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	if layer != dt.layer {
+		return dt.Tiler.TileFeatures(ctx, layer, t, fn)
+	}
+
+	layers, err := dt.Tiler.Layers()
+	if err != nil {
+		return err
+	}
+
+	var (
+		ext, tileSRID = t.Extent()
+		spec          = AggSpec{Cells: dt.gridSize}
+		cellW, cellH  = ext.XSpan() / float64(dt.gridSize), ext.YSpan() / float64(dt.gridSize)
+		counts        = make([]int, dt.gridSize*dt.gridSize)
+	)
+	for _, l := range layers {
+		err := dt.Tiler.TileFeatures(ctx, l.Name(), t, func(f *Feature) error {
+			if f.Geometry == nil || geom.IsEmpty(f.Geometry) {
+				return nil
+			}
+			g, err := reprojectFeature(f, tileSRID)
+			if err != nil {
+				return err
+			}
+			fext, err := geom.NewExtentFromGeometry(g)
+			if err != nil {
+				return fmt.Errorf("layer (%v) feature %v: %w", l.Name(), f.ID, err)
+			}
+
+			x := densityCell((fext.MinX()+fext.MaxX())/2-ext.MinX(), cellW, dt.gridSize)
+			y := densityCell(ext.MaxY()-(fext.MinY()+fext.MaxY())/2, cellH, dt.gridSize)
+			counts[y*dt.gridSize+x]++
+			return nil
+		})
+		if err != nil && !errors.Is(err, ErrNoFeatures) {
+			return err
+		}
+	}
+
+	for i, n := range counts {
+		if n == 0 {
+			continue
+		}
+		x, y := uint(i)%dt.gridSize, uint(i)/dt.gridSize
+		f := Feature{
+			ID:       uint64(i) + 1,
+			Geometry: spec.CellPolygon(t, x, y),
+			SRID:     tileSRID,
+			Tags:     map[string]interface{}{DensityCountKey: n},
+		}
+		if err := fn(&f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// densityCell returns the cell at the offset, clamped to the grid
+func densityCell(offset, size float64, cells uint) uint {
+	if offset <= 0 {
+		return 0
+	}
+	if c := uint(offset / size); c < cells {
+		return c
+	}
+	return cells - 1
+}
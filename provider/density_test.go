@@ -0,0 +1,108 @@
+package provider_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+// testLayer is a LayerInfo of a point layer in WebMercator
+type testLayer string
+
+func (l testLayer) Name() string            { return string(l) }
+func (l testLayer) GeomType() geom.Geometry { return geom.Point{} }
+func (l testLayer) SRID() uint64            { return 3857 }
+
+// namedLayersTiler is a layersTiler whose Layers returns its layers
+type namedLayersTiler struct {
+	layersTiler
+}
+
+func (nt namedLayersTiler) Layers() ([]provider.LayerInfo, error) {
+	names := make([]string, 0, len(nt.layersTiler))
+	for name := range nt.layersTiler {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	layers := make([]provider.LayerInfo, len(names))
+	for i, name := range names {
+		layers[i] = testLayer(name)
+	}
+	return layers, nil
+}
+
+func TestDensityLayer(t *testing.T) {
+	tile := provider.NewTile(0, 0, 0, 0, 3857)
+	ext, _ := tile.Extent()
+
+	// 10 points in the north west quarter of the tile and 3 in the south east
+	var nw, se []provider.Feature
+	for i := 0; i < 10; i++ {
+		nw = append(nw, provider.Feature{ID: uint64(i + 1), Geometry: geom.Point{ext.MinX() / 2, ext.MaxY() / 2}})
+	}
+	for i := 0; i < 3; i++ {
+		se = append(se, provider.Feature{ID: uint64(i + 1), Geometry: geom.Point{ext.MaxX() / 2, ext.MinY() / 2}})
+	}
+	// a line whose extent is centered in the south east quarter, and a point in
+	// the tile's buffer which is counted in the nearest cell
+	se = append(se,
+		provider.Feature{ID: 4, Geometry: geom.LineString{{1, -1}, {ext.MaxX() - 1, ext.MinY() + 1}}},
+		provider.Feature{ID: 5, Geometry: geom.Point{ext.MaxX() * 1.01, ext.MinY() * 1.01}},
+		provider.Feature{ID: 6},
+	)
+
+	tiler := provider.DensityLayer(namedLayersTiler{layersTiler{"a": nw, "b": se}}, "debug_density", 2)
+
+	layers, err := tiler.Layers()
+	if err != nil {
+		t.Fatalf("layers error, expected nil got %v", err)
+	}
+	if len(layers) != 3 || layers[2].Name() != "debug_density" {
+		t.Errorf("layers, expected a, b and debug_density got %v", layers)
+	}
+
+	got, err := collect(tiler, "debug_density", tile)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+
+	counts := make(map[uint64]int)
+	var sum int
+	for _, f := range got {
+		n, _ := f.Tags[provider.DensityCountKey].(int)
+		counts[f.ID] = n
+		sum += n
+
+		if _, ok := f.Geometry.(geom.Polygon); !ok {
+			t.Errorf("cell %v geometry, expected a polygon got %T", f.ID, f.Geometry)
+		}
+	}
+
+	// the underlying features, less the feature without a geometry
+	if expected := len(nw) + len(se) - 1; sum != expected {
+		t.Errorf("sum of counts, expected %v got %v", expected, sum)
+	}
+	// cells are numbered from the top left, row by row
+	expected := map[uint64]int{1: 10, 4: 5}
+	if len(counts) != len(expected) {
+		t.Errorf("cells, expected %v got %v", expected, counts)
+	}
+	for id, n := range expected {
+		if counts[id] != n {
+			t.Errorf("cell %v count, expected %v got %v", id, n, counts[id])
+		}
+	}
+
+	// other layers are passed through
+	got, err = collect(tiler, "a", tile)
+	if err != nil {
+		t.Fatalf("layer a error, expected nil got %v", err)
+	}
+	if len(got) != len(nw) {
+		t.Errorf("layer a features, expected %v got %v", len(nw), len(got))
+	}
+
+}
@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+)
+
+// diffKey matches the features of DiffTile, by ID or, for features without
+// an ID, by the hash of their geometry
+type diffKey struct {
+	id       uint64
+	geomHash uint64
+}
+
+// diffFeature is a feature of the first Tiler with the hash of its geometry
+// and SRID and the hash of its properties
+type diffFeature struct {
+	f        *Feature
+	geomHash uint64
+	tagsHash uint64
+	// matched is set once a feature of the second Tiler is matched to it
+	matched bool
+}
+
+// DiffTile compares the features of the layer for the tile returned by a and
+// b, i.e. before and after a data migration. Features are matched by ID:
+// added are the features of b without a match in a, removed the features of
+// a without a match in b, and changed the features of b whose geometry, SRID
+// or properties differ from the feature of a they are matched to. Properties
+// differ if their keys, values or value types differ, so 1 and int64(1)
+// differ.
+//
+// Features without an ID (an ID of 0) are matched by their geometry instead,
+// so a feature without an ID whose geometry changed is reported as removed
+// and added rather than changed. Features with the same ID, or without an ID
+// and the same geometry, are matched in the order they are returned.
+// Geometries are compared by a 64 bit hash.
+//
+// The features of a are held in memory while the features of b are
+// streamed. added and changed are in the order b returned them, and removed
+// in the order a returned them.
+func DiffTile(ctx context.Context, a, b Tiler, layer string, tile Tile) (added, removed, changed []*Feature, err error) {
+	var (
+		h     = fnv.New64a()
+		srid  [8]byte
+		keys  []string
+		order []*diffFeature
+		byKey = make(map[diffKey][]*diffFeature)
+	)
+	hashes := func(f *Feature) (geomHash, tagsHash uint64) {
+		h.Reset()
+		hashGeometry(h, f.Geometry)
+		binary.LittleEndian.PutUint64(srid[:], f.SRID)
+		h.Write(srid[:])
+		geomHash = h.Sum64()
+
+		h.Reset()
+		keys = hashTags(h, f.Tags, keys)
+		return geomHash, h.Sum64()
+	}
+	key := func(f *Feature, geomHash uint64) diffKey {
+		if f.ID != 0 {
+			return diffKey{id: f.ID}
+		}
+		return diffKey{geomHash: geomHash}
+	}
+
+	err = a.TileFeatures(ctx, layer, tile, func(f *Feature) error {
+		ff := *f
+		df := &diffFeature{f: &ff}
+		df.geomHash, df.tagsHash = hashes(f)
+
+		k := key(f, df.geomHash)
+		byKey[k] = append(byKey[k], df)
+		order = append(order, df)
+		return nil
+	})
+	if err != nil && !errors.Is(err, ErrNoFeatures) {
+		return nil, nil, nil, err
+	}
+
+	err = b.TileFeatures(ctx, layer, tile, func(f *Feature) error {
+		ff := *f
+		geomHash, tagsHash := hashes(f)
+
+		k := key(f, geomHash)
+		matches := byKey[k]
+		if len(matches) == 0 {
+			added = append(added, &ff)
+			return nil
+		}
+		df := matches[0]
+		byKey[k] = matches[1:]
+		df.matched = true
+
+		if df.geomHash != geomHash || df.tagsHash != tagsHash {
+			changed = append(changed, &ff)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, ErrNoFeatures) {
+		return nil, nil, nil, err
+	}
+
+	for _, df := range order {
+		if !df.matched {
+			removed = append(removed, df.f)
+		}
+	}
+	return added, removed, changed, nil
+}
@@ -0,0 +1,113 @@
+package provider_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestDiffTile(t *testing.T) {
+	type tcase struct {
+		a, b    []provider.Feature
+		added   []uint64
+		removed []uint64
+		changed []uint64
+	}
+
+	ids := func(fs []*provider.Feature) (ids []uint64) {
+		for _, f := range fs {
+			ids = append(ids, f.ID)
+		}
+		return ids
+	}
+	equal := func(a, b []uint64) bool {
+		if len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if a[i] != b[i] {
+				return false
+			}
+		}
+		return true
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		added, removed, changed, err := provider.DiffTile(
+			context.Background(),
+			featuresTiler{features: tc.a},
+			featuresTiler{features: tc.b},
+			"roads",
+			provider.NewTile(0, 0, 0, 0, 3857),
+		)
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		if got := ids(added); !equal(got, tc.added) {
+			t.Errorf("added, expected %v got %v", tc.added, got)
+		}
+		if got := ids(removed); !equal(got, tc.removed) {
+			t.Errorf("removed, expected %v got %v", tc.removed, got)
+		}
+		if got := ids(changed); !equal(got, tc.changed) {
+			t.Errorf("changed, expected %v got %v", tc.changed, got)
+		}
+	}
+
+	roads := []provider.Feature{
+		{ID: 1, Geometry: geom.LineString{{0, 0}, {1, 1}}, Tags: map[string]interface{}{"name": "Main St"}},
+		{ID: 2, Geometry: geom.LineString{{1, 1}, {2, 2}}, Tags: map[string]interface{}{"name": "High St"}},
+	}
+
+	tests := map[string]tcase{
+		"unchanged": {
+			a: roads,
+			b: roads,
+		},
+		"changed property": {
+			a: roads,
+			b: []provider.Feature{
+				roads[0],
+				{ID: 2, Geometry: geom.LineString{{1, 1}, {2, 2}}, Tags: map[string]interface{}{"name": "High Street"}},
+			},
+			changed: []uint64{2},
+		},
+		"changed geometry": {
+			a: roads,
+			b: []provider.Feature{
+				{ID: 1, Geometry: geom.LineString{{0, 0}, {1, 2}}, Tags: map[string]interface{}{"name": "Main St"}},
+				roads[1],
+			},
+			changed: []uint64{1},
+		},
+		"added and removed": {
+			a: roads,
+			b: []provider.Feature{
+				roads[1],
+				{ID: 3, Geometry: geom.Point{0, 0}},
+			},
+			added:   []uint64{3},
+			removed: []uint64{1},
+		},
+		"without ids matched by geometry": {
+			a: []provider.Feature{
+				{Geometry: geom.Point{0, 0}, Tags: map[string]interface{}{"kind": "stop"}},
+				{Geometry: geom.Point{1, 1}},
+			},
+			b: []provider.Feature{
+				{Geometry: geom.Point{0, 0}, Tags: map[string]interface{}{"kind": "station"}},
+				{Geometry: geom.Point{2, 2}},
+			},
+			added:   []uint64{0},
+			removed: []uint64{0},
+			changed: []uint64{0},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
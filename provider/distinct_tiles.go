@@ -0,0 +1,162 @@
+package provider
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"math/bits"
+	"sync"
+	"time"
+)
+
+// distinctTilesWindow is the length of the rolling window over which
+// distinct tiles are estimated by a Tiler wrapped with WithDistinctTiles.
+// Estimates cover between one and two windows of requests.
+const distinctTilesWindow = time.Hour
+
+// hllPrecision is the number of bits of the hash used to pick a register,
+// giving 4096 registers and a standard error of about 1.6%
+const hllPrecision = 12
+
+// hyperLogLog estimates the number of distinct values added to it,
+// using a fixed 4KB of memory
+type hyperLogLog [1 << hllPrecision]uint8
+
+func (hll *hyperLogLog) add(v string) {
+	h := fnv.New64a()
+	h.Write([]byte(v))
+	x := mix64(h.Sum64())
+
+	idx := x >> (64 - hllPrecision)
+	// the remaining bits, with a sentinel so rank is at most 64-precision+1
+	rest := x<<hllPrecision | 1<<(hllPrecision-1)
+	if rank := uint8(bits.LeadingZeros64(rest) + 1); rank > hll[idx] {
+		hll[idx] = rank
+	}
+}
+
+// merge sets each register to the max of both sketches
+func (hll *hyperLogLog) merge(other *hyperLogLog) {
+	for i := range hll {
+		if other[i] > hll[i] {
+			hll[i] = other[i]
+		}
+	}
+}
+
+func (hll *hyperLogLog) estimate() uint64 {
+	const m = float64(len(hll))
+
+	var (
+		sum   float64
+		zeros int
+	)
+	for _, r := range hll {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	alpha := 0.7213 / (1 + 1.079/m)
+	est := alpha * m * m / sum
+	// small range correction, using linear counting
+	if est <= 2.5*m && zeros > 0 {
+		est = m * math.Log(m/float64(zeros))
+	}
+	return uint64(est + 0.5)
+}
+
+// mix64 is the splitmix64 finalizer, used to spread the bits of the hash
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// distinctTiles holds the sketches of the current and previous window
+type distinctTiles struct {
+	lock        sync.Mutex
+	windowStart time.Time
+	current     hyperLogLog
+	previous    hyperLogLog
+}
+
+// rotate moves to a new window if the current one has passed, it must be
+// called with the lock held
+func (dt *distinctTiles) rotate(now time.Time) {
+	elapsed := now.Sub(dt.windowStart)
+	switch {
+	case elapsed < distinctTilesWindow:
+		return
+	case elapsed < 2*distinctTilesWindow:
+		dt.previous = dt.current
+	default:
+		dt.previous = hyperLogLog{}
+	}
+	dt.current = hyperLogLog{}
+	dt.windowStart = now
+}
+
+// estimate returns the distinct tiles of the current and previous window
+func (dt *distinctTiles) estimate(now time.Time) uint64 {
+	dt.lock.Lock()
+	defer dt.lock.Unlock()
+
+	dt.rotate(now)
+	merged := dt.current
+	merged.merge(&dt.previous)
+	return merged.estimate()
+}
+
+// distinctTilesByName holds the *distinctTiles of each provider
+var distinctTilesByName sync.Map
+
+// DistinctTiles returns an estimate of the number of distinct tiles requested
+// from the named provider over the last one to two hours, as
+// recorded by a Tiler wrapped with WithDistinctTiles. Tiles are identified by
+// their TileKey, so requests for different layers of the same tile count
+// once. The estimate has a standard error of about 1.6%.
+func DistinctTiles(name string) uint64 {
+	v, ok := distinctTilesByName.Load(name)
+	if !ok {
+		return 0
+	}
+
+	return v.(*distinctTiles).estimate(time.Now())
+}
+
+// WithDistinctTiles wraps the Tiler so the distinct tiles requested through
+// TileFeatures are estimated under the provider name, see DistinctTiles.
+// Compared with the total number of requests this gives the potential hit
+// rate of a tile cache. Memory use is fixed at 8KB per provider name,
+// regardless of the number of requests.
+func WithDistinctTiles(t Tiler, name string) Tiler {
+	v, ok := distinctTilesByName.Load(name)
+	if !ok {
+		v, _ = distinctTilesByName.LoadOrStore(name, &distinctTiles{windowStart: time.Now()})
+	}
+	return &distinctTilesTiler{
+		Tiler: t,
+		dt:    v.(*distinctTiles),
+	}
+}
+
+type distinctTilesTiler struct {
+	Tiler
+	dt *distinctTiles
+}
+
+func (dtt *distinctTilesTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	key := TileKey(t)
+
+	dtt.dt.lock.Lock()
+	dtt.dt.rotate(time.Now())
+	dtt.dt.current.add(key)
+	dtt.dt.lock.Unlock()
+
+	return dtt.Tiler.TileFeatures(ctx, layer, t, fn)
+}
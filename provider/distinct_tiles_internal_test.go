@@ -0,0 +1,32 @@
+package provider
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDistinctTilesWindow(t *testing.T) {
+	start := time.Now()
+	dt := &distinctTiles{windowStart: start}
+	for x := uint(0); x < 10; x++ {
+		dt.current.add(TileKey(NewTile(4, x, 0, 0, 3857)))
+	}
+
+	type tcase struct {
+		elapsed  time.Duration
+		expected uint64
+	}
+	// the cases are run in order, sharing dt
+	tests := []tcase{
+		{elapsed: distinctTilesWindow / 2, expected: 10},
+		// the tiles move to the previous window
+		{elapsed: distinctTilesWindow, expected: 10},
+		// once two windows have passed the tiles are forgotten
+		{elapsed: 2 * distinctTilesWindow, expected: 0},
+	}
+	for _, tc := range tests {
+		if got := dt.estimate(start.Add(tc.elapsed)); got != tc.expected {
+			t.Errorf("distinct tiles after %v, expected %v got %v", tc.elapsed, tc.expected, got)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package provider_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestDistinctTiles(t *testing.T) {
+	const name = "distinct-tiles-test"
+
+	if got := provider.DistinctTiles(name); got != 0 {
+		t.Errorf("unknown provider, expected 0 got %v", got)
+	}
+
+	type tcase struct {
+		distinct int
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		// a separate provider name per case, so the counts do not mix
+		tiler := provider.WithDistinctTiles(featuresTiler{}, name+t.Name())
+
+		// every tile is requested three times
+		for i := 0; i < 3; i++ {
+			for n := 0; n < tc.distinct; n++ {
+				tile := provider.NewTile(14, uint(n%16384), uint(n/16384), 0, 3857)
+				err := tiler.TileFeatures(context.Background(), "roads", tile, func(*provider.Feature) error { return nil })
+				if err != nil {
+					t.Fatalf("error, expected nil got %v", err)
+				}
+			}
+		}
+
+		got := float64(provider.DistinctTiles(name + t.Name()))
+		if diff := math.Abs(got-float64(tc.distinct)) / float64(tc.distinct); diff > 0.05 {
+			t.Errorf("distinct tiles, expected %v (±5%%) got %v", tc.distinct, got)
+		}
+	}
+
+	tests := map[string]tcase{
+		"few": {
+			distinct: 100,
+		},
+		"many": {
+			distinct: 50000,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
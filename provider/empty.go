@@ -0,0 +1,27 @@
+package provider
+
+import "context"
+
+// WithEmptySentinel wraps the Tiler so that TileFeatures returns ErrNoFeatures
+// when the wrapped Tiler completed successfully without passing any features
+// to the callback. This makes empty tiles authoritative for providers which do
+// not report ErrNoFeatures themselves.
+func WithEmptySentinel(t Tiler) Tiler {
+	return &emptySentinelTiler{Tiler: t}
+}
+
+type emptySentinelTiler struct {
+	Tiler
+}
+
+func (est *emptySentinelTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	var count int
+	err := est.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		count++
+		return fn(f)
+	})
+	if err == nil && count == 0 {
+		return ErrNoFeatures
+	}
+	return err
+}
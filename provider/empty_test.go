@@ -0,0 +1,45 @@
+package provider_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithEmptySentinel(t *testing.T) {
+	errQuery := errors.New("query failed")
+
+	type tcase struct {
+		tiler featuresTiler
+		err   error
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		_, err := collect(provider.WithEmptySentinel(tc.tiler), "", provider.NewTile(0, 0, 0, 0, 3857))
+		if !errors.Is(err, tc.err) {
+			t.Errorf("error, expected %v got %v", tc.err, err)
+		}
+	}
+
+	tests := map[string]tcase{
+		"empty": {
+			err: provider.ErrNoFeatures,
+		},
+		"features": {
+			tiler: featuresTiler{
+				features: []provider.Feature{{ID: 1, Geometry: geom.Point{1, 1}}},
+			},
+		},
+		"query error": {
+			tiler: featuresTiler{err: errQuery},
+			err:   errQuery,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
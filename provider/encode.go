@@ -0,0 +1,293 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/mvt"
+	vectorTile "github.com/go-spatial/geom/encoding/mvt/vector_tile"
+	"github.com/golang/protobuf/proto"
+)
+
+// EncodeStream encodes the features of the layer for the tile as an MVT and
+// writes it to w. Features are encoded into the MVT layer as they are streamed
+// from TileFeatures, so peak memory is roughly one decoded feature plus the
+// encoded output, rather than every decoded feature of the tile.
+//
+// Features are reprojected to the tile's SRID if needed and converted to
+// tile coordinates. No simplification, clipping or validation is applied, so
+// features extending past the tile's buffered extent are encoded as is.
+// If t implements LocalTiler the features are requested in tile coordinates
+// and encoded without any transformation.
+func EncodeStream(ctx context.Context, t Tiler, layer string, tile Tile, w io.Writer) error {
+	return EncodeStreamOptions(ctx, t, layer, tile, w, EncodeOptions{})
+}
+
+// EncodeOptions are the options of EncodeStreamOptions
+type EncodeOptions struct {
+	// Extent is the MVT extent of the layer, the number of units across
+	// each side of the tile that coordinates are quantized to. 0 uses
+	// mvt.DefaultExtent (4096). A smaller extent trades precision for a
+	// smaller tile, i.e. 256 for low detail or 8192 for high detail.
+	Extent uint32
+}
+
+// EncodeStreamOptions encodes the features of the layer for the tile as an
+// MVT and writes it to w, as EncodeStream, with the options.
+func EncodeStreamOptions(ctx context.Context, t Tiler, layer string, tile Tile, w io.Writer, opts EncodeOptions) error {
+	vl, err := encodeLayer(ctx, t, layer, layer, tile, opts.Extent)
+	if err != nil {
+		return err
+	}
+	return writeTile(w, vl)
+}
+
+// EncodeLayersStream encodes the features of each of the layers for the tile
+// into a single MVT and writes it to w, as EncodeStream. Each layer is named
+// its MVTName, or its Name if not set, and is encoded with its own Extent,
+// so layers needing less precision, i.e. labels, can use a smaller extent
+// than the rest of the tile. The layers are encoded one after the other.
+func EncodeLayersStream(ctx context.Context, t Tiler, layers []Layer, tile Tile, w io.Writer) error {
+	vls := make([]*vectorTile.Tile_Layer, len(layers))
+	for i, l := range layers {
+		name := l.MVTName
+		if name == "" {
+			name = l.Name
+		}
+		vl, err := encodeLayer(ctx, t, l.Name, name, tile, l.Extent)
+		if err != nil {
+			return err
+		}
+		vls[i] = vl
+	}
+	return writeTile(w, vls...)
+}
+
+// encodeLayer encodes the features of the layer for the tile into a MVT
+// layer named name. An extent of 0 uses mvt.DefaultExtent.
+func encodeLayer(ctx context.Context, t Tiler, layer, name string, tile Tile, extent uint32) (*vectorTile.Tile_Layer, error) {
+	if extent == 0 {
+		extent = mvt.DefaultExtent
+	}
+	le := newLayerEncoder(name, tile, extent)
+
+	err := TileFeaturesLocal(ctx, t, layer, tile, int(le.extent), func(f *Feature) error {
+		return le.addFeature(ctx, f, true)
+	})
+	if errors.Is(err, ErrUnsupported) {
+		err = t.TileFeatures(ctx, layer, tile, func(f *Feature) error {
+			return le.addFeature(ctx, f, false)
+		})
+	}
+	if err != nil && !errors.Is(err, ErrNoFeatures) {
+		return nil, err
+	}
+	return le.vtileLayer(), nil
+}
+
+// writeTile marshals a MVT of the layers and writes it to w
+func writeTile(w io.Writer, layers ...*vectorTile.Tile_Layer) error {
+	b, err := proto.Marshal(&vectorTile.Tile{
+		Layers: layers,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(b)
+	return err
+}
+
+// layerEncoder incrementally encodes features into a MVT layer
+type layerEncoder struct {
+	name     string
+	extent   uint32
+	tileExt  *geom.Extent
+	tileSRID uint64
+
+	features []*vectorTile.Tile_Feature
+	keys     []string
+	keyIdx   map[string]uint32
+	values   []*vectorTile.Tile_Value
+	valueIdx map[interface{}]uint32
+}
+
+func newLayerEncoder(name string, tile Tile, extent uint32) *layerEncoder {
+	tileExt, tileSRID := tile.Extent()
+	return &layerEncoder{
+		name:     name,
+		extent:   extent,
+		tileExt:  tileExt,
+		tileSRID: tileSRID,
+		keyIdx:   make(map[string]uint32),
+		valueIdx: make(map[interface{}]uint32),
+	}
+}
+
+// addFeature encodes the feature and appends it to the layer. If local is
+// true the feature's geometry is already in tile coordinates.
+func (le *layerEncoder) addFeature(ctx context.Context, f *Feature, local bool) error {
+	if f.Geometry == nil || geom.IsEmpty(f.Geometry) {
+		return nil
+	}
+
+	geo := f.Geometry
+	if !local {
+		g, err := reprojectFeature(f, le.tileSRID)
+		if err != nil {
+			return err
+		}
+		geo = g
+	}
+
+	tags, err := le.tags(f.Tags)
+	if err != nil {
+		return fmt.Errorf("feature %v: %w", f.ID, err)
+	}
+
+	// the ID is copied so the encoded feature does not keep f reachable
+	id := f.ID
+
+	// collections are encoded as a feature per geometry
+	for _, mf := range mvt.NewFeatures(geo, nil) {
+		// a collection may have empty parts, which can not be encoded
+		if mf.Geometry == nil || geom.IsEmpty(mf.Geometry) {
+			continue
+		}
+		mf.ID = &id
+		if !local {
+			mf.Geometry = mvt.PrepareGeo(mf.Geometry, le.tileExt, float64(le.extent))
+		}
+		if mf.Geometry == nil || geom.IsEmpty(mf.Geometry) {
+			continue
+		}
+
+		// tags are encoded by the layerEncoder, so none are passed here
+		vtf, err := mf.VTileFeature(ctx, nil, nil)
+		if err != nil {
+			return err
+		}
+		if vtf == nil {
+			continue
+		}
+
+		vtf.Tags = tags
+		le.features = append(le.features, vtf)
+	}
+
+	return nil
+}
+
+// tags encodes the tags, adding any new keys and values to the layer
+func (le *layerEncoder) tags(tags map[string]interface{}) ([]uint32, error) {
+	encoded := make([]uint32, 0, len(tags)*2)
+	for k, v := range tags {
+		// nil values are skipped
+		// https://github.com/mapbox/vector-tile-spec/issues/62
+		if v == nil {
+			continue
+		}
+
+		tv, hashable, err := tileValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("tag (%v): %w", k, err)
+		}
+
+		kidx, ok := le.keyIdx[k]
+		if !ok {
+			kidx = uint32(len(le.keys))
+			le.keys = append(le.keys, k)
+			le.keyIdx[k] = kidx
+		}
+
+		vidx, ok := le.valueIdx[hashable]
+		if !ok {
+			vidx = uint32(len(le.values))
+			le.values = append(le.values, tv)
+			le.valueIdx[hashable] = vidx
+		}
+
+		encoded = append(encoded, kidx, vidx)
+	}
+	return encoded, nil
+}
+
+// vtileLayer returns the encoded layer
+func (le *layerEncoder) vtileLayer() *vectorTile.Tile_Layer {
+	name := le.name
+	version := uint32(mvt.Version)
+	extent := le.extent
+	return &vectorTile.Tile_Layer{
+		Version:  &version,
+		Name:     &name,
+		Features: le.features,
+		Keys:     le.keys,
+		Values:   le.values,
+		Extent:   &extent,
+	}
+}
+
+// tileValue converts v to a MVT value. The returned hashable value is unique
+// per MVT value and is used to dedupe values in the layer's values table.
+func tileValue(v interface{}) (tv *vectorTile.Tile_Value, hashable interface{}, err error) {
+	tv = new(vectorTile.Tile_Value)
+	switch val := v.(type) {
+	case string:
+		tv.StringValue = &val
+	case fmt.Stringer:
+		str := val.String()
+		tv.StringValue = &str
+		v = str
+	case bool:
+		tv.BoolValue = &val
+	case int:
+		i := int64(val)
+		tv.SintValue = &i
+		v = i
+	case int8:
+		i := int64(val)
+		tv.SintValue = &i
+		v = i
+	case int16:
+		i := int64(val)
+		tv.SintValue = &i
+		v = i
+	case int32:
+		i := int64(val)
+		tv.SintValue = &i
+		v = i
+	case int64:
+		tv.IntValue = &val
+		// int64 uses a different field than the other ints,
+		// so it needs its own entry in the values table
+		v = [1]int64{val}
+	case uint:
+		u := uint64(val)
+		tv.UintValue = &u
+		v = u
+	case uint8:
+		i := int64(val)
+		tv.SintValue = &i
+		v = i
+	case uint16:
+		i := int64(val)
+		tv.SintValue = &i
+		v = i
+	case uint32:
+		i := int64(val)
+		tv.SintValue = &i
+		v = i
+	case uint64:
+		tv.UintValue = &val
+	case float32:
+		tv.FloatValue = &val
+	case float64:
+		tv.DoubleValue = &val
+	default:
+		return nil, nil, fmt.Errorf("value (%[1]v) of type (%[1]T) is not supported", v)
+	}
+	return tv, v, nil
+}
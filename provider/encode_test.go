@@ -0,0 +1,283 @@
+package provider_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	vectorTile "github.com/go-spatial/geom/encoding/mvt/vector_tile"
+	"github.com/go-spatial/geom/slippy"
+	"github.com/go-spatial/tegola/provider"
+	"github.com/golang/protobuf/proto"
+)
+
+func TestEncodeStream(t *testing.T) {
+	tiler := featuresTiler{
+		features: []provider.Feature{
+			{ID: 1, SRID: 3857, Geometry: geom.Point{0, 0}, Tags: map[string]interface{}{"class": "city"}},
+			{ID: 2, SRID: 3857, Geometry: geom.Point{100, 100}, Tags: map[string]interface{}{"class": "city", "pop": 10}},
+			// empty geometries are skipped
+			{ID: 3, SRID: 3857, Geometry: geom.Collection{}},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := provider.EncodeStream(context.Background(), tiler, "places", provider.NewTile(0, 0, 0, 64, 3857), &buf)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+
+	var tile vectorTile.Tile
+	if err = proto.Unmarshal(buf.Bytes(), &tile); err != nil {
+		t.Fatalf("unmarshal, expected nil got %v", err)
+	}
+
+	if len(tile.Layers) != 1 {
+		t.Fatalf("layers, expected 1 got %v", len(tile.Layers))
+	}
+	layer := tile.Layers[0]
+	if layer.GetName() != "places" {
+		t.Errorf("layer name, expected places got %v", layer.GetName())
+	}
+	if len(layer.Features) != 2 {
+		t.Errorf("features, expected 2 got %v", len(layer.Features))
+	}
+	if len(layer.Keys) != 2 {
+		t.Errorf("keys, expected 2 got %v", layer.Keys)
+	}
+	// "city" is shared by both features
+	if len(layer.Values) != 2 {
+		t.Errorf("values, expected 2 got %v", layer.Values)
+	}
+	// the point at the origin is in the center of the z0 tile
+	geo := layer.Features[0].Geometry
+	// MoveTo(1), zigzag encoded 2048, 2048
+	expected := []uint32{9, 4096, 4096}
+	if len(geo) != len(expected) || geo[0] != expected[0] || geo[1] != expected[1] || geo[2] != expected[2] {
+		t.Errorf("geometry, expected %v got %v", expected, geo)
+	}
+}
+
+func TestEncodeStreamEmptyPart(t *testing.T) {
+	tiler := featuresTiler{
+		features: []provider.Feature{
+			// the empty line of the collection is skipped, the point is encoded
+			{ID: 4, SRID: 3857, Geometry: geom.Collection{geom.LineString{}, geom.Point{0, 0}}},
+			{ID: 5, SRID: 3857, Geometry: geom.Collection{geom.LineString{}}},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := provider.EncodeStream(context.Background(), tiler, "places", provider.NewTile(0, 0, 0, 64, 3857), &buf)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+
+	var tile vectorTile.Tile
+	if err = proto.Unmarshal(buf.Bytes(), &tile); err != nil {
+		t.Fatalf("unmarshal, expected nil got %v", err)
+	}
+	if len(tile.Layers) != 1 {
+		t.Fatalf("layers, expected 1 got %v", len(tile.Layers))
+	}
+	features := tile.Layers[0].Features
+	if len(features) != 1 {
+		t.Fatalf("features, expected 1 got %v", len(features))
+	}
+	if features[0].GetId() != 4 {
+		t.Errorf("feature id, expected 4 got %v", features[0].GetId())
+	}
+}
+
+func TestEncodeStreamWrappedNoFeatures(t *testing.T) {
+	// decorators wrap ErrNoFeatures with the layer's context
+	tiler := featuresTiler{err: fmt.Errorf("layer (places): %w", provider.ErrNoFeatures)}
+
+	var buf bytes.Buffer
+	err := provider.EncodeStream(context.Background(), tiler, "places", provider.NewTile(0, 0, 0, 64, 3857), &buf)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+
+	var tile vectorTile.Tile
+	if err = proto.Unmarshal(buf.Bytes(), &tile); err != nil {
+		t.Fatalf("unmarshal, expected nil got %v", err)
+	}
+	if len(tile.Layers) != 1 || len(tile.Layers[0].Features) != 0 {
+		t.Errorf("layers, expected 1 empty layer got %v", tile.Layers)
+	}
+}
+
+// localTiler returns its features from TileFeaturesLocal, and no features
+// from TileFeatures
+type localTiler struct {
+	featuresTiler
+}
+
+func (lt localTiler) TileFeatures(ctx context.Context, layer string, t provider.Tile, fn func(f *provider.Feature) error) error {
+	return provider.ErrNoFeatures
+}
+
+func (lt localTiler) TileFeaturesLocal(ctx context.Context, layer string, t provider.Tile, extent int, fn func(f *provider.Feature) error) error {
+	return lt.featuresTiler.TileFeatures(ctx, layer, t, fn)
+}
+
+func TestEncodeStreamLocal(t *testing.T) {
+	tile := provider.NewTile(0, 0, 0, 64, 3857)
+
+	err := provider.TileFeaturesLocal(context.Background(), featuresTiler{}, "places", tile, 4096, func(*provider.Feature) error { return nil })
+	if err != provider.ErrUnsupported {
+		t.Errorf("unsupported error, expected %v got %v", provider.ErrUnsupported, err)
+	}
+
+	tiler := localTiler{featuresTiler{
+		features: []provider.Feature{
+			// already in tile coordinates, so it must not be transformed
+			{ID: 1, Geometry: geom.Point{2048, 2048}},
+		},
+	}}
+
+	var buf bytes.Buffer
+	if err = provider.EncodeStream(context.Background(), tiler, "places", tile, &buf); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+
+	var vtile vectorTile.Tile
+	if err = proto.Unmarshal(buf.Bytes(), &vtile); err != nil {
+		t.Fatalf("unmarshal, expected nil got %v", err)
+	}
+	if len(vtile.Layers) != 1 || len(vtile.Layers[0].Features) != 1 {
+		t.Fatalf("features, expected 1 got %v", vtile.Layers)
+	}
+
+	geo := vtile.Layers[0].Features[0].Geometry
+	expected := []uint32{9, 4096, 4096}
+	if len(geo) != len(expected) || geo[0] != expected[0] || geo[1] != expected[1] || geo[2] != expected[2] {
+		t.Errorf("geometry, expected %v got %v", expected, geo)
+	}
+}
+
+func TestEncodeStreamExtent(t *testing.T) {
+	// a point a quarter of the way across and down the z0 tile
+	q := slippy.WebMercatorMax / 2
+	tiler := featuresTiler{
+		features: []provider.Feature{
+			{ID: 1, SRID: 3857, Geometry: geom.Point{-q, q}},
+		},
+	}
+
+	type tcase struct {
+		extent         uint32
+		expectedExtent uint32
+		// MoveTo(1), followed by the zigzag encoded x and y
+		expected []uint32
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		var buf bytes.Buffer
+		err := provider.EncodeStreamOptions(context.Background(), tiler, "places", provider.NewTile(0, 0, 0, 64, 3857), &buf, provider.EncodeOptions{Extent: tc.extent})
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+
+		var tile vectorTile.Tile
+		if err = proto.Unmarshal(buf.Bytes(), &tile); err != nil {
+			t.Fatalf("unmarshal, expected nil got %v", err)
+		}
+		if len(tile.Layers) != 1 || len(tile.Layers[0].Features) != 1 {
+			t.Fatalf("features, expected 1 layer with 1 feature got %v", tile.Layers)
+		}
+
+		layer := tile.Layers[0]
+		if layer.GetExtent() != tc.expectedExtent {
+			t.Errorf("extent, expected %v got %v", tc.expectedExtent, layer.GetExtent())
+		}
+		if geo := layer.Features[0].Geometry; !reflect.DeepEqual(geo, tc.expected) {
+			t.Errorf("geometry, expected %v got %v", tc.expected, geo)
+		}
+	}
+
+	tests := map[string]tcase{
+		"default": {
+			expectedExtent: 4096,
+			// 1024, 1024
+			expected: []uint32{9, 2048, 2048},
+		},
+		"4096": {
+			extent:         4096,
+			expectedExtent: 4096,
+			expected:       []uint32{9, 2048, 2048},
+		},
+		// the coordinates scale with the extent
+		"8192": {
+			extent:         8192,
+			expectedExtent: 8192,
+			// 2048, 2048
+			expected: []uint32{9, 4096, 4096},
+		},
+		"256": {
+			extent:         256,
+			expectedExtent: 256,
+			// 64, 64
+			expected: []uint32{9, 128, 128},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
+
+func TestEncodeLayersStream(t *testing.T) {
+	q := slippy.WebMercatorMax / 2
+	tiler := featuresTiler{
+		features: []provider.Feature{
+			{ID: 1, SRID: 3857, Geometry: geom.Point{-q, q}},
+		},
+	}
+
+	layers := []provider.Layer{
+		{Name: "coastline", Extent: 8192},
+		{Name: "labels", MVTName: "place_labels", Extent: 256},
+	}
+
+	var buf bytes.Buffer
+	if err := provider.EncodeLayersStream(context.Background(), tiler, layers, provider.NewTile(0, 0, 0, 64, 3857), &buf); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+
+	var tile vectorTile.Tile
+	if err := proto.Unmarshal(buf.Bytes(), &tile); err != nil {
+		t.Fatalf("unmarshal, expected nil got %v", err)
+	}
+	if len(tile.Layers) != 2 {
+		t.Fatalf("layers, expected 2 got %v", len(tile.Layers))
+	}
+
+	expected := []struct {
+		name   string
+		extent uint32
+		geo    []uint32
+	}{
+		{"coastline", 8192, []uint32{9, 4096, 4096}},
+		{"place_labels", 256, []uint32{9, 128, 128}},
+	}
+	for i, l := range tile.Layers {
+		if l.GetName() != expected[i].name {
+			t.Errorf("layer %v name, expected %v got %v", i, expected[i].name, l.GetName())
+		}
+		if l.GetExtent() != expected[i].extent {
+			t.Errorf("layer %v extent, expected %v got %v", i, expected[i].extent, l.GetExtent())
+		}
+		if len(l.Features) != 1 {
+			t.Fatalf("layer %v features, expected 1 got %v", i, len(l.Features))
+		}
+		if geo := l.Features[0].Geometry; !reflect.DeepEqual(geo, expected[i].geo) {
+			t.Errorf("layer %v geometry, expected %v got %v", i, expected[i].geo, geo)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// enrichConcurrency is the maximum number of features a Tiler wrapped with
+// WithPropertyEnricher enriches concurrently, per TileFeatures call
+const enrichConcurrency = 8
+
+// WithPropertyEnricher wraps the Tiler so enrich is called for every feature
+// before it is passed to the callback, i.e. to add properties computed from
+// another data source such as sampling an elevation raster. Up to 8
+// features are enriched at once, while the callback is
+// still called for one feature at a time, in the order the provider returned
+// them.
+//
+// The features passed to enrich are copies, with their own Tags map, so
+// enrich may modify them without affecting the provider. If enrich returns
+// an error the TileFeatures call is stopped, the context passed to any
+// enrich calls in flight is canceled, and the error is returned.
+func WithPropertyEnricher(t Tiler, enrich func(ctx context.Context, f *Feature) error) Tiler {
+	if enrich == nil {
+		return t
+	}
+	return &enrichTiler{
+		Tiler:  t,
+		enrich: enrich,
+	}
+}
+
+type enrichTiler struct {
+	Tiler
+	enrich func(ctx context.Context, f *Feature) error
+}
+
+// enrichItem is a feature being enriched, the result is sent on done
+type enrichItem struct {
+	f    Feature
+	done chan error
+}
+
+func (et *enrichTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		// pending holds the features in provider order, bounding
+		// how far enriching can get ahead of the callback
+		pending = make(chan *enrichItem, enrichConcurrency)
+		sem     = make(chan struct{}, enrichConcurrency)
+		emitted = make(chan struct{})
+
+		lock sync.Mutex
+		// the first error from enrich or fn
+		emitErr error
+	)
+
+	setErr := func(err error) {
+		lock.Lock()
+		defer lock.Unlock()
+		if emitErr == nil {
+			emitErr = err
+			cancel()
+		}
+	}
+	getErr := func() error {
+		lock.Lock()
+		defer lock.Unlock()
+		return emitErr
+	}
+
+	// pass the enriched features to fn in order
+	go func() {
+		defer close(emitted)
+		for item := range pending {
+			err := <-item.done
+			if getErr() != nil {
+				continue
+			}
+			if err != nil {
+				setErr(fmt.Errorf("enriching layer (%v) feature %v: %w", layer, item.f.ID, err))
+				continue
+			}
+			if err = fn(&item.f); err != nil {
+				setErr(err)
+			}
+		}
+	}()
+
+	err := et.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		if err := getErr(); err != nil {
+			return err
+		}
+
+		item := &enrichItem{
+			f:    *f,
+			done: make(chan error, 1),
+		}
+		item.f.Tags = make(map[string]interface{}, len(f.Tags))
+		for k, v := range f.Tags {
+			item.f.Tags[k] = v
+		}
+
+		pending <- item
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem }()
+			item.done <- et.enrich(ctx, &item.f)
+		}()
+		return nil
+	})
+
+	close(pending)
+	<-emitted
+
+	if err := getErr(); err != nil {
+		return err
+	}
+	return err
+}
@@ -0,0 +1,77 @@
+package provider_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithPropertyEnricher(t *testing.T) {
+	features := make([]provider.Feature, 20)
+	for i := range features {
+		features[i] = provider.Feature{ID: uint64(i), Geometry: geom.Point{float64(i), 0}}
+	}
+	tiler := featuresTiler{features: features}
+	tile := provider.NewTile(0, 0, 0, 0, 3857)
+
+	var running, maxRunning int32
+	enriched := provider.WithPropertyEnricher(tiler, func(ctx context.Context, f *provider.Feature) error {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+				break
+			}
+		}
+
+		// later features finish first, to check the order is kept
+		time.Sleep(time.Duration(20-f.ID) * time.Millisecond)
+		f.Tags["elevation"] = f.Geometry.(geom.Point).X() * 10
+		return nil
+	})
+
+	got, err := collect(enriched, "peaks", tile)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if len(got) != len(features) {
+		t.Fatalf("features, expected %v got %v", len(features), len(got))
+	}
+	for i, f := range got {
+		if f.ID != uint64(i) {
+			t.Errorf("feature %v id, expected %v got %v", i, i, f.ID)
+		}
+		if f.Tags["elevation"] != float64(i*10) {
+			t.Errorf("feature %v elevation, expected %v got %v", i, i*10, f.Tags["elevation"])
+		}
+	}
+	// up to 8 features are enriched at once
+	if max := atomic.LoadInt32(&maxRunning); max < 2 || max > 8 {
+		t.Errorf("concurrency, expected between 2 and 8 got %v", max)
+	}
+	// the provider's features are not modified
+	if features[0].Tags != nil {
+		t.Errorf("provider tags, expected nil got %v", features[0].Tags)
+	}
+
+	errSample := errors.New("no elevation data")
+	failing := provider.WithPropertyEnricher(tiler, func(ctx context.Context, f *provider.Feature) error {
+		if f.ID == 5 {
+			return errSample
+		}
+		return nil
+	})
+	got, err = collect(failing, "peaks", tile)
+	if !errors.Is(err, errSample) {
+		t.Errorf("error, expected %v got %v", errSample, err)
+	}
+	if len(got) != 5 {
+		t.Errorf("features before error, expected 5 got %v", len(got))
+	}
+}
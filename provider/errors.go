@@ -1,14 +1,34 @@
 package provider
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 )
 
 var (
 	ErrCanceled    = errors.New("provider: canceled")
 	ErrUnsupported = errors.New("provider: unsupported")
+	// ErrNoFeatures may be returned by TileFeatures when the query ran
+	// successfully but there were no features for the tile. This allows
+	// callers to treat the empty tile as authoritative (i.e. negative caching).
+	// Callers should treat it as a successful, empty, result.
+	ErrNoFeatures = errors.New("provider: no features")
+	// ErrCircuitOpen is returned by a circuit breaker wrapped Tiler while
+	// the circuit is open
+	ErrCircuitOpen = errors.New("provider: circuit open")
+	// ErrTileTooLarge is returned by a WithMaxTileBytes wrapped MVTTiler
+	// when the encoded tile exceeds the maximum size
+	ErrTileTooLarge = errors.New("provider: tile too large")
+	// ErrMemoryExceeded is returned by a WithMemoryBudget wrapped Tiler
+	// when the estimated memory of a tile's features exceeds the budget
+	ErrMemoryExceeded = errors.New("provider: memory budget exceeded")
+	// ErrRateLimited is returned by a WithPerClientRateLimit wrapped Tiler
+	// when the client has exceeded its rate limit
+	ErrRateLimited = errors.New("provider: rate limited")
 )
 
 type ErrUnableToConvertFeatureID struct {
@@ -49,3 +69,188 @@ func (err ErrUnknownProvider) Error() string {
 	}
 	return errStr.String()
 }
+
+// ErrInvalidTilePath is returned when a tile request path can not be parsed
+type ErrInvalidTilePath struct {
+	Path   string
+	Reason string
+}
+
+func (err ErrInvalidTilePath) Error() string {
+	return fmt.Sprintf("invalid tile path (%v): %v", err.Path, err.Reason)
+}
+
+// ErrInvalidConfig is returned when a provider's config map
+// could not be decoded into the provider's typed config
+type ErrInvalidConfig struct {
+	Name string
+	Err  error
+}
+
+func (err ErrInvalidConfig) Unwrap() error { return err.Err }
+func (err ErrInvalidConfig) Error() string {
+	return fmt.Sprintf("invalid config for provider %s: %v", err.Name, err.Err)
+}
+
+// ErrLazyInit is returned by a provider created with ForLazy while the
+// underlying provider is failing to initialize
+type ErrLazyInit struct {
+	Name string
+	Err  error
+}
+
+func (err ErrLazyInit) Unwrap() error { return err.Err }
+func (err ErrLazyInit) Error() string {
+	return fmt.Sprintf("provider %s failed to initialize: %v", err.Name, err.Err)
+}
+
+// ErrNullGeometry is returned by a Tiler wrapped with WithNullGeometryPolicy
+// and the NullGeomError policy when a feature has a nil geometry
+type ErrNullGeometry struct {
+	Layer string
+	ID    uint64
+}
+
+func (err ErrNullGeometry) Error() string {
+	return fmt.Sprintf("layer (%v) feature %v has a null geometry", err.Layer, err.ID)
+}
+
+// ErrSRIDAssertion is returned by a Tiler wrapped with WithSRIDAssertion when
+// a feature's geometry falls outside of the valid bounds of the SRID
+type ErrSRIDAssertion struct {
+	Layer string
+	ID    uint64
+	SRID  uint64
+	// Extent of the feature's geometry as minx, miny, maxx, maxy
+	Extent [4]float64
+}
+
+func (err ErrSRIDAssertion) Error() string {
+	return fmt.Sprintf("layer (%v) feature %v has an extent %v outside of the bounds of SRID %v", err.Layer, err.ID, err.Extent, err.SRID)
+}
+
+// ErrInvalidWKT is returned by TileFromWKT when the WKT can not be parsed
+type ErrInvalidWKT struct {
+	WKT    string
+	Reason string
+}
+
+func (err ErrInvalidWKT) Error() string {
+	return fmt.Sprintf("invalid wkt (%v): %v", err.WKT, err.Reason)
+}
+
+// ErrTooManyLayers is returned by LayerLimit when a tile is requested with
+// more layers than MaxLayersPerTile
+type ErrTooManyLayers struct {
+	Count int
+	Max   int
+}
+
+func (err ErrTooManyLayers) Error() string {
+	return fmt.Sprintf("tile requested with %v layers, exceeds the maximum of %v", err.Count, err.Max)
+}
+
+// ErrInitTimeout is returned by InitAll when a provider does not initialize
+// within the timeout
+type ErrInitTimeout struct {
+	Name    string
+	Timeout time.Duration
+}
+
+func (err ErrInitTimeout) Error() string {
+	return fmt.Sprintf("provider %s did not initialize within %v", err.Name, err.Timeout)
+}
+
+// ErrInitAll is returned by InitAll when one or more providers failed to
+// initialize, it holds the error of each provider keyed by name
+type ErrInitAll map[string]error
+
+func (err ErrInitAll) Error() string {
+	names := make([]string, 0, len(err))
+	for name := range err {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	errs := make([]string, len(names))
+	for i, name := range names {
+		errs[i] = fmt.Sprintf("%v: %v", name, err[name])
+	}
+	return fmt.Sprintf("%v providers failed to initialize: %v", len(err), strings.Join(errs, "; "))
+}
+
+// ErrPropertyType is returned by a Tiler wrapped with WithPropertyTypeCheck
+// and the PropTypeError policy when a property value can not be encoded
+// into a MVT
+type ErrPropertyType struct {
+	Layer string
+	ID    uint64
+	Key   string
+	Value interface{}
+}
+
+func (err ErrPropertyType) Error() string {
+	return fmt.Sprintf("layer (%v) feature %v property (%v) has an unsupported value of type %T", err.Layer, err.ID, err.Key, err.Value)
+}
+
+// ErrConfigSchemaVersion is returned when a provider's config can not be
+// migrated to the provider's current schema version, see
+// RegisterConfigMigration
+type ErrConfigSchemaVersion struct {
+	Name    string
+	Version int
+	Current int
+}
+
+func (err ErrConfigSchemaVersion) Error() string {
+	if err.Version > err.Current {
+		return fmt.Sprintf("provider %v config schema_version %v is newer than the supported version %v", err.Name, err.Version, err.Current)
+	}
+	return fmt.Sprintf("provider %v config schema_version %v is unknown, unable to migrate to version %v", err.Name, err.Version, err.Current)
+}
+
+// ErrQueryTimeout is returned by a Tiler wrapped with WithQueryTimeouts when
+// a layer's query exceeds its timeout
+type ErrQueryTimeout struct {
+	Layer   string
+	Timeout time.Duration
+}
+
+func (err ErrQueryTimeout) Unwrap() error { return context.DeadlineExceeded }
+func (err ErrQueryTimeout) Error() string {
+	return fmt.Sprintf("layer (%v) query exceeded its timeout of %v", err.Layer, err.Timeout)
+}
+
+// ErrPropertySchema is returned by a Tiler wrapped with WithPropertySchema
+// and the ViolationError mode when a feature violates the schema
+type ErrPropertySchema struct {
+	Layer      string
+	ID         uint64
+	Violations []PropertyViolation
+}
+
+func (err ErrPropertySchema) Error() string {
+	return fmt.Sprintf("layer (%v) feature %v violates the property schema: %v", err.Layer, err.ID, err.Violations)
+}
+
+// ErrConfigParse is returned by ForReader when the provider's config can not
+// be decoded
+type ErrConfigParse struct {
+	Format string
+	// Line and Column of the error, 0 if unknown
+	Line   int
+	Column int
+	Err    error
+}
+
+func (err ErrConfigParse) Unwrap() error { return err.Err }
+func (err ErrConfigParse) Error() string {
+	switch {
+	case err.Line > 0 && err.Column > 0:
+		return fmt.Sprintf("provider config (%v) line %v column %v: %v", err.Format, err.Line, err.Column, err.Err)
+	case err.Line > 0:
+		return fmt.Sprintf("provider config (%v) line %v: %v", err.Format, err.Line, err.Err)
+	default:
+		return fmt.Sprintf("provider config (%v): %v", err.Format, err.Err)
+	}
+}
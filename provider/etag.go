@@ -0,0 +1,24 @@
+package provider
+
+import "context"
+
+// TileETagger is implemented by providers which are able to compute a version
+// tag for the features of a layer and tile without fetching them, i.e. from
+// the number of features and the latest update time in the tile's extent.
+// The tag must change whenever the features of the tile change, and should
+// be cheap to compute compared to TileFeatures.
+type TileETagger interface {
+	TileETag(ctx context.Context, layer string, t Tile) (string, error)
+}
+
+// TileETag returns the provider's version tag for the layer and tile, if the
+// Tiler implements TileETagger. Otherwise ErrUnsupported is returned and the
+// caller should render the tile to determine if it has changed.
+func TileETag(ctx context.Context, t Tiler, layer string, tile Tile) (string, error) {
+	te, release, ok := acquireAs[TileETagger](t)
+	if !ok {
+		return "", ErrUnsupported
+	}
+	defer release()
+	return te.TileETag(ctx, layer, tile)
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/go-spatial/geom"
+)
+
+// ExplodeIDMultiplier is used to derive the IDs of the features exploded from
+// a multi geometry feature. The ID of each part is:
+//
+//	id*ExplodeIDMultiplier + index
+//
+// where index is the position of the part in the multi geometry. IDs will
+// collide if a multi geometry has more than ExplodeIDMultiplier parts, or if
+// the derived IDs overlap with the IDs of other features in the layer (i.e. a
+// layer with a feature 1 exploded into 1000 and a feature 1000 that is not a
+// multi geometry). IDs which would overflow a uint64 wrap around.
+const ExplodeIDMultiplier = 1000
+
+// WithExplode wraps the Tiler so that multi geometry features (multi points,
+// multi lines and multi polygons) are passed to the callback as one feature
+// per constituent geometry. Each part gets a copy of the feature's properties
+// and an ID derived as documented on ExplodeIDMultiplier. All other features,
+// including empty multi geometries, are passed through unchanged.
+func WithExplode(t Tiler) Tiler {
+	return &explodeTiler{Tiler: t}
+}
+
+type explodeTiler struct {
+	Tiler
+}
+
+func (et *explodeTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	return et.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		parts := explodeGeometry(f.Geometry)
+		if len(parts) == 0 {
+			return fn(f)
+		}
+
+		for i, part := range parts {
+			tags := make(map[string]interface{}, len(f.Tags))
+			for k, v := range f.Tags {
+				tags[k] = v
+			}
+
+			err := fn(&Feature{
+				ID:       f.ID*ExplodeIDMultiplier + uint64(i),
+				Geometry: part,
+				SRID:     f.SRID,
+				Tags:     tags,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// explodeGeometry returns the parts of a multi geometry, or nil if the
+// geometry is not a multi geometry. An empty multi geometry has no parts.
+func explodeGeometry(g geom.Geometry) []geom.Geometry {
+	var parts []geom.Geometry
+	switch gg := g.(type) {
+	case geom.MultiPoint:
+		parts = make([]geom.Geometry, len(gg))
+		for i := range gg {
+			parts[i] = geom.Point(gg[i])
+		}
+	case geom.MultiLineString:
+		parts = make([]geom.Geometry, len(gg))
+		for i := range gg {
+			parts[i] = geom.LineString(gg[i])
+		}
+	case geom.MultiPolygon:
+		parts = make([]geom.Geometry, len(gg))
+		for i := range gg {
+			parts[i] = geom.Polygon(gg[i])
+		}
+	}
+	return parts
+}
@@ -0,0 +1,51 @@
+package provider_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithExplode(t *testing.T) {
+	tiler := featuresTiler{
+		features: []provider.Feature{
+			{
+				ID: 5,
+				Geometry: geom.MultiLineString{
+					{{0, 0}, {1, 1}},
+					{{2, 2}, {3, 3}},
+					{{4, 4}, {5, 5}},
+				},
+				Tags: map[string]interface{}{"class": "river"},
+			},
+			{
+				ID:       6,
+				Geometry: geom.LineString{{0, 0}, {1, 1}},
+			},
+			{
+				ID:       7,
+				Geometry: geom.MultiPolygon{},
+				Tags:     map[string]interface{}{"class": "lake"},
+			},
+		},
+	}
+
+	features, err := collect(provider.WithExplode(tiler), "", provider.NewTile(0, 0, 0, 0, 3857))
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+
+	expected := []provider.Feature{
+		{ID: 5000, Geometry: geom.LineString{{0, 0}, {1, 1}}, Tags: map[string]interface{}{"class": "river"}},
+		{ID: 5001, Geometry: geom.LineString{{2, 2}, {3, 3}}, Tags: map[string]interface{}{"class": "river"}},
+		{ID: 5002, Geometry: geom.LineString{{4, 4}, {5, 5}}, Tags: map[string]interface{}{"class": "river"}},
+		{ID: 6, Geometry: geom.LineString{{0, 0}, {1, 1}}},
+		// an empty multi geometry is passed through, not dropped
+		{ID: 7, Geometry: geom.MultiPolygon{}, Tags: map[string]interface{}{"class": "lake"}},
+	}
+	if !reflect.DeepEqual(features, expected) {
+		t.Errorf("features, expected %v got %v", expected, features)
+	}
+}
@@ -0,0 +1,131 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-spatial/tegola/internal/log"
+)
+
+var (
+	// FailoverThreshold is the number of consecutive primary failures after
+	// which a Failover Tiler stops trying the primary and goes directly to
+	// the standby.
+	FailoverThreshold = 3
+	// FailoverCooldown is how long a tripped Failover Tiler will go directly
+	// to the standby before trying the primary again.
+	FailoverCooldown = 30 * time.Second
+)
+
+// Failover returns a Tiler which uses the primary Tiler, and falls back to the
+// standby Tiler when the primary fails. isHealthy is called with the error
+// returned by the primary, if it returns false the request is retried on the
+// standby within the same call. A nil isHealthy treats every error as unhealthy.
+// Context cancellations and errors returned by the callback never fail over.
+//
+// Failover only occurs if the primary has not yet passed any features to the
+// callback, as the callback would otherwise see the features twice.
+//
+// After FailoverThreshold consecutive failures, requests are sent directly to
+// the standby for FailoverCooldown, after which the primary is tried again.
+func Failover(primary, standby Tiler, isHealthy func(error) bool) Tiler {
+	if isHealthy == nil {
+		isHealthy = func(error) bool { return false }
+	}
+	return &failoverTiler{
+		primary:   primary,
+		standby:   standby,
+		isHealthy: isHealthy,
+		threshold: FailoverThreshold,
+		cooldown:  FailoverCooldown,
+	}
+}
+
+type failoverTiler struct {
+	primary   Tiler
+	standby   Tiler
+	isHealthy func(error) bool
+	threshold int
+	cooldown  time.Duration
+
+	lock     sync.Mutex
+	failures int
+	// trippedAt is the time the primary was last tripped
+	trippedAt time.Time
+}
+
+// usePrimary reports if the primary should be tried
+func (ft *failoverTiler) usePrimary() bool {
+	ft.lock.Lock()
+	defer ft.lock.Unlock()
+	if ft.failures < ft.threshold {
+		return true
+	}
+	return time.Since(ft.trippedAt) >= ft.cooldown
+}
+
+// report records the outcome of a request to the primary
+func (ft *failoverTiler) report(healthy bool) {
+	ft.lock.Lock()
+	defer ft.lock.Unlock()
+	if healthy {
+		ft.failures = 0
+		return
+	}
+	ft.failures++
+	if ft.failures >= ft.threshold {
+		if ft.failures == ft.threshold {
+			log.Warnf("failover: primary failed %v consecutive times, using standby for %v", ft.failures, ft.cooldown)
+		}
+		ft.trippedAt = time.Now()
+	}
+}
+
+func (ft *failoverTiler) Layers() ([]LayerInfo, error) {
+	if ft.usePrimary() {
+		layers, err := ft.primary.Layers()
+		if err == nil {
+			return layers, nil
+		}
+		log.Warnf("failover: primary layers error: %v, using standby", err)
+	}
+	return ft.standby.Layers()
+}
+
+func (ft *failoverTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	if !ft.usePrimary() {
+		return ft.standby.TileFeatures(ctx, layer, t, fn)
+	}
+
+	var (
+		streamed bool
+		fnErr    error
+	)
+	err := ft.primary.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		streamed = true
+		fnErr = fn(f)
+		return fnErr
+	})
+
+	switch {
+	case err == nil, errors.Is(err, ErrNoFeatures):
+		ft.report(true)
+		return err
+	case fnErr != nil, errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return err
+	case ft.isHealthy(err):
+		ft.report(true)
+		return err
+	}
+
+	ft.report(false)
+	if streamed {
+		return err
+	}
+
+	z, x, y := t.ZXY()
+	log.Warnf("failover: primary error for layer (%v) tile %v/%v/%v: %v, using standby", layer, z, x, y, err)
+	return ft.standby.TileFeatures(ctx, layer, t, fn)
+}
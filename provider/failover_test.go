@@ -0,0 +1,67 @@
+package provider_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+// countingTiler counts the calls made to TileFeatures
+type countingTiler struct {
+	provider.Tiler
+	calls int
+}
+
+func (ct *countingTiler) TileFeatures(ctx context.Context, layer string, t provider.Tile, fn func(f *provider.Feature) error) error {
+	ct.calls++
+	return ct.Tiler.TileFeatures(ctx, layer, t, fn)
+}
+
+func TestFailover(t *testing.T) {
+	errDown := errors.New("database down")
+	primary := &countingTiler{Tiler: featuresTiler{err: errDown}}
+	standby := &countingTiler{Tiler: featuresTiler{
+		features: []provider.Feature{{ID: 1, Geometry: geom.Point{1, 1}}},
+	}}
+
+	tiler := provider.Failover(primary, standby, nil)
+	tile := provider.NewTile(0, 0, 0, 0, 3857)
+
+	for i := 0; i < provider.FailoverThreshold+2; i++ {
+		features, err := collect(tiler, "", tile)
+		if err != nil {
+			t.Errorf("request %v error, expected nil got %v", i, err)
+			return
+		}
+		if len(features) != 1 || features[0].ID != 1 {
+			t.Errorf("request %v features, expected standby feature got %v", i, features)
+			return
+		}
+	}
+
+	if primary.calls != provider.FailoverThreshold {
+		t.Errorf("primary calls, expected %v got %v", provider.FailoverThreshold, primary.calls)
+	}
+	if standby.calls != provider.FailoverThreshold+2 {
+		t.Errorf("standby calls, expected %v got %v", provider.FailoverThreshold+2, standby.calls)
+	}
+}
+
+func TestFailoverHealthyError(t *testing.T) {
+	errBadLayer := errors.New("bad layer")
+	primary := featuresTiler{err: errBadLayer}
+	standby := &countingTiler{Tiler: featuresTiler{}}
+
+	tiler := provider.Failover(primary, standby, func(err error) bool { return err == errBadLayer })
+
+	_, err := collect(tiler, "", provider.NewTile(0, 0, 0, 0, 3857))
+	if err != errBadLayer {
+		t.Errorf("error, expected %v got %v", errBadLayer, err)
+	}
+	if standby.calls != 0 {
+		t.Errorf("standby calls, expected 0 got %v", standby.calls)
+	}
+}
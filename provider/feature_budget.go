@@ -0,0 +1,174 @@
+package provider
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-spatial/tegola/internal/log"
+)
+
+// WithTileFeatureBudget wraps the Tiler so the layers of a tile share a total
+// budget of features, rather than each layer being capped on its own. The
+// budget is divided between the layers in proportion to their weight in
+// allocation, rounding down, and each call to TileFeatures passes at most
+// the layer's share of features to the callback. The remaining features of
+// the layer are dropped and their number logged.
+//
+// Layers without a weight, or with a weight <= 0, have no share of the
+// budget. A nil or empty allocation divides the budget evenly between the
+// layers returned by Layers.
+//
+// Budget a sparse layer leaves unused is spilled to the layers requested
+// after it when the calls to TileFeatures share a scope, see
+// WithFeatureBudgetScope, as the calls for a tile are otherwise unrelated.
+// The total number of features passed to the callbacks never exceeds the
+// budget. A total <= 0 returns t.
+func WithTileFeatureBudget(t Tiler, total int, allocation map[string]float64) Tiler {
+	if total <= 0 {
+		return t
+	}
+	weights := make(map[string]float64, len(allocation))
+	for layer, w := range allocation {
+		if w > 0 {
+			weights[layer] = w
+		}
+	}
+	return &featureBudgetTiler{
+		Tiler:   t,
+		total:   total,
+		weights: weights,
+		even:    len(allocation) == 0,
+	}
+}
+
+type featureBudgetTiler struct {
+	Tiler
+	total   int
+	weights map[string]float64
+	// even is set when the budget is divided evenly between the layers
+	even bool
+}
+
+// shares returns the share of the budget of each layer
+func (fbt *featureBudgetTiler) shares() (map[string]int, error) {
+	weights := fbt.weights
+	if fbt.even {
+		layers, err := fbt.Tiler.Layers()
+		if err != nil {
+			return nil, err
+		}
+		weights = make(map[string]float64, len(layers))
+		for _, l := range layers {
+			weights[l.Name()] = 1
+		}
+	}
+
+	var sum float64
+	for _, w := range weights {
+		sum += w
+	}
+	shares := make(map[string]int, len(weights))
+	for layer, w := range weights {
+		shares[layer] = int(float64(fbt.total) * w / sum)
+	}
+	return shares, nil
+}
+
+func (fbt *featureBudgetTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	shares, err := fbt.shares()
+	if err != nil {
+		return err
+	}
+
+	var (
+		limit = shares[layer]
+		pool  *budgetPool
+	)
+	if scope, ok := ctx.Value(featureBudgetScopeKey{}).(*featureBudgetScope); ok {
+		pool = scope.pool(fbt, t, shares)
+		limit += pool.take()
+	}
+
+	var used, dropped int
+	err = fbt.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		if used >= limit {
+			dropped++
+			return nil
+		}
+		used++
+		return fn(f)
+	})
+
+	if pool != nil {
+		pool.give(limit - used)
+	}
+	if dropped > 0 {
+		log.Infof("layer (%v) tile %v dropped %v features, exceeding the layer's feature budget of %v", layer, TileKey(t), dropped, limit)
+	}
+	return err
+}
+
+type featureBudgetScopeKey struct{}
+
+// featureBudgetScope holds the budget left unused by the layers of each tile
+// requested within the scope
+type featureBudgetScope struct {
+	sync.Mutex
+	pools map[featureBudgetPoolKey]*budgetPool
+}
+
+type featureBudgetPoolKey struct {
+	tiler *featureBudgetTiler
+	tile  string
+}
+
+// WithFeatureBudgetScope returns a copy of ctx in which the calls to
+// TileFeatures of a Tiler wrapped by WithTileFeatureBudget share their unused
+// budget, i.e. the calls encoding the layers of a tile. The budget of the
+// layers of a tile is spilled to the layers of the same tile only.
+func WithFeatureBudgetScope(ctx context.Context) context.Context {
+	return context.WithValue(ctx, featureBudgetScopeKey{}, &featureBudgetScope{
+		pools: make(map[featureBudgetPoolKey]*budgetPool),
+	})
+}
+
+// pool returns the pool of the tiler's tile, the budget left over from
+// rounding down the shares starts in the pool
+func (scope *featureBudgetScope) pool(fbt *featureBudgetTiler, t Tile, shares map[string]int) *budgetPool {
+	scope.Lock()
+	defer scope.Unlock()
+
+	key := featureBudgetPoolKey{tiler: fbt, tile: TileKey(t)}
+	pool, ok := scope.pools[key]
+	if !ok {
+		spare := fbt.total
+		for _, n := range shares {
+			spare -= n
+		}
+		pool = &budgetPool{spare: spare}
+		scope.pools[key] = pool
+	}
+	return pool
+}
+
+// budgetPool is the budget the layers of a tile left unused
+type budgetPool struct {
+	sync.Mutex
+	spare int
+}
+
+// take empties the pool, returning the budget it held
+func (pool *budgetPool) take() int {
+	pool.Lock()
+	defer pool.Unlock()
+	n := pool.spare
+	pool.spare = 0
+	return n
+}
+
+// give returns unused budget to the pool
+func (pool *budgetPool) give(n int) {
+	pool.Lock()
+	defer pool.Unlock()
+	pool.spare += n
+}
@@ -0,0 +1,113 @@
+package provider_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithTileFeatureBudget(t *testing.T) {
+	features := func(n int) []provider.Feature {
+		fs := make([]provider.Feature, n)
+		for i := range fs {
+			fs[i] = provider.Feature{ID: uint64(i + 1), Geometry: geom.Point{float64(i), 0}}
+		}
+		return fs
+	}
+	tiler := layersTiler{
+		"roads":     features(20),
+		"water":     features(1),
+		"buildings": features(20),
+	}
+
+	type tcase struct {
+		total      int
+		allocation map[string]float64
+		scoped     bool
+		// layers are requested in order
+		layers   []string
+		expected map[string]int
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		var (
+			ctx   = context.Background()
+			tile  = provider.NewTile(4, 8, 7, 0, 3857)
+			got   = make(map[string]int)
+			total int
+		)
+		if tc.scoped {
+			ctx = provider.WithFeatureBudgetScope(ctx)
+		}
+
+		budget := provider.WithTileFeatureBudget(tiler, tc.total, tc.allocation)
+		for _, layer := range tc.layers {
+			err := budget.TileFeatures(ctx, layer, tile, func(f *provider.Feature) error {
+				got[layer]++
+				total++
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("layer (%v) error, expected nil got %v", layer, err)
+			}
+		}
+
+		if total > tc.total {
+			t.Errorf("total, expected at most %v got %v", tc.total, total)
+		}
+		for _, layer := range tc.layers {
+			if got[layer] != tc.expected[layer] {
+				t.Errorf("layer (%v) features, expected %v got %v", layer, tc.expected[layer], got[layer])
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"weighted": {
+			total:      10,
+			allocation: map[string]float64{"roads": 3, "water": 1, "buildings": 1},
+			layers:     []string{"water", "roads", "buildings"},
+			expected:   map[string]int{"roads": 6, "water": 1, "buildings": 2},
+		},
+		"weighted spill": {
+			total:      10,
+			allocation: map[string]float64{"roads": 3, "water": 1, "buildings": 1},
+			scoped:     true,
+			layers:     []string{"water", "roads", "buildings"},
+			expected:   map[string]int{"roads": 7, "water": 1, "buildings": 2},
+		},
+		"spill to later layers only": {
+			total:      10,
+			allocation: map[string]float64{"roads": 3, "water": 1, "buildings": 1},
+			scoped:     true,
+			layers:     []string{"roads", "buildings", "water"},
+			expected:   map[string]int{"roads": 6, "water": 1, "buildings": 2},
+		},
+		"rounding spill": {
+			total:      10,
+			allocation: map[string]float64{"roads": 1, "water": 1, "buildings": 1},
+			scoped:     true,
+			layers:     []string{"roads", "buildings", "water"},
+			expected:   map[string]int{"roads": 4, "water": 1, "buildings": 3},
+		},
+		"unweighted layer": {
+			total:      10,
+			allocation: map[string]float64{"roads": 1},
+			layers:     []string{"roads", "buildings"},
+			expected:   map[string]int{"roads": 10, "buildings": 0},
+		},
+		"under budget": {
+			total:      100,
+			allocation: map[string]float64{"roads": 1, "buildings": 1},
+			layers:     []string{"roads", "buildings"},
+			expected:   map[string]int{"roads": 20, "buildings": 20},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola"
+	"github.com/go-spatial/tegola/basic"
+)
+
+// GeoJSON returns the feature encoded as a GeoJSON Feature object, with the
+// feature's ID and tags as the id and properties members.
+//
+// GeoJSON coordinates are WGS84, so WebMercator geometries are reprojected.
+// Geometries without an SRID are assumed to be WGS84 already. A nil or empty
+// geometry is encoded as a null geometry.
+func (f *Feature) GeoJSON() ([]byte, error) {
+	geo, err := f.wgs84Geometry()
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := geoJSONGeometry(geo)
+	if err != nil {
+		return nil, fmt.Errorf("feature %v: %w", f.ID, err)
+	}
+
+	return json.Marshal(struct {
+		Type       string                 `json:"type"`
+		ID         uint64                 `json:"id"`
+		Geometry   *geoJSONGeom           `json:"geometry"`
+		Properties map[string]interface{} `json:"properties"`
+	}{
+		Type:       "Feature",
+		ID:         f.ID,
+		Geometry:   g,
+		Properties: f.Tags,
+	})
+}
+
+// wgs84Geometry returns the feature's geometry in WGS84, reprojecting
+// WebMercator geometries. Geometries without an SRID are assumed to be
+// WGS84 already.
+func (f *Feature) wgs84Geometry() (geom.Geometry, error) {
+	geo := f.Geometry
+	if geo == nil || geom.IsEmpty(geo) || f.SRID == 0 || f.SRID == tegola.WGS84 {
+		return geo, nil
+	}
+	if f.SRID != tegola.WebMercator {
+		return nil, fmt.Errorf("feature %v: unable to transform geometry to WGS84 from SRID (%v)", f.ID, f.SRID)
+	}
+
+	geo, err := basic.FromWebMercator(tegola.WGS84, geo)
+	if err != nil {
+		return nil, fmt.Errorf("feature %v: %w", f.ID, err)
+	}
+	return geo, nil
+}
+
+// geoJSONGeom is a GeoJSON geometry object. Coordinates is used for all
+// types except GeometryCollection, which uses Geometries.
+type geoJSONGeom struct {
+	Type        string         `json:"type"`
+	Coordinates interface{}    `json:"coordinates,omitempty"`
+	Geometries  []*geoJSONGeom `json:"geometries,omitempty"`
+}
+
+// geoJSONGeometry converts g to a GeoJSON geometry object, a nil or empty
+// geometry returns nil
+func geoJSONGeometry(g geom.Geometry) (*geoJSONGeom, error) {
+	if g == nil || geom.IsEmpty(g) {
+		return nil, nil
+	}
+
+	switch gg := g.(type) {
+	case geom.Pointer:
+		return &geoJSONGeom{Type: "Point", Coordinates: gg.XY()}, nil
+	case geom.MultiPointer:
+		return &geoJSONGeom{Type: "MultiPoint", Coordinates: gg.Points()}, nil
+	case geom.LineStringer:
+		return &geoJSONGeom{Type: "LineString", Coordinates: gg.Verticies()}, nil
+	case geom.MultiLineStringer:
+		return &geoJSONGeom{Type: "MultiLineString", Coordinates: gg.LineStrings()}, nil
+	case geom.Polygoner:
+		return &geoJSONGeom{Type: "Polygon", Coordinates: closeRings(gg.LinearRings())}, nil
+	case geom.MultiPolygoner:
+		polys := gg.Polygons()
+		coords := make([][][][2]float64, len(polys))
+		for i := range polys {
+			coords[i] = closeRings(polys[i])
+		}
+		return &geoJSONGeom{Type: "MultiPolygon", Coordinates: coords}, nil
+	case geom.Collectioner:
+		col := &geoJSONGeom{Type: "GeometryCollection", Geometries: []*geoJSONGeom{}}
+		for _, cg := range gg.Geometries() {
+			gj, err := geoJSONGeometry(cg)
+			if err != nil {
+				return nil, err
+			}
+			if gj == nil {
+				continue
+			}
+			col.Geometries = append(col.Geometries, gj)
+		}
+		return col, nil
+	default:
+		return nil, geom.ErrUnknownGeometry{Geom: g}
+	}
+}
+
+// closeRings returns the rings with the first point repeated at the end, as
+// GeoJSON requires linear rings to be closed
+func closeRings(rings [][][2]float64) [][][2]float64 {
+	closed := make([][][2]float64, 0, len(rings))
+	for _, ring := range rings {
+		if len(ring) == 0 {
+			continue
+		}
+		if ring[0] != ring[len(ring)-1] {
+			ring = append(ring[:len(ring):len(ring)], ring[0])
+		}
+		closed = append(closed, ring)
+	}
+	return closed
+}
@@ -0,0 +1,84 @@
+package provider_test
+
+import (
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestFeatureGeoJSON(t *testing.T) {
+	type tcase struct {
+		feature  provider.Feature
+		expected string
+		err      bool
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		b, err := tc.feature.GeoJSON()
+		if tc.err {
+			if err == nil {
+				t.Errorf("error, expected error got nil")
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		if string(b) != tc.expected {
+			t.Errorf("geojson, expected %v got %v", tc.expected, string(b))
+		}
+	}
+
+	tests := map[string]tcase{
+		"point": {
+			feature:  provider.Feature{ID: 1, Geometry: geom.Point{1, 2}, Tags: map[string]interface{}{"name": "a"}},
+			expected: `{"type":"Feature","id":1,"geometry":{"type":"Point","coordinates":[1,2]},"properties":{"name":"a"}}`,
+		},
+		"multi point": {
+			feature:  provider.Feature{ID: 2, Geometry: geom.MultiPoint{{1, 2}, {3, 4}}},
+			expected: `{"type":"Feature","id":2,"geometry":{"type":"MultiPoint","coordinates":[[1,2],[3,4]]},"properties":null}`,
+		},
+		"line string": {
+			feature:  provider.Feature{ID: 3, Geometry: geom.LineString{{1, 2}, {3, 4}}},
+			expected: `{"type":"Feature","id":3,"geometry":{"type":"LineString","coordinates":[[1,2],[3,4]]},"properties":null}`,
+		},
+		"multi line string": {
+			feature:  provider.Feature{ID: 4, Geometry: geom.MultiLineString{{{1, 2}, {3, 4}}}},
+			expected: `{"type":"Feature","id":4,"geometry":{"type":"MultiLineString","coordinates":[[[1,2],[3,4]]]},"properties":null}`,
+		},
+		"polygon rings are closed": {
+			feature:  provider.Feature{ID: 5, Geometry: geom.Polygon{{{0, 0}, {1, 0}, {1, 1}}}},
+			expected: `{"type":"Feature","id":5,"geometry":{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,0]]]},"properties":null}`,
+		},
+		"multi polygon": {
+			feature:  provider.Feature{ID: 6, Geometry: geom.MultiPolygon{{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}}},
+			expected: `{"type":"Feature","id":6,"geometry":{"type":"MultiPolygon","coordinates":[[[[0,0],[1,0],[1,1],[0,0]]]]},"properties":null}`,
+		},
+		"collection": {
+			feature:  provider.Feature{ID: 7, Geometry: geom.Collection{geom.Point{1, 2}, geom.LineString{{1, 2}, {3, 4}}}},
+			expected: `{"type":"Feature","id":7,"geometry":{"type":"GeometryCollection","geometries":[{"type":"Point","coordinates":[1,2]},{"type":"LineString","coordinates":[[1,2],[3,4]]}]},"properties":null}`,
+		},
+		"nil geometry": {
+			feature:  provider.Feature{ID: 8, Tags: map[string]interface{}{"name": "a"}},
+			expected: `{"type":"Feature","id":8,"geometry":null,"properties":{"name":"a"}}`,
+		},
+		"empty geometry": {
+			feature:  provider.Feature{ID: 9, Geometry: geom.LineString{}},
+			expected: `{"type":"Feature","id":9,"geometry":null,"properties":null}`,
+		},
+		"webmercator": {
+			feature:  provider.Feature{ID: 10, SRID: 3857, Geometry: geom.Point{0, 0}},
+			expected: `{"type":"Feature","id":10,"geometry":{"type":"Point","coordinates":[0,0]},"properties":null}`,
+		},
+		"unsupported srid": {
+			feature: provider.Feature{ID: 11, SRID: 2163, Geometry: geom.Point{0, 0}},
+			err:     true,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
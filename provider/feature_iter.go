@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+)
+
+// FeatureIterator pulls the features of a tile one at a time
+type FeatureIterator interface {
+	// Next returns the next feature. Once there are no more features io.EOF
+	// is returned, or the error the provider failed with.
+	Next() (*Feature, error)
+	// Close stops the iteration and releases its resources. It must be
+	// called once the caller is done with the iterator, even if Next has
+	// not returned io.EOF.
+	Close()
+}
+
+// IterTiler is implemented by providers which are able to return the
+// features of a tile through a FeatureIterator natively, i.e. from a
+// database cursor.
+type IterTiler interface {
+	TileFeaturesIter(ctx context.Context, layer string, t Tile) (FeatureIterator, error)
+}
+
+// TileFeaturesIter returns an iterator over the features of the layer for
+// the tile, for callers which want to pull features rather than have them
+// pushed to a callback, i.e. to interleave them with other work. If the
+// Tiler implements IterTiler its iterator is used, otherwise TileFeatures is
+// adapted.
+//
+// The adapter runs TileFeatures in a goroutine which hands over one feature
+// at a time, so it does not read ahead of the caller. The goroutine blocks
+// until the caller asks for the next feature, so an iterator which is
+// abandoned without calling Close leaks its goroutine, and whatever the
+// provider holds open, until ctx is done. Close cancels the provider's
+// context, stops the callback with ErrCanceled and waits for TileFeatures
+// to return, so once Close returns nothing is left running.
+func TileFeaturesIter(ctx context.Context, t Tiler, layer string, tile Tile) (FeatureIterator, error) {
+	if it, release, ok := acquireAs[IterTiler](t); ok {
+		fi, err := it.TileFeaturesIter(ctx, layer, tile)
+		if err != nil {
+			release()
+			return nil, err
+		}
+		return &heldIter{FeatureIterator: fi, release: release}, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	it := &featureIter{
+		features: make(chan *Feature),
+		done:     make(chan struct{}),
+		cancel:   cancel,
+	}
+	go func() {
+		it.err = t.TileFeatures(ctx, layer, tile, func(f *Feature) error {
+			// the provider may reuse the feature once the callback returns
+			ff := *f
+			select {
+			case it.features <- &ff:
+				return nil
+			case <-it.done:
+				return ErrCanceled
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		// the error is read once the channel is closed
+		close(it.features)
+	}()
+	return it, nil
+}
+
+// featureIter adapts TileFeatures to a FeatureIterator
+type featureIter struct {
+	features chan *Feature
+	// err is the error TileFeatures returned, set before features is closed
+	err error
+
+	done      chan struct{}
+	closeOnce sync.Once
+	cancel    context.CancelFunc
+}
+
+func (it *featureIter) Next() (*Feature, error) {
+	// once closed the features channel is closed as well, done is checked
+	// first so a closed iterator always reports io.EOF
+	select {
+	case <-it.done:
+		return nil, io.EOF
+	default:
+	}
+
+	select {
+	case f, ok := <-it.features:
+		if ok {
+			return f, nil
+		}
+	case <-it.done:
+		return nil, io.EOF
+	}
+
+	if it.err == nil || errors.Is(it.err, ErrNoFeatures) {
+		return nil, io.EOF
+	}
+	return nil, it.err
+}
+
+func (it *featureIter) Close() {
+	it.closeOnce.Do(func() {
+		close(it.done)
+		it.cancel()
+		// wait for TileFeatures to return
+		for range it.features {
+		}
+	})
+}
+
+// heldIter releases the provider instance the iterator was returned by once
+// it is closed, see acquireAs
+type heldIter struct {
+	FeatureIterator
+	release   func()
+	closeOnce sync.Once
+}
+
+func (it *heldIter) Close() {
+	it.closeOnce.Do(func() {
+		it.FeatureIterator.Close()
+		it.release()
+	})
+}
@@ -0,0 +1,116 @@
+package provider_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+// returnedTiler records the error TileFeatures returned with
+type returnedTiler struct {
+	featuresTiler
+	returned chan error
+}
+
+func (rt returnedTiler) TileFeatures(ctx context.Context, layer string, t provider.Tile, fn func(f *provider.Feature) error) error {
+	err := rt.featuresTiler.TileFeatures(ctx, layer, t, fn)
+	rt.returned <- err
+	return err
+}
+
+func TestTileFeaturesIter(t *testing.T) {
+	features := []provider.Feature{
+		{ID: 1, Geometry: geom.Point{1, 1}},
+		{ID: 2, Geometry: geom.Point{2, 2}},
+		{ID: 3, Geometry: geom.Point{3, 3}},
+	}
+	errProvider := errors.New("provider failed")
+
+	type tcase struct {
+		err error
+		// take is the number of features read before closing, -1 reads them all
+		take     int
+		ids      []uint64
+		expected error
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		tiler := returnedTiler{
+			featuresTiler: featuresTiler{features: features, err: tc.err},
+			returned:      make(chan error, 1),
+		}
+		it, err := provider.TileFeaturesIter(context.Background(), tiler, "", provider.NewTile(0, 0, 0, 0, 3857))
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+
+		var ids []uint64
+		for tc.take < 0 || len(ids) < tc.take {
+			f, err := it.Next()
+			if err != nil {
+				if err != tc.expected {
+					t.Errorf("next error, expected %v got %v", tc.expected, err)
+				}
+				break
+			}
+			ids = append(ids, f.ID)
+		}
+		it.Close()
+
+		if !reflect.DeepEqual(ids, tc.ids) {
+			t.Errorf("features, expected %v got %v", tc.ids, ids)
+		}
+
+		// TileFeatures has returned once Close returns
+		select {
+		case err := <-tiler.returned:
+			// the callback is stopped, or the provider sees its context canceled
+			if tc.take >= 0 && tc.take < len(features) && err != provider.ErrCanceled && err != context.Canceled {
+				t.Errorf("returned, expected %v got %v", provider.ErrCanceled, err)
+			}
+		default:
+			t.Errorf("returned, expected TileFeatures to have returned")
+		}
+
+		if _, err := it.Next(); err != io.EOF {
+			t.Errorf("next after close, expected %v got %v", io.EOF, err)
+		}
+	}
+
+	tests := map[string]tcase{
+		"all": {
+			take:     -1,
+			ids:      []uint64{1, 2, 3},
+			expected: io.EOF,
+		},
+		"no features": {
+			err:      provider.ErrNoFeatures,
+			take:     -1,
+			ids:      []uint64{1, 2, 3},
+			expected: io.EOF,
+		},
+		"provider error": {
+			err:      errProvider,
+			take:     -1,
+			ids:      []uint64{1, 2, 3},
+			expected: errProvider,
+		},
+		"closed early": {
+			take: 1,
+			ids:  []uint64{1},
+		},
+		"closed before reading": {
+			take: 0,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
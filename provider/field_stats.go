@@ -0,0 +1,33 @@
+package provider
+
+import "context"
+
+// FieldStat holds statistics of a layer's field, i.e. to decide which fields
+// are worth including in tiles and which are near constant. The statistics
+// may be estimates, i.e. those gathered by a database's planner.
+type FieldStat struct {
+	// Distinct is the number of distinct non-null values
+	Distinct float64
+	// NullFraction is the fraction of features where the field is null
+	NullFraction float64
+	// Min and Max are the range of a numeric field. They are nil for
+	// non-numeric fields or when the range is not known.
+	Min, Max *float64
+}
+
+// FieldStatter is implemented by providers which are able to report
+// statistics of the fields of a layer, keyed by field name.
+type FieldStatter interface {
+	FieldStats(ctx context.Context, layer string) (map[string]FieldStat, error)
+}
+
+// FieldStats returns the statistics of the fields of the layer, if the Tiler
+// implements FieldStatter. Otherwise ErrUnsupported is returned.
+func FieldStats(ctx context.Context, t Tiler, layer string) (map[string]FieldStat, error) {
+	fs, release, ok := acquireAs[FieldStatter](t)
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	defer release()
+	return fs.FieldStats(ctx, layer)
+}
@@ -0,0 +1,247 @@
+package flatgeobuf
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/go-spatial/geom"
+)
+
+// feature table fields
+// ref: https://github.com/flatgeobuf/flatgeobuf/blob/master/src/fbs/feature.fbs
+const (
+	featureGeometry = iota
+	featureProperties
+	featureColumns
+)
+
+// geometry table fields
+const (
+	geometryEnds = iota
+	geometryXY
+	geometryZ
+	geometryM
+	geometryT
+	geometryTM
+	geometryType
+	geometryParts
+)
+
+// maxGeometryDepth limits the nesting of geometry parts
+const maxGeometryDepth = 8
+
+// decodeFeature decodes the geometry and properties of a feature. columns
+// are the header's columns, which are used unless the feature has its own.
+func decodeFeature(buf []byte, geomType uint8, columns []column) (geom.Geometry, map[string]interface{}, error) {
+	fb := &fbReader{buf: buf}
+	tbl := fb.root()
+
+	var (
+		geo geom.Geometry
+		err error
+	)
+	if gtbl, ok := tbl.table(featureGeometry); ok {
+		if geo, err = decodeGeometry(gtbl, geomType, 0); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if cols := readColumns(tbl.tables(featureColumns)); cols != nil {
+		columns = cols
+	}
+	tags, err := decodeProperties(tbl.byteVector(featureProperties), columns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if fb.err != nil {
+		return nil, nil, fb.err
+	}
+	return geo, tags, nil
+}
+
+// decodeGeometry decodes the geometry table. Only the x and y values are
+// decoded. If typ is unknown the geometry's own type is used.
+func decodeGeometry(tbl fbTable, typ uint8, depth int) (geom.Geometry, error) {
+	if depth > maxGeometryDepth {
+		return nil, fmt.Errorf("flatgeobuf: geometry nested deeper than %v", maxGeometryDepth)
+	}
+	if typ == geomTypeUnknown {
+		typ = tbl.u8(geometryType, geomTypeUnknown)
+	}
+
+	xy := tbl.f64s(geometryXY)
+	pts := make([][2]float64, len(xy)/2)
+	for i := range pts {
+		pts[i] = [2]float64{xy[i*2], xy[i*2+1]}
+	}
+
+	switch typ {
+	case geomTypePoint:
+		if len(pts) == 0 {
+			return nil, nil
+		}
+		return geom.Point(pts[0]), nil
+	case geomTypeMultiPoint:
+		return geom.MultiPoint(pts), nil
+	case geomTypeLineString:
+		return geom.LineString(pts), nil
+	case geomTypeMultiLineString:
+		var lines geom.MultiLineString
+		for _, line := range splitEnds(pts, tbl.u32s(geometryEnds)) {
+			lines = append(lines, line)
+		}
+		return lines, nil
+	case geomTypePolygon:
+		return decodePolygon(pts, tbl.u32s(geometryEnds)), nil
+	case geomTypeMultiPolygon:
+		var mp geom.MultiPolygon
+		for _, part := range tbl.tables(geometryParts) {
+			xy := part.f64s(geometryXY)
+			pts := make([][2]float64, len(xy)/2)
+			for i := range pts {
+				pts[i] = [2]float64{xy[i*2], xy[i*2+1]}
+			}
+			mp = append(mp, decodePolygon(pts, part.u32s(geometryEnds)))
+		}
+		return mp, nil
+	case geomTypeGeometryCollection:
+		var col geom.Collection
+		for _, part := range tbl.tables(geometryParts) {
+			g, err := decodeGeometry(part, geomTypeUnknown, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			if g != nil {
+				col = append(col, g)
+			}
+		}
+		return col, nil
+	default:
+		// curves, surfaces etc. are not supported
+		return nil, nil
+	}
+}
+
+// splitEnds splits the points at the end indices, with no ends
+// all the points are a single part
+func splitEnds(pts [][2]float64, ends []uint32) [][][2]float64 {
+	if len(ends) == 0 {
+		if len(pts) == 0 {
+			return nil
+		}
+		return [][][2]float64{pts}
+	}
+
+	parts := make([][][2]float64, 0, len(ends))
+	var start uint32
+	for _, end := range ends {
+		if end < start || int(end) > len(pts) {
+			break
+		}
+		parts = append(parts, pts[start:end])
+		start = end
+	}
+	return parts
+}
+
+// decodePolygon splits the points into rings, removing the closing
+// point of each ring as the geom package's polygons are not closed
+func decodePolygon(pts [][2]float64, ends []uint32) geom.Polygon {
+	rings := splitEnds(pts, ends)
+	poly := make(geom.Polygon, 0, len(rings))
+	for _, ring := range rings {
+		if n := len(ring); n > 1 && ring[0] == ring[n-1] {
+			ring = ring[:n-1]
+		}
+		poly = append(poly, ring)
+	}
+	return poly
+}
+
+// decodeProperties decodes the feature's properties, which are a sequence of
+// a column index followed by the value, encoded based on the column type
+func decodeProperties(b []byte, columns []column) (map[string]interface{}, error) {
+	tags := make(map[string]interface{})
+	le := binary.LittleEndian
+
+	for len(b) > 0 {
+		if len(b) < 2 {
+			return nil, ErrInvalidFlatBuffer
+		}
+		idx := int(le.Uint16(b))
+		b = b[2:]
+		if idx >= len(columns) {
+			return nil, fmt.Errorf("flatgeobuf: property column index (%v) out of range", idx)
+		}
+		col := columns[idx]
+
+		size := columnSize(col.typ)
+		if size < 0 {
+			return nil, fmt.Errorf("flatgeobuf: unsupported column type (%v) for column (%v)", col.typ, col.name)
+		}
+		if size == 0 {
+			// variable length values are prefixed with their length
+			if len(b) < 4 {
+				return nil, ErrInvalidFlatBuffer
+			}
+			size = 4 + int(le.Uint32(b))
+		}
+		if len(b) < size {
+			return nil, ErrInvalidFlatBuffer
+		}
+		v := b[:size]
+		b = b[size:]
+
+		switch col.typ {
+		case colTypeByte:
+			tags[col.name] = int8(v[0])
+		case colTypeUByte:
+			tags[col.name] = v[0]
+		case colTypeBool:
+			tags[col.name] = v[0] != 0
+		case colTypeShort:
+			tags[col.name] = int16(le.Uint16(v))
+		case colTypeUShort:
+			tags[col.name] = le.Uint16(v)
+		case colTypeInt:
+			tags[col.name] = int32(le.Uint32(v))
+		case colTypeUInt:
+			tags[col.name] = le.Uint32(v)
+		case colTypeLong:
+			tags[col.name] = int64(le.Uint64(v))
+		case colTypeULong:
+			tags[col.name] = le.Uint64(v)
+		case colTypeFloat:
+			tags[col.name] = math.Float32frombits(le.Uint32(v))
+		case colTypeDouble:
+			tags[col.name] = math.Float64frombits(le.Uint64(v))
+		case colTypeString, colTypeJSON, colTypeDateTime:
+			tags[col.name] = string(v[4:])
+		case colTypeBinary:
+			// binary values can not be encoded in a MVT
+		}
+	}
+
+	return tags, nil
+}
+
+// columnSize returns the size of fixed size values, 0 is returned
+// for variable length values and -1 for unknown types
+func columnSize(typ uint8) int {
+	switch typ {
+	case colTypeByte, colTypeUByte, colTypeBool:
+		return 1
+	case colTypeShort, colTypeUShort:
+		return 2
+	case colTypeInt, colTypeUInt, colTypeFloat:
+		return 4
+	case colTypeLong, colTypeULong, colTypeDouble:
+		return 8
+	case colTypeString, colTypeJSON, colTypeDateTime, colTypeBinary:
+		return 0
+	default:
+		return -1
+	}
+}
@@ -0,0 +1,207 @@
+package flatgeobuf
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// ErrInvalidFlatBuffer is returned when a flatbuffer references data
+// outside of its buffer
+var ErrInvalidFlatBuffer = errors.New("flatgeobuf: invalid flatbuffer")
+
+// fbReader reads flatbuffers tables. Rather than check the error of every
+// read, reads out of range return zero values and record ErrInvalidFlatBuffer,
+// which is checked once decoding is complete.
+//
+// ref: https://flatbuffers.dev/flatbuffers_internals.html
+type fbReader struct {
+	buf []byte
+	err error
+}
+
+func (r *fbReader) bytes(pos, n int) []byte {
+	if pos < 0 || n < 0 || pos+n > len(r.buf) {
+		r.err = ErrInvalidFlatBuffer
+		return nil
+	}
+	return r.buf[pos : pos+n]
+}
+
+func (r *fbReader) u8(pos int) uint8 {
+	if b := r.bytes(pos, 1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *fbReader) u16(pos int) uint16 {
+	if b := r.bytes(pos, 2); b != nil {
+		return binary.LittleEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *fbReader) u32(pos int) uint32 {
+	if b := r.bytes(pos, 4); b != nil {
+		return binary.LittleEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *fbReader) u64(pos int) uint64 {
+	if b := r.bytes(pos, 8); b != nil {
+		return binary.LittleEndian.Uint64(b)
+	}
+	return 0
+}
+
+// root returns the root table of the buffer
+func (r *fbReader) root() fbTable {
+	return r.table(int(r.u32(0)))
+}
+
+// table returns the table at pos
+func (r *fbReader) table(pos int) fbTable {
+	vtable := pos - int(int32(r.u32(pos)))
+	return fbTable{
+		r:      r,
+		pos:    pos,
+		vtable: vtable,
+		vsize:  int(r.u16(vtable)),
+	}
+}
+
+// fbTable is a flatbuffers table, fields are referenced by their index in the schema
+type fbTable struct {
+	r      *fbReader
+	pos    int
+	vtable int
+	vsize  int
+}
+
+// field returns the position of the field's value, or 0 if it is not set
+func (t fbTable) field(i int) int {
+	o := 4 + 2*i
+	if o+2 > t.vsize {
+		return 0
+	}
+	off := int(t.r.u16(t.vtable + o))
+	if off == 0 {
+		return 0
+	}
+	return t.pos + off
+}
+
+func (t fbTable) u8(i int, def uint8) uint8 {
+	if pos := t.field(i); pos != 0 {
+		return t.r.u8(pos)
+	}
+	return def
+}
+
+func (t fbTable) bool(i int, def bool) bool {
+	if pos := t.field(i); pos != 0 {
+		return t.r.u8(pos) != 0
+	}
+	return def
+}
+
+func (t fbTable) u16(i int, def uint16) uint16 {
+	if pos := t.field(i); pos != 0 {
+		return t.r.u16(pos)
+	}
+	return def
+}
+
+func (t fbTable) i32(i int, def int32) int32 {
+	if pos := t.field(i); pos != 0 {
+		return int32(t.r.u32(pos))
+	}
+	return def
+}
+
+func (t fbTable) u64(i int, def uint64) uint64 {
+	if pos := t.field(i); pos != 0 {
+		return t.r.u64(pos)
+	}
+	return def
+}
+
+// indirect returns the position of the object referenced by the field
+func (t fbTable) indirect(i int) (int, bool) {
+	pos := t.field(i)
+	if pos == 0 {
+		return 0, false
+	}
+	return pos + int(t.r.u32(pos)), true
+}
+
+// vector returns the position of the first element and the length of the
+// vector referenced by the field
+func (t fbTable) vector(i int) (pos, n int) {
+	pos, ok := t.indirect(i)
+	if !ok {
+		return 0, 0
+	}
+	return pos + 4, int(t.r.u32(pos))
+}
+
+func (t fbTable) string(i int) string {
+	return string(t.byteVector(i))
+}
+
+func (t fbTable) byteVector(i int) []byte {
+	pos, n := t.vector(i)
+	if n == 0 {
+		return nil
+	}
+	return t.r.bytes(pos, n)
+}
+
+func (t fbTable) table(i int) (fbTable, bool) {
+	pos, ok := t.indirect(i)
+	if !ok {
+		return fbTable{}, false
+	}
+	return t.r.table(pos), true
+}
+
+func (t fbTable) tables(i int) []fbTable {
+	pos, n := t.vector(i)
+	if t.r.bytes(pos, n*4) == nil {
+		return nil
+	}
+	tables := make([]fbTable, n)
+	for j := range tables {
+		p := pos + j*4
+		tables[j] = t.r.table(p + int(t.r.u32(p)))
+	}
+	return tables
+}
+
+func (t fbTable) u32s(i int) []uint32 {
+	pos, n := t.vector(i)
+	b := t.r.bytes(pos, n*4)
+	if b == nil {
+		return nil
+	}
+	vals := make([]uint32, n)
+	for j := range vals {
+		vals[j] = binary.LittleEndian.Uint32(b[j*4:])
+	}
+	return vals
+}
+
+func (t fbTable) f64s(i int) []float64 {
+	pos, n := t.vector(i)
+	b := t.r.bytes(pos, n*8)
+	if b == nil {
+		return nil
+	}
+	vals := make([]float64, n)
+	for j := range vals {
+		vals[j] = math.Float64frombits(binary.LittleEndian.Uint64(b[j*8:]))
+	}
+	return vals
+}
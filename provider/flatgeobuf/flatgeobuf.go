@@ -0,0 +1,365 @@
+// Package flatgeobuf provides a provider for FlatGeobuf files, using the
+// file's packed Hilbert R-tree index to read only the features of a tile.
+//
+// ref: https://flatgeobuf.org
+package flatgeobuf
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola"
+	"github.com/go-spatial/tegola/basic"
+	"github.com/go-spatial/tegola/dict"
+	"github.com/go-spatial/tegola/internal/log"
+	"github.com/go-spatial/tegola/provider"
+)
+
+const (
+	Name = "flatgeobuf"
+	// DefaultSRID is used when the file does not specify an EPSG CRS
+	DefaultSRID = tegola.WGS84
+)
+
+// config keys
+const (
+	ConfigKeyFilePath    = "filepath"
+	ConfigKeyLayerName   = "layer_name"
+	ConfigKeyGeomIDField = "id_fieldname"
+	ConfigKeySRID        = "srid"
+)
+
+// maxFeatureSize guards against allocating for a corrupt feature size
+const maxFeatureSize = 256 * 1024 * 1024
+
+func init() {
+	provider.Register(Name, NewTileProvider, Cleanup)
+}
+
+// Layer is the single layer of a FlatGeobuf file
+type Layer struct {
+	name     string
+	geomType geom.Geometry
+	srid     uint64
+	fields   []string
+}
+
+func (l Layer) Name() string            { return l.name }
+func (l Layer) GeomType() geom.Geometry { return l.geomType }
+func (l Layer) SRID() uint64            { return l.srid }
+
+// Fields returns the names of the columns of the file
+func (l Layer) Fields() []string { return l.fields }
+
+type Provider struct {
+	// path to the FlatGeobuf file
+	Filepath string
+
+	file        *os.File
+	header      *header
+	index       *packedRTree
+	layer       Layer
+	idFieldname string
+	// offset of the first feature in the file
+	featuresStart int64
+}
+
+// NewTileProvider instantiates and returns a new FlatGeobuf provider or an error.
+// The file's header and index are read when the provider is created. This Provider
+// supports the following fields in the provided config:
+//
+//	filepath (string): [Required] the path to the FlatGeobuf file
+//	layer_name (string): [Optional] the name of the layer. Defaults to the name in the file's header, or the file name without its extension
+//	id_fieldname (string): [Optional] the column to use as the feature id. Defaults to the position of the feature in the file
+//	srid (int): [Optional] the SRID of the file. Defaults to the file's EPSG CRS, otherwise 4326. Supports 3857 (WebMercator) or 4326 (WGS84).
+func NewTileProvider(config dict.Dicter) (provider.Tiler, error) {
+	path, err := config.String(ConfigKeyFilePath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := newProvider(f, config)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("flatgeobuf (%v): %w", path, err)
+	}
+	p.Filepath = path
+
+	providersLock.Lock()
+	providers = append(providers, p)
+	providersLock.Unlock()
+
+	return p, nil
+}
+
+func newProvider(f *os.File, config dict.Dicter) (*Provider, error) {
+	h, err := readHeader(f)
+	if err != nil {
+		return nil, err
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	index, err := readIndex(f, fi.Size(), h)
+	if err != nil {
+		return nil, err
+	}
+
+	name := h.name
+	if name == "" {
+		base := filepath.Base(f.Name())
+		name = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	if name, err = config.String(ConfigKeyLayerName, &name); err != nil {
+		return nil, err
+	}
+
+	idFieldname := ""
+	if idFieldname, err = config.String(ConfigKeyGeomIDField, &idFieldname); err != nil {
+		return nil, err
+	}
+
+	srid := int(h.srid)
+	if srid == 0 {
+		srid = DefaultSRID
+	}
+	if srid, err = config.Int(ConfigKeySRID, &srid); err != nil {
+		return nil, err
+	}
+
+	fields := make([]string, 0, len(h.columns))
+	for _, col := range h.columns {
+		fields = append(fields, col.name)
+	}
+
+	return &Provider{
+		file:   f,
+		header: h,
+		index:  index,
+		layer: Layer{
+			name:     name,
+			geomType: geomTypeFor(h.geomType),
+			srid:     uint64(srid),
+			fields:   fields,
+		},
+		idFieldname:   idFieldname,
+		featuresStart: h.size + indexSize(h),
+	}, nil
+}
+
+func (p *Provider) Layers() ([]provider.LayerInfo, error) {
+	return []provider.LayerInfo{p.layer}, nil
+}
+
+// tileExtent returns the buffered extent of the tile in the layer's SRID
+func (p *Provider) tileExtent(layer string, tile provider.Tile) (*geom.Extent, error) {
+	if layer != p.layer.name {
+		return nil, fmt.Errorf("flatgeobuf: layer (%v) not found", layer)
+	}
+
+	// read the tile extent
+	tileBBox, tileSRID := tile.BufferedExtent()
+
+	// TODO: reimplement once the geom package has reprojection
+	if p.layer.srid != tileSRID {
+		minGeo, err := basic.FromWebMercator(p.layer.srid, geom.Point{tileBBox.MinX(), tileBBox.MinY()})
+		if err != nil {
+			return nil, fmt.Errorf("error converting point: %v ", err)
+		}
+
+		maxGeo, err := basic.FromWebMercator(p.layer.srid, geom.Point{tileBBox.MaxX(), tileBBox.MaxY()})
+		if err != nil {
+			return nil, fmt.Errorf("error converting point: %v ", err)
+		}
+
+		tileBBox = geom.NewExtent(minGeo.(geom.Point), maxGeo.(geom.Point))
+	}
+
+	return tileBBox, nil
+}
+
+// EstimateCost adheres to the provider.CostEstimator interface. The cost is
+// the number of features which need to be read for the tile: the features
+// whose bounding box intersects the tile if the file has an index, otherwise
+// every feature in the file.
+func (p *Provider) EstimateCost(ctx context.Context, layer string, tile provider.Tile) (float64, error) {
+	tileBBox, err := p.tileExtent(layer, tile)
+	if err != nil {
+		return 0, err
+	}
+
+	if p.index == nil {
+		return float64(p.header.featuresCount), nil
+	}
+
+	results, err := p.index.search(tileBBox)
+	if err != nil {
+		return 0, err
+	}
+	return float64(len(results)), nil
+}
+
+// TileFeatures adheres to the provider.Tiler interface
+func (p *Provider) TileFeatures(ctx context.Context, layer string, tile provider.Tile, fn func(f *provider.Feature) error) error {
+	tileBBox, err := p.tileExtent(layer, tile)
+	if err != nil {
+		return err
+	}
+
+	if p.index == nil {
+		return p.scanFeatures(ctx, tileBBox, fn)
+	}
+
+	results, err := p.index.search(tileBBox)
+	if err != nil {
+		return err
+	}
+
+	for _, res := range results {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		buf, _, err := p.readFeature(p.featuresStart + int64(res.offset))
+		if err != nil {
+			return err
+		}
+
+		if err = p.emit(buf, res.index, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// scanFeatures reads every feature of a file without an index,
+// passing those which intersect the extent to fn
+func (p *Provider) scanFeatures(ctx context.Context, ext *geom.Extent, fn func(f *provider.Feature) error) error {
+	offset := p.featuresStart
+	for i := 0; p.header.featuresCount == 0 || uint64(i) < p.header.featuresCount; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		buf, next, err := p.readFeature(offset)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		offset = next
+
+		geo, tags, err := decodeFeature(buf, p.header.geomType, p.header.columns)
+		if err != nil {
+			return fmt.Errorf("flatgeobuf: decoding feature (%v): %w", i, err)
+		}
+		if geo == nil || geom.IsEmpty(geo) {
+			continue
+		}
+		if fext, err := geom.NewExtentFromGeometry(geo); err != nil {
+			continue
+		} else if _, ok := fext.Intersect(ext); !ok {
+			continue
+		}
+
+		if err = p.callback(geo, tags, i, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readFeature reads the size prefixed feature at the offset, returning
+// the feature and the offset of the next feature
+func (p *Provider) readFeature(offset int64) (buf []byte, next int64, err error) {
+	var size [4]byte
+	if _, err = p.file.ReadAt(size[:], offset); err != nil {
+		return nil, 0, err
+	}
+
+	n := binary.LittleEndian.Uint32(size[:])
+	if n > maxFeatureSize {
+		return nil, 0, fmt.Errorf("flatgeobuf: feature size (%v) at offset (%v) exceeds %v", n, offset, maxFeatureSize)
+	}
+
+	buf = make([]byte, n)
+	if _, err = p.file.ReadAt(buf, offset+4); err != nil {
+		return nil, 0, fmt.Errorf("flatgeobuf: reading feature at offset (%v): %w", offset, err)
+	}
+	return buf, offset + 4 + int64(n), nil
+}
+
+func (p *Provider) emit(buf []byte, index int, fn func(f *provider.Feature) error) error {
+	geo, tags, err := decodeFeature(buf, p.header.geomType, p.header.columns)
+	if err != nil {
+		return fmt.Errorf("flatgeobuf: decoding feature (%v): %w", index, err)
+	}
+	if geo == nil {
+		return nil
+	}
+	return p.callback(geo, tags, index, fn)
+}
+
+func (p *Provider) callback(geo geom.Geometry, tags map[string]interface{}, index int, fn func(f *provider.Feature) error) error {
+	feature := provider.Feature{
+		ID:       uint64(index),
+		Geometry: geo,
+		SRID:     p.layer.srid,
+		Tags:     tags,
+	}
+
+	if p.idFieldname != "" {
+		if v, ok := tags[p.idFieldname]; ok {
+			id, err := provider.ConvertFeatureID(v)
+			if err != nil {
+				return err
+			}
+			feature.ID = id
+			delete(tags, p.idFieldname)
+		}
+	}
+
+	return fn(&feature)
+}
+
+// Close will close the Provider's file
+func (p *Provider) Close() error { return p.file.Close() }
+
+var (
+	// reference to all instantiated providers
+	providers     []*Provider
+	providersLock sync.Mutex
+)
+
+// Cleanup will close all open files and destroy all previously instantiated Provider instances
+func Cleanup() {
+	providersLock.Lock()
+	defer providersLock.Unlock()
+
+	if len(providers) > 0 {
+		log.Infof("cleaning up flatgeobuf providers")
+	}
+
+	for i := range providers {
+		if err := providers[i].Close(); err != nil {
+			log.Errorf("closing flatgeobuf file (%v): %v", providers[i].Filepath, err)
+		}
+	}
+	providers = providers[:0]
+}
@@ -0,0 +1,249 @@
+package flatgeobuf
+
+import (
+	"context"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/dict"
+	"github.com/go-spatial/tegola/provider"
+)
+
+var placesFile = testFile{
+	name:     "places",
+	geomType: geomTypePoint,
+	columns: []column{
+		{name: "osm_id", typ: colTypeLong},
+		{name: "name", typ: colTypeString},
+		{name: "pop", typ: colTypeInt},
+	},
+	nodeSize: 2,
+	srid:     4326,
+	features: []testFeature{
+		// one point in each quarter of the world, plus one more in the north west
+		{xy: []float64{-100, 40}, props: props(0, int64(10), 1, "nw", 2, int32(100))},
+		{xy: []float64{-50, 20}, props: props(0, int64(11), 1, "nw2")},
+		{xy: []float64{100, 40}, props: props(0, int64(12), 1, "ne")},
+		{xy: []float64{-100, -40}, props: props(0, int64(13), 1, "sw")},
+		{xy: []float64{100, -40}, props: props(0, int64(14), 1, "se", 2, int32(-5))},
+	},
+}
+
+func TestLayers(t *testing.T) {
+	p, err := NewTileProvider(dict.Dict{ConfigKeyFilePath: placesFile.write(t)})
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	defer p.(*Provider).Close()
+
+	layers, err := p.Layers()
+	if err != nil {
+		t.Fatalf("layers error, expected nil got %v", err)
+	}
+	if len(layers) != 1 {
+		t.Fatalf("layers, expected 1 got %v", len(layers))
+	}
+
+	l := layers[0].(Layer)
+	if l.Name() != "places" {
+		t.Errorf("name, expected places got %v", l.Name())
+	}
+	if _, ok := l.GeomType().(geom.Point); !ok {
+		t.Errorf("geom type, expected geom.Point got %T", l.GeomType())
+	}
+	if l.SRID() != 4326 {
+		t.Errorf("srid, expected 4326 got %v", l.SRID())
+	}
+	if expected := []string{"osm_id", "name", "pop"}; !reflect.DeepEqual(l.Fields(), expected) {
+		t.Errorf("fields, expected %v got %v", expected, l.Fields())
+	}
+}
+
+func TestTileFeatures(t *testing.T) {
+	type tcase struct {
+		file     testFile
+		config   dict.Dict
+		tile     provider.Tile
+		expected map[uint64]map[string]interface{}
+		geoms    map[uint64]geom.Geometry
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		tc.config[ConfigKeyFilePath] = tc.file.write(t)
+		p, err := NewTileProvider(tc.config)
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		defer p.(*Provider).Close()
+
+		layers, _ := p.Layers()
+		got := make(map[uint64]map[string]interface{})
+		err = p.TileFeatures(context.Background(), layers[0].Name(), tc.tile, func(f *provider.Feature) error {
+			got[f.ID] = f.Tags
+			if g, ok := tc.geoms[f.ID]; ok && !reflect.DeepEqual(f.Geometry, g) {
+				t.Errorf("feature %v geometry, expected %v got %v", f.ID, g, f.Geometry)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("tile features error, expected nil got %v", err)
+		}
+		if !reflect.DeepEqual(got, tc.expected) {
+			ids := make([]uint64, 0, len(got))
+			for id := range got {
+				ids = append(ids, id)
+			}
+			sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+			t.Errorf("features, expected %v got %v (ids %v)", tc.expected, got, ids)
+		}
+	}
+
+	tests := map[string]tcase{
+		"index north west": {
+			file:   placesFile,
+			config: dict.Dict{},
+			tile:   provider.NewTile(1, 0, 0, 0, 3857),
+			expected: map[uint64]map[string]interface{}{
+				0: {"osm_id": int64(10), "name": "nw", "pop": int32(100)},
+				1: {"osm_id": int64(11), "name": "nw2"},
+			},
+			geoms: map[uint64]geom.Geometry{0: geom.Point{-100, 40}},
+		},
+		"index id field": {
+			file:   placesFile,
+			config: dict.Dict{ConfigKeyGeomIDField: "osm_id"},
+			tile:   provider.NewTile(1, 1, 1, 0, 3857),
+			expected: map[uint64]map[string]interface{}{
+				14: {"name": "se", "pop": int32(-5)},
+			},
+		},
+		"no index": {
+			file: testFile{
+				geomType: geomTypePolygon,
+				features: []testFeature{
+					// a polygon with a hole, in the north east
+					{
+						xy: []float64{
+							10, 10, 50, 10, 50, 50, 10, 50, 10, 10,
+							20, 20, 20, 30, 30, 30, 20, 20,
+						},
+						ends: []uint32{5, 9},
+					},
+					// in the south west
+					{xy: []float64{-10, -10, -5, -10, -5, -5, -10, -10}},
+				},
+				srid: 3857,
+			},
+			config: dict.Dict{},
+			tile:   provider.NewTile(1, 1, 0, 0, 3857),
+			expected: map[uint64]map[string]interface{}{
+				0: {},
+			},
+			geoms: map[uint64]geom.Geometry{
+				0: geom.Polygon{
+					{{10, 10}, {50, 10}, {50, 50}, {10, 50}},
+					{{20, 20}, {20, 30}, {30, 30}},
+				},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
+
+func TestEstimateCost(t *testing.T) {
+	p, err := NewTileProvider(dict.Dict{ConfigKeyFilePath: placesFile.write(t)})
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	defer p.(*Provider).Close()
+
+	type tcase struct {
+		tile     provider.Tile
+		expected float64
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		cost, err := provider.EstimateCost(context.Background(), p, "places", tc.tile)
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		if cost != tc.expected {
+			t.Errorf("cost, expected %v got %v", tc.expected, cost)
+		}
+	}
+
+	tests := map[string]tcase{
+		"world": {
+			tile:     provider.NewTile(0, 0, 0, 0, 3857),
+			expected: 5,
+		},
+		"north west": {
+			tile:     provider.NewTile(1, 0, 0, 0, 3857),
+			expected: 2,
+		},
+		"south east": {
+			tile:     provider.NewTile(1, 1, 1, 0, 3857),
+			expected: 1,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
+
+func TestNotFlatGeobuf(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.fgb")
+	if err := os.WriteFile(path, []byte("not a flatgeobuf file"), 0o644); err != nil {
+		t.Fatalf("writing test file: %v", err)
+	}
+
+	_, err := NewTileProvider(dict.Dict{ConfigKeyFilePath: path})
+	if !errors.Is(err, ErrNotFlatGeobuf) {
+		t.Errorf("error, expected %v got %v", ErrNotFlatGeobuf, err)
+	}
+}
+
+func TestCorruptFeaturesCount(t *testing.T) {
+	type tcase struct {
+		featuresCount uint64
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		tf := placesFile
+		tf.featuresCount = tc.featuresCount
+
+		_, err := NewTileProvider(dict.Dict{ConfigKeyFilePath: tf.write(t)})
+		if err == nil {
+			t.Errorf("error, expected an error got nil")
+		}
+	}
+
+	tests := map[string]tcase{
+		"more than the file holds": {
+			featuresCount: 1000,
+		},
+		"max uint64": {
+			featuresCount: math.MaxUint64,
+		},
+		"overflows int": {
+			featuresCount: 1 << 63,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
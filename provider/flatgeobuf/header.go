@@ -0,0 +1,183 @@
+package flatgeobuf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/go-spatial/geom"
+)
+
+// magicBytes start every FlatGeobuf file, the fourth byte is the major
+// version of the spec and the eighth the patch version
+var magicBytes = []byte{0x66, 0x67, 0x62, 0x03, 0x66, 0x67, 0x62}
+
+// maxHeaderSize guards against allocating for a corrupt header size
+const maxHeaderSize = 10 * 1024 * 1024
+
+var ErrNotFlatGeobuf = errors.New("flatgeobuf: not a FlatGeobuf file")
+
+// geometry types
+// ref: https://github.com/flatgeobuf/flatgeobuf/blob/master/src/fbs/header.fbs
+const (
+	geomTypeUnknown uint8 = iota
+	geomTypePoint
+	geomTypeLineString
+	geomTypePolygon
+	geomTypeMultiPoint
+	geomTypeMultiLineString
+	geomTypeMultiPolygon
+	geomTypeGeometryCollection
+)
+
+// column types
+const (
+	colTypeByte uint8 = iota
+	colTypeUByte
+	colTypeBool
+	colTypeShort
+	colTypeUShort
+	colTypeInt
+	colTypeUInt
+	colTypeLong
+	colTypeULong
+	colTypeFloat
+	colTypeDouble
+	colTypeString
+	colTypeJSON
+	colTypeDateTime
+	colTypeBinary
+)
+
+// header table fields
+const (
+	headerName = iota
+	headerEnvelope
+	headerGeometryType
+	headerHasZ
+	headerHasM
+	headerHasT
+	headerHasTM
+	headerColumns
+	headerFeaturesCount
+	headerIndexNodeSize
+	headerCRS
+)
+
+// column table fields
+const (
+	columnName = iota
+	columnType
+)
+
+// crs table fields
+const (
+	crsOrg = iota
+	crsCode
+)
+
+type column struct {
+	name string
+	typ  uint8
+}
+
+type header struct {
+	name          string
+	envelope      []float64
+	geomType      uint8
+	columns       []column
+	featuresCount uint64
+	indexNodeSize uint16
+	// srid is 0 if the crs is not set or is not an EPSG code
+	srid uint64
+	// size of the magic bytes, header size and header, i.e. the offset of the index
+	size int64
+}
+
+// readHeader reads the magic bytes and header at the start of the file
+func readHeader(r io.ReaderAt) (*header, error) {
+	var prefix [12]byte
+	if _, err := r.ReadAt(prefix[:], 0); err != nil {
+		if err == io.EOF {
+			return nil, ErrNotFlatGeobuf
+		}
+		return nil, err
+	}
+	if !bytes.Equal(prefix[:len(magicBytes)], magicBytes) {
+		return nil, ErrNotFlatGeobuf
+	}
+
+	size := binary.LittleEndian.Uint32(prefix[8:])
+	if size > maxHeaderSize {
+		return nil, fmt.Errorf("flatgeobuf: header size (%v) exceeds %v", size, maxHeaderSize)
+	}
+
+	buf := make([]byte, size)
+	if _, err := r.ReadAt(buf, int64(len(prefix))); err != nil {
+		return nil, fmt.Errorf("flatgeobuf: reading header: %w", err)
+	}
+
+	fb := &fbReader{buf: buf}
+	tbl := fb.root()
+
+	h := header{
+		name:          tbl.string(headerName),
+		envelope:      tbl.f64s(headerEnvelope),
+		geomType:      tbl.u8(headerGeometryType, geomTypeUnknown),
+		featuresCount: tbl.u64(headerFeaturesCount, 0),
+		indexNodeSize: tbl.u16(headerIndexNodeSize, 16),
+		size:          int64(len(prefix)) + int64(size),
+	}
+	h.columns = readColumns(tbl.tables(headerColumns))
+
+	if crs, ok := tbl.table(headerCRS); ok {
+		org := crs.string(crsOrg)
+		if org == "" || org == "EPSG" || org == "epsg" {
+			h.srid = uint64(crs.i32(crsCode, 0))
+		}
+	}
+
+	if fb.err != nil {
+		return nil, fmt.Errorf("flatgeobuf: reading header: %w", fb.err)
+	}
+	return &h, nil
+}
+
+func readColumns(tables []fbTable) []column {
+	if len(tables) == 0 {
+		return nil
+	}
+	cols := make([]column, len(tables))
+	for i, tbl := range tables {
+		cols[i] = column{
+			name: tbl.string(columnName),
+			typ:  tbl.u8(columnType, colTypeByte),
+		}
+	}
+	return cols
+}
+
+// geomTypeFor returns the geometry used by the provider package to report
+// the geometry type of a layer
+func geomTypeFor(typ uint8) geom.Geometry {
+	switch typ {
+	case geomTypePoint:
+		return geom.Point{}
+	case geomTypeLineString:
+		return geom.LineString{}
+	case geomTypePolygon:
+		return geom.Polygon{}
+	case geomTypeMultiPoint:
+		return geom.MultiPoint{}
+	case geomTypeMultiLineString:
+		return geom.MultiLineString{}
+	case geomTypeMultiPolygon:
+		return geom.MultiPolygon{}
+	case geomTypeGeometryCollection:
+		return geom.Collection{}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,153 @@
+package flatgeobuf
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/go-spatial/geom"
+)
+
+// nodeSize is the size, in bytes, of a node of the packed R-tree:
+// min x, min y, max x, max y and offset
+const nodeSize = 40
+
+// packedRTree is the static, packed Hilbert R-tree index of a FlatGeobuf
+// file. The nodes are stored root first with the leaves, one per feature in
+// the same order as the features, at the end. The offset of a leaf is the
+// byte offset of its feature from the start of the features, the offset of
+// any other node is the index of its first child.
+//
+// ref: https://github.com/flatgeobuf/flatgeobuf/blob/master/src/ts/packedrtree.ts
+type packedRTree struct {
+	numItems int
+	branch   int
+	// levelBounds holds the [start, end) node index of each level, leaves first
+	levelBounds [][2]int
+	nodes       []byte
+}
+
+// levelBounds returns the node index bounds of each level of a tree with
+// numItems leaves, starting at the leaves, and the total number of nodes
+func levelBounds(numItems, branch int) (bounds [][2]int, numNodes int) {
+	n := numItems
+	numNodes = n
+	levelNumNodes := []int{n}
+	for n != 1 {
+		n = (n + branch - 1) / branch
+		levelNumNodes = append(levelNumNodes, n)
+		numNodes += n
+	}
+
+	end := numNodes
+	for _, size := range levelNumNodes {
+		bounds = append(bounds, [2]int{end - size, end})
+		end -= size
+	}
+	return bounds, numNodes
+}
+
+// indexSize returns the size, in bytes, of the index for the header
+func indexSize(h *header) int64 {
+	if h.indexNodeSize < 2 || h.featuresCount == 0 {
+		return 0
+	}
+	_, numNodes := levelBounds(int(h.featuresCount), int(h.indexNodeSize))
+	return int64(numNodes) * nodeSize
+}
+
+// readIndex reads the index following the header. The index must fit in
+// the fileSize bytes of the file, so a corrupt features count is reported
+// rather than allocated for.
+func readIndex(r io.ReaderAt, fileSize int64, h *header) (*packedRTree, error) {
+	if h.indexNodeSize < 2 || h.featuresCount == 0 {
+		return nil, nil
+	}
+
+	avail := fileSize - h.size
+	if avail < 0 {
+		avail = 0
+	}
+	// the leaves alone take nodeSize bytes per feature, checked first so
+	// levelBounds is only given counts which fit in an int
+	if h.featuresCount > uint64(avail)/nodeSize {
+		return nil, fmt.Errorf("flatgeobuf: index of %v features exceeds the file size (%v bytes)", h.featuresCount, fileSize)
+	}
+	size := indexSize(h)
+	if size > avail {
+		return nil, fmt.Errorf("flatgeobuf: index of %v features exceeds the file size (%v bytes)", h.featuresCount, fileSize)
+	}
+
+	nodes := make([]byte, size)
+	if _, err := r.ReadAt(nodes, h.size); err != nil {
+		return nil, fmt.Errorf("flatgeobuf: reading index: %w", err)
+	}
+
+	bounds, _ := levelBounds(int(h.featuresCount), int(h.indexNodeSize))
+	return &packedRTree{
+		numItems:    int(h.featuresCount),
+		branch:      int(h.indexNodeSize),
+		levelBounds: bounds,
+		nodes:       nodes,
+	}, nil
+}
+
+func (t *packedRTree) node(i int) (minx, miny, maxx, maxy float64, offset uint64) {
+	b := t.nodes[i*nodeSize:]
+	f := func(j int) float64 { return math.Float64frombits(binary.LittleEndian.Uint64(b[j*8:])) }
+	return f(0), f(1), f(2), f(3), binary.LittleEndian.Uint64(b[32:])
+}
+
+// searchResult is a feature matched by the index
+type searchResult struct {
+	// offset of the feature from the start of the features
+	offset uint64
+	// index of the feature in the file
+	index int
+}
+
+// search returns the features whose bounding box intersects the extent,
+// ordered by their position in the file
+func (t *packedRTree) search(ext *geom.Extent) ([]searchResult, error) {
+	type item struct {
+		node  int
+		level int
+	}
+
+	leavesStart := t.levelBounds[0][0]
+	numNodes := len(t.nodes) / nodeSize
+
+	var results []searchResult
+	queue := []item{{node: 0, level: len(t.levelBounds) - 1}}
+	for len(queue) > 0 {
+		it := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+
+		if it.level < 0 || it.node < 0 || it.node >= numNodes {
+			return nil, fmt.Errorf("flatgeobuf: invalid index node (%v)", it.node)
+		}
+
+		isLeaf := it.node >= leavesStart
+		end := it.node + t.branch
+		if levelEnd := t.levelBounds[it.level][1]; end > levelEnd {
+			end = levelEnd
+		}
+
+		for pos := it.node; pos < end; pos++ {
+			minx, miny, maxx, maxy, offset := t.node(pos)
+			if maxx < ext.MinX() || maxy < ext.MinY() || minx > ext.MaxX() || miny > ext.MaxY() {
+				continue
+			}
+			if isLeaf {
+				results = append(results, searchResult{offset: offset, index: pos - leavesStart})
+				continue
+			}
+			queue = append(queue, item{node: int(offset), level: it.level - 1})
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].offset < results[j].offset })
+	return results, nil
+}
@@ -0,0 +1,286 @@
+package flatgeobuf
+
+import (
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// the following is a minimal FlatGeobuf writer used to create test files
+
+// fbTableB is a table to be built, fields are indexed by their id. A field
+// is either nil (not set), []byte (an inline scalar) or one of fbString,
+// fbVector, *fbTableB or []*fbTableB which are referenced by offset
+type fbTableB struct {
+	fields []interface{}
+}
+
+type fbString string
+
+// fbVector is a vector of scalars, data holds the encoded elements
+type fbVector struct {
+	n    int
+	data []byte
+}
+
+type fbBuilder struct {
+	buf []byte
+}
+
+// appendU16, appendU32 and appendU64 append little endian integers
+func appendU16(b []byte, v uint16) []byte {
+	var buf [2]byte
+	binary.LittleEndian.PutUint16(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendU32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendU64(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func (b *fbBuilder) u32(v uint32) {
+	b.buf = appendU32(b.buf, v)
+}
+
+func (b *fbBuilder) patch(pos, target int) {
+	binary.LittleEndian.PutUint32(b.buf[pos:], uint32(target-pos))
+}
+
+// build returns the encoded flatbuffer with tbl as the root table
+func (b *fbBuilder) build(tbl *fbTableB) []byte {
+	b.buf = make([]byte, 4)
+	b.patch(0, b.table(tbl))
+	return b.buf
+}
+
+func (b *fbBuilder) object(v interface{}) int {
+	switch v := v.(type) {
+	case fbString:
+		pos := len(b.buf)
+		b.u32(uint32(len(v)))
+		b.buf = append(append(b.buf, v...), 0)
+		return pos
+	case fbVector:
+		pos := len(b.buf)
+		b.u32(uint32(v.n))
+		b.buf = append(b.buf, v.data...)
+		return pos
+	case *fbTableB:
+		return b.table(v)
+	case []*fbTableB:
+		pos := len(b.buf)
+		b.u32(uint32(len(v)))
+		elems := len(b.buf)
+		b.buf = append(b.buf, make([]byte, 4*len(v))...)
+		for i := range v {
+			b.patch(elems+4*i, b.table(v[i]))
+		}
+		return pos
+	}
+	panic("unsupported object")
+}
+
+func (b *fbBuilder) table(tbl *fbTableB) int {
+	vtable := len(b.buf)
+	vsize := 4 + 2*len(tbl.fields)
+	b.buf = append(b.buf, make([]byte, vsize)...)
+
+	pos := len(b.buf)
+	b.u32(uint32(pos - vtable))
+
+	offsets := map[int]interface{}{}
+	for i, f := range tbl.fields {
+		if f == nil {
+			continue
+		}
+		binary.LittleEndian.PutUint16(b.buf[vtable+4+2*i:], uint16(len(b.buf)-pos))
+		if scalar, ok := f.([]byte); ok {
+			b.buf = append(b.buf, scalar...)
+			continue
+		}
+		offsets[len(b.buf)] = f
+		b.u32(0)
+	}
+	binary.LittleEndian.PutUint16(b.buf[vtable:], uint16(vsize))
+	binary.LittleEndian.PutUint16(b.buf[vtable+2:], uint16(len(b.buf)-pos))
+
+	for fpos, f := range offsets {
+		b.patch(fpos, b.object(f))
+	}
+	return pos
+}
+
+func f64Vector(vals ...float64) fbVector {
+	var data []byte
+	for _, v := range vals {
+		data = appendU64(data, math.Float64bits(v))
+	}
+	return fbVector{n: len(vals), data: data}
+}
+
+func u32Vector(vals ...uint32) fbVector {
+	var data []byte
+	for _, v := range vals {
+		data = appendU32(data, v)
+	}
+	return fbVector{n: len(vals), data: data}
+}
+
+type testFeature struct {
+	geomType uint8
+	xy       []float64
+	ends     []uint32
+	// properties encoded as column index and value
+	props []byte
+}
+
+type testFile struct {
+	name     string
+	geomType uint8
+	columns  []column
+	nodeSize uint16
+	srid     int32
+	features []testFeature
+	// featuresCount, if set, is written to the header instead of the number
+	// of features, i.e. to corrupt it
+	featuresCount uint64
+}
+
+// write writes the test file to a temporary directory, returning its path
+func (tf testFile) write(t *testing.T) string {
+	t.Helper()
+
+	var cols []*fbTableB
+	for _, c := range tf.columns {
+		cols = append(cols, &fbTableB{fields: []interface{}{fbString(c.name), []byte{c.typ}}})
+	}
+	var featuresCount [8]byte
+	binary.LittleEndian.PutUint64(featuresCount[:], uint64(len(tf.features)))
+	if tf.featuresCount != 0 {
+		binary.LittleEndian.PutUint64(featuresCount[:], tf.featuresCount)
+	}
+	var nodeSize [2]byte
+	binary.LittleEndian.PutUint16(nodeSize[:], tf.nodeSize)
+
+	hdr := &fbTableB{fields: make([]interface{}, 11)}
+	if tf.name != "" {
+		hdr.fields[headerName] = fbString(tf.name)
+	}
+	hdr.fields[headerGeometryType] = []byte{tf.geomType}
+	if len(cols) > 0 {
+		hdr.fields[headerColumns] = cols
+	}
+	hdr.fields[headerFeaturesCount] = featuresCount[:]
+	hdr.fields[headerIndexNodeSize] = nodeSize[:]
+	if tf.srid != 0 {
+		var code [4]byte
+		binary.LittleEndian.PutUint32(code[:], uint32(tf.srid))
+		hdr.fields[headerCRS] = &fbTableB{fields: []interface{}{nil, code[:]}}
+	}
+	header := new(fbBuilder).build(hdr)
+
+	// features and the leaves of the index
+	var (
+		features []byte
+		leaves   [][5]float64
+	)
+	for _, f := range tf.features {
+		g := &fbTableB{fields: make([]interface{}, 8)}
+		g.fields[geometryXY] = f64Vector(f.xy...)
+		if len(f.ends) > 0 {
+			g.fields[geometryEnds] = u32Vector(f.ends...)
+		}
+		if f.geomType != geomTypeUnknown {
+			g.fields[geometryType] = []byte{f.geomType}
+		}
+		feat := &fbTableB{fields: []interface{}{g, nil}}
+		if len(f.props) > 0 {
+			feat.fields[featureProperties] = fbVector{n: len(f.props), data: f.props}
+		}
+
+		leaf := [5]float64{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1), float64(len(features))}
+		for i := 0; i < len(f.xy); i += 2 {
+			leaf[0], leaf[1] = math.Min(leaf[0], f.xy[i]), math.Min(leaf[1], f.xy[i+1])
+			leaf[2], leaf[3] = math.Max(leaf[2], f.xy[i]), math.Max(leaf[3], f.xy[i+1])
+		}
+		leaves = append(leaves, leaf)
+
+		buf := new(fbBuilder).build(feat)
+		features = appendU32(features, uint32(len(buf)))
+		features = append(features, buf...)
+	}
+
+	file := append([]byte{}, magicBytes...)
+	file = append(file, 0)
+	file = appendU32(file, uint32(len(header)))
+	file = append(file, header...)
+	if tf.nodeSize > 0 && len(leaves) > 0 {
+		file = append(file, packIndex(leaves, int(tf.nodeSize))...)
+	}
+	file = append(file, features...)
+
+	path := filepath.Join(t.TempDir(), "test.fgb")
+	if err := os.WriteFile(path, file, 0o644); err != nil {
+		t.Fatalf("writing test file: %v", err)
+	}
+	return path
+}
+
+// packIndex builds a packed R-tree over the leaves, in the given order
+func packIndex(leaves [][5]float64, branch int) []byte {
+	bounds, numNodes := levelBounds(len(leaves), branch)
+	nodes := make([][5]float64, numNodes)
+	copy(nodes[bounds[0][0]:], leaves)
+
+	for level := 1; level < len(bounds); level++ {
+		children := bounds[level-1]
+		for i := bounds[level][0]; i < bounds[level][1]; i++ {
+			first := children[0] + (i-bounds[level][0])*branch
+			n := [5]float64{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1), float64(first)}
+			for c := first; c < first+branch && c < children[1]; c++ {
+				n[0], n[1] = math.Min(n[0], nodes[c][0]), math.Min(n[1], nodes[c][1])
+				n[2], n[3] = math.Max(n[2], nodes[c][2]), math.Max(n[3], nodes[c][3])
+			}
+			nodes[i] = n
+		}
+	}
+
+	var buf []byte
+	for _, n := range nodes {
+		for _, v := range n[:4] {
+			buf = appendU64(buf, math.Float64bits(v))
+		}
+		buf = appendU64(buf, uint64(n[4]))
+	}
+	return buf
+}
+
+// props encodes properties, values must be the Go type matching the column type
+func props(vals ...interface{}) []byte {
+	var b []byte
+	for i := 0; i < len(vals); i += 2 {
+		b = appendU16(b, uint16(vals[i].(int)))
+		switch v := vals[i+1].(type) {
+		case int32:
+			b = appendU32(b, uint32(v))
+		case int64:
+			b = appendU64(b, uint64(v))
+		case float64:
+			b = appendU64(b, math.Float64bits(v))
+		case string:
+			b = appendU32(b, uint32(len(v)))
+			b = append(b, v...)
+		}
+	}
+	return b
+}
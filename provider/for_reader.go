@@ -0,0 +1,178 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+	"github.com/go-spatial/tegola/internal/env"
+)
+
+// ConfigDecoder decodes a provider config from r. Decoders should return an
+// ErrConfigParse with the Line and Column of syntax errors when known.
+type ConfigDecoder func(r io.Reader) (map[string]interface{}, error)
+
+var (
+	configFormatsLock sync.RWMutex
+	configFormats     = map[string]ConfigDecoder{
+		"toml": decodeTOMLConfig,
+		"json": decodeJSONConfig,
+	}
+)
+
+// RegisterConfigFormat registers the decoder for the format, i.e. "yaml",
+// for use by ForReader. Formats are case insensitive. "toml" and "json" are
+// registered by default; registering a format again replaces its decoder.
+func RegisterConfigFormat(format string, dec ConfigDecoder) {
+	configFormatsLock.Lock()
+	defer configFormatsLock.Unlock()
+	configFormats[strings.ToLower(format)] = dec
+}
+
+// ConfigFormats returns the formats registered for ForReader, sorted
+func ConfigFormats() []string {
+	configFormatsLock.RLock()
+	defer configFormatsLock.RUnlock()
+
+	formats := make([]string, 0, len(configFormats))
+	for f := range configFormats {
+		formats = append(formats, f)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// ForReader decodes the config of a provider of type name from r in the
+// format, see RegisterConfigFormat, and returns the provider initialized by
+// For. The config is the provider's table, i.e. the keys of a
+// [[providers]] entry of a tegola config file. As in config files, string
+// values may reference environment variables, i.e. "${POSTGRES_PASSWORD}".
+//
+// Errors decoding the config are an ErrConfigParse naming the format and,
+// where the decoder reports it, the line and column of the error.
+func ForReader(name string, r io.Reader, format string) (Tiler, error) {
+	configFormatsLock.RLock()
+	dec, ok := configFormats[strings.ToLower(format)]
+	configFormatsLock.RUnlock()
+	if !ok {
+		return nil, ErrConfigParse{
+			Format: format,
+			Err:    fmt.Errorf("unknown format, supported formats are %v", strings.Join(ConfigFormats(), ", ")),
+		}
+	}
+
+	m, err := dec(r)
+	if err != nil {
+		perr, ok := err.(ErrConfigParse)
+		if !ok {
+			perr = ErrConfigParse{Err: err}
+		}
+		perr.Format = format
+		return nil, perr
+	}
+
+	return For(name, configDict(m))
+}
+
+// configDict converts the decoded config to the env.Dict used for config
+// files, so values are read the same way regardless of the format
+func configDict(m map[string]interface{}) env.Dict {
+	d := make(env.Dict, len(m))
+	for k, v := range m {
+		d[k] = configValue(v)
+	}
+	return d
+}
+
+func configValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return configDict(v)
+	case []map[string]interface{}:
+		for i := range v {
+			v[i] = map[string]interface{}(configDict(v[i]))
+		}
+		return v
+	case []interface{}:
+		// arrays of tables, i.e. layers, are read as []map[string]interface{}
+		tables := make([]map[string]interface{}, 0, len(v))
+		for i := range v {
+			t, ok := v[i].(map[string]interface{})
+			if !ok {
+				break
+			}
+			tables = append(tables, map[string]interface{}(configDict(t)))
+		}
+		if len(v) > 0 && len(tables) == len(v) {
+			return tables
+		}
+		for i := range v {
+			v[i] = configValue(v[i])
+		}
+		return v
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	default:
+		return v
+	}
+}
+
+func decodeTOMLConfig(r io.Reader) (map[string]interface{}, error) {
+	var m map[string]interface{}
+	if _, err := toml.DecodeReader(r, &m); err != nil {
+		perr := ErrConfigParse{Err: err}
+		// parse errors are formatted as "Near line N (last key parsed ...)"
+		fmt.Sscanf(err.Error(), "Near line %d", &perr.Line)
+		return nil, perr
+	}
+	return m, nil
+}
+
+func decodeJSONConfig(r io.Reader) (map[string]interface{}, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var m map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	// integers are kept as integers rather than float64
+	dec.UseNumber()
+	if err = dec.Decode(&m); err != nil {
+		perr := ErrConfigParse{Err: err}
+		var offset int64
+		switch err := err.(type) {
+		case *json.SyntaxError:
+			offset = err.Offset
+		case *json.UnmarshalTypeError:
+			offset = err.Offset
+		}
+		if offset > 0 {
+			perr.Line, perr.Column = lineColumn(b, offset)
+		}
+		return nil, perr
+	}
+	return m, nil
+}
+
+// lineColumn returns the 1 based line and column of the byte before the
+// offset, the offset of encoding/json errors being the bytes read
+func lineColumn(b []byte, offset int64) (line, column int) {
+	if offset > int64(len(b)) {
+		offset = int64(len(b))
+	}
+	before := b[:offset-1]
+	line = bytes.Count(before, []byte("\n")) + 1
+	column = len(before) - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
@@ -0,0 +1,136 @@
+package provider_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/go-spatial/tegola/dict"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestForReader(t *testing.T) {
+	type config struct {
+		host   string
+		port   int
+		layers []string
+	}
+	var got config
+	err := provider.Register("reader_test", func(d dict.Dicter) (provider.Tiler, error) {
+		got = config{}
+		var err error
+		if got.host, err = d.String("host", nil); err != nil {
+			return nil, err
+		}
+		if got.port, err = d.Int("port", nil); err != nil {
+			return nil, err
+		}
+		layers, err := d.MapSlice("layers")
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range layers {
+			name, err := l.String("name", nil)
+			if err != nil {
+				return nil, err
+			}
+			got.layers = append(got.layers, name)
+		}
+		return featuresTiler{}, nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("register, expected nil got %v", err)
+	}
+
+	// a format registered by the caller, of key=value lines
+	provider.RegisterConfigFormat("KV", func(r io.Reader) (map[string]interface{}, error) {
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		m := make(map[string]interface{})
+		for i, line := range strings.Split(strings.TrimSpace(string(b)), "\n") {
+			kv := strings.SplitN(line, "=", 2)
+			if len(kv) != 2 {
+				return nil, provider.ErrConfigParse{Line: i + 1, Err: errors.New("expected key=value")}
+			}
+			m[kv[0]] = kv[1]
+		}
+		return m, nil
+	})
+
+	type tcase struct {
+		format   string
+		config   string
+		expected config
+		err      string
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		_, err := provider.ForReader("reader_test", strings.NewReader(tc.config), tc.format)
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("error, expected %v got %v", tc.err, err)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		if got.host != tc.expected.host || got.port != tc.expected.port || strings.Join(got.layers, ",") != strings.Join(tc.expected.layers, ",") {
+			t.Errorf("config, expected %+v got %+v", tc.expected, got)
+		}
+	}
+
+	tests := map[string]tcase{
+		"toml": {
+			format: "toml",
+			config: `host = "db"
+port = 5432
+
+[[layers]]
+name = "roads"
+
+[[layers]]
+name = "water"
+`,
+			expected: config{host: "db", port: 5432, layers: []string{"roads", "water"}},
+		},
+		"json": {
+			format:   "JSON",
+			config:   `{"host": "db", "port": 5432, "layers": [{"name": "roads"}, {"name": "water"}]}`,
+			expected: config{host: "db", port: 5432, layers: []string{"roads", "water"}},
+		},
+		"registered format": {
+			format:   "kv",
+			config:   "host=db\nport=5432",
+			expected: config{host: "db", port: 5432},
+		},
+		"toml error": {
+			format: "toml",
+			config: "host = \"db\"\nport = \n",
+			err:    `provider config (toml) line 2: Near line 2 (last key parsed 'port'): expected value but found '\n' instead`,
+		},
+		"json error": {
+			format: "json",
+			config: "{\n  \"host\": \"db\",\n  \"port\": 54 32\n}",
+			err:    `provider config (json) line 3 column 14: invalid character '3' after object key:value pair`,
+		},
+		"registered format error": {
+			format: "kv",
+			config: "host=db\nport",
+			err:    `provider config (kv) line 2: expected key=value`,
+		},
+		"unknown format": {
+			format: "ini",
+			config: "host=db",
+			err:    `provider config (ini): unknown format, supported formats are json, kv, toml`,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
@@ -0,0 +1,160 @@
+package provider
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"math"
+	"sort"
+
+	"github.com/go-spatial/geom"
+)
+
+// IDStrategy determines how WithGeneratedIDs assigns IDs to features
+type IDStrategy uint8
+
+const (
+	// IDSequential numbers the features of each TileFeatures call from 1.
+	// It is cheap, but the IDs are only unique within a tile: the same
+	// feature has different IDs in adjacent tiles, and different features
+	// share IDs across tiles.
+	IDSequential IDStrategy = iota
+	// IDHash derives the ID from a hash of the feature's geometry and tags,
+	// so the same feature has the same ID in every tile and request. It is
+	// more expensive, and identical features share an ID.
+	IDHash
+)
+
+func (s IDStrategy) String() string {
+	switch s {
+	case IDSequential:
+		return "sequential"
+	case IDHash:
+		return "hash"
+	default:
+		return "unknown"
+	}
+}
+
+// maxSafeID is the largest integer a JavaScript client can represent
+// exactly, hashed IDs are kept below it
+const maxSafeID = 1<<53 - 1
+
+// WithGeneratedIDs wraps the Tiler so features without an ID (an ID of 0)
+// are assigned one using the strategy, for data sources without a usable
+// ID. Clients relying on IDs, i.e. for feature state, need stable IDs across
+// tiles and should use IDHash; IDHash is only stable if the provider returns
+// the same, unclipped geometry for the feature in each tile. Features which
+// already have an ID are passed on as is.
+func WithGeneratedIDs(t Tiler, strategy IDStrategy) Tiler {
+	return &generatedIDsTiler{
+		Tiler:    t,
+		strategy: strategy,
+	}
+}
+
+type generatedIDsTiler struct {
+	Tiler
+	strategy IDStrategy
+}
+
+func (gt *generatedIDsTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	var (
+		seq  uint64
+		h    = fnv.New64a()
+		keys []string
+	)
+	return gt.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		if f.ID != 0 {
+			return fn(f)
+		}
+
+		switch gt.strategy {
+		case IDHash:
+			h.Reset()
+			hashGeometry(h, f.Geometry)
+			keys = hashTags(h, f.Tags, keys)
+
+			f.ID = mix64(h.Sum64()) & maxSafeID
+			if f.ID == 0 {
+				f.ID = 1
+			}
+		default:
+			seq++
+			f.ID = seq
+		}
+		return fn(f)
+	})
+}
+
+// hashTags writes the tags to h in key order, keys is reused to sort the
+// keys and is returned for the next call
+func hashTags(h hash.Hash64, tags map[string]interface{}, keys []string) []string {
+	keys = keys[:0]
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	// the value types are included so 1 and "1" differ
+	for _, k := range keys {
+		fmt.Fprintf(h, "%q:%T:%#v,", k, tags[k], tags[k])
+	}
+	return keys
+}
+
+// hashGeometry writes the type and coordinates of the geometry to h
+func hashGeometry(h hash.Hash64, g geom.Geometry) {
+	var buf [8]byte
+	writePoints := func(pts ...[2]float64) {
+		binary.LittleEndian.PutUint64(buf[:], uint64(len(pts)))
+		h.Write(buf[:])
+		for _, pt := range pts {
+			binary.LittleEndian.PutUint64(buf[:], math.Float64bits(pt[0]))
+			h.Write(buf[:])
+			binary.LittleEndian.PutUint64(buf[:], math.Float64bits(pt[1]))
+			h.Write(buf[:])
+		}
+	}
+
+	switch gg := g.(type) {
+	case geom.Pointer:
+		h.Write([]byte("P"))
+		writePoints(gg.XY())
+	case geom.MultiPointer:
+		h.Write([]byte("MP"))
+		writePoints(gg.Points()...)
+	case geom.LineStringer:
+		h.Write([]byte("L"))
+		writePoints(gg.Verticies()...)
+	case geom.MultiLineStringer:
+		h.Write([]byte("ML"))
+		for _, line := range gg.LineStrings() {
+			writePoints(line...)
+		}
+	case geom.Polygoner:
+		h.Write([]byte("A"))
+		for _, ring := range gg.LinearRings() {
+			writePoints(ring...)
+		}
+	case geom.MultiPolygoner:
+		h.Write([]byte("MA"))
+		for _, poly := range gg.Polygons() {
+			h.Write([]byte("("))
+			for _, ring := range poly {
+				writePoints(ring...)
+			}
+			h.Write([]byte(")"))
+		}
+	case geom.Collectioner:
+		h.Write([]byte("C("))
+		for _, cg := range gg.Geometries() {
+			hashGeometry(h, cg)
+		}
+		h.Write([]byte(")"))
+	default:
+		// nil and unknown geometries only hash their type
+		fmt.Fprintf(h, "%T", g)
+	}
+}
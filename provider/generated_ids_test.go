@@ -0,0 +1,60 @@
+package provider_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithGeneratedIDs(t *testing.T) {
+	features := []provider.Feature{
+		{Geometry: geom.Point{1, 2}, Tags: map[string]interface{}{"name": "a"}},
+		{Geometry: geom.LineString{{0, 0}, {1, 1}}, Tags: map[string]interface{}{"name": "b"}},
+		// the same geometry, a different tag type
+		{Geometry: geom.Point{1, 2}, Tags: map[string]interface{}{"name": 1}},
+		{ID: 42, Geometry: geom.Point{3, 4}},
+		{Geometry: geom.Polygon{{{0, 0}, {1, 0}, {1, 1}}}},
+	}
+
+	ids := func(t *testing.T, strategy provider.IDStrategy, z, x, y uint) []uint64 {
+		got, err := collect(provider.WithGeneratedIDs(featuresTiler{features: features}, strategy), "", provider.NewTile(z, x, y, 0, 3857))
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		ids := make([]uint64, len(got))
+		for i := range got {
+			ids[i] = got[i].ID
+		}
+		return ids
+	}
+
+	t.Run("sequential", func(t *testing.T) {
+		if got, expected := ids(t, provider.IDSequential, 0, 0, 0), []uint64{1, 2, 3, 42, 4}; !reflect.DeepEqual(got, expected) {
+			t.Errorf("ids, expected %v got %v", expected, got)
+		}
+	})
+
+	t.Run("hash", func(t *testing.T) {
+		first := ids(t, provider.IDHash, 1, 0, 0)
+		// a second render, of an adjacent tile, gives the same ids
+		if second := ids(t, provider.IDHash, 1, 1, 0); !reflect.DeepEqual(first, second) {
+			t.Errorf("ids, expected %v got %v", first, second)
+		}
+
+		seen := make(map[uint64]bool)
+		for i, id := range first {
+			if id == 0 || id >= 1<<53 {
+				t.Errorf("feature %v id, expected in (0, 2^53) got %v", i, id)
+			}
+			if seen[id] {
+				t.Errorf("feature %v id, expected unique got %v", i, id)
+			}
+			seen[id] = true
+		}
+		if first[3] != 42 {
+			t.Errorf("existing id, expected 42 got %v", first[3])
+		}
+	})
+}
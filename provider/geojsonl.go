@@ -0,0 +1,33 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// EncodeGeoJSONL writes the features of the layer for the tile to w as
+// newline delimited GeoJSON: each feature is written as a single line
+// GeoJSON Feature object, see Feature.GeoJSON, followed by a newline.
+// Features are written as they are streamed from TileFeatures, nothing is
+// buffered. If ctx is canceled mid stream writing stops after the current
+// line and the context's error is returned, so w only holds whole lines.
+func EncodeGeoJSONL(ctx context.Context, t Tiler, layer string, tile Tile, w io.Writer) error {
+	err := t.TileFeatures(ctx, layer, tile, func(f *Feature) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		b, err := f.GeoJSON()
+		if err != nil {
+			return err
+		}
+		// json.Marshal does not produce newlines, so the feature is a single line
+		_, err = w.Write(append(b, '\n'))
+		return err
+	})
+	if err != nil && !errors.Is(err, ErrNoFeatures) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package provider_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+// cancelWriter cancels the context once n writes have been made
+type cancelWriter struct {
+	bytes.Buffer
+	n      int
+	cancel context.CancelFunc
+}
+
+func (cw *cancelWriter) Write(b []byte) (int, error) {
+	if cw.n--; cw.n == 0 {
+		cw.cancel()
+	}
+	return cw.Buffer.Write(b)
+}
+
+func TestEncodeGeoJSONL(t *testing.T) {
+	tiler := featuresTiler{
+		features: []provider.Feature{
+			{ID: 1, Geometry: geom.Point{1, 2}, Tags: map[string]interface{}{"name": "a"}},
+			{ID: 2, Geometry: geom.LineString{{0, 0}, {1, 1}}},
+			{ID: 3},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := provider.EncodeGeoJSONL(context.Background(), tiler, "", provider.NewTile(0, 0, 0, 0, 3857), &buf); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+
+	expected := `{"type":"Feature","id":1,"geometry":{"type":"Point","coordinates":[1,2]},"properties":{"name":"a"}}
+{"type":"Feature","id":2,"geometry":{"type":"LineString","coordinates":[[0,0],[1,1]]},"properties":null}
+{"type":"Feature","id":3,"geometry":null,"properties":null}
+`
+	if got := buf.String(); got != expected {
+		t.Errorf("output, expected\n%v\ngot\n%v", expected, got)
+	}
+
+	// canceling mid stream stops after the current line
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cw := &cancelWriter{n: 1, cancel: cancel}
+	err := provider.EncodeGeoJSONL(ctx, tiler, "", provider.NewTile(0, 0, 0, 0, 3857), cw)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled error, expected %v got %v", context.Canceled, err)
+	}
+	if lines := strings.Count(cw.String(), "\n"); lines != 1 {
+		t.Errorf("canceled lines, expected 1 got %v", lines)
+	}
+}
@@ -0,0 +1,162 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/internal/log"
+)
+
+// geomKind is the dimension of a geometry, ignoring if it is a multi geometry
+type geomKind uint8
+
+const (
+	geomKindUnknown geomKind = iota
+	geomKindPoint
+	geomKindLine
+	geomKindPolygon
+)
+
+// geomKindOf returns the kind of the geometry and if it is a multi geometry
+func geomKindOf(g geom.Geometry) (kind geomKind, multi bool) {
+	switch g.(type) {
+	case geom.Pointer:
+		return geomKindPoint, false
+	case geom.MultiPointer:
+		return geomKindPoint, true
+	case geom.LineStringer:
+		return geomKindLine, false
+	case geom.MultiLineStringer:
+		return geomKindLine, true
+	case geom.Polygoner:
+		return geomKindPolygon, false
+	case geom.MultiPolygoner:
+		return geomKindPolygon, true
+	default:
+		return geomKindUnknown, false
+	}
+}
+
+// WithGeomTypeCoercion wraps the Tiler so every feature's geometry is coerced
+// to the type of target, which should be one of geom.Point, geom.MultiPoint,
+// geom.LineString, geom.MultiLineString, geom.Polygon or geom.MultiPolygon,
+// i.e. geom.Polygon{}. Parts of the target's dimension are extracted from
+// collections and multi geometries, other parts are dropped. Features with
+// no parts of the target's dimension are dropped and logged.
+//
+// A feature with several matching parts becomes the multi geometry of the
+// target's dimension even if target is not a multi geometry, so no parts are
+// lost. If target is not one of the types above t is returned.
+func WithGeomTypeCoercion(t Tiler, target geom.Geometry) Tiler {
+	kind, multi := geomKindOf(target)
+	if kind == geomKindUnknown {
+		return t
+	}
+	return &geomCoercionTiler{
+		Tiler: t,
+		kind:  kind,
+		multi: multi,
+	}
+}
+
+type geomCoercionTiler struct {
+	Tiler
+	kind  geomKind
+	multi bool
+}
+
+func (gct *geomCoercionTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	return gct.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		if f.Geometry == nil {
+			return fn(f)
+		}
+
+		geo := coerceGeometry(f.Geometry, gct.kind, gct.multi)
+		if geo == nil {
+			log.Debugf("layer (%v) feature %v dropped, unable to coerce %T", layer, f.ID, f.Geometry)
+			return nil
+		}
+		f.Geometry = geo
+		return fn(f)
+	})
+}
+
+// coerceGeometry returns the parts of g of the kind as a single geometry, or
+// a multi geometry if multi is true or there is more than one part. If there
+// are no parts of the kind nil is returned.
+func coerceGeometry(g geom.Geometry, kind geomKind, multi bool) geom.Geometry {
+	var parts geomParts
+	parts.add(g)
+
+	switch kind {
+	case geomKindPoint:
+		switch {
+		case len(parts.points) == 0:
+			return nil
+		case len(parts.points) == 1 && !multi:
+			return geom.Point(parts.points[0])
+		default:
+			return geom.MultiPoint(parts.points)
+		}
+	case geomKindLine:
+		switch {
+		case len(parts.lines) == 0:
+			return nil
+		case len(parts.lines) == 1 && !multi:
+			return geom.LineString(parts.lines[0])
+		default:
+			return geom.MultiLineString(parts.lines)
+		}
+	case geomKindPolygon:
+		switch {
+		case len(parts.polygons) == 0:
+			return nil
+		case len(parts.polygons) == 1 && !multi:
+			return geom.Polygon(parts.polygons[0])
+		default:
+			return geom.MultiPolygon(parts.polygons)
+		}
+	default:
+		return nil
+	}
+}
+
+// geomParts collects the non empty parts of geometries by kind
+type geomParts struct {
+	points   [][2]float64
+	lines    [][][2]float64
+	polygons [][][][2]float64
+}
+
+func (gp *geomParts) add(g geom.Geometry) {
+	switch gg := g.(type) {
+	case geom.Pointer:
+		gp.points = append(gp.points, gg.XY())
+	case geom.MultiPointer:
+		gp.points = append(gp.points, gg.Points()...)
+	case geom.LineStringer:
+		if line := gg.Verticies(); len(line) > 0 {
+			gp.lines = append(gp.lines, line)
+		}
+	case geom.MultiLineStringer:
+		for _, line := range gg.LineStrings() {
+			if len(line) > 0 {
+				gp.lines = append(gp.lines, line)
+			}
+		}
+	case geom.Polygoner:
+		if poly := gg.LinearRings(); len(poly) > 0 {
+			gp.polygons = append(gp.polygons, poly)
+		}
+	case geom.MultiPolygoner:
+		for _, poly := range gg.Polygons() {
+			if len(poly) > 0 {
+				gp.polygons = append(gp.polygons, poly)
+			}
+		}
+	case geom.Collectioner:
+		for _, cg := range gg.Geometries() {
+			gp.add(cg)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package provider_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithGeomTypeCoercion(t *testing.T) {
+	square := geom.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}}
+	triangle := geom.Polygon{{{20, 20}, {30, 20}, {30, 30}}}
+
+	type tcase struct {
+		target   geom.Geometry
+		geom     geom.Geometry
+		expected geom.Geometry
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		tiler := provider.WithGeomTypeCoercion(featuresTiler{
+			features: []provider.Feature{{ID: 1, Geometry: tc.geom}},
+		}, tc.target)
+
+		got, err := collect(tiler, "parcels", provider.NewTile(0, 0, 0, 0, 3857))
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+
+		if tc.expected == nil {
+			if len(got) != 0 {
+				t.Errorf("features, expected 0 got %v", got)
+			}
+			return
+		}
+		if len(got) != 1 {
+			t.Fatalf("features, expected 1 got %v", len(got))
+		}
+		if !reflect.DeepEqual(got[0].Geometry, tc.expected) {
+			t.Errorf("geometry, expected %v got %v", tc.expected, got[0].Geometry)
+		}
+	}
+
+	tests := map[string]tcase{
+		"polygon from collection": {
+			target:   geom.Polygon{},
+			geom:     geom.Collection{geom.Point{1, 1}, square},
+			expected: square,
+		},
+		"polygons from collection": {
+			target:   geom.Polygon{},
+			geom:     geom.Collection{square, geom.LineString{{0, 0}, {1, 1}}, geom.Collection{triangle}},
+			expected: geom.MultiPolygon{square, triangle},
+		},
+		"multi polygon target": {
+			target:   geom.MultiPolygon{},
+			geom:     square,
+			expected: geom.MultiPolygon{square},
+		},
+		"points from multi point": {
+			target:   geom.Point{},
+			geom:     geom.MultiPoint{{1, 2}},
+			expected: geom.Point{1, 2},
+		},
+		"no matching parts": {
+			target: geom.LineString{},
+			geom:   geom.Collection{geom.Point{1, 1}, square},
+		},
+		"unsupported target": {
+			target:   geom.Collection{},
+			geom:     geom.Point{1, 1},
+			expected: geom.Point{1, 1},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
@@ -0,0 +1,196 @@
+package provider
+
+import (
+	"context"
+	"math"
+
+	"github.com/go-spatial/geom"
+)
+
+// BufferQuadrantSegments is the number of segments approximating a quarter
+// circle in the geometries buffered by WithGeometryBuffer, as PostGIS'
+// quad_segs
+var BufferQuadrantSegments = 8
+
+// WithGeometryBuffer wraps the Tiler so point and line features are replaced
+// by their buffer, the area within distance(zoom) of the geometry, i.e. to
+// render roads as polygons or to create click targets. The distance is in
+// the units of the tile's SRID, meters for WebMercator tiles, and features
+// not in the tile's SRID are reprojected first.
+//
+// Points become circles and lines become polygons with round caps and
+// joins, approximated with BufferQuadrantSegments segments per quarter
+// circle. Multi points and multi lines become multi polygons of the buffer
+// of each part, which overlap where the parts are closer than twice the
+// distance. The buffers are not unioned, so the buffer of a line turning
+// back on itself within the distance, or with segments shorter than the
+// distance at a sharp turn, self intersects. Polygons and collections are
+// passed through unchanged, as are lines without any points and all
+// features when the distance is <= 0. The empty parts of multi lines are
+// dropped, and multi lines with only empty parts are passed through.
+//
+// Buffering is CPU heavy, so it is opt in.
+func WithGeometryBuffer(t Tiler, distance func(zoom uint) float64) Tiler {
+	return &geomBufferTiler{
+		Tiler:    t,
+		distance: distance,
+	}
+}
+
+type geomBufferTiler struct {
+	Tiler
+	distance func(zoom uint) float64
+}
+
+func (gbt *geomBufferTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	z, _, _ := t.ZXY()
+	d := gbt.distance(z)
+	_, tileSRID := t.Extent()
+
+	return gbt.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		if d <= 0 {
+			return fn(f)
+		}
+		switch f.Geometry.(type) {
+		case geom.Point, geom.MultiPoint, geom.LineString, geom.MultiLineString:
+		default:
+			return fn(f)
+		}
+
+		if err := reprojectFeatureInPlace(f, tileSRID); err != nil {
+			return err
+		}
+
+		switch g := f.Geometry.(type) {
+		case geom.Point:
+			f.Geometry = bufferLine([][2]float64{g}, d)
+		case geom.MultiPoint:
+			mp := make(geom.MultiPolygon, len(g))
+			for i := range g {
+				mp[i] = bufferLine([][2]float64{g[i]}, d)
+			}
+			f.Geometry = mp
+		case geom.LineString:
+			if poly := bufferLine(g, d); poly != nil {
+				f.Geometry = poly
+			}
+		case geom.MultiLineString:
+			mp := make(geom.MultiPolygon, 0, len(g))
+			for i := range g {
+				if poly := bufferLine(g[i], d); poly != nil {
+					mp = append(mp, poly)
+				}
+			}
+			if len(mp) > 0 {
+				f.Geometry = mp
+			}
+		}
+		return fn(f)
+	})
+}
+
+// bufferLine returns the polygon within d of the line, a circle if the line
+// is a single point. The polygon's outline follows the left side of the
+// line, around the cap at its end, back along its right side and around the
+// cap at its start, and is reversed so it is counter clockwise like the
+// circles. An empty line has no buffer and nil is returned.
+func bufferLine(line [][2]float64, d float64) geom.Polygon {
+	// repeated points have no direction
+	pts := make([][2]float64, 0, len(line))
+	for _, pt := range line {
+		if len(pts) == 0 || pts[len(pts)-1] != pt {
+			pts = append(pts, pt)
+		}
+	}
+	switch len(pts) {
+	case 0:
+		return nil
+	case 1:
+		return geom.Polygon{bufferArc(nil, pts[0], d, 0, 2*math.Pi)[1:]}
+	}
+
+	reversed := make([][2]float64, len(pts))
+	for i := range pts {
+		reversed[len(pts)-1-i] = pts[i]
+	}
+
+	ring := bufferSide(nil, pts, d)
+	ring = bufferCap(ring, pts, d)
+	ring = bufferSide(ring, reversed, d)
+	ring = bufferCap(ring, reversed, d)
+	// the ring ends where it started
+	ring = ring[:len(ring)-1]
+	for i, j := 0, len(ring)-1; i < j; i, j = i+1, j-1 {
+		ring[i], ring[j] = ring[j], ring[i]
+	}
+	return geom.Polygon{ring}
+}
+
+// bufferNormal returns the unit normal to the left of the segment from a to b
+func bufferNormal(a, b [2]float64) [2]float64 {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	l := math.Hypot(dx, dy)
+	return [2]float64{-dy / l, dx / l}
+}
+
+// bufferSide appends the points offset by d to the left of the line to ring.
+// Joins turning right, around the outside of the turn, are round, while
+// joins turning left are the intersection of the offset segments.
+func bufferSide(ring [][2]float64, pts [][2]float64, d float64) [][2]float64 {
+	n := bufferNormal(pts[0], pts[1])
+	ring = append(ring, [2]float64{pts[0][0] + d*n[0], pts[0][1] + d*n[1]})
+
+	for i := 1; i < len(pts)-1; i++ {
+		prev, next := n, bufferNormal(pts[i], pts[i+1])
+		// the cross product of the segments' normals is that of their directions
+		cross := prev[0]*next[1] - prev[1]*next[0]
+
+		switch {
+		case cross < 0:
+			// a right turn, around the outside of the turn
+			from := math.Atan2(prev[1], prev[0])
+			sweep := math.Atan2(next[1], next[0]) - from
+			if sweep > 0 {
+				sweep -= 2 * math.Pi
+			}
+			ring = bufferArc(ring, pts[i], d, from, sweep)
+		case cross > 0:
+			// a left turn, where the offset segments intersect
+			a := [2]float64{pts[i-1][0] + d*prev[0], pts[i-1][1] + d*prev[1]}
+			b := [2]float64{pts[i][0] + d*next[0], pts[i][1] + d*next[1]}
+			da := [2]float64{pts[i][0] - pts[i-1][0], pts[i][1] - pts[i-1][1]}
+			db := [2]float64{pts[i+1][0] - pts[i][0], pts[i+1][1] - pts[i][1]}
+			t := ((b[0]-a[0])*db[1] - (b[1]-a[1])*db[0]) / (da[0]*db[1] - da[1]*db[0])
+			ring = append(ring, [2]float64{a[0] + t*da[0], a[1] + t*da[1]})
+		default:
+			// straight on
+			ring = append(ring, [2]float64{pts[i][0] + d*next[0], pts[i][1] + d*next[1]})
+		}
+		n = next
+	}
+
+	last := pts[len(pts)-1]
+	return append(ring, [2]float64{last[0] + d*n[0], last[1] + d*n[1]})
+}
+
+// bufferCap appends the round cap at the end of the line to ring, from the
+// left of the line clockwise to its right
+func bufferCap(ring [][2]float64, pts [][2]float64, d float64) [][2]float64 {
+	n := bufferNormal(pts[len(pts)-2], pts[len(pts)-1])
+	return bufferArc(ring, pts[len(pts)-1], d, math.Atan2(n[1], n[0]), -math.Pi)
+}
+
+// bufferArc appends the arc of radius d around c, from the angle from
+// sweeping by sweep radians, to ring. The arc's first point, which ends the
+// ring, is not appended.
+func bufferArc(ring [][2]float64, c [2]float64, d, from, sweep float64) [][2]float64 {
+	segs := int(math.Ceil(math.Abs(sweep) / (math.Pi / 2) * float64(BufferQuadrantSegments)))
+	if segs < 1 {
+		segs = 1
+	}
+	for i := 1; i <= segs; i++ {
+		a := from + sweep*float64(i)/float64(segs)
+		ring = append(ring, [2]float64{c[0] + d*math.Cos(a), c[1] + d*math.Sin(a)})
+	}
+	return ring
+}
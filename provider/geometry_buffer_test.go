@@ -0,0 +1,131 @@
+package provider_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithGeometryBuffer(t *testing.T) {
+	const d = 50.0
+
+	// area returns the signed area of the polygon's ring, positive when it is
+	// counter clockwise
+	area := func(t *testing.T, g geom.Geometry) float64 {
+		poly, ok := g.(geom.Polygon)
+		if !ok || len(poly) != 1 {
+			t.Fatalf("geometry, expected a polygon with 1 ring got %v", g)
+		}
+		var a float64
+		for i, pt := range poly[0] {
+			next := poly[0][(i+1)%len(poly[0])]
+			a += pt[0]*next[1] - next[0]*pt[1]
+		}
+		return a / 2
+	}
+
+	type tcase struct {
+		geom geom.Geometry
+		// area is the expected area of the buffer, 0 for geometries which are
+		// not buffered
+		area float64
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		var zoom uint
+		tiler := provider.WithGeometryBuffer(featuresTiler{
+			features: []provider.Feature{{ID: 1, Geometry: tc.geom}},
+		}, func(z uint) float64 {
+			zoom = z
+			return d
+		})
+
+		got, err := collect(tiler, "roads", provider.NewTile(12, 2048, 2047, 0, 3857))
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		if zoom != 12 {
+			t.Errorf("zoom, expected 12 got %v", zoom)
+		}
+		if len(got) != 1 {
+			t.Fatalf("features, expected 1 got %v", len(got))
+		}
+
+		if tc.area == 0 {
+			if !reflect.DeepEqual(got[0].Geometry, tc.geom) {
+				t.Errorf("geometry, expected %v got %v", tc.geom, got[0].Geometry)
+			}
+			return
+		}
+		// the round caps and joins are approximated by polygons inside the
+		// circles, which are about 0.6% smaller
+		if a := area(t, got[0].Geometry); math.Abs(a-tc.area) > tc.area*0.01 {
+			t.Errorf("area, expected %v got %v", tc.area, a)
+		}
+	}
+
+	tests := map[string]tcase{
+		"point": {
+			geom: geom.Point{100, 100},
+			area: math.Pi * d * d,
+		},
+		"straight line": {
+			geom: geom.LineString{{0, 0}, {300, 400}},
+			area: 2*d*500 + math.Pi*d*d,
+		},
+		"line with repeated points": {
+			geom: geom.LineString{{0, 0}, {0, 0}, {1000, 0}, {1000, 0}},
+			area: 2*d*1000 + math.Pi*d*d,
+		},
+		"line turning left": {
+			geom: geom.LineString{{0, 0}, {1000, 0}, {1000, 1000}},
+			area: 2*d*2000 + math.Pi*d*d,
+		},
+		"line turning right": {
+			geom: geom.LineString{{0, 0}, {1000, 0}, {1000, -1000}},
+			area: 2*d*2000 + math.Pi*d*d,
+		},
+		"line going straight on": {
+			geom: geom.LineString{{0, 0}, {500, 0}, {1000, 0}},
+			area: 2*d*1000 + math.Pi*d*d,
+		},
+		"polygon": {
+			geom: geom.Polygon{{{0, 0}, {1000, 0}, {1000, 1000}}},
+		},
+		"empty line": {
+			geom: geom.LineString{},
+		},
+		"multi line with only empty parts": {
+			geom: geom.MultiLineString{{}},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
+
+func TestWithGeometryBufferEmptyParts(t *testing.T) {
+	tiler := provider.WithGeometryBuffer(featuresTiler{
+		features: []provider.Feature{
+			{ID: 1, Geometry: geom.MultiLineString{{}, {{0, 0}, {300, 400}}, {}}},
+		},
+	}, func(z uint) float64 { return 50 })
+
+	got, err := collect(tiler, "roads", provider.NewTile(12, 2048, 2047, 0, 3857))
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("features, expected 1 got %v", len(got))
+	}
+	// the empty parts are dropped
+	mp, ok := got[0].Geometry.(geom.MultiPolygon)
+	if !ok || len(mp) != 1 {
+		t.Errorf("geometry, expected a multi polygon with 1 polygon got %v", got[0].Geometry)
+	}
+}
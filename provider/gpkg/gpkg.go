@@ -7,6 +7,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"math"
+	"strconv"
 
 	"github.com/go-spatial/geom"
 	"github.com/go-spatial/geom/encoding/wkb"
@@ -131,6 +133,75 @@ func (p *Provider) TileFeatures(ctx context.Context, layer string, tile provider
 	}
 	defer rows.Close()
 
+	return decodeFeatures(ctx, pLayer, rows, fn)
+}
+
+// ScanLayer pages through the features of the layer in order of the layer's
+// id field. The cursor is the id of the last feature of the previous page.
+// The !BBOX! token of a layer configured with sql matches every feature and
+// the !ZOOM! token is replaced with tegola.MaxZ.
+func (p *Provider) ScanLayer(ctx context.Context, layer string, cursor provider.Cursor, pageSize int, fn func(f *provider.Feature) error) (provider.Cursor, error) {
+	pLayer, ok := p.layers[layer]
+	if !ok {
+		return "", fmt.Errorf("gpkg: layer (%v) not found", layer)
+	}
+	if pageSize <= 0 {
+		return "", fmt.Errorf("gpkg: invalid page size (%v)", pageSize)
+	}
+
+	var lastID uint64
+	if cursor != "" {
+		var err error
+		if lastID, err = strconv.ParseUint(string(cursor), 10, 64); err != nil {
+			return "", fmt.Errorf("gpkg: invalid cursor (%v)", cursor)
+		}
+	}
+
+	var qtext string
+	if pLayer.tablename != "" {
+		selectClause := fmt.Sprintf("SELECT l.`%v`, l.`%v`", pLayer.idFieldname, pLayer.geomFieldname)
+
+		for _, tf := range pLayer.tagFieldnames {
+			selectClause += fmt.Sprintf(", l.`%v`", tf)
+		}
+
+		qtext = fmt.Sprintf("%v FROM `%v` l WHERE l.`%v` IS NOT NULL AND l.`%v` > ? ORDER BY l.`%v` LIMIT ?", selectClause, pLayer.tablename, pLayer.geomFieldname, pLayer.idFieldname, pLayer.idFieldname)
+	} else {
+		world := geom.NewExtent(
+			[2]float64{-math.MaxFloat64, -math.MaxFloat64},
+			[2]float64{math.MaxFloat64, math.MaxFloat64},
+		)
+		qtext = fmt.Sprintf("SELECT * FROM (%v) WHERE `%v` > ? ORDER BY `%v` LIMIT ?", replaceTokens(pLayer.sql, tegola.MaxZ, world), pLayer.idFieldname, pLayer.idFieldname)
+	}
+
+	log.Debugf("qtext: %v", qtext)
+
+	rows, err := p.db.QueryContext(ctx, qtext, lastID, pageSize)
+	if err != nil {
+		log.Errorf("err during query: %v - %v", qtext, err)
+		return "", err
+	}
+	defer rows.Close()
+
+	var count int
+	err = decodeFeatures(ctx, pLayer, rows, func(f *provider.Feature) error {
+		count++
+		lastID = f.ID
+		return fn(f)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// a short page is the last one
+	if count < pageSize {
+		return "", nil
+	}
+	return provider.Cursor(strconv.FormatUint(lastID, 10)), nil
+}
+
+// decodeFeatures passes each row of rows, as a feature of the layer, to fn
+func decodeFeatures(ctx context.Context, pLayer Layer, rows *sql.Rows, fn func(f *provider.Feature) error) error {
 	cols, err := rows.Columns()
 	if err != nil {
 		return err
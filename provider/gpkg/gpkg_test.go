@@ -250,6 +250,10 @@ func (t *MockTile) BufferedExtent() (*geom.Extent, uint64) { return t.bufferedEx
 
 func (t *MockTile) ZXY() (uint, uint, uint) { return t.Z, t.X, t.Y }
 
+func (t *MockTile) Resolution() float64 {
+	return provider.NewTile(t.Z, t.X, t.Y, 0, uint(t.srid)).Resolution()
+}
+
 func TestTileFeatures(t *testing.T) {
 	type tcase struct {
 		config               dict.Dict
@@ -400,6 +404,148 @@ func TestTileFeatures(t *testing.T) {
 	}
 }
 
+func TestScanLayer(t *testing.T) {
+	type tcase struct {
+		config               dict.Dict
+		layerName            string
+		pageSize             int
+		expectedFeatureCount int
+		expectedPages        int
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			t.Parallel()
+
+			p, err := gpkg.NewTileProvider(tc.config)
+			if err != nil {
+				t.Fatalf("new tile, expected nil got %v", err)
+				return
+			}
+
+			var (
+				cursor       provider.Cursor
+				pages        int
+				featureCount int
+				lastID       uint64
+			)
+			for {
+				cursor, err = provider.ScanLayer(context.TODO(), p, tc.layerName, cursor, tc.pageSize, func(f *provider.Feature) error {
+					if f.ID <= lastID {
+						t.Errorf("feature id, expected > %v got %v", lastID, f.ID)
+					}
+					if f.Geometry == nil {
+						t.Errorf("feature (%v) geometry, expected geometry got nil", f.ID)
+					}
+					lastID = f.ID
+					featureCount++
+					return nil
+				})
+				if err != nil {
+					t.Fatalf("scan page %v, expected nil got %v", pages, err)
+				}
+				pages++
+				if cursor == "" {
+					break
+				}
+			}
+
+			if tc.expectedFeatureCount != featureCount {
+				t.Errorf("feature count, expected %v got %v", tc.expectedFeatureCount, featureCount)
+			}
+			if tc.expectedPages != pages {
+				t.Errorf("pages, expected %v got %v", tc.expectedPages, pages)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"tablename": {
+			config: map[string]interface{}{
+				"filepath": GPKGAthensFilePath,
+				"layers": []map[string]interface{}{
+					{"name": "rl_lines", "tablename": "rail_lines"},
+				},
+			},
+			layerName:            "rl_lines",
+			pageSize:             50,
+			expectedFeatureCount: 187,
+			expectedPages:        4,
+		},
+		"sql with tokens": {
+			config: map[string]interface{}{
+				"filepath": GPKGNaturalEarthFilePath,
+				"layers": []map[string]interface{}{
+					{
+						"name": "land",
+						"sql": `
+							SELECT
+								fid, geom, featurecla, min_zoom, minx, miny, maxx, maxy
+							FROM
+								ne_110m_land t JOIN rtree_ne_110m_land_geom si ON t.fid = si.id
+							WHERE
+								!BBOX! AND min_zoom <= !ZOOM!`,
+					},
+				},
+			},
+			layerName:            "land",
+			pageSize:             40,
+			expectedFeatureCount: 127,
+			expectedPages:        4,
+		},
+		"single page": {
+			config: map[string]interface{}{
+				"filepath": GPKGNaturalEarthFilePath,
+				"layers": []map[string]interface{}{
+					{"name": "land", "tablename": "ne_110m_land"},
+				},
+			},
+			layerName:            "land",
+			pageSize:             1000,
+			expectedFeatureCount: 127,
+			expectedPages:        1,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestScanLayerErrors(t *testing.T) {
+	p, err := gpkg.NewTileProvider(dict.Dict{
+		"filepath": GPKGNaturalEarthFilePath,
+		"layers": []map[string]interface{}{
+			{"name": "land", "tablename": "ne_110m_land"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("new tile, expected nil got %v", err)
+	}
+
+	type tcase struct {
+		layerName string
+		cursor    provider.Cursor
+		pageSize  int
+	}
+
+	tests := map[string]tcase{
+		"unknown layer":     {layerName: "water", pageSize: 10},
+		"invalid cursor":    {layerName: "land", cursor: "abc", pageSize: 10},
+		"invalid page size": {layerName: "land", pageSize: 0},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			_, err := provider.ScanLayer(context.TODO(), p, tc.layerName, tc.cursor, tc.pageSize, func(f *provider.Feature) error { return nil })
+			if err == nil {
+				t.Errorf("error, expected error got nil")
+			}
+		})
+	}
+}
+
 func TestConfigs(t *testing.T) {
 	type tcase struct {
 		config       dict.Dict
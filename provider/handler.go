@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-spatial/geom/encoding/mvt"
+	"github.com/go-spatial/tegola"
+	"github.com/go-spatial/tegola/internal/log"
+)
+
+// HandlerOpts configures the http.Handler returned by Handler
+type HandlerOpts struct {
+	// Buffer is the tile buffer, in pixels, used to request features
+	Buffer uint
+	// SRID of the requested tiles, defaults to tegola.WebMercator
+	SRID uint
+	// MaxAge is set as the Cache-Control max-age of tile responses,
+	// a MaxAge <= 0 sets Cache-Control to no-cache
+	MaxAge time.Duration
+}
+
+// Handler returns an http.Handler serving the layer of the Tiler as MVT
+// tiles, encoded with EncodeStream, at paths ending in /:z/:x/:y.pbf. Any
+// segments before the tile coordinates are ignored, so the handler can be
+// mounted under any prefix. This allows using a provider in a net/http
+// server without the full tegola server.
+//
+// Requests for an invalid tile path respond with 400 Bad Request and failed
+// encodes with 500 Internal Server Error.
+func Handler(t Tiler, layer string, opts HandlerOpts) http.Handler {
+	if opts.SRID == 0 {
+		opts.SRID = tegola.WebMercator
+	}
+	return &tileHandler{
+		tiler: t,
+		layer: layer,
+		opts:  opts,
+	}
+}
+
+type tileHandler struct {
+	tiler Tiler
+	layer string
+	opts  HandlerOpts
+}
+
+func (th *tileHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	tile, err := th.parsePath(r.URL.Path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeStream(r.Context(), th.tiler, th.layer, tile, &buf); err != nil {
+		if errors.Is(err, context.Canceled) {
+			return
+		}
+		errMsg := fmt.Sprintf("error encoding layer (%v) tile %v: %v", th.layer, TileKey(tile), err)
+		log.Error(errMsg)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	// mimetype for mapbox vector tiles
+	// https://www.iana.org/assignments/media-types/application/vnd.mapbox-vector-tile
+	w.Header().Set("Content-Type", mvt.MimeType)
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", buf.Len()))
+	if th.opts.MaxAge > 0 {
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int64(th.opts.MaxAge/time.Second)))
+	} else {
+		w.Header().Set("Cache-Control", "no-cache")
+	}
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodHead {
+		return
+	}
+	w.Write(buf.Bytes())
+}
+
+// parsePath parses the tile from the last three segments of the path
+func (th *tileHandler) parsePath(path string) (Tile, error) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) < 3 {
+		return nil, ErrInvalidTilePath{Path: path, Reason: "expected /:z/:x/:y.pbf"}
+	}
+	zxy := parts[len(parts)-3:]
+
+	if !strings.HasSuffix(zxy[2], ".pbf") {
+		return nil, ErrInvalidTilePath{Path: path, Reason: "expected .pbf extension"}
+	}
+	zxy[2] = strings.TrimSuffix(zxy[2], ".pbf")
+
+	z, err := parseTileCoord("Z", zxy[0], tegola.MaxZ)
+	if err != nil {
+		return nil, ErrInvalidTilePath{Path: path, Reason: err.Error()}
+	}
+
+	maxXY := uint64(1)<<z - 1
+
+	x, err := parseTileCoord("X", zxy[1], maxXY)
+	if err != nil {
+		return nil, ErrInvalidTilePath{Path: path, Reason: err.Error()}
+	}
+
+	y, err := parseTileCoord("Y", zxy[2], maxXY)
+	if err != nil {
+		return nil, ErrInvalidTilePath{Path: path, Reason: err.Error()}
+	}
+
+	return NewTile(uint(z), uint(x), uint(y), th.opts.Buffer, th.opts.SRID), nil
+}
@@ -0,0 +1,130 @@
+package provider_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/mvt"
+	vectorTile "github.com/go-spatial/geom/encoding/mvt/vector_tile"
+	"github.com/go-spatial/tegola/provider"
+	"github.com/golang/protobuf/proto"
+)
+
+func TestHandler(t *testing.T) {
+	tiler := featuresTiler{
+		features: []provider.Feature{
+			{ID: 1, SRID: 3857, Geometry: geom.Point{0, 0}},
+			{ID: 2, SRID: 3857, Geometry: geom.Point{100, 100}},
+		},
+	}
+
+	type tcase struct {
+		tiler        provider.Tiler
+		method       string
+		path         string
+		opts         provider.HandlerOpts
+		status       int
+		cacheControl string
+		features     int
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		method := tc.method
+		if method == "" {
+			method = http.MethodGet
+		}
+		w := httptest.NewRecorder()
+		provider.Handler(tc.tiler, "places", tc.opts).ServeHTTP(w, httptest.NewRequest(method, tc.path, nil))
+
+		if w.Code != tc.status {
+			t.Fatalf("status, expected %v got %v: %v", tc.status, w.Code, w.Body.String())
+		}
+		if tc.status != http.StatusOK {
+			return
+		}
+
+		if ct := w.Header().Get("Content-Type"); ct != mvt.MimeType {
+			t.Errorf("content type, expected %v got %v", mvt.MimeType, ct)
+		}
+		if cc := w.Header().Get("Cache-Control"); cc != tc.cacheControl {
+			t.Errorf("cache control, expected %v got %v", tc.cacheControl, cc)
+		}
+		if method == http.MethodHead {
+			if w.Body.Len() != 0 {
+				t.Errorf("body, expected empty got %v bytes", w.Body.Len())
+			}
+			return
+		}
+
+		var tile vectorTile.Tile
+		if err := proto.Unmarshal(w.Body.Bytes(), &tile); err != nil {
+			t.Fatalf("unmarshal, expected nil got %v", err)
+		}
+		if len(tile.Layers) != 1 || tile.Layers[0].GetName() != "places" {
+			t.Fatalf("layers, expected [places] got %v", tile.Layers)
+		}
+		if len(tile.Layers[0].Features) != tc.features {
+			t.Errorf("features, expected %v got %v", tc.features, len(tile.Layers[0].Features))
+		}
+	}
+
+	tests := map[string]tcase{
+		"tile": {
+			tiler:        tiler,
+			path:         "/0/0/0.pbf",
+			status:       http.StatusOK,
+			cacheControl: "no-cache",
+			features:     2,
+		},
+		"prefix and max age": {
+			tiler:        tiler,
+			path:         "/tiles/places/1/1/0.pbf",
+			opts:         provider.HandlerOpts{MaxAge: time.Hour},
+			status:       http.StatusOK,
+			cacheControl: "public, max-age=3600",
+			features:     2,
+		},
+		"head": {
+			tiler:        tiler,
+			method:       http.MethodHead,
+			path:         "/0/0/0.pbf",
+			status:       http.StatusOK,
+			cacheControl: "no-cache",
+		},
+		"post": {
+			tiler:  tiler,
+			method: http.MethodPost,
+			path:   "/0/0/0.pbf",
+			status: http.StatusMethodNotAllowed,
+		},
+		"out of range": {
+			tiler:  tiler,
+			path:   "/1/2/0.pbf",
+			status: http.StatusBadRequest,
+		},
+		"wrong extension": {
+			tiler:  tiler,
+			path:   "/0/0/0.json",
+			status: http.StatusBadRequest,
+		},
+		"short path": {
+			tiler:  tiler,
+			path:   "/0/0.pbf",
+			status: http.StatusBadRequest,
+		},
+		"provider error": {
+			tiler:  featuresTiler{err: errors.New("boom")},
+			path:   "/0/0/0.pbf",
+			status: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
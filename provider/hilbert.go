@@ -0,0 +1,115 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/go-spatial/geom"
+)
+
+// hilbertOrder is the order of the Hilbert curve used to sort features,
+// the tile is divided into a 2^hilbertOrder by 2^hilbertOrder grid
+const hilbertOrder = 16
+
+// WithHilbertOrder wraps the Tiler so features are passed to the callback
+// sorted by the position of their center along a Hilbert curve covering the
+// tile. Spatially close features are then close in the output, which makes
+// encoded tiles more compressible, and the order is reproducible regardless
+// of the order the provider returns features in. Features with the same
+// position keep their relative order.
+//
+// The center used is the center of the feature's bounding box. As every
+// feature of the tile must be read before any can be passed on, all the
+// features of the tile are held in memory.
+func WithHilbertOrder(t Tiler) Tiler {
+	return &hilbertTiler{Tiler: t}
+}
+
+type hilbertTiler struct {
+	Tiler
+}
+
+type hilbertFeature struct {
+	f Feature
+	d uint64
+}
+
+func (ht *hilbertTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	ext, tileSRID := t.BufferedExtent()
+
+	var features []hilbertFeature
+	err := ht.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		hf := hilbertFeature{f: *f}
+		if f.Geometry != nil && !geom.IsEmpty(f.Geometry) {
+			g, err := reprojectFeature(f, tileSRID)
+			if err != nil {
+				return err
+			}
+			if fext, err := geom.NewExtentFromGeometry(g); err == nil {
+				hf.d = hilbertIndex(ext, (fext.MinX()+fext.MaxX())/2, (fext.MinY()+fext.MaxY())/2)
+			}
+		}
+		features = append(features, hf)
+		return nil
+	})
+	if err != nil && !errors.Is(err, ErrNoFeatures) {
+		return err
+	}
+
+	sort.SliceStable(features, func(i, j int) bool { return features[i].d < features[j].d })
+
+	for i := range features {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(&features[i].f); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// hilbertIndex returns the distance along the Hilbert curve covering the
+// extent of the point. Points outside of the extent are clamped to its edge.
+func hilbertIndex(ext *geom.Extent, x, y float64) uint64 {
+	const n = 1 << hilbertOrder
+
+	cell := func(v, min, span float64) uint64 {
+		c := (v - min) / span * n
+		switch {
+		case c < 0 || c != c:
+			return 0
+		case c >= n:
+			return n - 1
+		default:
+			return uint64(c)
+		}
+	}
+	hx := cell(x, ext.MinX(), ext.XSpan())
+	// y is flipped so the curve starts at the top left of the tile
+	hy := cell(ext.MaxY()-y, 0, ext.YSpan())
+
+	// ref: https://en.wikipedia.org/wiki/Hilbert_curve#Applications_and_mapping_algorithms
+	var d uint64
+	for s := uint64(n / 2); s > 0; s /= 2 {
+		var rx, ry uint64
+		if hx&s > 0 {
+			rx = 1
+		}
+		if hy&s > 0 {
+			ry = 1
+		}
+		d += s * s * ((3 * rx) ^ ry)
+
+		// rotate the quadrant
+		if ry == 0 {
+			if rx == 1 {
+				hx = n - 1 - hx
+				hy = n - 1 - hy
+			}
+			hx, hy = hy, hx
+		}
+	}
+	return d
+}
@@ -0,0 +1,92 @@
+package provider_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/slippy"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithHilbertOrder(t *testing.T) {
+	const q = slippy.WebMercatorMax / 2
+
+	tiler := featuresTiler{
+		features: []provider.Feature{
+			{ID: 1, SRID: 3857, Geometry: geom.Point{q, q}},
+			{ID: 2, SRID: 3857, Geometry: geom.Point{-q, -q}},
+			{ID: 3, SRID: 3857, Geometry: geom.Point{q, -q}},
+			{ID: 4, SRID: 3857, Geometry: geom.Point{-q, q}},
+			// no geometry sorts first
+			{ID: 5, SRID: 3857},
+		},
+	}
+
+	features, err := collect(provider.WithHilbertOrder(tiler), "", provider.NewTile(0, 0, 0, 0, 3857))
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+
+	var ids []uint64
+	for _, f := range features {
+		ids = append(ids, f.ID)
+	}
+	// the curve starts at the top left, going down, right and then up
+	expected := []uint64{5, 4, 2, 3, 1}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Errorf("order, expected %v got %v", expected, ids)
+	}
+}
+
+// BenchmarkHilbertOrder reports the gzipped size of a tile encoded with and
+// without the features in Hilbert order
+func BenchmarkHilbertOrder(b *testing.B) {
+	const n = 5000
+
+	tile := provider.NewTile(14, 8192, 8192, 64, 3857)
+	ext, _ := tile.Extent()
+
+	// features are clustered around a few hundred locations, but in a
+	// random order, with each location having similar properties
+	r := rand.New(rand.NewSource(1))
+	tiler := featuresTiler{features: make([]provider.Feature, n)}
+	for i := range tiler.features {
+		loc := r.Intn(300)
+		lr := rand.New(rand.NewSource(int64(loc)))
+		x := ext.MinX() + lr.Float64()*ext.XSpan() + r.Float64()*10
+		y := ext.MinY() + lr.Float64()*ext.YSpan() + r.Float64()*10
+		tiler.features[i] = provider.Feature{
+			ID:       uint64(i + 1),
+			SRID:     3857,
+			Geometry: geom.LineString{{x, y}, {x + 20, y + 10}, {x + 30, y + 40}},
+			Tags:     map[string]interface{}{"street": fmt.Sprintf("street %v", loc)},
+		}
+	}
+
+	for name, t := range map[string]provider.Tiler{
+		"unordered": tiler,
+		"hilbert":   provider.WithHilbertOrder(tiler),
+	} {
+		t := t
+		b.Run(name, func(b *testing.B) {
+			var size int
+			for i := 0; i < b.N; i++ {
+				var buf, gz bytes.Buffer
+				if err := provider.EncodeStream(context.Background(), t, "roads", tile, &buf); err != nil {
+					b.Fatal(err)
+				}
+				zw := gzip.NewWriter(&gz)
+				zw.Write(buf.Bytes())
+				zw.Close()
+				size = gz.Len()
+			}
+			b.ReportMetric(float64(size), "gzip-bytes")
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-spatial/tegola/dict"
+	"github.com/go-spatial/tegola/internal/log"
+)
+
+// InitOpts controls how InitAll initializes providers
+type InitOpts struct {
+	// Parallelism is the most providers initialized at once. Values less
+	// than 1 initialize the providers one at a time.
+	Parallelism int
+	// Timeout is how long each provider has to initialize, 0 means no timeout
+	Timeout time.Duration
+	// OnInit is called with each provider which initialized. Calls are
+	// serialized, so OnInit does not need to be safe for concurrent use.
+	OnInit func(name string, t Tiler)
+}
+
+// InitAll initializes the providers, keyed by name, concurrently with at most
+// opts.Parallelism providers initializing at once. This keeps a slow provider
+// from holding up the rest, without opening every provider's connections at
+// once. The provider type is read from each config's "type", and the
+// providers are tracked by name so they can be reloaded, see ForNamed.
+//
+// InitAll returns once every provider has initialized or failed, with an
+// ErrInitAll holding the error of each provider which failed. Providers
+// which fail are not passed to opts.OnInit, so callers treating any failure
+// as fatal should call Cleanup.
+//
+// A provider which is still initializing when opts.Timeout passes fails with
+// an ErrInitTimeout and its slot is given to the next provider. As a
+// provider's initialization can not be interrupted it carries on in the
+// background, and the provider is closed, if it has a Close method, should
+// it go on to initialize.
+func InitAll(configs map[string]dict.Dicter, opts InitOpts) error {
+	parallelism := opts.Parallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	// start the providers in a consistent order
+	names := make([]string, 0, len(configs))
+	for name := range configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var (
+		wg   sync.WaitGroup
+		lock sync.Mutex
+		errs = make(ErrInitAll)
+		sem  = make(chan struct{}, parallelism)
+	)
+	for _, name := range names {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(name string, config dict.Dicter) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			tiler, err := initNamed(name, config, opts.Timeout)
+
+			lock.Lock()
+			defer lock.Unlock()
+			if err != nil {
+				log.Errorf("provider (%v) failed to initialize: %v", name, err)
+				errs[name] = err
+				return
+			}
+			log.Infof("provider (%v) initialized", name)
+			if opts.OnInit != nil {
+				opts.OnInit(name, tiler)
+			}
+		}(name, configs[name])
+	}
+	wg.Wait()
+
+	if len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
+
+// initNamed initializes the named provider, giving up after timeout
+func initNamed(name string, config dict.Dicter, timeout time.Duration) (Tiler, error) {
+	typ, err := config.String("type", nil)
+	if err != nil {
+		return nil, err
+	}
+	if timeout <= 0 {
+		return ForNamed(name, typ, config)
+	}
+
+	type result struct {
+		tiler Tiler
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		tiler, err := For(typ, config)
+		done <- result{tiler: tiler, err: err}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			return nil, r.err
+		}
+		return trackNamed(name, typ, r.tiler), nil
+	case <-timer.C:
+		go func() {
+			if r := <-done; r.err == nil {
+				log.Warnf("provider (%v) initialized after its timeout, closing", name)
+				(&liveInstance{Tiler: r.tiler}).close()
+			}
+		}()
+		return nil, ErrInitTimeout{Name: name, Timeout: timeout}
+	}
+}
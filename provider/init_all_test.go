@@ -0,0 +1,161 @@
+package provider_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-spatial/tegola/dict"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestInitAll(t *testing.T) {
+	var (
+		lock            sync.Mutex
+		running, peak   int
+		errInitAllFails = errors.New("init failed")
+	)
+	err := provider.Register("init_all_test", func(d dict.Dicter) (provider.Tiler, error) {
+		lock.Lock()
+		if running++; running > peak {
+			peak = running
+		}
+		lock.Unlock()
+		defer func() {
+			lock.Lock()
+			running--
+			lock.Unlock()
+		}()
+
+		delay, err := d.Uint("delay", new(uint))
+		if err != nil {
+			return nil, err
+		}
+		time.Sleep(time.Duration(delay) * time.Millisecond)
+
+		if fail, _ := d.Bool("fail", new(bool)); fail {
+			return nil, errInitAllFails
+		}
+		return featuresTiler{}, nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("register, expected nil got %v", err)
+	}
+
+	type tcase struct {
+		configs     map[string]dict.Dicter
+		opts        provider.InitOpts
+		initialized []string
+		failed      map[string]error
+		peak        int
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		lock.Lock()
+		peak = 0
+		lock.Unlock()
+
+		var initialized []string
+		tc.opts.OnInit = func(name string, _ provider.Tiler) {
+			initialized = append(initialized, name)
+		}
+
+		err := provider.InitAll(tc.configs, tc.opts)
+		if len(tc.failed) == 0 {
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+		} else {
+			var errs provider.ErrInitAll
+			if !errors.As(err, &errs) {
+				t.Fatalf("error, expected ErrInitAll got %v", err)
+			}
+			if len(errs) != len(tc.failed) {
+				t.Errorf("failed, expected %v got %v", tc.failed, errs)
+			}
+			for name, expected := range tc.failed {
+				if !errors.Is(errs[name], expected) {
+					t.Errorf("provider (%v) error, expected %v got %v", name, expected, errs[name])
+				}
+			}
+		}
+
+		if len(initialized) != len(tc.initialized) {
+			t.Fatalf("initialized, expected %v got %v", tc.initialized, initialized)
+		}
+		got := make(map[string]bool)
+		for _, name := range initialized {
+			got[name] = true
+		}
+		for _, name := range tc.initialized {
+			if !got[name] {
+				t.Errorf("initialized, expected %v got %v", tc.initialized, initialized)
+			}
+		}
+
+		lock.Lock()
+		if peak > tc.peak {
+			t.Errorf("parallelism, expected at most %v got %v", tc.peak, peak)
+		}
+		lock.Unlock()
+
+		// wait for providers which timed out to finish initializing
+		for {
+			lock.Lock()
+			r := running
+			lock.Unlock()
+			if r == 0 {
+				break
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	config := func(delay uint, fail bool) dict.Dicter {
+		return dict.Dict{"type": "init_all_test", "delay": delay, "fail": fail}
+	}
+
+	tests := map[string]tcase{
+		"bounded": {
+			configs: map[string]dict.Dicter{
+				"a": config(10, false),
+				"b": config(10, false),
+				"c": config(10, false),
+				"d": config(10, false),
+			},
+			opts:        provider.InitOpts{Parallelism: 2},
+			initialized: []string{"a", "b", "c", "d"},
+			peak:        2,
+		},
+		"serial": {
+			configs: map[string]dict.Dicter{
+				"a": config(1, false),
+				"b": config(1, false),
+			},
+			initialized: []string{"a", "b"},
+			peak:        1,
+		},
+		"aggregated errors": {
+			configs: map[string]dict.Dicter{
+				"ok":      config(0, false),
+				"fails":   config(0, true),
+				"slow":    config(100, false),
+				"untyped": dict.Dict{},
+			},
+			opts:        provider.InitOpts{Parallelism: 4, Timeout: 50 * time.Millisecond},
+			initialized: []string{"ok"},
+			failed: map[string]error{
+				"fails":   errInitAllFails,
+				"slow":    provider.ErrInitTimeout{Name: "slow", Timeout: 50 * time.Millisecond},
+				"untyped": dict.ErrKeyRequired("type"),
+			},
+			peak: 4,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
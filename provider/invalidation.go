@@ -0,0 +1,25 @@
+package provider
+
+import (
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola"
+)
+
+// InvalidationTiles returns the tiles, from minZoom to maxZoom inclusive,
+// intersecting the extent of a data edit, so the cached tiles affected by the
+// edit can be purged. The extent must be in WebMercator or WGS84, and may
+// cross the antimeridian, see TilesForExtent. The tiles have no buffer, as
+// used in cache keys, and are ordered by zoom.
+//
+// maxZoom is capped at tegola.MaxZ. nil is
+// returned if minZoom is greater than maxZoom or the SRID is not supported.
+func InvalidationTiles(ext *geom.Extent, srid uint64, minZoom, maxZoom uint) []Tile {
+	if maxZoom > tegola.MaxZ {
+		maxZoom = tegola.MaxZ
+	}
+	tiles, err := TilesForExtent(ext, srid, minZoom, maxZoom)
+	if err != nil {
+		return nil
+	}
+	return tiles
+}
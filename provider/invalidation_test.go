@@ -0,0 +1,77 @@
+package provider_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/slippy"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestInvalidationTiles(t *testing.T) {
+	// the width of a z10 tile
+	res := slippy.WebMercatorMax * 2 / 1024
+
+	// tileRange returns the z/x/y of the tiles in the range, ordered as zxys
+	tileRange := func(z, minx, maxx, miny, maxy uint) (keys [][3]uint) {
+		for x := minx; x <= maxx; x++ {
+			for y := miny; y <= maxy; y++ {
+				keys = append(keys, [3]uint{z, x, y})
+			}
+		}
+		return keys
+	}
+
+	type tcase struct {
+		ext      *geom.Extent
+		srid     uint64
+		minZoom  uint
+		maxZoom  uint
+		expected [][3]uint
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		tiles := provider.InvalidationTiles(tc.ext, tc.srid, tc.minZoom, tc.maxZoom)
+		if got := zxys(tiles); !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("tiles, expected %v got %v", tc.expected, got)
+		}
+		for _, tile := range tiles {
+			ext, _ := tile.Extent()
+			buffered, _ := tile.BufferedExtent()
+			if !reflect.DeepEqual(ext, buffered) {
+				t.Errorf("tile %v buffer, expected none", provider.TileKey(tile))
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"small edit": {
+			// a z10 tile wide edit in the north east quarter of the world,
+			// centered on the corner of the z10 tiles 513/510 and 514/511
+			ext:      &geom.Extent{res * 1.5, res * 0.5, res * 2.5, res * 1.5},
+			srid:     3857,
+			minZoom:  10,
+			maxZoom:  12,
+			expected: append(append(tileRange(10, 513, 514, 510, 511), tileRange(11, 1027, 1029, 1021, 1023)...), tileRange(12, 2054, 2058, 2042, 2046)...),
+		},
+		"antimeridian": {
+			ext:      &geom.Extent{179, 1, -179, 2},
+			srid:     4326,
+			minZoom:  2,
+			maxZoom:  3,
+			expected: [][3]uint{{2, 0, 1}, {2, 3, 1}, {3, 0, 3}, {3, 7, 3}},
+		},
+		"invalid zooms": {
+			ext:     &geom.Extent{0, 0, 1, 1},
+			srid:    3857,
+			minZoom: 5,
+			maxZoom: 4,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+)
+
+// JoinPredicate is the spatial relationship of a JoinSpec
+type JoinPredicate uint8
+
+const (
+	// JoinContains joins the related feature containing the feature, i.e. the
+	// region a point is in
+	JoinContains JoinPredicate = iota
+	// JoinIntersects joins a related feature intersecting the feature
+	JoinIntersects
+)
+
+func (p JoinPredicate) String() string {
+	switch p {
+	case JoinContains:
+		return "contains"
+	case JoinIntersects:
+		return "intersects"
+	default:
+		return "unknown"
+	}
+}
+
+// JoinSpec describes how TileFeaturesJoin enriches the features of a layer
+// with the properties of a spatially related layer. Each feature is joined to
+// a feature of the related Layer matching the Predicate, and the Fields of
+// the related feature are added to the feature's properties, named Prefix
+// followed by the field, i.e. a Prefix of "region_" names the field "name"
+// "region_name". If several related features match, which is joined is up
+// to the provider; features without a match keep their own properties only.
+type JoinSpec struct {
+	// Layer is the related layer of the provider
+	Layer     string
+	Predicate JoinPredicate
+	Fields    []string
+	Prefix    string
+}
+
+// Validate reports if the spec can be used to join a layer
+func (spec JoinSpec) Validate() error {
+	if spec.Layer == "" {
+		return fmt.Errorf("join spec requires a related layer")
+	}
+	if spec.Predicate > JoinIntersects {
+		return fmt.Errorf("join spec has an unknown predicate %v", spec.Predicate)
+	}
+	if len(spec.Fields) == 0 {
+		return fmt.Errorf("join spec must have at least 1 field")
+	}
+	names := make(map[string]bool, len(spec.Fields))
+	for _, field := range spec.Fields {
+		if field == "" {
+			return fmt.Errorf("join spec has an empty field")
+		}
+		if names[field] {
+			return fmt.Errorf("join field (%v) is duplicated", field)
+		}
+		names[field] = true
+	}
+	return nil
+}
+
+// SpatialJoiner is implemented by providers which are able to join the
+// features of a layer to the features of a spatially related layer in the
+// data source, i.e. in the database, rather than the features being joined
+// by the caller. The joined properties take precedence over properties of
+// the feature with the same name.
+type SpatialJoiner interface {
+	TileFeaturesJoin(ctx context.Context, layer string, t Tile, join JoinSpec, fn func(f *Feature) error) error
+}
+
+// TileFeaturesJoin streams the features of the layer for the tile joined as
+// described by the spec, if the Tiler implements SpatialJoiner. Otherwise
+// ErrUnsupported is returned. The spec is validated before it is passed to
+// the provider.
+func TileFeaturesJoin(ctx context.Context, t Tiler, layer string, tile Tile, join JoinSpec, fn func(f *Feature) error) error {
+	sj, release, ok := acquireAs[SpatialJoiner](t)
+	if !ok {
+		return ErrUnsupported
+	}
+	defer release()
+	if err := join.Validate(); err != nil {
+		return err
+	}
+	return sj.TileFeaturesJoin(ctx, layer, tile, join, fn)
+}
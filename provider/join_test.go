@@ -0,0 +1,57 @@
+package provider_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestJoinSpecValidate(t *testing.T) {
+	type tcase struct {
+		spec provider.JoinSpec
+		err  bool
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		err := tc.spec.Validate()
+		if tc.err != (err != nil) {
+			t.Errorf("error, expected error %v got %v", tc.err, err)
+		}
+	}
+
+	tests := map[string]tcase{
+		"valid": {
+			spec: provider.JoinSpec{Layer: "regions", Predicate: provider.JoinContains, Fields: []string{"name", "code"}, Prefix: "region_"},
+		},
+		"no layer": {
+			spec: provider.JoinSpec{Fields: []string{"name"}},
+			err:  true,
+		},
+		"unknown predicate": {
+			spec: provider.JoinSpec{Layer: "regions", Predicate: provider.JoinIntersects + 1, Fields: []string{"name"}},
+			err:  true,
+		},
+		"no fields": {
+			spec: provider.JoinSpec{Layer: "regions"},
+			err:  true,
+		},
+		"duplicate field": {
+			spec: provider.JoinSpec{Layer: "regions", Fields: []string{"name", "name"}},
+			err:  true,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
+
+func TestTileFeaturesJoinUnsupported(t *testing.T) {
+	spec := provider.JoinSpec{Layer: "regions", Fields: []string{"name"}}
+	err := provider.TileFeaturesJoin(context.Background(), featuresTiler{}, "", provider.NewTile(0, 0, 0, 0, 3857), spec, func(f *provider.Feature) error { return nil })
+	if err != provider.ErrUnsupported {
+		t.Errorf("error, expected %v got %v", provider.ErrUnsupported, err)
+	}
+}
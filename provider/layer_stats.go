@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// LayerStat holds counts of the TileFeatures results for a provider layer
+type LayerStat struct {
+	// Empty is the number of tiles which had no features
+	Empty uint64
+	// NonEmpty is the number of tiles which had at least one feature
+	NonEmpty uint64
+}
+
+// EmptyRatio returns the fraction of tiles which were empty,
+// 0 is returned if no tiles have been counted
+func (ls LayerStat) EmptyRatio() float64 {
+	total := ls.Empty + ls.NonEmpty
+	if total == 0 {
+		return 0
+	}
+	return float64(ls.Empty) / float64(total)
+}
+
+type layerStatKey struct {
+	name  string
+	layer string
+}
+
+type layerStatCounts struct {
+	empty    uint64
+	nonEmpty uint64
+}
+
+// layerStats holds the *layerStatCounts for each provider layer
+var layerStats sync.Map
+
+// LayerStats returns the counts of empty and non-empty tiles for the layer of
+// the named provider, as recorded by a Tiler wrapped with WithLayerStats.
+func LayerStats(name, layer string) LayerStat {
+	v, ok := layerStats.Load(layerStatKey{name: name, layer: layer})
+	if !ok {
+		return LayerStat{}
+	}
+	counts := v.(*layerStatCounts)
+	return LayerStat{
+		Empty:    atomic.LoadUint64(&counts.empty),
+		NonEmpty: atomic.LoadUint64(&counts.nonEmpty),
+	}
+}
+
+// WithLayerStats wraps the Tiler so the number of empty and non-empty results
+// of TileFeatures are counted per layer under the provider name, see
+// LayerStats. Only successful calls are counted, a call returning ErrNoFeatures
+// is counted as empty.
+func WithLayerStats(t Tiler, name string) Tiler {
+	return &layerStatsTiler{
+		Tiler: t,
+		name:  name,
+	}
+}
+
+type layerStatsTiler struct {
+	Tiler
+	name string
+}
+
+func (lst *layerStatsTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	var count int
+	err := lst.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		count++
+		return fn(f)
+	})
+	if err != nil && !errors.Is(err, ErrNoFeatures) {
+		return err
+	}
+
+	key := layerStatKey{name: lst.name, layer: layer}
+	v, ok := layerStats.Load(key)
+	if !ok {
+		v, _ = layerStats.LoadOrStore(key, new(layerStatCounts))
+	}
+	counts := v.(*layerStatCounts)
+	if count == 0 {
+		atomic.AddUint64(&counts.empty, 1)
+	} else {
+		atomic.AddUint64(&counts.nonEmpty, 1)
+	}
+
+	return err
+}
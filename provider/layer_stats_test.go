@@ -0,0 +1,43 @@
+package provider_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithLayerStats(t *testing.T) {
+	tile := provider.NewTile(0, 0, 0, 0, 3857)
+	noop := func(*provider.Feature) error { return nil }
+
+	full := provider.WithLayerStats(featuresTiler{
+		features: []provider.Feature{{ID: 1, Geometry: geom.Point{0, 0}}},
+	}, "stats_test")
+	empty := provider.WithLayerStats(featuresTiler{}, "stats_test")
+	noFeatures := provider.WithLayerStats(featuresTiler{err: provider.ErrNoFeatures}, "stats_test")
+	failing := provider.WithLayerStats(featuresTiler{err: provider.ErrCanceled}, "stats_test")
+
+	for _, tiler := range []provider.Tiler{full, full, full, empty, noFeatures, failing} {
+		tiler.TileFeatures(context.Background(), "roads", tile, noop)
+	}
+	empty.TileFeatures(context.Background(), "water", tile, noop)
+
+	expected := provider.LayerStat{Empty: 2, NonEmpty: 3}
+	if got := provider.LayerStats("stats_test", "roads"); got != expected {
+		t.Errorf("roads, expected %+v got %+v", expected, got)
+	}
+	if got := provider.LayerStats("stats_test", "roads").EmptyRatio(); got != 0.4 {
+		t.Errorf("roads empty ratio, expected 0.4 got %v", got)
+	}
+
+	expected = provider.LayerStat{Empty: 1}
+	if got := provider.LayerStats("stats_test", "water"); got != expected {
+		t.Errorf("water, expected %+v got %+v", expected, got)
+	}
+
+	if got := provider.LayerStats("stats_unknown", "roads"); got != (provider.LayerStat{}) {
+		t.Errorf("unknown, expected zero got %+v", got)
+	}
+}
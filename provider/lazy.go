@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-spatial/tegola/dict"
+	"github.com/go-spatial/tegola/internal/log"
+)
+
+var (
+	// LazyInitBackoff is how long a lazy provider waits after a failed
+	// initialization before trying again. The wait doubles after each
+	// consecutive failure, up to LazyInitMaxBackoff.
+	LazyInitBackoff = time.Second
+	// LazyInitMaxBackoff is the longest a lazy provider waits between
+	// initialization attempts
+	LazyInitMaxBackoff = time.Minute
+)
+
+// ForLazy returns a provider of the given type which is not initialized until
+// its first Layers or TileFeatures call. This allows a server to start while
+// a provider's backend is unavailable. The provider is initialized at most
+// once; a failed initialization is cached and returned, as an ErrLazyInit, to
+// callers until the backoff has passed, after which it is tried again.
+//
+// Only the provider name is checked when ForLazy is called, the config is not
+// validated until the provider is initialized. Looking up one of the
+// provider's optional interfaces with As initializes it as well, and finds
+// none while it fails to initialize.
+func ForLazy(name string, config dict.Dicter) (Tiler, error) {
+	if registered, pt := IsRegistered(name); !registered || pt&TypeStd == 0 {
+		return nil, ErrUnknownProvider{Name: name, KnownProviders: Drivers()}
+	}
+	return &lazyTiler{
+		name:   name,
+		config: config,
+	}, nil
+}
+
+// ForNamedLazy returns a provider of the given type, like ForLazy, which is
+// tracked under name so it can be replaced by Reload, like ForNamed. Reload
+// initializes the new instance right away, so a failed initialization keeps
+// the current instance.
+func ForNamedLazy(name, typ string, config dict.Dicter) (Tiler, error) {
+	tiler, err := ForLazy(typ, config)
+	if err != nil {
+		return nil, err
+	}
+	return trackNamed(name, typ, tiler), nil
+}
+
+type lazyTiler struct {
+	name   string
+	config dict.Dicter
+
+	lock    sync.Mutex
+	tiler   Tiler
+	err     error
+	backoff time.Duration
+	retryAt time.Time
+}
+
+// init returns the initialized provider. Concurrent callers wait on the
+// lock while the provider is being initialized.
+func (lt *lazyTiler) init() (Tiler, error) {
+	lt.lock.Lock()
+	defer lt.lock.Unlock()
+
+	if lt.tiler != nil {
+		return lt.tiler, nil
+	}
+	if lt.err != nil && time.Now().Before(lt.retryAt) {
+		return nil, lt.err
+	}
+
+	tiler, err := For(lt.name, lt.config)
+	if err != nil {
+		if lt.backoff == 0 {
+			lt.backoff = LazyInitBackoff
+		} else if lt.backoff *= 2; lt.backoff > LazyInitMaxBackoff {
+			lt.backoff = LazyInitMaxBackoff
+		}
+		lt.retryAt = time.Now().Add(lt.backoff)
+		lt.err = ErrLazyInit{Name: lt.name, Err: err}
+
+		log.Warnf("lazy provider (%v) failed to initialize, retrying in %v: %v", lt.name, lt.backoff, err)
+		return nil, lt.err
+	}
+
+	log.Infof("lazy provider (%v) initialized", lt.name)
+	lt.tiler = tiler
+	// the config is no longer needed
+	lt.config, lt.err = nil, nil
+	return lt.tiler, nil
+}
+
+func (lt *lazyTiler) Layers() ([]LayerInfo, error) {
+	tiler, err := lt.init()
+	if err != nil {
+		return nil, err
+	}
+	return tiler.Layers()
+}
+
+func (lt *lazyTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	tiler, err := lt.init()
+	if err != nil {
+		return err
+	}
+	return tiler.TileFeatures(ctx, layer, t, fn)
+}
+
+// Unwrap adheres to the Unwrapper interface, returning the initialized
+// provider, or nil if it fails to initialize
+func (lt *lazyTiler) Unwrap() Tiler {
+	tiler, err := lt.init()
+	if err != nil {
+		return nil
+	}
+	return tiler
+}
+
+// Close closes the provider if it has been initialized, without
+// initializing it, i.e. when it is replaced by Reload before it was used
+func (lt *lazyTiler) Close() error {
+	lt.lock.Lock()
+	tiler := lt.tiler
+	lt.lock.Unlock()
+
+	if tiler != nil {
+		(&liveInstance{Tiler: tiler}).close()
+	}
+	return nil
+}
+
+// LazyPending reports if t is a provider returned by ForLazy or ForNamedLazy
+// which has not been initialized yet. Callers which only need the provider's
+// layers once it is used, i.e. when registering maps, can check it to avoid
+// initializing the provider.
+func LazyPending(t Tiler) bool {
+	lt, release, ok := acquireAs[*lazyTiler](t)
+	if !ok {
+		return false
+	}
+	defer release()
+	lt.lock.Lock()
+	defer lt.lock.Unlock()
+	return lt.tiler == nil
+}
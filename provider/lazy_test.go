@@ -0,0 +1,105 @@
+package provider_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-spatial/tegola/dict"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestForLazy(t *testing.T) {
+	defer func(backoff time.Duration) { provider.LazyInitBackoff = backoff }(provider.LazyInitBackoff)
+	provider.LazyInitBackoff = 50 * time.Millisecond
+
+	var (
+		lock  sync.Mutex
+		calls int
+		down  = errors.New("database down")
+	)
+	err := provider.Register("lazy_test", func(dict.Dicter) (provider.Tiler, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		calls++
+		// the backend is down for the first attempt
+		if calls == 1 {
+			return nil, down
+		}
+		return featuresTiler{}, nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("register, expected nil got %v", err)
+	}
+
+	if _, err = provider.ForLazy("lazy_unknown", dict.Dict{}); !errors.As(err, &provider.ErrUnknownProvider{}) {
+		t.Errorf("unknown provider, expected ErrUnknownProvider got %v", err)
+	}
+
+	tiler, err := provider.ForLazy("lazy_test", dict.Dict{})
+	if err != nil {
+		t.Fatalf("for lazy, expected nil got %v", err)
+	}
+	if calls != 0 {
+		t.Fatalf("init calls before use, expected 0 got %v", calls)
+	}
+
+	_, err = tiler.Layers()
+	if !errors.As(err, &provider.ErrLazyInit{}) || !errors.Is(err, down) {
+		t.Errorf("first layers, expected %v got %v", down, err)
+	}
+
+	// the error is cached until the backoff has passed
+	err = tiler.TileFeatures(context.Background(), "", provider.NewTile(0, 0, 0, 0, 3857), func(*provider.Feature) error { return nil })
+	if !errors.Is(err, down) {
+		t.Errorf("cached error, expected %v got %v", down, err)
+	}
+	if calls != 1 {
+		t.Errorf("init calls during backoff, expected 1 got %v", calls)
+	}
+
+	time.Sleep(provider.LazyInitBackoff)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := tiler.Layers(); err != nil {
+				t.Errorf("layers after backoff, expected nil got %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls != 2 {
+		t.Errorf("init calls, expected 2 got %v", calls)
+	}
+}
+
+func TestForLazyOptionalInterfaces(t *testing.T) {
+	err := provider.Register("lazy_etag_test", func(dict.Dicter) (provider.Tiler, error) {
+		return localDelayTiler{}, nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("register, expected nil got %v", err)
+	}
+
+	tiler, err := provider.ForLazy("lazy_etag_test", dict.Dict{})
+	if err != nil {
+		t.Fatalf("for lazy, expected nil got %v", err)
+	}
+	if !provider.LazyPending(tiler) {
+		t.Errorf("pending before use, expected true got false")
+	}
+
+	// looking up an optional interface initializes the provider
+	if _, ok := provider.As[provider.TileETagger](tiler); !ok {
+		t.Errorf("as, expected the provider to be a TileETagger")
+	}
+	if provider.LazyPending(tiler) {
+		t.Errorf("pending after use, expected false got true")
+	}
+}
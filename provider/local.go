@@ -0,0 +1,31 @@
+package provider
+
+import "context"
+
+// LocalTiler is implemented by providers which are able to return features
+// already in tile local coordinates, i.e. PostGIS with ST_AsMVTGeom. This
+// saves the reprojection and quantization that is otherwise needed before
+// the features can be encoded into a MVT.
+type LocalTiler interface {
+	// TileFeaturesLocal will stream the features of the layer for the tile to
+	// the callback function fn. Feature geometries are in tile local pixel
+	// coordinates, with the origin at the top left of the tile, y increasing
+	// downwards and the tile spanning 0 to extent on both axes. Geometries in
+	// the tile's buffer will have coordinates outside of this range. The SRID
+	// of the features is not meaningful and should be ignored.
+	// If fn returns ErrCanceled, TileFeaturesLocal should stop processing.
+	TileFeaturesLocal(ctx context.Context, layer string, t Tile, extent int, fn func(f *Feature) error) error
+}
+
+// TileFeaturesLocal will stream the features of the layer for the tile in
+// tile local coordinates, if the Tiler implements LocalTiler. Otherwise
+// ErrUnsupported is returned and the caller should use TileFeatures and
+// transform the features itself.
+func TileFeaturesLocal(ctx context.Context, t Tiler, layer string, tile Tile, extent int, fn func(f *Feature) error) error {
+	lt, release, ok := acquireAs[LocalTiler](t)
+	if !ok {
+		return ErrUnsupported
+	}
+	defer release()
+	return lt.TileFeaturesLocal(ctx, layer, tile, extent, fn)
+}
@@ -0,0 +1,28 @@
+package provider
+
+var (
+	// MaxLayersPerTile is the maximum number of layers a single tile may be
+	// requested with, protecting providers from a misconfigured map fanning
+	// out into dozens of queries per tile. 0 or less disables the limit.
+	MaxLayersPerTile = 64
+	// TruncateExcessLayers, when true, renders only the first
+	// MaxLayersPerTile layers of a tile requested with more layers, rather
+	// than rejecting the tile with ErrTooManyLayers
+	TruncateExcessLayers = false
+)
+
+// LayerLimit checks the number of layers a tile is requested with against
+// MaxLayersPerTile, returning how many of the layers should be rendered.
+// When the limit is exceeded ErrTooManyLayers is returned, unless
+// TruncateExcessLayers is set in which case the limit is returned and the
+// caller should warn that layers were dropped.
+func LayerLimit(count int) (int, error) {
+	max := MaxLayersPerTile
+	if max <= 0 || count <= max {
+		return count, nil
+	}
+	if TruncateExcessLayers {
+		return max, nil
+	}
+	return 0, ErrTooManyLayers{Count: count, Max: max}
+}
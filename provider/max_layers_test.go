@@ -0,0 +1,74 @@
+package provider_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestLayerLimit(t *testing.T) {
+	type tcase struct {
+		max      int
+		truncate bool
+		count    int
+		expected int
+		err      error
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		defer func(max int, truncate bool) {
+			provider.MaxLayersPerTile, provider.TruncateExcessLayers = max, truncate
+		}(provider.MaxLayersPerTile, provider.TruncateExcessLayers)
+		provider.MaxLayersPerTile, provider.TruncateExcessLayers = tc.max, tc.truncate
+
+		n, err := provider.LayerLimit(tc.count)
+		if tc.err != nil {
+			var tooMany provider.ErrTooManyLayers
+			if !errors.As(err, &tooMany) || tooMany != tc.err {
+				t.Errorf("error, expected %v got %v", tc.err, err)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		if n != tc.expected {
+			t.Errorf("layers, expected %v got %v", tc.expected, n)
+		}
+	}
+
+	tests := map[string]tcase{
+		"under": {
+			max:      4,
+			count:    3,
+			expected: 3,
+		},
+		"at": {
+			max:      4,
+			count:    4,
+			expected: 4,
+		},
+		"over": {
+			max:   4,
+			count: 10,
+			err:   provider.ErrTooManyLayers{Count: 10, Max: 4},
+		},
+		"truncated": {
+			max:      4,
+			truncate: true,
+			count:    10,
+			expected: 4,
+		},
+		"disabled": {
+			max:      0,
+			count:    1000,
+			expected: 1000,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
@@ -0,0 +1,79 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	vectorTile "github.com/go-spatial/geom/encoding/mvt/vector_tile"
+	"github.com/go-spatial/tegola/internal/log"
+	"github.com/golang/protobuf/proto"
+)
+
+// WithMaxTileBytes wraps the MVTTiler so encoded tiles larger than maxBytes
+// are not returned. When a tile is too large, the layers named in dropOrder
+// (by MVT layer name) are removed from the tile one at a time, least
+// important first, until the tile fits. If the tile still does not fit once
+// every layer in dropOrder has been removed, ErrTileTooLarge is returned.
+// Layers not in dropOrder are never dropped, so with no dropOrder any tile
+// over the limit is an error. A maxBytes of 0 or less disables the limit.
+func WithMaxTileBytes(mt MVTTiler, maxBytes int, dropOrder ...string) MVTTiler {
+	if maxBytes <= 0 {
+		return mt
+	}
+	return &maxBytesTiler{
+		MVTTiler:  mt,
+		maxBytes:  maxBytes,
+		dropOrder: dropOrder,
+	}
+}
+
+type maxBytesTiler struct {
+	MVTTiler
+	maxBytes  int
+	dropOrder []string
+}
+
+func (mbt *maxBytesTiler) MVTForLayers(ctx context.Context, t Tile, layers []Layer) ([]byte, error) {
+	b, err := mbt.MVTTiler.MVTForLayers(ctx, t, layers)
+	if err != nil || len(b) <= mbt.maxBytes {
+		return b, err
+	}
+
+	size := len(b)
+	if len(mbt.dropOrder) == 0 {
+		return nil, fmt.Errorf("%w: %v bytes exceeds %v", ErrTileTooLarge, size, mbt.maxBytes)
+	}
+
+	var vtile vectorTile.Tile
+	if err = proto.Unmarshal(b, &vtile); err != nil {
+		return nil, err
+	}
+
+	z, x, y := t.ZXY()
+	for _, name := range mbt.dropOrder {
+		if !dropVTileLayer(&vtile, name) {
+			continue
+		}
+		log.Infof("tile (%v/%v/%v) of %v bytes exceeds %v, dropped layer (%v)", z, x, y, size, mbt.maxBytes, name)
+
+		if b, err = proto.Marshal(&vtile); err != nil {
+			return nil, err
+		}
+		if size = len(b); size <= mbt.maxBytes {
+			return b, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %v bytes exceeds %v", ErrTileTooLarge, size, mbt.maxBytes)
+}
+
+// dropVTileLayer removes the named layer from the tile, reporting if it was found
+func dropVTileLayer(vtile *vectorTile.Tile, name string) bool {
+	for i := range vtile.Layers {
+		if vtile.Layers[i].GetName() == name {
+			vtile.Layers = append(vtile.Layers[:i], vtile.Layers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package provider_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	vectorTile "github.com/go-spatial/geom/encoding/mvt/vector_tile"
+	"github.com/go-spatial/tegola/provider"
+	"github.com/golang/protobuf/proto"
+)
+
+// bytesMVTTiler returns the same encoded tile for every request
+type bytesMVTTiler struct {
+	featuresTiler
+	b []byte
+}
+
+func (bt bytesMVTTiler) MVTForLayers(ctx context.Context, t provider.Tile, layers []provider.Layer) ([]byte, error) {
+	return bt.b, nil
+}
+
+// sizedLayer returns a layer which encodes to roughly size bytes
+func sizedLayer(name string, size int) *vectorTile.Tile_Layer {
+	version := uint32(2)
+	return &vectorTile.Tile_Layer{
+		Version: &version,
+		Name:    &name,
+		Keys:    []string{strings.Repeat("k", size)},
+	}
+}
+
+func TestWithMaxTileBytes(t *testing.T) {
+	b, err := proto.Marshal(&vectorTile.Tile{
+		Layers: []*vectorTile.Tile_Layer{
+			sizedLayer("water", 100),
+			sizedLayer("buildings", 1000),
+			sizedLayer("pois", 500),
+		},
+	})
+	if err != nil {
+		t.Fatalf("marshal, expected nil got %v", err)
+	}
+
+	type tcase struct {
+		maxBytes  int
+		dropOrder []string
+		expected  []string
+		err       error
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		mt := provider.WithMaxTileBytes(bytesMVTTiler{b: b}, tc.maxBytes, tc.dropOrder...)
+		got, err := mt.MVTForLayers(context.Background(), provider.NewTile(0, 0, 0, 0, 3857), nil)
+		if !errors.Is(err, tc.err) {
+			t.Fatalf("error, expected %v got %v", tc.err, err)
+		}
+		if tc.err != nil {
+			return
+		}
+		if len(got) > tc.maxBytes {
+			t.Errorf("size, expected at most %v got %v", tc.maxBytes, len(got))
+		}
+
+		var vtile vectorTile.Tile
+		if err = proto.Unmarshal(got, &vtile); err != nil {
+			t.Fatalf("unmarshal, expected nil got %v", err)
+		}
+		var names []string
+		for _, l := range vtile.Layers {
+			names = append(names, l.GetName())
+		}
+		if !reflect.DeepEqual(names, tc.expected) {
+			t.Errorf("layers, expected %v got %v", tc.expected, names)
+		}
+	}
+
+	tests := map[string]tcase{
+		"under limit": {
+			maxBytes: 4096,
+			expected: []string{"water", "buildings", "pois"},
+		},
+		"too large": {
+			maxBytes: 1024,
+			err:      provider.ErrTileTooLarge,
+		},
+		"drop least important": {
+			maxBytes:  1024,
+			dropOrder: []string{"pois", "buildings"},
+			expected:  []string{"water"},
+		},
+		"drop until fits": {
+			maxBytes:  1200,
+			dropOrder: []string{"pois", "buildings"},
+			expected:  []string{"water", "buildings"},
+		},
+		"drop order exhausted": {
+			maxBytes:  1000,
+			dropOrder: []string{"pois", "water"},
+			err:       provider.ErrTileTooLarge,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
@@ -0,0 +1,115 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-spatial/geom"
+)
+
+// Sizes, in bytes, used by EstimateFeatureSize
+const (
+	featureBaseSize  = 64 // the Feature struct and the pointer to it
+	coordinateSize   = 16 // a [2]float64
+	sliceHeaderSize  = 24 // a line, ring or part of a geometry
+	stringHeaderSize = 16 // a tag key or string value, excluding its bytes
+	tagEntrySize     = 48 // a tag map entry, including the interface value
+)
+
+// FeatureSizeEstimator estimates the memory, in bytes, used by a feature. It
+// is used by WithMemoryBudget and can be replaced to account for providers
+// whose features use more or less memory than EstimateFeatureSize assumes.
+var FeatureSizeEstimator = EstimateFeatureSize
+
+// EstimateFeatureSize returns an approximation of the memory used by the
+// feature. It is the sum of:
+//
+//	64 bytes for the feature itself
+//	16 bytes per coordinate, plus 24 bytes per line, ring or geometry part
+//	48 bytes per tag, plus the length of the key and of string values
+//
+// The estimate does not account for memory shared between features, i.e.
+// interned tag values.
+func EstimateFeatureSize(f *Feature) int {
+	size := featureBaseSize + geometrySize(f.Geometry)
+	for k, v := range f.Tags {
+		size += tagEntrySize + stringHeaderSize + len(k)
+		if s, ok := v.(string); ok {
+			size += len(s)
+		}
+	}
+	return size
+}
+
+// geometrySize returns the estimated size of the geometry's coordinates
+func geometrySize(g geom.Geometry) int {
+	switch gg := g.(type) {
+	case geom.Pointer:
+		return coordinateSize
+	case geom.MultiPointer:
+		return sliceHeaderSize + len(gg.Points())*coordinateSize
+	case geom.LineStringer:
+		return sliceHeaderSize + len(gg.Verticies())*coordinateSize
+	case geom.MultiLineStringer:
+		return linesSize(gg.LineStrings())
+	case geom.Polygoner:
+		return linesSize(gg.LinearRings())
+	case geom.MultiPolygoner:
+		size := sliceHeaderSize
+		for _, poly := range gg.Polygons() {
+			size += linesSize(poly)
+		}
+		return size
+	case geom.Collectioner:
+		size := sliceHeaderSize
+		for _, cg := range gg.Geometries() {
+			size += geometrySize(cg)
+		}
+		return size
+	default:
+		return 0
+	}
+}
+
+func linesSize(lines [][][2]float64) int {
+	size := sliceHeaderSize
+	for _, line := range lines {
+		size += sliceHeaderSize + len(line)*coordinateSize
+	}
+	return size
+}
+
+// WithMemoryBudget wraps the Tiler so the estimated memory of the features
+// passed to the callback during a TileFeatures call is tracked, and the call
+// is aborted with ErrMemoryExceeded once it exceeds maxBytes. This protects
+// callers which accumulate a tile's features, i.e. to encode them, from a
+// single pathological tile using all of the available memory.
+//
+// The memory of each feature is estimated with FeatureSizeEstimator. The
+// feature that crosses the budget is not passed to the callback. A maxBytes
+// <= 0 disables the budget.
+func WithMemoryBudget(t Tiler, maxBytes int) Tiler {
+	if maxBytes <= 0 {
+		return t
+	}
+	return &memoryBudgetTiler{
+		Tiler:    t,
+		maxBytes: maxBytes,
+	}
+}
+
+type memoryBudgetTiler struct {
+	Tiler
+	maxBytes int
+}
+
+func (mbt *memoryBudgetTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	var used int
+	return mbt.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		used += FeatureSizeEstimator(f)
+		if used > mbt.maxBytes {
+			return fmt.Errorf("%w: layer (%v) tile %v used more than %v bytes", ErrMemoryExceeded, layer, TileKey(t), mbt.maxBytes)
+		}
+		return fn(f)
+	})
+}
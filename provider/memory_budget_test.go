@@ -0,0 +1,98 @@
+package provider_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestEstimateFeatureSize(t *testing.T) {
+	type tcase struct {
+		feature  provider.Feature
+		expected int
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		if got := provider.EstimateFeatureSize(&tc.feature); got != tc.expected {
+			t.Errorf("size, expected %v got %v", tc.expected, got)
+		}
+	}
+
+	tests := map[string]tcase{
+		"empty": {
+			expected: 64,
+		},
+		"point": {
+			feature:  provider.Feature{Geometry: geom.Point{1, 2}},
+			expected: 64 + 16,
+		},
+		"line": {
+			feature:  provider.Feature{Geometry: geom.LineString{{1, 2}, {3, 4}}},
+			expected: 64 + 24 + 2*16,
+		},
+		"polygon": {
+			feature:  provider.Feature{Geometry: geom.Polygon{{{0, 0}, {1, 0}, {1, 1}}}},
+			expected: 64 + 24 + 24 + 3*16,
+		},
+		"tags": {
+			feature:  provider.Feature{Tags: map[string]interface{}{"name": "abc", "pop": 10}},
+			expected: 64 + (48 + 16 + 4 + 3) + (48 + 16 + 3),
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
+
+func TestWithMemoryBudget(t *testing.T) {
+	// each feature is estimated at 64 + 24 + 2*16 = 120 bytes
+	line := geom.LineString{{0, 0}, {1, 1}}
+	tiler := featuresTiler{
+		features: []provider.Feature{
+			{ID: 1, Geometry: line},
+			{ID: 2, Geometry: line},
+			{ID: 3, Geometry: line},
+		},
+	}
+
+	type tcase struct {
+		maxBytes int
+		expected int
+		err      error
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		got, err := collect(provider.WithMemoryBudget(tiler, tc.maxBytes), "roads", provider.NewTile(0, 0, 0, 0, 3857))
+		if !errors.Is(err, tc.err) {
+			t.Errorf("error, expected %v got %v", tc.err, err)
+		}
+		if len(got) != tc.expected {
+			t.Errorf("features, expected %v got %v", tc.expected, len(got))
+		}
+	}
+
+	tests := map[string]tcase{
+		"within budget": {
+			maxBytes: 360,
+			expected: 3,
+		},
+		"exceeded": {
+			maxBytes: 300,
+			expected: 2,
+			err:      provider.ErrMemoryExceeded,
+		},
+		"disabled": {
+			maxBytes: 0,
+			expected: 3,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
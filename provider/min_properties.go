@@ -0,0 +1,35 @@
+package provider
+
+import "context"
+
+// WithMinProperties wraps the Tiler so features without any properties are
+// given the fallback properties, for clients which can not handle features
+// with no properties. Features with properties are passed on as is.
+func WithMinProperties(t Tiler, fallback map[string]interface{}) Tiler {
+	if len(fallback) == 0 {
+		return t
+	}
+	return &minPropsTiler{
+		Tiler:    t,
+		fallback: fallback,
+	}
+}
+
+type minPropsTiler struct {
+	Tiler
+	fallback map[string]interface{}
+}
+
+func (mpt *minPropsTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	return mpt.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		if len(f.Tags) == 0 {
+			// each feature gets its own copy, so later
+			// modifications do not leak between features
+			f.Tags = make(map[string]interface{}, len(mpt.fallback))
+			for k, v := range mpt.fallback {
+				f.Tags[k] = v
+			}
+		}
+		return fn(f)
+	})
+}
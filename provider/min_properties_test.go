@@ -0,0 +1,38 @@
+package provider_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithMinProperties(t *testing.T) {
+	tiler := featuresTiler{
+		features: []provider.Feature{
+			{ID: 1, Geometry: geom.Point{1, 1}},
+			{ID: 2, Geometry: geom.Point{1, 1}, Tags: map[string]interface{}{}},
+			{ID: 3, Geometry: geom.Point{1, 1}, Tags: map[string]interface{}{"name": "a"}},
+		},
+	}
+
+	features, err := collect(provider.WithMinProperties(tiler, map[string]interface{}{"kind": "unknown"}), "", provider.NewTile(0, 0, 0, 0, 3857))
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+
+	expected := []map[string]interface{}{
+		{"kind": "unknown"},
+		{"kind": "unknown"},
+		{"name": "a"},
+	}
+	if len(features) != len(expected) {
+		t.Fatalf("features, expected %v got %v", len(expected), len(features))
+	}
+	for i := range features {
+		if !reflect.DeepEqual(features[i].Tags, expected[i]) {
+			t.Errorf("feature %v tags, expected %v got %v", features[i].ID, expected[i], features[i].Tags)
+		}
+	}
+}
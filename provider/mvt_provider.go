@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/go-spatial/tegola/dict"
+	"github.com/go-spatial/tegola/internal/log"
+)
+
+// MVTNamePrefix is prefixed to the names of MVT providers in the config
+const MVTNamePrefix = "mvt_"
+
+// Layer holds information about a query.
+type Layer struct {
+	// Name is the name of the Layer as recognized by the provider
+	Name string
+	// MVTName is the name of the layer to encode into the MVT.
+	// this is often used when different provider layers are used
+	// at different zoom levels but the MVT layer name is consistent
+	MVTName string
+	// Extent is the MVT extent the layer is encoded with, the number of
+	// units across each side of the tile. 0 uses the default of 4096.
+	Extent uint32
+}
+
+// MVTTiler is a provider which encodes the MVT itself, rather than returning
+// features for tegola to encode.
+type MVTTiler interface {
+	Layerer
+
+	// MVTForLayers will return a MVT byte array or an error for the given layer names.
+	MVTForLayers(ctx context.Context, tile Tile, layers []Layer) ([]byte, error)
+}
+
+// MVTInitFunc initialize a MVT provider given a config map. The init function should validate the config map, and report any errors. This is called by the MVTFor function.
+type MVTInitFunc func(dicter dict.Dicter) (MVTTiler, error)
+
+// MVTRegister the MVT provider with the system. This call is generally made in the init functions of the provider.
+// A MVT provider may share its name with a standard provider. The clean up function will be called during
+// shutdown of the provider to allow the provider to do any cleanup.
+func MVTRegister(name string, init MVTInitFunc, cleanup CleanupFunc) error {
+	if providers == nil {
+		providers = make(map[string]pfns)
+	}
+
+	p := providers[name]
+	if p.mvtInit != nil {
+		return ErrProviderAlreadyExists{Name: name}
+	}
+
+	p.mvtInit = init
+	p.mvtCleanup = cleanup
+	providers[name] = p
+
+	notifyObservers(registerObservers, name, TypeMvt)
+	return nil
+}
+
+// MVTDrivers returns a list of registered MVT drivers, prefixed with MVTNamePrefix.
+func MVTDrivers() (l []string) {
+	for k, p := range providers {
+		if p.mvtInit != nil {
+			l = append(l, MVTNamePrefix+k)
+		}
+	}
+
+	return l
+}
+
+// MVTFor function returns a configured MVT provider of the given type, provided the correct config map.
+func MVTFor(name string, config dict.Dicter) (MVTTiler, error) {
+	p, ok := providers[name]
+	if !ok || p.mvtInit == nil {
+		return nil, ErrUnknownProvider{Name: name}
+	}
+
+	config, err := migrateConfig(name, config)
+	if err != nil {
+		return nil, err
+	}
+
+	if config, err = runConfigHooks(name, config); err != nil {
+		return nil, err
+	}
+
+	return p.mvtInit(config)
+}
+
+// MVTCleanup calls the cleanup function of each registered MVT provider.
+func MVTCleanup() {
+	log.Info("cleaning up mvt providers")
+	for _, p := range providers {
+		if p.mvtCleanup != nil {
+			p.mvtCleanup()
+		}
+	}
+}
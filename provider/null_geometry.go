@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/internal/log"
+)
+
+// NullGeomPolicy determines how a feature with a nil geometry is handled
+type NullGeomPolicy uint8
+
+const (
+	// NullGeomSkip drops the feature, counting it. See NullGeometriesSkipped
+	NullGeomSkip NullGeomPolicy = iota
+	// NullGeomError returns an ErrNullGeometry from TileFeatures
+	NullGeomError
+	// NullGeomEmpty passes the feature on with an empty geometry collection
+	NullGeomEmpty
+)
+
+func (p NullGeomPolicy) String() string {
+	switch p {
+	case NullGeomSkip:
+		return "skip"
+	case NullGeomError:
+		return "error"
+	case NullGeomEmpty:
+		return "empty"
+	default:
+		return "unknown"
+	}
+}
+
+// WithNullGeometryPolicy wraps the Tiler so features with a nil geometry are
+// handled according to policy, rather than however the provider handles them.
+func WithNullGeometryPolicy(t Tiler, policy NullGeomPolicy) Tiler {
+	return &nullGeomTiler{
+		Tiler:  t,
+		policy: policy,
+	}
+}
+
+type nullGeomTiler struct {
+	Tiler
+	policy  NullGeomPolicy
+	skipped uint64
+}
+
+func (ngt *nullGeomTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	return ngt.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		if f.Geometry != nil {
+			return fn(f)
+		}
+
+		switch ngt.policy {
+		case NullGeomError:
+			return ErrNullGeometry{Layer: layer, ID: f.ID}
+		case NullGeomEmpty:
+			f.Geometry = geom.Collection{}
+			return fn(f)
+		default:
+			atomic.AddUint64(&ngt.skipped, 1)
+			log.Debugf("layer (%v) feature %v skipped, null geometry", layer, f.ID)
+			return nil
+		}
+	})
+}
+
+// NullGeometriesSkipped returns the number of features skipped by a Tiler
+// wrapped with WithNullGeometryPolicy. If the Tiler was not wrapped ok is
+// false.
+func NullGeometriesSkipped(t Tiler) (skipped uint64, ok bool) {
+	ngt, ok := t.(*nullGeomTiler)
+	if !ok {
+		return 0, false
+	}
+	return atomic.LoadUint64(&ngt.skipped), true
+}
@@ -0,0 +1,77 @@
+package provider_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithNullGeometryPolicy(t *testing.T) {
+	tiler := featuresTiler{
+		features: []provider.Feature{
+			{ID: 1, Geometry: geom.Point{1, 1}},
+			{ID: 2},
+			{ID: 3, Geometry: geom.Point{2, 2}},
+		},
+	}
+
+	type tcase struct {
+		policy   provider.NullGeomPolicy
+		expected []provider.Feature
+		skipped  uint64
+		err      error
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		nt := provider.WithNullGeometryPolicy(tiler, tc.policy)
+		features, err := collect(nt, "roads", provider.NewTile(0, 0, 0, 0, 3857))
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Fatalf("error, expected %v got %v", tc.err, err)
+		}
+		if tc.err == nil && !reflect.DeepEqual(features, tc.expected) {
+			t.Errorf("features, expected %v got %v", tc.expected, features)
+		}
+
+		skipped, ok := provider.NullGeometriesSkipped(nt)
+		if !ok {
+			t.Fatalf("skipped, expected ok got not ok")
+		}
+		if skipped != tc.skipped {
+			t.Errorf("skipped, expected %v got %v", tc.skipped, skipped)
+		}
+	}
+
+	tests := map[string]tcase{
+		"skip": {
+			policy: provider.NullGeomSkip,
+			expected: []provider.Feature{
+				{ID: 1, Geometry: geom.Point{1, 1}},
+				{ID: 3, Geometry: geom.Point{2, 2}},
+			},
+			skipped: 1,
+		},
+		"error": {
+			policy: provider.NullGeomError,
+			err:    provider.ErrNullGeometry{Layer: "roads", ID: 2},
+		},
+		"empty": {
+			policy: provider.NullGeomEmpty,
+			expected: []provider.Feature{
+				{ID: 1, Geometry: geom.Point{1, 1}},
+				{ID: 2, Geometry: geom.Collection{}},
+				{ID: 3, Geometry: geom.Point{2, 2}},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+
+	if _, ok := provider.NullGeometriesSkipped(tiler); ok {
+		t.Errorf("unwrapped skipped, expected not ok got ok")
+	}
+}
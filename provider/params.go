@@ -0,0 +1,23 @@
+package provider
+
+import "context"
+
+type paramsKey struct{}
+
+// WithParams returns a copy of ctx carrying named parameters of the request,
+// i.e. a filter value chosen by the user. Providers supporting parameters
+// read them with ParamsFromContext and must pass them to their queries as
+// bound parameters, never by interpolating them into the query, as their
+// values are not trusted. Providers without parameter support ignore them.
+//
+// Tiles rendered with parameters should not be cached with the tiles
+// rendered without them.
+func WithParams(ctx context.Context, params map[string]interface{}) context.Context {
+	return context.WithValue(ctx, paramsKey{}, params)
+}
+
+// ParamsFromContext returns the parameters set by WithParams, if any
+func ParamsFromContext(ctx context.Context) (map[string]interface{}, bool) {
+	params, ok := ctx.Value(paramsKey{}).(map[string]interface{})
+	return params, ok
+}
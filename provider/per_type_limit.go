@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"context"
+	"sort"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/internal/log"
+)
+
+// geomTypeName returns the name of the geometry's type as used by the
+// PostGIS provider's geometry_type config, i.e. "point" or "multipolygon".
+// An empty string is returned for nil and unknown geometries.
+func geomTypeName(g geom.Geometry) string {
+	if _, ok := g.(geom.Collectioner); ok {
+		return "geometrycollection"
+	}
+
+	kind, multi := geomKindOf(g)
+	var name string
+	switch kind {
+	case geomKindPoint:
+		name = "point"
+	case geomKindLine:
+		name = "linestring"
+	case geomKindPolygon:
+		name = "polygon"
+	default:
+		return ""
+	}
+	if multi {
+		name = "multi" + name
+	}
+	return name
+}
+
+// WithPerTypeLimit wraps the Tiler so each geometry type is capped at its own
+// number of features per call to TileFeatures. limits is keyed by the
+// geometry type's name: point, linestring, polygon, multipoint,
+// multilinestring, multipolygon or geometrycollection. Once a type reaches
+// its limit its remaining features are dropped while features of the other
+// types continue to be passed to the callback; types without a limit are not
+// capped. The number of features dropped for each type is logged.
+//
+// The limits are keyed by name rather than by a geometry value (i.e.
+// geom.Polygon{}) as most geometry types are slices, which can not be map
+// keys. Negative limits are ignored; if no limits remain t is returned.
+func WithPerTypeLimit(t Tiler, limits map[string]int) Tiler {
+	lim := make(map[string]int, len(limits))
+	for name, n := range limits {
+		if n >= 0 {
+			lim[name] = n
+		}
+	}
+	if len(lim) == 0 {
+		return t
+	}
+	return &perTypeLimitTiler{
+		Tiler:  t,
+		limits: lim,
+	}
+}
+
+type perTypeLimitTiler struct {
+	Tiler
+	limits map[string]int
+}
+
+func (ptl *perTypeLimitTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	var (
+		counts  = make(map[string]int, len(ptl.limits))
+		dropped = make(map[string]int)
+	)
+	err := ptl.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		name := geomTypeName(f.Geometry)
+		limit, ok := ptl.limits[name]
+		if !ok {
+			return fn(f)
+		}
+		if counts[name] >= limit {
+			dropped[name]++
+			return nil
+		}
+		counts[name]++
+		return fn(f)
+	})
+
+	names := make([]string, 0, len(dropped))
+	for name := range dropped {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		log.Infof("layer (%v) tile %v dropped %v %v features, exceeding the limit of %v", layer, TileKey(t), dropped[name], name, ptl.limits[name])
+	}
+	return err
+}
@@ -0,0 +1,74 @@
+package provider_test
+
+import (
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithPerTypeLimit(t *testing.T) {
+	poly := geom.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 1}}}
+	var features []provider.Feature
+	// points and polygons interleaved, so limiting one type must not stop the other
+	for i := 0; i < 10; i++ {
+		features = append(features,
+			provider.Feature{ID: uint64(2*i + 1), Geometry: geom.Point{float64(i), float64(i)}},
+			provider.Feature{ID: uint64(2*i + 2), Geometry: poly},
+		)
+	}
+	features = append(features, provider.Feature{ID: 21, Geometry: geom.LineString{{0, 0}, {1, 1}}})
+	tiler := featuresTiler{features: features}
+
+	type tcase struct {
+		limits   map[string]int
+		expected map[string]int
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		got, err := collect(provider.WithPerTypeLimit(tiler, tc.limits), "places", provider.NewTile(0, 0, 0, 0, 3857))
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+
+		counts := make(map[string]int)
+		for _, f := range got {
+			switch f.Geometry.(type) {
+			case geom.Point:
+				counts["point"]++
+			case geom.Polygon:
+				counts["polygon"]++
+			case geom.LineString:
+				counts["linestring"]++
+			}
+		}
+		for name, expected := range tc.expected {
+			if counts[name] != expected {
+				t.Errorf("%v features, expected %v got %v", name, expected, counts[name])
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"points and polygons": {
+			limits:   map[string]int{"point": 2, "polygon": 7},
+			expected: map[string]int{"point": 2, "polygon": 7, "linestring": 1},
+		},
+		"zero limit": {
+			limits:   map[string]int{"point": 0},
+			expected: map[string]int{"point": 0, "polygon": 10, "linestring": 1},
+		},
+		"limit above count": {
+			limits:   map[string]int{"polygon": 20, "linestring": 1},
+			expected: map[string]int{"point": 10, "polygon": 10, "linestring": 1},
+		},
+		"no limits": {
+			expected: map[string]int{"point": 10, "polygon": 10, "linestring": 1},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-spatial/tegola/internal/log"
+)
+
+// QueryPlanner is implemented by providers which are able to explain how
+// they query a layer for a tile, i.e. with EXPLAIN ANALYZE. The plan is the
+// provider's textual output, and the query may be run to produce it.
+type QueryPlanner interface {
+	ExplainTile(ctx context.Context, layer string, t Tile) (string, error)
+}
+
+// ExplainTile returns the provider's query plan for TileFeatures of the layer
+// and tile, if the Tiler implements QueryPlanner. Otherwise ErrUnsupported is
+// returned.
+func ExplainTile(ctx context.Context, t Tiler, layer string, tile Tile) (string, error) {
+	qp, release, ok := acquireAs[QueryPlanner](t)
+	if !ok {
+		return "", ErrUnsupported
+	}
+	defer release()
+	return qp.ExplainTile(ctx, layer, tile)
+}
+
+var (
+	// PlanCaptureInterval is the minimum time between two plans captured by
+	// a Tiler wrapped by WithPlanCapture
+	PlanCaptureInterval = time.Minute
+	// PlanCaptureTimeout bounds the time taken to capture a plan
+	PlanCaptureTimeout = 5 * time.Minute
+)
+
+// WithPlanCapture wraps the Tiler so when a TileFeatures call takes longer
+// than threshold the provider's plan for the query, see QueryPlanner, is
+// captured and passed to sink, automating the reproduction of slow tiles.
+// The duration includes the time spent in the callback.
+//
+// Capturing a plan may run the query again, so at most one plan is captured
+// at a time and at most one every PlanCaptureInterval; slow calls in between
+// are not captured. Plans are captured in the background once the call
+// returns, with the values of its context but without its cancellation, so
+// the call is not slowed further. Errors capturing a plan are logged.
+//
+// If t does not implement QueryPlanner, threshold <= 0 or sink is nil t is
+// returned.
+func WithPlanCapture(t Tiler, threshold time.Duration, sink func(tile Tile, layer, plan string)) Tiler {
+	if threshold <= 0 || sink == nil {
+		return t
+	}
+	_, release, ok := acquireAs[QueryPlanner](t)
+	if !ok {
+		return t
+	}
+	release()
+	return &planCaptureTiler{
+		Tiler:     t,
+		threshold: threshold,
+		sink:      sink,
+	}
+}
+
+type planCaptureTiler struct {
+	Tiler
+	threshold time.Duration
+	sink      func(tile Tile, layer, plan string)
+
+	mu        sync.Mutex
+	capturing bool
+	last      time.Time
+}
+
+func (pct *planCaptureTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	start := time.Now()
+	err := pct.Tiler.TileFeatures(ctx, layer, t, fn)
+	if time.Since(start) > pct.threshold && pct.acquire() {
+		go pct.capture(detachedContext{ctx}, layer, t)
+	}
+	return err
+}
+
+// acquire reports if a plan may be captured, marking a capture as running
+func (pct *planCaptureTiler) acquire() bool {
+	pct.mu.Lock()
+	defer pct.mu.Unlock()
+	if pct.capturing || (!pct.last.IsZero() && time.Since(pct.last) < PlanCaptureInterval) {
+		return false
+	}
+	pct.capturing, pct.last = true, time.Now()
+	return true
+}
+
+func (pct *planCaptureTiler) capture(ctx context.Context, layer string, t Tile) {
+	defer func() {
+		pct.mu.Lock()
+		pct.capturing = false
+		pct.mu.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, PlanCaptureTimeout)
+	defer cancel()
+
+	plan, err := ExplainTile(ctx, pct.Tiler, layer, t)
+	if err != nil {
+		log.Warnf("capturing query plan for layer (%v) tile %v: %v", layer, TileKey(t), err)
+		return
+	}
+	pct.sink(t, layer, plan)
+}
+
+// detachedContext carries the values of its context without its deadline or
+// cancellation
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
@@ -0,0 +1,81 @@
+package provider_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-spatial/tegola/provider"
+)
+
+// planTiler is a delayTiler which explains its queries
+type planTiler struct {
+	delayTiler
+	// asOf is set to the as of time of the context of the last explain
+	asOf chan time.Time
+}
+
+func (pt planTiler) ExplainTile(ctx context.Context, layer string, t provider.Tile) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	asOf, _ := provider.AsOfFromContext(ctx)
+	pt.asOf <- asOf
+	return "Seq Scan on " + layer, nil
+}
+
+func TestWithPlanCapture(t *testing.T) {
+	interval := provider.PlanCaptureInterval
+	defer func() { provider.PlanCaptureInterval = interval }()
+	provider.PlanCaptureInterval = time.Hour
+
+	tiler := planTiler{
+		delayTiler: delayTiler{delays: map[string]time.Duration{"roads": 20 * time.Millisecond}},
+		asOf:       make(chan time.Time, 2),
+	}
+	plans := make(chan string, 2)
+	captured := provider.WithPlanCapture(tiler, 10*time.Millisecond, func(tile provider.Tile, layer, plan string) {
+		plans <- plan
+	})
+
+	asOf := time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC)
+	ctx, cancel := context.WithCancel(provider.WithAsOf(context.Background(), asOf))
+	tile := provider.NewTile(0, 0, 0, 0, 3857)
+	if err := captured.TileFeatures(ctx, "roads", tile, func(f *provider.Feature) error { return nil }); err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	// the plan is captured after the call returns, without its cancellation
+	cancel()
+
+	select {
+	case plan := <-plans:
+		if plan != "Seq Scan on roads" {
+			t.Errorf("plan, expected %q got %q", "Seq Scan on roads", plan)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("plan, expected a plan got none")
+	}
+	if got := <-tiler.asOf; !got.Equal(asOf) {
+		t.Errorf("as of, expected %v got %v", asOf, got)
+	}
+
+	// a second slow call within the interval is not captured
+	if err := captured.TileFeatures(context.Background(), "roads", tile, func(f *provider.Feature) error { return nil }); err != nil {
+		t.Fatalf("second call error, expected nil got %v", err)
+	}
+	select {
+	case plan := <-plans:
+		t.Errorf("second plan, expected none got %q", plan)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// providers which can not explain are not wrapped
+	plain := delayTiler{}
+	if _, ok := provider.WithPlanCapture(plain, time.Millisecond, func(provider.Tile, string, string) {}).(delayTiler); !ok {
+		t.Errorf("unsupported provider, expected the tiler")
+	}
+	if _, err := provider.ExplainTile(context.Background(), plain, "roads", tile); !errors.Is(err, provider.ErrUnsupported) {
+		t.Errorf("explain unsupported, expected %v got %v", provider.ErrUnsupported, err)
+	}
+}
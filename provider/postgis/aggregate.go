@@ -0,0 +1,125 @@
+package postgis
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/go-spatial/tegola"
+	"github.com/go-spatial/tegola/provider"
+)
+
+// AggregateTile adheres to the provider.Aggregator interface. The layer's
+// SQL is wrapped in a query grouping the features by the grid cell their
+// centroid falls in. Features are queried without the tile buffer, so each
+// feature is counted in a single tile.
+func (p Provider) AggregateTile(ctx context.Context, layer string, tile provider.Tile, spec provider.AggSpec, fn func(f *provider.Feature) error) error {
+	plyr, ok := p.Layer(layer)
+	if !ok {
+		return ErrLayerNotFound{layer}
+	}
+	if err := spec.Validate(); err != nil {
+		return err
+	}
+
+	sql, args := replaceParamTokens(ctx, plyr.sql, nil)
+	sql, err := replaceTokens(replaceAsOfToken(ctx, sql), &plyr, tile, false)
+	if err != nil {
+		return fmt.Errorf("error replacing layer tokens for layer (%v) SQL (%v): %v", layer, sql, err)
+	}
+	sql = aggregateSQL(sql, plyr.GeomFieldName(), plyr.SRID(), tile, spec)
+
+	if debugExecuteSQL {
+		log.Printf("TEGOLA_SQL_DEBUG:EXECUTE_SQL for layer (%v): %v", layer, sql)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	rows, err := p.pool.Query(sql, args...)
+	if err != nil {
+		return fmt.Errorf("error running layer (%v) SQL (%v): %v", layer, sql, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		vals, err := rows.Values()
+		if err != nil {
+			return fmt.Errorf("error running layer (%v) SQL (%v): %v", layer, sql, err)
+		}
+
+		// gx and gy are NULL for features without a centroid, which
+		// aggregateSQL excludes, any such row is skipped
+		gx, ok := vals[0].(int32)
+		if !ok {
+			continue
+		}
+		gy, ok := vals[1].(int32)
+		if !ok {
+			continue
+		}
+		// centroids on the far edges of the tile fall outside the grid
+		if gx < 0 || gy < 0 || uint(gx) >= spec.Cells || uint(gy) >= spec.Cells {
+			continue
+		}
+
+		tags := make(map[string]interface{}, len(spec.Aggregations))
+		for i, agg := range spec.Aggregations {
+			// sums and averages of no values are null
+			if v := vals[i+2]; v != nil {
+				tags[agg.Name()] = v
+			}
+		}
+
+		feature := provider.Feature{
+			ID:       uint64(gy)*uint64(spec.Cells) + uint64(gx) + 1,
+			Geometry: spec.CellPolygon(tile, uint(gx), uint(gy)),
+			SRID:     tegola.WebMercator,
+			Tags:     tags,
+		}
+		if err = fn(&feature); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// aggregateSQL wraps the layer's SQL in a query returning the column and row
+// of each grid cell, followed by the value of each aggregation. The layer's
+// SQL returns its geometry as WKB, which is decoded to find the centroid.
+// Features with a NULL or empty geometry have no centroid and are excluded.
+func aggregateSQL(sql, geomField string, srid uint64, tile provider.Tile, spec provider.AggSpec) string {
+	ext, _ := tile.Extent()
+	cellW, cellH := ext.XSpan()/float64(spec.Cells), ext.YSpan()/float64(spec.Cells)
+
+	aggs := make([]string, len(spec.Aggregations))
+	for i, agg := range spec.Aggregations {
+		field := `q."` + strings.ReplaceAll(agg.Field, `"`, `""`) + `"`
+		switch agg.Func {
+		case provider.AggCount:
+			aggs[i] = "count(*)"
+		case provider.AggSum:
+			aggs[i] = "sum(" + field + ")::float8"
+		case provider.AggAvg:
+			aggs[i] = "avg(" + field + ")::float8"
+		}
+	}
+
+	return fmt.Sprintf(
+		`SELECT floor((ST_X(q.tegola_centroid) - %[2]v) / %[4]v)::int4 AS gx, floor((%[3]v - ST_Y(q.tegola_centroid)) / %[5]v)::int4 AS gy, %[6]v FROM (SELECT ST_Transform(ST_Centroid(ST_GeomFromWKB(l."%[7]v", %[8]v)), %[9]v) AS tegola_centroid, l.* FROM (%[1]v) AS l) AS q WHERE q.tegola_centroid IS NOT NULL AND NOT ST_IsEmpty(q.tegola_centroid) GROUP BY gx, gy`,
+		sql,
+		ext.MinX(), ext.MaxY(),
+		cellW, cellH,
+		strings.Join(aggs, ", "),
+		strings.ReplaceAll(geomField, `"`, `""`),
+		srid,
+		tegola.WebMercator,
+	)
+}
@@ -14,14 +14,18 @@ const (
 	EnvSQLDebugName    = "TEGOLA_SQL_DEBUG"
 	EnvSQLDebugLayer   = "LAYER_SQL"
 	EnvSQLDebugExecute = "EXECUTE_SQL"
+	EnvSQLDebugRaw     = "RAW_FEATURES"
 )
 
 var (
 	debugLayerSQL   bool
 	debugExecuteSQL bool
+	// debugRawFeatures enables DebugTileFeatures
+	debugRawFeatures bool
 )
 
 func init() {
 	debugLayerSQL = strings.Contains(os.Getenv(EnvSQLDebugName), EnvSQLDebugLayer)
 	debugExecuteSQL = strings.Contains(os.Getenv(EnvSQLDebugName), EnvSQLDebugExecute)
+	debugRawFeatures = strings.Contains(os.Getenv(EnvSQLDebugName), EnvSQLDebugRaw)
 }
@@ -0,0 +1,49 @@
+package postgis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-spatial/tegola/provider"
+)
+
+// ExplainTile adheres to the provider.QueryPlanner interface. The layer's SQL
+// is run under EXPLAIN (ANALYZE, BUFFERS) and the plan is returned as text,
+// one line per row of the output.
+func (p Provider) ExplainTile(ctx context.Context, layer string, tile provider.Tile) (string, error) {
+	plyr, ok := p.Layer(layer)
+	if !ok {
+		return "", ErrLayerNotFound{layer}
+	}
+
+	sql, args := replaceParamTokens(ctx, plyr.sql, nil)
+	sql, err := replaceTokens(replaceAsOfToken(ctx, sql), &plyr, tile, true)
+	if err != nil {
+		return "", fmt.Errorf("error replacing layer tokens for layer (%v) SQL (%v): %v", layer, sql, err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	rows, err := p.pool.Query("EXPLAIN (ANALYZE, BUFFERS) "+sql, args...)
+	if err != nil {
+		return "", fmt.Errorf("error explaining layer (%v) SQL (%v): %v", layer, sql, err)
+	}
+	defer rows.Close()
+
+	var plan []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return "", fmt.Errorf("error explaining layer (%v) SQL (%v): %v", layer, sql, err)
+		}
+		plan = append(plan, line)
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("error explaining layer (%v) SQL (%v): %v", layer, sql, err)
+	}
+
+	return strings.Join(plan, "\n"), nil
+}
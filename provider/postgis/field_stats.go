@@ -0,0 +1,97 @@
+package postgis
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/pgtype"
+
+	"github.com/go-spatial/tegola/provider"
+)
+
+// fieldStatsQuery reads the planner statistics of each column of the table
+// $1. The range of numeric columns is taken from the bounds of the column's
+// histogram and its most common values.
+const fieldStatsQuery = `SELECT s.attname::text, s.null_frac::float8, s.n_distinct::float8, c.reltuples::float8,
+	CASE WHEN t.typcategory = 'N' THEN (SELECT min(v) FROM unnest(s.histogram_bounds::text::float8[] || s.most_common_vals::text::float8[]) AS v) END,
+	CASE WHEN t.typcategory = 'N' THEN (SELECT max(v) FROM unnest(s.histogram_bounds::text::float8[] || s.most_common_vals::text::float8[]) AS v) END
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+JOIN pg_stats s ON s.schemaname = n.nspname AND s.tablename = c.relname
+JOIN pg_attribute a ON a.attrelid = c.oid AND a.attname = s.attname
+JOIN pg_type t ON t.oid = a.atttypid
+WHERE c.oid = to_regclass($1)`
+
+// fieldStatsSQL returns the SQL, and its arguments, reading the statistics of
+// the layer's table. The table name is passed as an argument, rather than
+// replaced in the SQL, as to_regclass parses it as the query would, schema
+// and quoting included. Layers configured with sql return
+// provider.ErrUnsupported.
+func fieldStatsSQL(plyr *Layer) (string, []interface{}, error) {
+	if plyr.tablename == "" {
+		return "", nil, provider.ErrUnsupported
+	}
+	return fieldStatsQuery, []interface{}{plyr.tablename}, nil
+}
+
+// FieldStats adheres to the provider.FieldStatter interface. The statistics
+// are the planner's estimates from pg_stats, so they are only as current as
+// the table's last ANALYZE, and columns which have not been analyzed are
+// omitted. Layers configured with sql, rather than a tablename, return
+// provider.ErrUnsupported.
+func (p Provider) FieldStats(ctx context.Context, layer string) (map[string]provider.FieldStat, error) {
+	plyr, ok := p.Layer(layer)
+	if !ok {
+		return nil, ErrLayerNotFound{layer}
+	}
+	sql, args, err := fieldStatsSQL(&plyr)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err := p.pool.Query(sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error reading field stats for layer (%v) table (%v): %v", layer, plyr.tablename, err)
+	}
+	defer rows.Close()
+
+	stats := make(map[string]provider.FieldStat)
+	for rows.Next() {
+		var (
+			name                         string
+			nullFrac, distinct, rowCount float64
+			min, max                     pgtype.Float8
+		)
+		if err = rows.Scan(&name, &nullFrac, &distinct, &rowCount, &min, &max); err != nil {
+			return nil, fmt.Errorf("error reading field stats for layer (%v) table (%v): %v", layer, plyr.tablename, err)
+		}
+		// partitioned and inherited tables have a row for the table alone as
+		// well as for the table with its children, the first is kept
+		if _, ok := stats[name]; ok {
+			continue
+		}
+
+		// a negative n_distinct is the negated fraction of rows which are distinct
+		if distinct < 0 {
+			distinct = -distinct * rowCount
+		}
+
+		stat := provider.FieldStat{
+			Distinct:     distinct,
+			NullFraction: nullFrac,
+		}
+		if min.Status == pgtype.Present {
+			stat.Min = &min.Float
+		}
+		if max.Status == pgtype.Present {
+			stat.Max = &max.Float
+		}
+		stats[name] = stat
+	}
+
+	return stats, rows.Err()
+}
@@ -0,0 +1,78 @@
+package postgis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-spatial/tegola/provider"
+)
+
+// TileFeaturesJoin adheres to the provider.SpatialJoiner interface. The
+// layer's SQL is joined laterally to the related layer's SQL, so each
+// feature is joined to the first related feature matching the predicate.
+// The related layer's geometry is transformed to the SRID of the layer's
+// geometry to be compared.
+func (p Provider) TileFeaturesJoin(ctx context.Context, layer string, tile provider.Tile, join provider.JoinSpec, fn func(f *provider.Feature) error) error {
+	plyr, ok := p.Layer(layer)
+	if !ok {
+		return ErrLayerNotFound{layer}
+	}
+	rlyr, ok := p.Layer(join.Layer)
+	if !ok {
+		return ErrLayerNotFound{join.Layer}
+	}
+	if err := join.Validate(); err != nil {
+		return err
+	}
+
+	sql, args := replaceParamTokens(ctx, plyr.sql, nil)
+	sql, err := replaceTokens(replaceAsOfToken(ctx, sql), &plyr, tile, true)
+	if err != nil {
+		return fmt.Errorf("error replacing layer tokens for layer (%v) SQL (%v): %v", layer, sql, err)
+	}
+	rsql, args := replaceParamTokens(ctx, rlyr.sql, args)
+	rsql, err = replaceTokens(replaceAsOfToken(ctx, rsql), &rlyr, tile, true)
+	if err != nil {
+		return fmt.Errorf("error replacing layer tokens for layer (%v) SQL (%v): %v", join.Layer, rsql, err)
+	}
+
+	sql = joinSQL(sql, &plyr, rsql, &rlyr, join)
+	return p.queryFeatures(ctx, plyr, sql, args, false, func(_ map[string]interface{}, f *provider.Feature) error {
+		return fn(f)
+	})
+}
+
+// joinSQL joins the layer's SQL laterally to the first row of the related
+// layer's SQL matching the predicate, selecting the join's fields. Both
+// layers' SQL return their geometry as WKB.
+func joinSQL(sql string, lyr *Layer, rsql string, rlyr *Layer, join provider.JoinSpec) string {
+	quote := func(s string) string {
+		return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+	}
+
+	fields := make([]string, len(join.Fields))
+	for i, field := range join.Fields {
+		fields[i] = fmt.Sprintf(`r.%v AS %v`, quote(field), quote(join.Prefix+field))
+	}
+
+	var (
+		geom  = fmt.Sprintf(`ST_GeomFromWKB(l.%v, %v)`, quote(lyr.GeomFieldName()), lyr.SRID())
+		rgeom = fmt.Sprintf(`ST_Transform(ST_GeomFromWKB(r.%v, %v), %v)`, quote(rlyr.GeomFieldName()), rlyr.SRID(), lyr.SRID())
+		pred  string
+	)
+	switch join.Predicate {
+	case provider.JoinContains:
+		pred = fmt.Sprintf(`ST_Contains(%v, %v)`, rgeom, geom)
+	case provider.JoinIntersects:
+		pred = fmt.Sprintf(`ST_Intersects(%v, %v)`, rgeom, geom)
+	}
+
+	return fmt.Sprintf(
+		`SELECT l.*, j.* FROM (%v) AS l LEFT JOIN LATERAL (SELECT %v FROM (%v) AS r WHERE %v LIMIT 1) AS j ON true`,
+		sql,
+		strings.Join(fields, ", "),
+		rsql,
+		pred,
+	)
+}
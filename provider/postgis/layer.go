@@ -8,10 +8,16 @@ type Layer struct {
 	name string
 	// The SQL to use when querying PostGIS for this layer
 	sql string
+	// The table queried, empty if the layer is configured with SQL or a
+	// sub-query. Used to look up the table's statistics
+	tablename string
 	// The ID field name, this will default to 'gid' if not set to something other then empty string.
 	idField string
 	// The Geometery field name, this will default to 'geom' if not set to something other then empty string.
 	geomField string
+	// The field holding the time the feature was last updated, used for
+	// the tile's ETag. Optional
+	updatedAtField string
 	// GeomType is the the type of geometry returned from the SQL
 	geomType geom.Geometry
 	// The SRID that the data in the table is stored in. This will default to WebMercator
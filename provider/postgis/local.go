@@ -0,0 +1,58 @@
+package postgis
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/go-spatial/tegola"
+	"github.com/go-spatial/tegola/provider"
+)
+
+// TileFeaturesLocal adheres to the provider.LocalTiler interface. The
+// layer's SQL is wrapped so PostGIS transforms, clips and quantizes each
+// geometry to tile local coordinates with ST_AsMVTGeom. Features whose
+// geometry is clipped away are skipped.
+func (p Provider) TileFeaturesLocal(ctx context.Context, layer string, tile provider.Tile, extent int, fn func(f *provider.Feature) error) error {
+	plyr, ok := p.Layer(layer)
+	if !ok {
+		return ErrLayerNotFound{layer}
+	}
+	if extent <= 0 {
+		extent = tegola.DefaultExtent
+	}
+
+	sql, args := replaceParamTokens(ctx, plyr.sql, nil)
+	sql, err := replaceTokens(replaceExtentToken(replaceAsOfToken(ctx, sql), uint32(extent)), &plyr, tile, true)
+	if err != nil {
+		return fmt.Errorf("error replacing layer tokens for layer (%v) SQL (%v): %v", layer, sql, err)
+	}
+
+	sql = localSQL(sql, &plyr, tile, extent)
+	return p.queryFeatures(ctx, plyr, sql, args, false, func(_ map[string]interface{}, f *provider.Feature) error {
+		return fn(f)
+	})
+}
+
+// localSQL wraps the layer's SQL, which returns its geometry as WKB, in a
+// query returning the geometry in tile local coordinates as well. The local
+// geometry is returned after the layer's columns under the name of the
+// geometry field, so it is the one decipherFields keeps. The tile's buffer
+// is converted to tile local units.
+func localSQL(sql string, lyr *Layer, tile provider.Tile, extent int) string {
+	ext, _ := tile.Extent()
+	bext, _ := tile.BufferedExtent()
+	buffer := math.Round((bext.XSpan() - ext.XSpan()) / 2 / ext.XSpan() * float64(extent))
+
+	return fmt.Sprintf(
+		`SELECT l.*, m.tegola_local AS "%[2]v" FROM (%[1]v) AS l CROSS JOIN LATERAL (SELECT ST_AsBinary(ST_AsMVTGeom(ST_Transform(ST_GeomFromWKB(l."%[2]v", %[3]v), %[4]v), ST_MakeEnvelope(%[5]g,%[6]g,%[7]g,%[8]g,%[4]v)::box2d, %[9]v, %[10]v, true)) AS tegola_local) AS m WHERE m.tegola_local IS NOT NULL`,
+		sql,
+		strings.ReplaceAll(lyr.GeomFieldName(), `"`, `""`),
+		lyr.SRID(),
+		tegola.WebMercator,
+		ext.MinX(), ext.MinY(), ext.MaxX(), ext.MaxY(),
+		extent,
+		buffer,
+	)
+}
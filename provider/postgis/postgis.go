@@ -70,6 +70,7 @@ const (
 	ConfigKeyGeomField   = "geometry_fieldname"
 	ConfigKeyGeomIDField = "id_fieldname"
 	ConfigKeyGeomType    = "geometry_type"
+	ConfigKeyUpdatedAt   = "updated_at_fieldname"
 )
 
 // isSelectQuery is a regexp to check if a query starts with `SELECT`,
@@ -96,6 +97,7 @@ var isSelectQuery = regexp.MustCompile(`(?i)^((\s*)(--.*\n)?)*select`)
 // 		id_fieldname (string): [Optional] the name of the feature id field. defaults to gid
 // 		fields ([]string): [Optional] a list of fields to include alongside the feature. Can be used if sql is not defined.
 // 		srid (int): [Optional] the SRID of the layer. Supports 3857 (WebMercator) or 4326 (WGS84).
+// 		updated_at_fieldname (string): [Optional] the name of the field holding the time the feature was last updated. Used for tile ETags.
 // 		sql (string): [*Required] custom SQL to use use. Required if tablename is not defined. Supports the following tokens:
 //
 // 			!BBOX! - [Required] will be replaced with the bounding box of the tile before the query is sent to the database.
@@ -239,6 +241,12 @@ func CreateProvider(config dict.Dicter) (*Provider, error) {
 			return nil, fmt.Errorf("for layer (%v) %v : %v", i, lname, err)
 		}
 
+		updatedAt := ""
+		updatedAt, err = layer.String(ConfigKeyUpdatedAt, &updatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("for layer (%v) %v : %v", i, lname, err)
+		}
+
 		var tblName string
 		tblName, err = layer.String(ConfigKeyTablename, &lname)
 		if err != nil {
@@ -261,10 +269,11 @@ func CreateProvider(config dict.Dicter) (*Provider, error) {
 		}
 
 		l := Layer{
-			name:      lname,
-			idField:   idfld,
-			geomField: geomfld,
-			srid:      uint64(lsrid),
+			name:           lname,
+			idField:        idfld,
+			geomField:      geomfld,
+			updatedAtField: updatedAt,
+			srid:           uint64(lsrid),
 		}
 
 		if sql != "" && !isSelectQuery.MatchString(sql) {
@@ -272,6 +281,8 @@ func CreateProvider(config dict.Dicter) (*Provider, error) {
 			// (`(select ...) as foo`) which we can handle like a tablename
 			tblName = sql
 			sql = ""
+		} else if sql == "" {
+			l.tablename = tblName
 		}
 
 		if sql != "" {
@@ -505,17 +516,50 @@ func (p Provider) Layers() ([]provider.LayerInfo, error) {
 
 // TileFeatures adheres to the provider.Tiler interface
 func (p Provider) TileFeatures(ctx context.Context, layer string, tile provider.Tile, fn func(f *provider.Feature) error) error {
+	return p.tileFeatures(ctx, layer, tile, false, func(_ map[string]interface{}, f *provider.Feature) error {
+		return fn(f)
+	})
+}
+
+// DebugTileFeatures adheres to the provider.FeatureDebugger interface. The
+// raw row maps each column name to the value read from the database, before
+// any conversion. It is only supported when the TEGOLA_SQL_DEBUG environment
+// variable contains RAW_FEATURES, otherwise provider.ErrUnsupported is returned.
+func (p Provider) DebugTileFeatures(ctx context.Context, layer string, tile provider.Tile, fn func(raw map[string]interface{}, f *provider.Feature) error) error {
+	if !debugRawFeatures {
+		return provider.ErrUnsupported
+	}
+	return p.tileFeatures(ctx, layer, tile, true, fn)
+}
+
+// tileFeatures streams the features of the layer for the tile, when withRaw
+// is true the raw row of each feature is passed to fn as well
+func (p Provider) tileFeatures(ctx context.Context, layer string, tile provider.Tile, withRaw bool, fn func(raw map[string]interface{}, f *provider.Feature) error) error {
 	// fetch the provider layer
 	plyr, ok := p.Layer(layer)
 	if !ok {
 		return ErrLayerNotFound{layer}
 	}
 
-	sql, err := replaceTokens(plyr.sql, &plyr, tile, true)
+	sql, args := replaceParamTokens(ctx, plyr.sql, nil)
+	sql, err := replaceTokens(replaceAsOfToken(ctx, sql), &plyr, tile, true)
 	if err != nil {
 		return fmt.Errorf("error replacing layer tokens for layer (%v) SQL (%v): %v", layer, sql, err)
 	}
 
+	return p.queryFeatures(ctx, plyr, sql, args, withRaw, fn)
+}
+
+// queryFeatures runs the layer's SQL, with its tokens replaced, and streams
+// the features of the rows. When withRaw is true the raw row of each feature
+// is passed to fn as well.
+func (p Provider) queryFeatures(ctx context.Context, plyr Layer, sql string, args []interface{}, withRaw bool, fn func(raw map[string]interface{}, f *provider.Feature) error) error {
+	layer := plyr.Name()
+
+	if hint, ok := provider.QueryHintFromContext(ctx); ok {
+		sql = hint.SQLComment() + " " + sql
+	}
+
 	if debugExecuteSQL {
 		log.Printf("TEGOLA_SQL_DEBUG:EXECUTE_SQL for layer (%v): %v", layer, sql)
 	}
@@ -525,7 +569,7 @@ func (p Provider) TileFeatures(ctx context.Context, layer string, tile provider.
 		return err
 	}
 
-	rows, err := p.pool.Query(sql)
+	rows, err := p.pool.Query(sql, args...)
 	if err != nil {
 		return fmt.Errorf("error running layer (%v) SQL (%v): %v", layer, sql, err)
 	}
@@ -594,8 +638,16 @@ func (p Provider) TileFeatures(ctx context.Context, layer string, tile provider.
 			Tags:     tags,
 		}
 
+		var raw map[string]interface{}
+		if withRaw {
+			raw = make(map[string]interface{}, len(vals))
+			for i := range vals {
+				raw[fdescs[i].Name] = vals[i]
+			}
+		}
+
 		// pass the feature to the provided callback
-		if err = fn(&feature); err != nil {
+		if err = fn(raw, &feature); err != nil {
 			return err
 		}
 	}
@@ -603,10 +655,73 @@ func (p Provider) TileFeatures(ctx context.Context, layer string, tile provider.
 	return rows.Err()
 }
 
+// EstimateCost adheres to the provider.CostEstimator interface. The cost is
+// the planner's total cost for the layer's SQL, as reported by EXPLAIN.
+func (p Provider) EstimateCost(ctx context.Context, layer string, tile provider.Tile) (float64, error) {
+	plyr, ok := p.Layer(layer)
+	if !ok {
+		return 0, ErrLayerNotFound{layer}
+	}
+
+	sql, args := replaceParamTokens(ctx, plyr.sql, nil)
+	sql, err := replaceTokens(replaceAsOfToken(ctx, sql), &plyr, tile, true)
+	if err != nil {
+		return 0, fmt.Errorf("error replacing layer tokens for layer (%v) SQL (%v): %v", layer, sql, err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	// the first row of the plan is the top node, which includes the total cost
+	var plan string
+	if err = p.pool.QueryRow("EXPLAIN "+sql, args...).Scan(&plan); err != nil {
+		return 0, fmt.Errorf("error explaining layer (%v) SQL (%v): %v", layer, sql, err)
+	}
+
+	return explainCost(plan)
+}
+
+// TileETag adheres to the provider.TileETagger interface. The tag is derived
+// from the number of features and the latest value of the layer's
+// updated_at_fieldname in the tile. Layers without an updated_at_fieldname
+// return provider.ErrUnsupported.
+func (p Provider) TileETag(ctx context.Context, layer string, tile provider.Tile) (string, error) {
+	plyr, ok := p.Layer(layer)
+	if !ok {
+		return "", ErrLayerNotFound{layer}
+	}
+	if plyr.updatedAtField == "" {
+		return "", provider.ErrUnsupported
+	}
+
+	sql, args := replaceParamTokens(ctx, plyr.sql, nil)
+	sql, err := replaceTokens(replaceAsOfToken(ctx, sql), &plyr, tile, true)
+	if err != nil {
+		return "", fmt.Errorf("error replacing layer tokens for layer (%v) SQL (%v): %v", layer, sql, err)
+	}
+	sql = fmt.Sprintf(`SELECT count(*), coalesce(max(q."%v")::text, '') FROM (%v) AS q`, plyr.updatedAtField, sql)
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	var (
+		count     int64
+		updatedAt string
+	)
+	if err = p.pool.QueryRow(sql, args...).Scan(&count, &updatedAt); err != nil {
+		return "", fmt.Errorf("error running layer (%v) SQL (%v): %v", layer, sql, err)
+	}
+
+	return fmt.Sprintf("%v-%v", count, updatedAt), nil
+}
+
 func (p Provider) MVTForLayers(ctx context.Context, tile provider.Tile, layers []mvtprovider.Layer) ([]byte, error) {
 	var (
 		err  error
 		sqls = make([]string, 0, len(layers))
+		args []interface{}
 	)
 
 	for i := range layers {
@@ -622,7 +737,14 @@ func (p Provider) MVTForLayers(ctx context.Context, tile provider.Tile, layers [
 		if debugLayerSQL {
 			log.Printf("SQL for Layer(%v):\n%v\n", l.Name(), l.sql)
 		}
-		sql, err := replaceTokens(l.sql, &l, tile, false)
+		extent := layers[i].Extent
+		if extent == 0 {
+			extent = tegola.DefaultExtent
+		}
+
+		var sql string
+		sql, args = replaceParamTokens(ctx, l.sql, args)
+		sql, err := replaceTokens(replaceExtentToken(replaceAsOfToken(ctx, sql), extent), &l, tile, false)
 		if err != nil {
 			return nil, err
 		}
@@ -632,7 +754,7 @@ func (p Provider) MVTForLayers(ctx context.Context, tile provider.Tile, layers [
 		sqls = append(sqls, fmt.Sprintf(
 			`(SELECT ST_AsMVT(q,'%s',%d,'%s','%s') AS data FROM (%s) AS q)`,
 			layers[i].MVTName,
-			tegola.DefaultExtent,
+			extent,
 			l.GeomFieldName(),
 			l.IDFieldName(),
 			sql,
@@ -640,11 +762,14 @@ func (p Provider) MVTForLayers(ctx context.Context, tile provider.Tile, layers [
 	}
 	subsqls := strings.Join(sqls, "||")
 	fsql := fmt.Sprintf(`SELECT (%s) AS data`, subsqls)
+	if hint, ok := provider.QueryHintFromContext(ctx); ok {
+		fsql = hint.SQLComment() + " " + fsql
+	}
 	var data pgtype.Bytea
 	if debugExecuteSQL {
 		log.Printf("%s:%s: %v", EnvSQLDebugName, EnvSQLDebugExecute, fsql)
 	}
-	err = p.pool.QueryRow(fsql).Scan(&data)
+	err = p.pool.QueryRow(fsql, args...).Scan(&data)
 	if debugExecuteSQL {
 		log.Printf("%s:%s: %v", EnvSQLDebugName, EnvSQLDebugExecute, fsql)
 		if err != nil {
@@ -274,3 +274,137 @@ func TestLayerGeomType(t *testing.T) {
 		t.Run(name, fn(tc))
 	}
 }
+
+func TestDebugTileFeaturesDisabled(t *testing.T) {
+	enabled := debugRawFeatures
+	defer func() { debugRawFeatures = enabled }()
+	debugRawFeatures = false
+
+	err := Provider{}.DebugTileFeatures(context.Background(), "land", provider.NewTile(1, 1, 1, 64, 3857), func(raw map[string]interface{}, f *provider.Feature) error {
+		t.Errorf("callback, expected no calls")
+		return nil
+	})
+	if err != provider.ErrUnsupported {
+		t.Errorf("error, expected %v got %v", provider.ErrUnsupported, err)
+	}
+}
+
+func TestDebugTileFeatures(t *testing.T) {
+	ttools.ShouldSkip(t, TESTENV)
+
+	enabled := debugRawFeatures
+	defer func() { debugRawFeatures = enabled }()
+	debugRawFeatures = true
+
+	config := TCConfig{
+		LayerConfig: []map[string]interface{}{{
+			ConfigKeyLayerName: "land",
+			ConfigKeySQL:       "SELECT gid, ST_AsBinary(geom) AS geom, scalerank FROM ne_10m_land_scale_rank WHERE geom && !BBOX!",
+		}},
+	}.Config()
+	p, err := NewTileProvider(config)
+	if err != nil {
+		t.Fatalf("new tile provider, expected nil got %v", err)
+	}
+	tile := provider.NewTile(1, 1, 1, 64, 3857)
+
+	var featureCount int
+	err = p.TileFeatures(context.Background(), "land", tile, func(f *provider.Feature) error {
+		featureCount++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("tile features, expected nil got %v", err)
+	}
+
+	var debugCount int
+	err = provider.DebugTileFeatures(context.Background(), p, "land", tile, func(raw map[string]interface{}, f *provider.Feature) error {
+		debugCount++
+		for _, col := range []string{"gid", "geom", "scalerank"} {
+			if _, ok := raw[col]; !ok {
+				t.Fatalf("raw column %v, expected in %v", col, raw)
+			}
+		}
+		if _, ok := raw["geom"].([]byte); !ok {
+			t.Errorf("raw geom, expected []byte got %T", raw["geom"])
+		}
+		if _, ok := f.Tags["scalerank"]; !ok {
+			t.Errorf("feature tag scalerank, expected in %v", f.Tags)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("debug tile features, expected nil got %v", err)
+	}
+	if debugCount != featureCount {
+		t.Errorf("feature count, expected %v got %v", featureCount, debugCount)
+	}
+}
+
+func TestExplainTile(t *testing.T) {
+	ttools.ShouldSkip(t, TESTENV)
+
+	config := TCConfig{
+		LayerConfig: []map[string]interface{}{{
+			ConfigKeyLayerName: "land",
+			ConfigKeySQL:       "SELECT gid, ST_AsBinary(geom) AS geom FROM ne_10m_land_scale_rank WHERE geom && !BBOX!",
+		}},
+	}.Config()
+	p, err := NewTileProvider(config)
+	if err != nil {
+		t.Fatalf("new tile provider, expected nil got %v", err)
+	}
+
+	plan, err := p.(*Provider).ExplainTile(context.Background(), "land", provider.NewTile(1, 1, 1, 64, 3857))
+	if err != nil {
+		t.Fatalf("explain tile, expected nil got %v", err)
+	}
+	// EXPLAIN ANALYZE ends the plan with the execution time
+	if !strings.Contains(plan, "Execution Time") {
+		t.Errorf("plan, expected execution time got %v", plan)
+	}
+
+	_, err = p.(*Provider).ExplainTile(context.Background(), "water", provider.NewTile(1, 1, 1, 64, 3857))
+	if _, ok := err.(ErrLayerNotFound); !ok {
+		t.Errorf("unknown layer error, expected ErrLayerNotFound got %v", err)
+	}
+}
+
+func TestTileFeaturesLocal(t *testing.T) {
+	ttools.ShouldSkip(t, TESTENV)
+
+	config := TCConfig{
+		LayerConfig: []map[string]interface{}{{
+			ConfigKeyLayerName: "land",
+			ConfigKeySQL:       "SELECT gid, ST_AsBinary(geom) AS geom, scalerank FROM ne_10m_land_scale_rank WHERE geom && !BBOX!",
+		}},
+	}.Config()
+	p, err := NewTileProvider(config)
+	if err != nil {
+		t.Fatalf("new tile provider, expected nil got %v", err)
+	}
+
+	const extent = 4096
+	var featureCount int
+	err = provider.TileFeaturesLocal(context.Background(), p, "land", provider.NewTile(1, 1, 1, 64, 3857), extent, func(f *provider.Feature) error {
+		featureCount++
+		if _, ok := f.Tags["scalerank"]; !ok {
+			t.Errorf("feature tag scalerank, expected in %v", f.Tags)
+		}
+		ext, err := geom.NewExtentFromGeometry(f.Geometry)
+		if err != nil {
+			return err
+		}
+		// the tile with its buffer of 64 pixels
+		if ext.MinX() < -64 || ext.MinY() < -64 || ext.MaxX() > extent+64 || ext.MaxY() > extent+64 {
+			t.Errorf("feature %v extent, expected within the buffered tile got %v", f.ID, ext)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("tile features local, expected nil got %v", err)
+	}
+	if featureCount == 0 {
+		t.Errorf("feature count, expected features got 0")
+	}
+}
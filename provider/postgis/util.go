@@ -7,6 +7,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-spatial/geom"
 	"github.com/go-spatial/tegola"
@@ -89,6 +90,9 @@ const (
 	idFieldToken          = "!ID_FIELD!"
 	geomFieldToken        = "!GEOM_FIELD!"
 	geomTypeToken         = "!GEOM_TYPE!"
+	asOfToken             = "!AS_OF!"
+	extentToken           = "!EXTENT!"
+	paramTokenPrefix      = "!PARAM_"
 )
 
 // replaceTokens replaces tokens in the provided SQL string
@@ -103,6 +107,9 @@ const (
 // !PIXEL_HEIGHT! - the pixel height in meters, assuming 256x256 tiles
 // !GEOM_FIELD! - the geom field name
 // !GEOM_TYPE! - the geom field type if defined otherwise ""
+// !AS_OF! - now(), unless already replaced by replaceAsOfToken
+// !EXTENT! - the default MVT extent (4096), unless already replaced by replaceExtentToken
+// !PARAM_<name>! - NULL, unless already replaced by replaceParamTokens
 func replaceTokens(sql string, lyr *Layer, tile provider.Tile, withBuffer bool) (string, error) {
 	var (
 		extent  *geom.Extent
@@ -160,14 +167,70 @@ func replaceTokens(sql string, lyr *Layer, tile provider.Tile, withBuffer bool)
 		scaleDenominatorToken, strconv.FormatFloat(scaleDenominator, 'f', -1, 64),
 		pixelWidthToken, strconv.FormatFloat(pixelWidth, 'f', -1, 64),
 		pixelHeightToken, strconv.FormatFloat(pixelHeight, 'f', -1, 64),
+		asOfToken, "now()",
+		extentToken, strconv.Itoa(tegola.DefaultExtent),
 	)
 
-	uppercaseTokenSQL := uppercaseTokens(sql)
+	uppercaseTokenSQL := paramTokenRe.ReplaceAllString(uppercaseTokens(sql), "NULL")
 
 	return tokenReplacer.Replace(uppercaseTokenSQL), nil
 }
 
-var tokenRe = regexp.MustCompile("![a-zA-Z0-9_-]+!")
+// replaceAsOfToken replaces the !AS_OF! token with the time set on the
+// context by provider.WithAsOf, as a timestamptz literal. Without a time
+// the token is left for replaceTokens, which uses now().
+func replaceAsOfToken(ctx context.Context, sql string) string {
+	asOf, ok := provider.AsOfFromContext(ctx)
+	if !ok {
+		return sql
+	}
+	return strings.ReplaceAll(uppercaseTokens(sql), asOfToken, fmt.Sprintf("'%v'::timestamptz", asOf.UTC().Format(time.RFC3339Nano)))
+}
+
+// replaceExtentToken replaces the !EXTENT! token with the MVT extent the
+// layer is encoded with. An extent of 0 leaves the token for replaceTokens,
+// which uses the default extent.
+func replaceExtentToken(sql string, extent uint32) string {
+	if extent == 0 {
+		return sql
+	}
+	return strings.ReplaceAll(uppercaseTokens(sql), extentToken, strconv.FormatUint(uint64(extent), 10))
+}
+
+// replaceParamTokens replaces each !PARAM_<name>! token with a placeholder
+// of a bound parameter, appending the value of the parameter set on the
+// context by provider.WithParams to args, which are returned. Parameter
+// names are case insensitive, as tokens are. A parameter used more than once
+// is bound once, and parameters missing from the context are bound as NULL.
+// The values are never interpolated into the SQL.
+func replaceParamTokens(ctx context.Context, sql string, args []interface{}) (string, []interface{}) {
+	params, _ := provider.ParamsFromContext(ctx)
+	bound := make(map[string]string)
+
+	sql = paramTokenRe.ReplaceAllStringFunc(uppercaseTokens(sql), func(token string) string {
+		name := token[len(paramTokenPrefix) : len(token)-1]
+		if placeholder, ok := bound[name]; ok {
+			return placeholder
+		}
+
+		var val interface{}
+		for k, v := range params {
+			if strings.EqualFold(k, name) {
+				val = v
+				break
+			}
+		}
+		args = append(args, val)
+		bound[name] = "$" + strconv.Itoa(len(args))
+		return bound[name]
+	})
+	return sql, args
+}
+
+var (
+	tokenRe      = regexp.MustCompile("![a-zA-Z0-9_-]+!")
+	paramTokenRe = regexp.MustCompile("!PARAM_[A-Z0-9_-]+!")
+)
 
 //	uppercaseTokens converts all !tokens! to uppercase !TOKENS!. Tokens can
 //	contain alphanumerics, dash and underline chars.
@@ -306,3 +369,16 @@ func gId(v interface{}) (gid uint64, err error) {
 		return gid, fmt.Errorf("unable to convert field into a uint64.")
 	}
 }
+
+// explainCostRegex matches the total cost of the top plan node of an EXPLAIN,
+// i.e. "Seq Scan on foo  (cost=0.00..35.50 rows=2550 width=4)"
+var explainCostRegex = regexp.MustCompile(`cost=[0-9.]+\.\.([0-9.]+)`)
+
+// explainCost returns the total cost from the first line of an EXPLAIN
+func explainCost(plan string) (float64, error) {
+	match := explainCostRegex.FindStringSubmatch(plan)
+	if match == nil {
+		return 0, fmt.Errorf("no cost found in query plan (%v)", plan)
+	}
+	return strconv.ParseFloat(match[1], 64)
+}
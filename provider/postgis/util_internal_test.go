@@ -3,7 +3,10 @@ package postgis
 import (
 	"context"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/jackc/pgx"
 
@@ -66,6 +69,152 @@ func TestReplaceTokens(t *testing.T) {
 			tile:     provider.NewTile(11, 1070, 676, 64, tegola.WebMercator),
 			expected: "SELECT id, 76.43702827453671 as width, 76.43702827453671 as height, 272989.38669477403 as scale_denom FROM foo WHERE geom && ST_MakeEnvelope(899816.6968478388,6.789748347570495e+06,919996.0723123164,6.809927723034973e+06,3857)",
 		},
+		"replace unbound PARAM": {
+			sql:      "SELECT * FROM foo WHERE class = !param_class!",
+			layer:    Layer{srid: tegola.WebMercator},
+			tile:     provider.NewTile(2, 1, 1, 64, tegola.WebMercator),
+			expected: "SELECT * FROM foo WHERE class = NULL",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestReplaceAsOfToken(t *testing.T) {
+	type tcase struct {
+		ctx      context.Context
+		sql      string
+		expected string
+	}
+
+	fn := func(tc tcase) func(t *testing.T) {
+		return func(t *testing.T) {
+			sql, err := replaceTokens(replaceAsOfToken(tc.ctx, tc.sql), &Layer{srid: tegola.WebMercator}, provider.NewTile(0, 0, 0, 0, tegola.WebMercator), true)
+			if err != nil {
+				t.Errorf("unexpected error, Expected nil Got %v", err)
+				return
+			}
+
+			if sql != tc.expected {
+				t.Errorf("incorrect sql,\n Expected \n \t%v\n Got \n \t%v", tc.expected, sql)
+				return
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"as of": {
+			ctx:      provider.WithAsOf(context.Background(), time.Date(2001, 2, 3, 4, 5, 6, 0, time.FixedZone("", 3600))),
+			sql:      "SELECT * FROM foo WHERE valid_from <= !as_of! AND valid_to > !AS_OF!",
+			expected: "SELECT * FROM foo WHERE valid_from <= '2001-02-03T03:05:06Z'::timestamptz AND valid_to > '2001-02-03T03:05:06Z'::timestamptz",
+		},
+		"now": {
+			ctx:      context.Background(),
+			sql:      "SELECT * FROM foo WHERE valid_from <= !AS_OF!",
+			expected: "SELECT * FROM foo WHERE valid_from <= now()",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestReplaceExtentToken(t *testing.T) {
+	type tcase struct {
+		extent   uint32
+		sql      string
+		expected string
+	}
+
+	fn := func(tc tcase) func(t *testing.T) {
+		return func(t *testing.T) {
+			sql, err := replaceTokens(replaceExtentToken(tc.sql, tc.extent), &Layer{srid: tegola.WebMercator}, provider.NewTile(0, 0, 0, 0, tegola.WebMercator), true)
+			if err != nil {
+				t.Errorf("unexpected error, Expected nil Got %v", err)
+				return
+			}
+
+			if sql != tc.expected {
+				t.Errorf("incorrect sql,\n Expected \n \t%v\n Got \n \t%v", tc.expected, sql)
+				return
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"extent": {
+			extent:   8192,
+			sql:      "SELECT ST_AsMVTGeom(geom, ST_MakeEnvelope(0,0,1,1), !extent!) AS geom FROM foo",
+			expected: "SELECT ST_AsMVTGeom(geom, ST_MakeEnvelope(0,0,1,1), 8192) AS geom FROM foo",
+		},
+		"default": {
+			sql:      "SELECT ST_AsMVTGeom(geom, ST_MakeEnvelope(0,0,1,1), !EXTENT!) AS geom FROM foo",
+			expected: "SELECT ST_AsMVTGeom(geom, ST_MakeEnvelope(0,0,1,1), 4096) AS geom FROM foo",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestReplaceParamTokens(t *testing.T) {
+	type tcase struct {
+		ctx          context.Context
+		sql          string
+		args         []interface{}
+		expected     string
+		expectedArgs []interface{}
+	}
+
+	fn := func(tc tcase) func(t *testing.T) {
+		return func(t *testing.T) {
+			sql, args := replaceParamTokens(tc.ctx, tc.sql, tc.args)
+			sql, err := replaceTokens(sql, &Layer{srid: tegola.WebMercator}, provider.NewTile(0, 0, 0, 0, tegola.WebMercator), true)
+			if err != nil {
+				t.Errorf("unexpected error, Expected nil Got %v", err)
+				return
+			}
+
+			if sql != tc.expected {
+				t.Errorf("incorrect sql,\n Expected \n \t%v\n Got \n \t%v", tc.expected, sql)
+				return
+			}
+			if !reflect.DeepEqual(args, tc.expectedArgs) {
+				t.Errorf("incorrect args, Expected %v Got %v", tc.expectedArgs, args)
+				return
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"bound": {
+			ctx:          provider.WithParams(context.Background(), map[string]interface{}{"class": "'; DROP TABLE foo; --", "min_pop": 1000}),
+			sql:          "SELECT * FROM foo WHERE class = !param_class! AND pop >= !PARAM_MIN_POP! AND (!PARAM_CLASS! IS NULL OR rank > 0)",
+			expected:     "SELECT * FROM foo WHERE class = $1 AND pop >= $2 AND ($1 IS NULL OR rank > 0)",
+			expectedArgs: []interface{}{"'; DROP TABLE foo; --", 1000},
+		},
+		"numbered after args": {
+			ctx:          provider.WithParams(context.Background(), map[string]interface{}{"class": "road"}),
+			sql:          "SELECT * FROM foo WHERE class = !PARAM_CLASS!",
+			args:         []interface{}{"river"},
+			expected:     "SELECT * FROM foo WHERE class = $2",
+			expectedArgs: []interface{}{"river", "road"},
+		},
+		"missing": {
+			ctx:          context.Background(),
+			sql:          "SELECT * FROM foo WHERE class = !PARAM_CLASS!",
+			expected:     "SELECT * FROM foo WHERE class = $1",
+			expectedArgs: []interface{}{nil},
+		},
+		"none": {
+			ctx:      provider.WithParams(context.Background(), map[string]interface{}{"class": "road"}),
+			sql:      "SELECT * FROM foo",
+			expected: "SELECT * FROM foo",
+		},
 	}
 
 	for name, tc := range tests {
@@ -214,3 +363,144 @@ func TestDecipherFields(t *testing.T) {
 		t.Run(name, fn(tc))
 	}
 }
+
+func TestExplainCost(t *testing.T) {
+	type tcase struct {
+		plan     string
+		expected float64
+		err      bool
+	}
+
+	fn := func(tc tcase) func(t *testing.T) {
+		return func(t *testing.T) {
+			cost, err := explainCost(tc.plan)
+			if tc.err {
+				if err == nil {
+					t.Errorf("expected error, Got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error, Expected nil Got %v", err)
+				return
+			}
+			if cost != tc.expected {
+				t.Errorf("incorrect cost, Expected %v Got %v", tc.expected, cost)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"seq scan": {
+			plan:     "Seq Scan on foo  (cost=0.00..35.50 rows=2550 width=4)",
+			expected: 35.5,
+		},
+		"index scan": {
+			plan:     "Bitmap Heap Scan on roads  (cost=4.31..1234.56 rows=12 width=64)",
+			expected: 1234.56,
+		},
+		"no cost": {
+			plan: "Result",
+			err:  true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestAggregateSQL(t *testing.T) {
+	spec := provider.AggSpec{
+		Cells: 2,
+		Aggregations: []provider.Aggregation{
+			{Func: provider.AggCount},
+			{Func: provider.AggSum, Field: "pop"},
+			{Func: provider.AggAvg, Field: "area"},
+		},
+	}
+
+	sql := aggregateSQL("SELECT gid, ST_AsBinary(geom) AS geom, pop, area FROM places", "geom", tegola.WGS84, provider.NewTile(0, 0, 0, 64, tegola.WebMercator), spec)
+	expected := `SELECT floor((ST_X(q.tegola_centroid) - -2.003750834e+07) / 2.003750834e+07)::int4 AS gx, floor((2.003750834e+07 - ST_Y(q.tegola_centroid)) / 2.003750834e+07)::int4 AS gy, count(*), sum(q."pop")::float8, avg(q."area")::float8 FROM (SELECT ST_Transform(ST_Centroid(ST_GeomFromWKB(l."geom", 4326)), 3857) AS tegola_centroid, l.* FROM (SELECT gid, ST_AsBinary(geom) AS geom, pop, area FROM places) AS l) AS q WHERE q.tegola_centroid IS NOT NULL AND NOT ST_IsEmpty(q.tegola_centroid) GROUP BY gx, gy`
+	if sql != expected {
+		t.Errorf("incorrect sql,\n Expected \n \t%v\n Got \n \t%v", expected, sql)
+	}
+}
+
+func TestJoinSQL(t *testing.T) {
+	var (
+		lyr  = Layer{geomField: "geom", srid: tegola.WebMercator}
+		rlyr = Layer{geomField: "the_geom", srid: tegola.WGS84}
+		join = provider.JoinSpec{Layer: "regions", Predicate: provider.JoinContains, Fields: []string{"name", `odd"name`}, Prefix: "region_"}
+	)
+
+	sql := joinSQL("SELECT gid, ST_AsBinary(geom) AS geom FROM places", &lyr, "SELECT ST_AsBinary(the_geom) AS the_geom, name FROM regions", &rlyr, join)
+	expected := `SELECT l.*, j.* FROM (SELECT gid, ST_AsBinary(geom) AS geom FROM places) AS l LEFT JOIN LATERAL (SELECT r."name" AS "region_name", r."odd""name" AS "region_odd""name" FROM (SELECT ST_AsBinary(the_geom) AS the_geom, name FROM regions) AS r WHERE ST_Contains(ST_Transform(ST_GeomFromWKB(r."the_geom", 4326), 3857), ST_GeomFromWKB(l."geom", 3857)) LIMIT 1) AS j ON true`
+	if sql != expected {
+		t.Errorf("incorrect sql,\n Expected \n \t%v\n Got \n \t%v", expected, sql)
+	}
+}
+
+func TestFieldStatsSQL(t *testing.T) {
+	type tcase struct {
+		layer        Layer
+		expectedArgs []interface{}
+		expectedErr  error
+	}
+
+	fn := func(tc tcase) func(t *testing.T) {
+		return func(t *testing.T) {
+			sql, args, err := fieldStatsSQL(&tc.layer)
+			if err != tc.expectedErr {
+				t.Fatalf("error, expected %v got %v", tc.expectedErr, err)
+			}
+			if tc.expectedErr != nil {
+				return
+			}
+
+			if sql != fieldStatsQuery {
+				t.Errorf("incorrect sql,\n Expected \n \t%v\n Got \n \t%v", fieldStatsQuery, sql)
+			}
+			// the table is only referenced through the $1 argument
+			if !strings.Contains(sql, "to_regclass($1)") || strings.Contains(sql, tc.layer.tablename) {
+				t.Errorf("sql, expected the table as $1 got \n \t%v", sql)
+			}
+			if !reflect.DeepEqual(args, tc.expectedArgs) {
+				t.Errorf("args, expected %v got %v", tc.expectedArgs, args)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"tablename": {
+			layer:        Layer{tablename: "ne_10m_land_scale_rank"},
+			expectedArgs: []interface{}{"ne_10m_land_scale_rank"},
+		},
+		"schema qualified tablename": {
+			layer:        Layer{tablename: "osm.roads"},
+			expectedArgs: []interface{}{"osm.roads"},
+		},
+		"quoted tablename": {
+			layer:        Layer{tablename: `"Roads"`},
+			expectedArgs: []interface{}{`"Roads"`},
+		},
+		"sql": {
+			layer:       Layer{sql: "SELECT gid, ST_AsBinary(geom) AS geom FROM roads WHERE geom && !BBOX!"},
+			expectedErr: provider.ErrUnsupported,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestLocalSQL(t *testing.T) {
+	lyr := Layer{geomField: "geom", srid: tegola.WGS84}
+
+	sql := localSQL("SELECT gid, ST_AsBinary(geom) AS geom FROM places", &lyr, provider.NewTile(0, 0, 0, 64, tegola.WebMercator), 4096)
+	expected := `SELECT l.*, m.tegola_local AS "geom" FROM (SELECT gid, ST_AsBinary(geom) AS geom FROM places) AS l CROSS JOIN LATERAL (SELECT ST_AsBinary(ST_AsMVTGeom(ST_Transform(ST_GeomFromWKB(l."geom", 4326), 3857), ST_MakeEnvelope(-2.003750834e+07,-2.003750834e+07,2.003750834e+07,2.003750834e+07,3857)::box2d, 4096, 64, true)) AS tegola_local) AS m WHERE m.tegola_local IS NOT NULL`
+	if sql != expected {
+		t.Errorf("incorrect sql,\n Expected \n \t%v\n Got \n \t%v", expected, sql)
+	}
+}
@@ -0,0 +1,46 @@
+package postgis
+
+import (
+	"context"
+
+	"github.com/jackc/pgx"
+)
+
+// Warmup adheres to the provider.Warmer interface. n connections, capped at
+// the pool's max_connections, are opened and returned to the pool idle.
+func (p *Provider) Warmup(ctx context.Context, n int) error {
+	if max := p.config.MaxConnections; max > 0 && n > max {
+		n = max
+	}
+
+	// acquiring a connection can not be canceled, so connections are
+	// opened in the background and released once they are all open
+	done := make(chan error, 1)
+	go func() {
+		var (
+			conns = make([]*pgx.Conn, 0, n)
+			err   error
+		)
+		for len(conns) < n && ctx.Err() == nil {
+			var conn *pgx.Conn
+			if conn, err = p.pool.Acquire(); err != nil {
+				break
+			}
+			conns = append(conns, conn)
+		}
+		for _, conn := range conns {
+			p.pool.Release(conn)
+		}
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return err
+		}
+		return ctx.Err()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"context"
+	"math"
+
+	"github.com/go-spatial/tegola/internal/log"
+)
+
+// WithPrecisionReduction wraps the Tiler so the coordinates of features are
+// rounded to the given number of decimal places in the units of the
+// feature's SRID: degrees for geographic SRIDs such as 4326, and meters for
+// web mercator (3857). A negative decimals rounds to tens, hundreds, etc. of
+// units, i.e. -1 rounds web mercator coordinates to 10 meters.
+//
+// Features are rounded in their own SRID, they are not reprojected. As with
+// WithSnapToGrid, repeated points created by rounding are removed, parts of
+// geometries which collapse are dropped, and features which collapse
+// entirely are not passed to the callback.
+func WithPrecisionReduction(t Tiler, decimals int) Tiler {
+	return &precisionTiler{
+		Tiler:    t,
+		decimals: decimals,
+		round:    decimalRounder(decimals),
+	}
+}
+
+type precisionTiler struct {
+	Tiler
+	decimals int
+	round    pointRounder
+}
+
+func (pt *precisionTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	return pt.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		if f.Geometry == nil {
+			return fn(f)
+		}
+
+		g := snapGeometry(f.Geometry, pt.round)
+		if g == nil {
+			log.Debugf("layer (%v) feature %v dropped, collapsed when rounded to %v decimals", layer, f.ID, pt.decimals)
+			return nil
+		}
+		f.Geometry = g
+		return fn(f)
+	})
+}
+
+// decimalRounder rounds points to the number of decimal places
+func decimalRounder(decimals int) pointRounder {
+	if decimals < 0 {
+		return gridRounder(math.Pow10(-decimals))
+	}
+	// multiplying by the scale, rather than dividing by the (inexact) grid
+	// size, gives the closest float to the rounded decimal
+	scale := math.Pow10(decimals)
+	return func(pt [2]float64) [2]float64 {
+		return [2]float64{
+			math.Round(pt[0]*scale) / scale,
+			math.Round(pt[1]*scale) / scale,
+		}
+	}
+}
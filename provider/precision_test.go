@@ -0,0 +1,116 @@
+package provider_test
+
+import (
+	"bytes"
+	"context"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithPrecisionReduction(t *testing.T) {
+	type tcase struct {
+		decimals int
+		features []provider.Feature
+		expected []provider.Feature
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		features, err := collect(provider.WithPrecisionReduction(featuresTiler{features: tc.features}, tc.decimals), "", provider.NewTile(0, 0, 0, 0, 3857))
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		if !reflect.DeepEqual(features, tc.expected) {
+			t.Errorf("features, expected %v got %v", tc.expected, features)
+		}
+	}
+
+	tests := map[string]tcase{
+		// features are rounded in their own SRID
+		"geographic": {
+			decimals: 2,
+			features: []provider.Feature{
+				{ID: 1, SRID: 4326, Geometry: geom.Point{12.34567, -7.65432}},
+				{ID: 2, SRID: 4326, Geometry: geom.LineString{{0.101, 0.104}, {0.3, 0.3}}},
+			},
+			expected: []provider.Feature{
+				{ID: 1, SRID: 4326, Geometry: geom.Point{12.35, -7.65}},
+				{ID: 2, SRID: 4326, Geometry: geom.LineString{{0.1, 0.1}, {0.3, 0.3}}},
+			},
+		},
+		"web mercator": {
+			decimals: 0,
+			features: []provider.Feature{
+				{ID: 1, SRID: 3857, Geometry: geom.Point{1234.5678, -0.4}},
+				// repeated points are removed
+				{ID: 2, SRID: 3857, Geometry: geom.LineString{{0.1, 0.1}, {0.2, 0.2}, {9.9, 10.1}}},
+			},
+			expected: []provider.Feature{
+				{ID: 1, SRID: 3857, Geometry: geom.Point{1235, 0}},
+				{ID: 2, SRID: 3857, Geometry: geom.LineString{{0, 0}, {10, 10}}},
+			},
+		},
+		"negative decimals": {
+			decimals: -1,
+			features: []provider.Feature{
+				{ID: 1, SRID: 3857, Geometry: geom.Point{1234.5678, -7.6}},
+				// collapses to a point
+				{ID: 2, SRID: 3857, Geometry: geom.LineString{{1, 1}, {2, 2}}},
+				// collapses to a line
+				{ID: 3, SRID: 3857, Geometry: geom.Polygon{{{0, 0}, {10, 1}, {20, 2}}}},
+			},
+			expected: []provider.Feature{
+				{ID: 1, SRID: 3857, Geometry: geom.Point{1230, -10}},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
+
+func BenchmarkPrecisionReduction(b *testing.B) {
+	const n = 500
+
+	tile := provider.NewTile(10, 512, 512, 64, 3857)
+	ext, _ := tile.Extent()
+
+	// lines with vertices a few meters apart, far closer than a
+	// pixel of the tile (~10m per unit of the 4096 MVT extent)
+	r := rand.New(rand.NewSource(1))
+	tiler := featuresTiler{features: make([]provider.Feature, n)}
+	for i := range tiler.features {
+		x := ext.MinX() + r.Float64()*ext.XSpan()
+		y := ext.MinY() + r.Float64()*ext.YSpan()
+		line := make(geom.LineString, 50)
+		for j := range line {
+			x, y = x+r.Float64()*4, y+r.Float64()*4
+			line[j] = [2]float64{x, y}
+		}
+		tiler.features[i] = provider.Feature{ID: uint64(i + 1), SRID: 3857, Geometry: line}
+	}
+
+	for name, t := range map[string]provider.Tiler{
+		"full": tiler,
+		"10m":  provider.WithPrecisionReduction(tiler, -1),
+		"100m": provider.WithPrecisionReduction(tiler, -2),
+	} {
+		t := t
+		b.Run(name, func(b *testing.B) {
+			var size int
+			for i := 0; i < b.N; i++ {
+				var buf bytes.Buffer
+				if err := provider.EncodeStream(context.Background(), t, "roads", tile, &buf); err != nil {
+					b.Fatal(err)
+				}
+				size = buf.Len()
+			}
+			b.ReportMetric(float64(size), "tile-bytes")
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"context"
+	"sort"
+	"strconv"
+)
+
+// WithPropertyFlatten wraps the Tiler so nested property maps, i.e. from
+// JSONB columns, are flattened into keys joined by separator, as MVT
+// properties can not be nested. With a separator of "." the property
+// {"addr": {"city": "X"}} becomes {"addr.city": "X"}, and array elements are
+// keyed by their index, so {"tags": ["a", "b"]} becomes {"tags.0": "a",
+// "tags.1": "b"}. An empty separator uses ".".
+//
+// Only map[string]interface{} and []interface{} values, as decoded from
+// JSON, are flattened. Empty maps and arrays are dropped. If a flattened key
+// collides with an existing property the existing property wins.
+func WithPropertyFlatten(t Tiler, separator string) Tiler {
+	if separator == "" {
+		separator = "."
+	}
+	return &propertyFlattenTiler{
+		Tiler: t,
+		sep:   separator,
+	}
+}
+
+type propertyFlattenTiler struct {
+	Tiler
+	sep string
+}
+
+func (pft *propertyFlattenTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	return pft.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		f.Tags = pft.flatten(f.Tags)
+		return fn(f)
+	})
+}
+
+func (pft *propertyFlattenTiler) flatten(tags map[string]interface{}) map[string]interface{} {
+	var nested []string
+	for k, v := range tags {
+		switch v.(type) {
+		case map[string]interface{}, []interface{}:
+			nested = append(nested, k)
+		}
+	}
+	if len(nested) == 0 {
+		return tags
+	}
+
+	flat := make(map[string]interface{}, len(tags))
+	for k, v := range tags {
+		flat[k] = v
+	}
+	for _, k := range nested {
+		delete(flat, k)
+	}
+
+	// sorted so collisions between flattened keys are deterministic
+	sort.Strings(nested)
+	for _, k := range nested {
+		pft.add(flat, k, tags[k])
+	}
+	return flat
+}
+
+// add adds the value to flat under key, flattening maps and arrays
+func (pft *propertyFlattenTiler) add(flat map[string]interface{}, key string, v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			pft.add(flat, key+pft.sep+k, v[k])
+		}
+	case []interface{}:
+		for i := range v {
+			pft.add(flat, key+pft.sep+strconv.Itoa(i), v[i])
+		}
+	default:
+		if _, ok := flat[key]; !ok {
+			flat[key] = v
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package provider_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithPropertyFlatten(t *testing.T) {
+	type tcase struct {
+		separator string
+		tags      map[string]interface{}
+		expected  map[string]interface{}
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		tiler := featuresTiler{
+			features: []provider.Feature{{ID: 1, Geometry: geom.Point{1, 1}, Tags: tc.tags}},
+		}
+		got, err := collect(provider.WithPropertyFlatten(tiler, tc.separator), "places", provider.NewTile(0, 0, 0, 0, 3857))
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		if len(got) != 1 {
+			t.Fatalf("features, expected 1 got %v", len(got))
+		}
+		if !reflect.DeepEqual(got[0].Tags, tc.expected) {
+			t.Errorf("tags, expected %v got %v", tc.expected, got[0].Tags)
+		}
+	}
+
+	nested := map[string]interface{}{
+		"name": "cafe",
+		"addr": map[string]interface{}{
+			"city": "X",
+			"street": map[string]interface{}{
+				"name":   "Main",
+				"number": 12.0,
+			},
+		},
+		"tags": []interface{}{"food", "drink"},
+	}
+
+	tests := map[string]tcase{
+		"nested map and array": {
+			tags: nested,
+			expected: map[string]interface{}{
+				"name":               "cafe",
+				"addr.city":          "X",
+				"addr.street.name":   "Main",
+				"addr.street.number": 12.0,
+				"tags.0":             "food",
+				"tags.1":             "drink",
+			},
+		},
+		"separator": {
+			separator: "_",
+			tags:      nested,
+			expected: map[string]interface{}{
+				"name":               "cafe",
+				"addr_city":          "X",
+				"addr_street_name":   "Main",
+				"addr_street_number": 12.0,
+				"tags_0":             "food",
+				"tags_1":             "drink",
+			},
+		},
+		"array of maps": {
+			tags: map[string]interface{}{
+				"refs": []interface{}{map[string]interface{}{"id": 1.0}, map[string]interface{}{}},
+			},
+			expected: map[string]interface{}{
+				"refs.0.id": 1.0,
+			},
+		},
+		"collision": {
+			tags: map[string]interface{}{
+				"addr.city": "Y",
+				"addr":      map[string]interface{}{"city": "X", "zip": "1"},
+			},
+			expected: map[string]interface{}{
+				"addr.city": "Y",
+				"addr.zip":  "1",
+			},
+		},
+		"flat": {
+			tags:     map[string]interface{}{"name": "cafe"},
+			expected: map[string]interface{}{"name": "cafe"},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
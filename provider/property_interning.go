@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// WithPropertyInterning wraps the Tiler so features with identical properties
+// share a single properties map, reducing memory for layers with few distinct
+// property sets. Maps are interned per TileFeatures call, so memory does not
+// grow across tiles.
+//
+// Since a map may be shared by many features, the features' Tags must be
+// treated as read-only by fn and anything downstream of it.
+func WithPropertyInterning(t Tiler) Tiler {
+	return &internTiler{Tiler: t}
+}
+
+type internTiler struct {
+	Tiler
+}
+
+func (it *internTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	var (
+		interned = make(map[string]map[string]interface{})
+		keys     []string
+		sb       strings.Builder
+	)
+
+	return it.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		if len(f.Tags) == 0 {
+			return fn(f)
+		}
+
+		keys = keys[:0]
+		for k := range f.Tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		// the canonical form includes the value types
+		// so 1 and "1" are not considered the same
+		sb.Reset()
+		for _, k := range keys {
+			fmt.Fprintf(&sb, "%q:%T:%#v,", k, f.Tags[k], f.Tags[k])
+		}
+
+		key := sb.String()
+		if tags, ok := interned[key]; ok {
+			f.Tags = tags
+		} else {
+			interned[key] = f.Tags
+		}
+		return fn(f)
+	})
+}
@@ -0,0 +1,40 @@
+package provider_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithPropertyInterning(t *testing.T) {
+	tiler := featuresTiler{
+		features: []provider.Feature{
+			{ID: 1, Geometry: geom.Point{1, 1}, Tags: map[string]interface{}{"class": "building", "levels": 2}},
+			{ID: 2, Geometry: geom.Point{1, 1}, Tags: map[string]interface{}{"levels": 2, "class": "building"}},
+			{ID: 3, Geometry: geom.Point{1, 1}, Tags: map[string]interface{}{"class": "building", "levels": "2"}},
+			{ID: 4, Geometry: geom.Point{1, 1}},
+		},
+	}
+
+	features, err := collect(provider.WithPropertyInterning(tiler), "", provider.NewTile(0, 0, 0, 0, 3857))
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+
+	ptr := func(i int) uintptr { return reflect.ValueOf(features[i].Tags).Pointer() }
+	if ptr(0) != ptr(1) {
+		t.Errorf("identical properties, expected a shared map")
+	}
+	// levels is a string, not an int
+	if ptr(0) == ptr(2) {
+		t.Errorf("different properties, expected distinct maps")
+	}
+	if !reflect.DeepEqual(features[2].Tags, map[string]interface{}{"class": "building", "levels": "2"}) {
+		t.Errorf("feature 3 tags, got %v", features[2].Tags)
+	}
+	if features[3].Tags != nil {
+		t.Errorf("feature 4 tags, expected nil got %v", features[3].Tags)
+	}
+}
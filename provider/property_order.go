@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"sort"
+)
+
+// WithPropertyOrder wraps the Tiler so features are passed to the callback
+// sorted by the value of their key property, ascending or descending. As
+// renderers draw features in the order they are encoded, this controls which
+// features are drawn on top, i.e. sorting roads by an ascending importance
+// draws major roads over minor ones, without an ORDER BY in every layer's
+// query. Features with the same value keep their relative order.
+//
+// Numbers are compared by value, regardless of their type, booleans sort
+// false before true and strings sort lexically. Numbers sort before
+// booleans, which sort before strings, and values of any other type are
+// compared as strings of their JSON encoding. Features without the property,
+// or with a nil value, always sort last, in both directions, as with NULLS
+// LAST in SQL. As every feature of the tile must be read before any can be
+// passed on, all the features of the tile are held in memory.
+func WithPropertyOrder(t Tiler, key string, descending bool) Tiler {
+	return &propertyOrderTiler{
+		Tiler:      t,
+		key:        key,
+		descending: descending,
+	}
+}
+
+type propertyOrderTiler struct {
+	Tiler
+	key        string
+	descending bool
+}
+
+// orderValue is the sort key of a property value
+type orderValue struct {
+	kind orderKind
+	num  float64
+	str  string
+}
+
+// orderKind is the kind of a property value, in sort order
+type orderKind uint8
+
+const (
+	orderNumber orderKind = iota
+	orderBool
+	orderString
+	orderMissing
+)
+
+func newOrderValue(v interface{}) orderValue {
+	if v == nil {
+		return orderValue{kind: orderMissing}
+	}
+	if n, ok := arrowFloat(v); ok {
+		return orderValue{kind: orderNumber, num: n}
+	}
+	switch v := v.(type) {
+	case bool:
+		if v {
+			return orderValue{kind: orderBool, num: 1}
+		}
+		return orderValue{kind: orderBool}
+	case string:
+		return orderValue{kind: orderString, str: v}
+	default:
+		return orderValue{kind: orderString, str: stringifyValue(v)}
+	}
+}
+
+// less reports if a sorts before b in ascending order
+func (a orderValue) less(b orderValue) bool {
+	switch {
+	case a.kind != b.kind:
+		return a.kind < b.kind
+	case a.kind == orderString:
+		return a.str < b.str
+	default:
+		return a.num < b.num
+	}
+}
+
+type orderedFeature struct {
+	f Feature
+	v orderValue
+}
+
+func (pt *propertyOrderTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	var features []orderedFeature
+	err := pt.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		features = append(features, orderedFeature{
+			f: *f,
+			v: newOrderValue(f.Tags[pt.key]),
+		})
+		return nil
+	})
+	if err != nil && !errors.Is(err, ErrNoFeatures) {
+		return err
+	}
+
+	sort.SliceStable(features, func(i, j int) bool {
+		a, b := features[i].v, features[j].v
+		if pt.descending && a.kind != orderMissing && b.kind != orderMissing {
+			return b.less(a)
+		}
+		return a.less(b)
+	})
+
+	for i := range features {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(&features[i].f); err != nil {
+			return err
+		}
+	}
+	return err
+}
@@ -0,0 +1,60 @@
+package provider_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithPropertyOrder(t *testing.T) {
+	tiler := featuresTiler{
+		features: []provider.Feature{
+			{ID: 1, Tags: map[string]interface{}{"importance": int64(2)}},
+			{ID: 2},
+			{ID: 3, Tags: map[string]interface{}{"importance": 7.5}},
+			{ID: 4, Tags: map[string]interface{}{"importance": nil}},
+			{ID: 5, Tags: map[string]interface{}{"importance": uint8(2)}},
+			{ID: 6, Tags: map[string]interface{}{"importance": "high"}},
+			{ID: 7, Tags: map[string]interface{}{"importance": int32(10)}},
+			{ID: 8, Tags: map[string]interface{}{"name": "a"}},
+			{ID: 9, Tags: map[string]interface{}{"importance": true}},
+		},
+	}
+
+	type tcase struct {
+		descending bool
+		expected   []uint64
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		features, err := collect(provider.WithPropertyOrder(tiler, "importance", tc.descending), "", provider.NewTile(0, 0, 0, 0, 3857))
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+
+		var ids []uint64
+		for _, f := range features {
+			ids = append(ids, f.ID)
+		}
+		if !reflect.DeepEqual(ids, tc.expected) {
+			t.Errorf("order, expected %v got %v", tc.expected, ids)
+		}
+	}
+
+	tests := map[string]tcase{
+		// features with the same value keep their order, missing values are last
+		"ascending": {
+			expected: []uint64{1, 5, 3, 7, 9, 6, 2, 4, 8},
+		},
+		"descending": {
+			descending: true,
+			expected:   []uint64{6, 9, 7, 3, 1, 5, 2, 4, 8},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
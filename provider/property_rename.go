@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"context"
+	"sort"
+)
+
+// WithPropertyRename wraps the Tiler so that feature property keys are renamed
+// according to mapping (source key -> target key) before being passed to the
+// callback. Keys not in the mapping are left untouched.
+//
+// If the target key already exists on the feature and is not itself being
+// renamed, the existing value wins and the source key is left as is. If
+// several source keys map to the same target, the first source key in sorted
+// order wins.
+func WithPropertyRename(t Tiler, mapping map[string]string) Tiler {
+	if len(mapping) == 0 {
+		return t
+	}
+	return &propertyRenameTiler{
+		Tiler:   t,
+		mapping: mapping,
+	}
+}
+
+type propertyRenameTiler struct {
+	Tiler
+	mapping map[string]string
+}
+
+func (prt *propertyRenameTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	return prt.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		f.Tags = prt.rename(f.Tags)
+		return fn(f)
+	})
+}
+
+func (prt *propertyRenameTiler) rename(tags map[string]interface{}) map[string]interface{} {
+	if len(tags) == 0 {
+		return tags
+	}
+
+	renamed := make(map[string]interface{}, len(tags))
+	var sources []string
+	for k, v := range tags {
+		if _, ok := prt.mapping[k]; ok {
+			sources = append(sources, k)
+			continue
+		}
+		renamed[k] = v
+	}
+	if len(sources) == 0 {
+		return tags
+	}
+
+	sort.Strings(sources)
+	for _, k := range sources {
+		target := prt.mapping[k]
+		if _, ok := renamed[target]; ok {
+			// collision, the existing key wins
+			renamed[k] = tags[k]
+			continue
+		}
+		renamed[target] = tags[k]
+	}
+
+	return renamed
+}
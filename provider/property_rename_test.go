@@ -0,0 +1,58 @@
+package provider_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithPropertyRename(t *testing.T) {
+	type tcase struct {
+		mapping  map[string]string
+		tags     map[string]interface{}
+		expected map[string]interface{}
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		tiler := featuresTiler{
+			features: []provider.Feature{{ID: 1, Geometry: geom.Point{1, 1}, Tags: tc.tags}},
+		}
+
+		features, err := collect(provider.WithPropertyRename(tiler, tc.mapping), "", provider.NewTile(0, 0, 0, 0, 3857))
+		if err != nil {
+			t.Errorf("error, expected nil got %v", err)
+			return
+		}
+		if !reflect.DeepEqual(features[0].Tags, tc.expected) {
+			t.Errorf("tags, expected %v got %v", tc.expected, features[0].Tags)
+		}
+	}
+
+	tests := map[string]tcase{
+		"rename": {
+			mapping:  map[string]string{"nom": "name"},
+			tags:     map[string]interface{}{"nom": "Paris", "pop": 2161000},
+			expected: map[string]interface{}{"name": "Paris", "pop": 2161000},
+		},
+		"collision existing key wins": {
+			mapping:  map[string]string{"name_en": "name"},
+			tags:     map[string]interface{}{"name": "København", "name_en": "Copenhagen"},
+			expected: map[string]interface{}{"name": "København", "name_en": "Copenhagen"},
+		},
+		"swap": {
+			mapping:  map[string]string{"a": "b", "b": "a"},
+			tags:     map[string]interface{}{"a": 1, "b": 2},
+			expected: map[string]interface{}{"a": 2, "b": 1},
+		},
+		"no tags": {
+			mapping: map[string]string{"nom": "name"},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
@@ -0,0 +1,169 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-spatial/tegola/internal/log"
+)
+
+// PropertyKind is the expected type of a property's value
+type PropertyKind uint8
+
+const (
+	// PropertyAny accepts any value
+	PropertyAny PropertyKind = iota
+	// PropertyString accepts strings
+	PropertyString
+	// PropertyNumber accepts integers and floating point numbers
+	PropertyNumber
+	// PropertyBool accepts booleans
+	PropertyBool
+)
+
+func (k PropertyKind) String() string {
+	switch k {
+	case PropertyAny:
+		return "any"
+	case PropertyString:
+		return "string"
+	case PropertyNumber:
+		return "number"
+	case PropertyBool:
+		return "bool"
+	default:
+		return "unknown"
+	}
+}
+
+// matches reports if the value is of the kind
+func (k PropertyKind) matches(v interface{}) bool {
+	switch k {
+	case PropertyString:
+		_, ok := v.(string)
+		return ok
+	case PropertyNumber:
+		switch v.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+			return true
+		}
+		return false
+	case PropertyBool:
+		_, ok := v.(bool)
+		return ok
+	default:
+		return true
+	}
+}
+
+// PropertyRule is the expectation of a single property
+type PropertyRule struct {
+	Kind PropertyKind
+	// Required properties must be present with a non nil value
+	Required bool
+}
+
+// PropertySchema is the expected properties of a layer's features, keyed by
+// property name. Properties not in the schema are not checked, and nil
+// values of properties which are not required are accepted.
+type PropertySchema map[string]PropertyRule
+
+// Validate returns the violations of the schema by the properties, ordered
+// by property name
+func (schema PropertySchema) Validate(tags map[string]interface{}) []PropertyViolation {
+	keys := make([]string, 0, len(schema))
+	for k := range schema {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var violations []PropertyViolation
+	for _, k := range keys {
+		rule := schema[k]
+		v := tags[k]
+		switch {
+		case v == nil && rule.Required:
+			violations = append(violations, PropertyViolation{Key: k, Reason: "missing required property"})
+		case v != nil && !rule.Kind.matches(v):
+			violations = append(violations, PropertyViolation{Key: k, Reason: fmt.Sprintf("expected %v got %T", rule.Kind, v)})
+		}
+	}
+	return violations
+}
+
+// PropertyViolation is a property which does not conform to a PropertySchema
+type PropertyViolation struct {
+	Key    string
+	Reason string
+}
+
+func (pv PropertyViolation) String() string {
+	return fmt.Sprintf("property (%v) %v", pv.Key, pv.Reason)
+}
+
+// ViolationMode determines how a feature violating a PropertySchema is handled
+type ViolationMode uint8
+
+const (
+	// ViolationDrop skips the feature, logging the violations at debug level
+	ViolationDrop ViolationMode = iota
+	// ViolationLog logs the violations as a warning and keeps the feature
+	ViolationLog
+	// ViolationError returns an ErrPropertySchema from TileFeatures
+	ViolationError
+)
+
+func (m ViolationMode) String() string {
+	switch m {
+	case ViolationDrop:
+		return "drop"
+	case ViolationLog:
+		return "log"
+	case ViolationError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// WithPropertySchema wraps the Tiler so each feature's properties are
+// validated against the schema, catching upstream data regressions such as a
+// renamed column when tiles are served. Features violating the schema are
+// handled according to onViolation. If the schema is empty t is returned.
+func WithPropertySchema(t Tiler, schema PropertySchema, onViolation ViolationMode) Tiler {
+	if len(schema) == 0 {
+		return t
+	}
+	return &propSchemaTiler{
+		Tiler:  t,
+		schema: schema,
+		mode:   onViolation,
+	}
+}
+
+type propSchemaTiler struct {
+	Tiler
+	schema PropertySchema
+	mode   ViolationMode
+}
+
+func (pst *propSchemaTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	return pst.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		violations := pst.schema.Validate(f.Tags)
+		if len(violations) == 0 {
+			return fn(f)
+		}
+
+		switch pst.mode {
+		case ViolationError:
+			return ErrPropertySchema{Layer: layer, ID: f.ID, Violations: violations}
+		case ViolationLog:
+			log.Warnf("layer (%v) feature %v violates the property schema: %v", layer, f.ID, violations)
+			return fn(f)
+		default:
+			log.Debugf("layer (%v) feature %v dropped, violates the property schema: %v", layer, f.ID, violations)
+			return nil
+		}
+	})
+}
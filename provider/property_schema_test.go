@@ -0,0 +1,110 @@
+package provider_test
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/internal/log"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithPropertySchema(t *testing.T) {
+	var out bytes.Buffer
+	log.SetOutput(&out)
+	defer log.SetOutput(os.Stderr)
+
+	schema := provider.PropertySchema{
+		"name": {Kind: provider.PropertyString, Required: true},
+		"pop":  {Kind: provider.PropertyNumber},
+	}
+
+	var (
+		valid       = provider.Feature{ID: 1, Geometry: geom.Point{1, 1}, Tags: map[string]interface{}{"name": "a", "pop": 10}}
+		missingName = provider.Feature{ID: 2, Geometry: geom.Point{2, 2}, Tags: map[string]interface{}{"title": "b", "pop": 2.5}}
+		wrongType   = provider.Feature{ID: 3, Geometry: geom.Point{3, 3}, Tags: map[string]interface{}{"name": "c", "pop": "many"}}
+	)
+
+	type tcase struct {
+		features []provider.Feature
+		mode     provider.ViolationMode
+		expected []uint64
+		logged   string
+		err      error
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		out.Reset()
+
+		tiler := featuresTiler{features: tc.features}
+		got, err := collect(provider.WithPropertySchema(tiler, schema, tc.mode), "places", provider.NewTile(0, 0, 0, 0, 3857))
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Fatalf("error, expected %v got %v", tc.err, err)
+		}
+		if tc.err != nil {
+			return
+		}
+
+		var ids []uint64
+		for _, f := range got {
+			ids = append(ids, f.ID)
+		}
+		if !reflect.DeepEqual(ids, tc.expected) {
+			t.Errorf("features, expected %v got %v", tc.expected, ids)
+		}
+		if !strings.Contains(out.String(), tc.logged) {
+			t.Errorf("log, expected to contain %q got %q", tc.logged, out.String())
+		}
+	}
+
+	tests := map[string]tcase{
+		"missing required drop": {
+			features: []provider.Feature{valid, missingName},
+			mode:     provider.ViolationDrop,
+			expected: []uint64{1},
+		},
+		"missing required log": {
+			features: []provider.Feature{valid, missingName},
+			mode:     provider.ViolationLog,
+			expected: []uint64{1, 2},
+			logged:   "feature 2 violates the property schema: [property (name) missing required property]",
+		},
+		"missing required error": {
+			features: []provider.Feature{valid, missingName},
+			mode:     provider.ViolationError,
+			err: provider.ErrPropertySchema{
+				Layer:      "places",
+				ID:         2,
+				Violations: []provider.PropertyViolation{{Key: "name", Reason: "missing required property"}},
+			},
+		},
+		"wrong type drop": {
+			features: []provider.Feature{valid, wrongType},
+			mode:     provider.ViolationDrop,
+			expected: []uint64{1},
+		},
+		"wrong type log": {
+			features: []provider.Feature{valid, wrongType},
+			mode:     provider.ViolationLog,
+			expected: []uint64{1, 3},
+			logged:   "feature 3 violates the property schema: [property (pop) expected number got string]",
+		},
+		"wrong type error": {
+			features: []provider.Feature{valid, wrongType},
+			mode:     provider.ViolationError,
+			err: provider.ErrPropertySchema{
+				Layer:      "places",
+				ID:         3,
+				Violations: []provider.PropertyViolation{{Key: "pop", Reason: "expected number got string"}},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
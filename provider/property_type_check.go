@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-spatial/tegola/internal/log"
+)
+
+// TypePolicy determines how a property value which is not a MVT scalar, i.e.
+// a nested map or slice from a JSONB column, is handled
+type TypePolicy uint8
+
+const (
+	// PropTypeDrop removes the property from the feature
+	PropTypeDrop TypePolicy = iota
+	// PropTypeStringify replaces the value with its JSON encoding, or its
+	// fmt.Sprint formatting if it can not be JSON encoded
+	PropTypeStringify
+	// PropTypeError returns an ErrPropertyType from TileFeatures
+	PropTypeError
+)
+
+func (p TypePolicy) String() string {
+	switch p {
+	case PropTypeDrop:
+		return "drop"
+	case PropTypeStringify:
+		return "stringify"
+	case PropTypeError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// WithPropertyTypeCheck wraps the Tiler so property values which can not be
+// encoded into a MVT, anything other than strings, numbers, bools and
+// fmt.Stringers, are handled according to policy rather than breaking the
+// tile when it is encoded. nil values are left as is, as encoders skip them.
+func WithPropertyTypeCheck(t Tiler, policy TypePolicy) Tiler {
+	return &propTypeTiler{
+		Tiler:  t,
+		policy: policy,
+	}
+}
+
+type propTypeTiler struct {
+	Tiler
+	policy TypePolicy
+}
+
+func (pt *propTypeTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	return pt.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		var tags map[string]interface{}
+		for k, v := range f.Tags {
+			if v == nil {
+				continue
+			}
+			if _, _, err := tileValue(v); err == nil {
+				continue
+			}
+
+			if pt.policy == PropTypeError {
+				return ErrPropertyType{Layer: layer, ID: f.ID, Key: k, Value: v}
+			}
+
+			// the provider may share the tags between features,
+			// so they are copied before they are changed
+			if tags == nil {
+				tags = make(map[string]interface{}, len(f.Tags))
+				for k, v := range f.Tags {
+					tags[k] = v
+				}
+			}
+
+			if pt.policy == PropTypeStringify {
+				tags[k] = stringifyValue(v)
+				continue
+			}
+			log.Debugf("layer (%v) feature %v property (%v) dropped, value of type %T", layer, f.ID, k, v)
+			delete(tags, k)
+		}
+
+		if tags != nil {
+			f.Tags = tags
+		}
+		return fn(f)
+	})
+}
+
+// stringifyValue returns the JSON encoding of v, falling back to fmt.Sprint
+func stringifyValue(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
@@ -0,0 +1,75 @@
+package provider_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithPropertyTypeCheck(t *testing.T) {
+	nested := map[string]interface{}{"a": []interface{}{1.0, "b"}}
+	tiler := featuresTiler{
+		features: []provider.Feature{
+			{ID: 1, Geometry: geom.Point{1, 1}, Tags: map[string]interface{}{"name": "a", "meta": nested, "empty": nil}},
+			{ID: 2, Geometry: geom.Point{2, 2}, Tags: map[string]interface{}{"name": "b", "pop": 10}},
+		},
+	}
+
+	type tcase struct {
+		policy   provider.TypePolicy
+		expected []map[string]interface{}
+		err      error
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		features, err := collect(provider.WithPropertyTypeCheck(tiler, tc.policy), "roads", provider.NewTile(0, 0, 0, 0, 3857))
+		if !reflect.DeepEqual(err, tc.err) {
+			t.Fatalf("error, expected %v got %v", tc.err, err)
+		}
+		if tc.err != nil {
+			return
+		}
+
+		if len(features) != len(tc.expected) {
+			t.Fatalf("features, expected %v got %v", len(tc.expected), len(features))
+		}
+		for i := range features {
+			if !reflect.DeepEqual(features[i].Tags, tc.expected[i]) {
+				t.Errorf("feature %v tags, expected %v got %v", features[i].ID, tc.expected[i], features[i].Tags)
+			}
+		}
+
+		// the provider's tags are not changed
+		if _, ok := tiler.features[0].Tags["meta"]; !ok {
+			t.Errorf("provider tags, expected meta got %v", tiler.features[0].Tags)
+		}
+	}
+
+	tests := map[string]tcase{
+		"drop": {
+			policy: provider.PropTypeDrop,
+			expected: []map[string]interface{}{
+				{"name": "a", "empty": nil},
+				{"name": "b", "pop": 10},
+			},
+		},
+		"stringify": {
+			policy: provider.PropTypeStringify,
+			expected: []map[string]interface{}{
+				{"name": "a", "meta": `{"a":[1,"b"]}`, "empty": nil},
+				{"name": "b", "pop": 10},
+			},
+		},
+		"error": {
+			policy: provider.PropTypeError,
+			err:    provider.ErrPropertyType{Layer: "roads", ID: 1, Key: "meta", Value: nested},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
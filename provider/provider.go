@@ -3,6 +3,7 @@ package provider
 import (
 	"context"
 	"fmt"
+	"math"
 
 	"github.com/go-spatial/geom"
 	"github.com/go-spatial/geom/slippy"
@@ -10,6 +11,15 @@ import (
 	"github.com/go-spatial/tegola/internal/log"
 )
 
+const (
+	// TileSize is the width and height of a tile, in pixels, assumed when
+	// calculating the resolution of a tile. Note this is the size of the
+	// rendered tile and not the MVT extent (4096) which the buffer is in.
+	TileSize = 256
+	// EarthRadius is the radius, in meters, of the sphere used by web mercator
+	EarthRadius = 6378137
+)
+
 // TODO(@ear7h) remove this atrocity from the code base
 // tile_t is an implementation of the Tile interface, it is
 // named as such as to not confuse from the 4 other possible meanings
@@ -40,6 +50,14 @@ func (tile *tile_t) BufferedExtent() (ext *geom.Extent, srid uint64) {
 	return tile.Extent3857().ExpandBy(slippy.Pixels2Webs(tile.Z, tile.buffer)), 3857
 }
 
+// Resolution returns the ground resolution, in meters per pixel, at the
+// tile's center latitude. A tile is assumed to be TileSize pixels wide.
+func (tile *tile_t) Resolution() float64 {
+	n := math.Exp2(float64(tile.Z))
+	lat := math.Atan(math.Sinh(math.Pi * (1 - 2*(float64(tile.Y)+0.5)/n)))
+	return math.Cos(lat) * 2 * math.Pi * EarthRadius / (TileSize * n)
+}
+
 // Tile is an interface used by Tiler, it is an unecessary abstraction and is
 // due to be removed. The tiler interface will, instead take a, *geom.Extent.
 type Tile interface {
@@ -49,13 +67,16 @@ type Tile interface {
 	Extent() (extent *geom.Extent, srid uint64)
 	// BufferedExtent returns the extent of the tile including any buffer
 	BufferedExtent() (extent *geom.Extent, srid uint64)
+	// Resolution returns the ground resolution of the tile in meters per pixel
+	Resolution() float64
 }
 
 type Tiler interface {
 	Layerer
 
 	// TileFeature will stream decoded features to the callback function fn
-	// if fn returns ErrCanceled, the TileFeatures method should stop processing
+	// if fn returns ErrCanceled, the TileFeatures method should stop processing.
+	// ErrNoFeatures may be returned to report the tile is definitively empty.
 	TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error
 }
 
@@ -76,9 +97,48 @@ type InitFunc func(dicter dict.Dicter) (Tiler, error)
 // CleanupFunc is called to when the system is shuting down, this allows the provider to cleanup.
 type CleanupFunc func()
 
+// providerType is the kind of provider registered under a name
+type providerType uint8
+
+const (
+	// TypeStd is a provider implementing Tiler
+	TypeStd providerType = 1 << iota
+	// TypeMvt is a provider implementing MVTTiler
+	TypeMvt
+
+	// TypeAll is a name registered as both a standard and a MVT provider
+	TypeAll = TypeStd | TypeMvt
+)
+
+func (pt providerType) String() string {
+	switch pt {
+	case TypeStd:
+		return "std"
+	case TypeMvt:
+		return "mvt"
+	case TypeAll:
+		return "std,mvt"
+	default:
+		return "none"
+	}
+}
+
 type pfns struct {
 	init    InitFunc
 	cleanup CleanupFunc
+
+	mvtInit    MVTInitFunc
+	mvtCleanup CleanupFunc
+}
+
+func (p pfns) providerType() (pt providerType) {
+	if p.init != nil {
+		pt |= TypeStd
+	}
+	if p.mvtInit != nil {
+		pt |= TypeMvt
+	}
+	return pt
 }
 
 var providers map[string]pfns
@@ -90,26 +150,52 @@ func Register(name string, init InitFunc, cleanup CleanupFunc) error {
 		providers = make(map[string]pfns)
 	}
 
-	if _, ok := providers[name]; ok {
+	p := providers[name]
+	if p.init != nil {
 		return fmt.Errorf("provider %v already exists", name)
 	}
 
-	providers[name] = pfns{
-		init:    init,
-		cleanup: cleanup,
-	}
+	p.init = init
+	p.cleanup = cleanup
+	providers[name] = p
 
+	notifyObservers(registerObservers, name, TypeStd)
 	return nil
 }
 
+// IsRegistered reports if a provider has been registered under the name,
+// without initializing it, and if so, the type of provider registered. The
+// name should not include MVTNamePrefix; a name registered as both a standard
+// and a MVT provider reports TypeAll.
+func IsRegistered(name string) (registered bool, pt providerType) {
+	pt = providers[name].providerType()
+	return pt != 0, pt
+}
+
+// RegisterTyped registers a provider whose init function takes a typed config
+// struct rather than a dict.Dicter. The provider's config map is decoded into
+// a T using dict.Decode (see it for the supported struct tags) before init is
+// called. Decoding errors are reported as ErrInvalidConfig.
+func RegisterTyped[T any](name string, init func(config T) (Tiler, error), cleanup CleanupFunc) error {
+	return Register(name, func(dicter dict.Dicter) (Tiler, error) {
+		var config T
+		if err := dict.Decode(dicter, &config); err != nil {
+			return nil, ErrInvalidConfig{Name: name, Err: err}
+		}
+		return init(config)
+	}, cleanup)
+}
+
 // Drivers returns a list of registered drivers.
 func Drivers() (l []string) {
 	if providers == nil {
 		return l
 	}
 
-	for k := range providers {
-		l = append(l, k)
+	for k, p := range providers {
+		if p.init != nil {
+			l = append(l, k)
+		}
 	}
 
 	return l
@@ -123,12 +209,26 @@ func For(name string, config dict.Dicter) (Tiler, error) {
 	}
 
 	p, ok := providers[name]
-	if !ok {
+	if !ok || p.init == nil {
 		err.Name = name
 		return nil, err
 	}
 
-	return p.init(config)
+	config, migrateErr := migrateConfig(name, config)
+	if migrateErr != nil {
+		return nil, migrateErr
+	}
+
+	config, hookErr := runConfigHooks(name, config)
+	if hookErr != nil {
+		return nil, hookErr
+	}
+
+	tiler, initErr := p.init(config)
+	if initErr != nil {
+		return nil, initErr
+	}
+	return withConfigQueryTimeouts(tiler, name, config)
 }
 
 func Cleanup() {
@@ -1,8 +1,12 @@
 package provider_test
 
 import (
+	"context"
+	"errors"
+	"math"
 	"testing"
 
+	"github.com/go-spatial/tegola/dict"
 	"github.com/go-spatial/tegola/provider"
 	"github.com/go-spatial/tegola/provider/test"
 )
@@ -20,3 +24,161 @@ func TestProviderInterface(t *testing.T) {
 		t.Errorf(" expected count , expected 0 got %v", test.Count)
 	}
 }
+
+// featuresTiler is a Tiler which streams a copy of the given features for every tile
+type featuresTiler struct {
+	layers   []provider.LayerInfo
+	features []provider.Feature
+	err      error
+}
+
+func (ft featuresTiler) Layers() ([]provider.LayerInfo, error) { return ft.layers, nil }
+
+func (ft featuresTiler) TileFeatures(ctx context.Context, layer string, t provider.Tile, fn func(f *provider.Feature) error) error {
+	for i := range ft.features {
+		f := ft.features[i]
+		if err := fn(&f); err != nil {
+			return err
+		}
+	}
+	return ft.err
+}
+
+// collect returns the features streamed by the Tiler for the given tile
+func collect(t provider.Tiler, layer string, tile provider.Tile) (features []provider.Feature, err error) {
+	err = t.TileFeatures(context.Background(), layer, tile, func(f *provider.Feature) error {
+		features = append(features, *f)
+		return nil
+	})
+	return features, err
+}
+
+func TestRegisterTyped(t *testing.T) {
+	type config struct {
+		Layer string `dict:"layer,required"`
+	}
+
+	var got config
+	err := provider.RegisterTyped("typed_test", func(c config) (provider.Tiler, error) {
+		got = c
+		return featuresTiler{}, nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("register, expected nil got %v", err)
+	}
+
+	if _, err = provider.For("typed_test", dict.Dict{"layer": "roads"}); err != nil {
+		t.Errorf("for, expected nil got %v", err)
+	}
+	if got.Layer != "roads" {
+		t.Errorf("config layer, expected roads got %v", got.Layer)
+	}
+
+	_, err = provider.For("typed_test", dict.Dict{})
+	if !errors.Is(err, dict.ErrKeyRequired("layer")) {
+		t.Errorf("for missing key, expected %v got %v", dict.ErrKeyRequired("layer"), err)
+	}
+}
+
+func TestIsRegistered(t *testing.T) {
+	var initialized bool
+	stdInit := func(dict.Dicter) (provider.Tiler, error) {
+		initialized = true
+		return featuresTiler{}, nil
+	}
+	mvtInit := func(dict.Dicter) (provider.MVTTiler, error) {
+		initialized = true
+		return nil, nil
+	}
+
+	if err := provider.Register("registered_std", stdInit, nil); err != nil {
+		t.Fatalf("register, expected nil got %v", err)
+	}
+	if err := provider.MVTRegister("registered_mvt", mvtInit, nil); err != nil {
+		t.Fatalf("mvt register, expected nil got %v", err)
+	}
+	if err := provider.Register("registered_all", stdInit, nil); err != nil {
+		t.Fatalf("register, expected nil got %v", err)
+	}
+	if err := provider.MVTRegister("registered_all", mvtInit, nil); err != nil {
+		t.Fatalf("mvt register, expected nil got %v", err)
+	}
+
+	type tcase struct {
+		name       string
+		registered bool
+		pt         string
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		registered, pt := provider.IsRegistered(tc.name)
+		if registered != tc.registered {
+			t.Errorf("registered, expected %v got %v", tc.registered, registered)
+		}
+		if pt.String() != tc.pt {
+			t.Errorf("type, expected %v got %v", tc.pt, pt)
+		}
+	}
+
+	tests := map[string]tcase{
+		"std": {
+			name:       "registered_std",
+			registered: true,
+			pt:         provider.TypeStd.String(),
+		},
+		"mvt": {
+			name:       "registered_mvt",
+			registered: true,
+			pt:         provider.TypeMvt.String(),
+		},
+		"all": {
+			name:       "registered_all",
+			registered: true,
+			pt:         provider.TypeAll.String(),
+		},
+		"unknown": {
+			name: "registered_unknown",
+			pt:   "none",
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+
+	if initialized {
+		t.Errorf("initialized, expected false got true")
+	}
+}
+
+func TestTileResolution(t *testing.T) {
+	type tcase struct {
+		tile     provider.Tile
+		expected float64
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		got := tc.tile.Resolution()
+		if math.Abs(got-tc.expected) > 0.01 {
+			t.Errorf("resolution, expected %v got %v", tc.expected, got)
+		}
+	}
+
+	tests := map[string]tcase{
+		"z0": {
+			tile:     provider.NewTile(0, 0, 0, 64, 3857),
+			expected: 156543.03,
+		},
+		// the center of tile 1/0/0 is at latitude ~66.51
+		"z1": {
+			tile:     provider.NewTile(1, 0, 0, 64, 3857),
+			expected: 78271.52 * math.Cos(66.51326044311186*math.Pi/180),
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// QueryHint describes where a TileFeatures or MVTForLayers request came from.
+// Providers backed by a database can embed it in their queries, i.e. as an
+// SQL comment, to correlate queries with the map, layer and tile requested.
+type QueryHint struct {
+	Map   string
+	Layer string
+	Z     uint
+	X     uint
+	Y     uint
+}
+
+// SQLComment returns the hint as an SQL block comment. Asterisks are removed
+// from the map and layer names so they can not terminate the comment.
+func (h QueryHint) SQLComment() string {
+	return fmt.Sprintf("/* tegola map=%v layer=%v tile=%v/%v/%v */",
+		sqlCommentSafe(h.Map), sqlCommentSafe(h.Layer), h.Z, h.X, h.Y)
+}
+
+func sqlCommentSafe(s string) string {
+	return strings.ReplaceAll(s, "*", "")
+}
+
+type queryHintKey struct{}
+
+// WithQueryHint returns a copy of ctx carrying the hint. Providers which
+// support hints read it with QueryHintFromContext, others ignore it.
+func WithQueryHint(ctx context.Context, hint QueryHint) context.Context {
+	return context.WithValue(ctx, queryHintKey{}, hint)
+}
+
+// QueryHintFromContext returns the hint set by WithQueryHint, if any
+func QueryHintFromContext(ctx context.Context) (QueryHint, bool) {
+	hint, ok := ctx.Value(queryHintKey{}).(QueryHint)
+	return hint, ok
+}
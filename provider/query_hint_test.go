@@ -0,0 +1,26 @@
+package provider_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestQueryHint(t *testing.T) {
+	ctx := context.Background()
+	if _, ok := provider.QueryHintFromContext(ctx); ok {
+		t.Errorf("hint, expected none got one")
+	}
+
+	hint := provider.QueryHint{Map: "osm", Layer: "roads*/; DROP TABLE roads; /*", Z: 3, X: 2, Y: 1}
+	got, ok := provider.QueryHintFromContext(provider.WithQueryHint(ctx, hint))
+	if !ok || got != hint {
+		t.Fatalf("hint, expected %v got %v", hint, got)
+	}
+
+	expected := "/* tegola map=osm layer=roads/; DROP TABLE roads; / tile=3/2/1 */"
+	if comment := got.SQLComment(); comment != expected {
+		t.Errorf("sql comment, expected %v got %v", expected, comment)
+	}
+}
@@ -0,0 +1,156 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-spatial/tegola/dict"
+)
+
+// ConfigKeyQueryTimeout is the provider and provider layer config key of
+// the query timeout, a duration such as "500ms" or "5s". The provider's
+// query_timeout is the default for layers without their own.
+const ConfigKeyQueryTimeout = "query_timeout"
+
+// WithQueryTimeouts wraps the Tiler so each TileFeatures call is given a
+// context deadline: the layer's timeout in layers, or def for layers without
+// one. A timeout <= 0 disables the deadline for the layer. When the deadline
+// is exceeded an ErrQueryTimeout is returned, which wraps
+// context.DeadlineExceeded. If no timeouts are set t is returned.
+//
+// The timeouts also apply to the optional interfaces streaming a layer's
+// features, LocalTiler, Aggregator and SpatialJoiner. The wrapped provider's
+// other optional interfaces are found through the wrapper with As.
+func WithQueryTimeouts(t Tiler, def time.Duration, layers map[string]time.Duration) Tiler {
+	if def <= 0 {
+		var set bool
+		for _, d := range layers {
+			set = set || d > 0
+		}
+		if !set {
+			return t
+		}
+	}
+	return &queryTimeoutTiler{
+		Tiler:  t,
+		def:    def,
+		layers: layers,
+	}
+}
+
+type queryTimeoutTiler struct {
+	Tiler
+	def    time.Duration
+	layers map[string]time.Duration
+}
+
+func (qtt *queryTimeoutTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	return qtt.query(ctx, layer, func(ctx context.Context) error {
+		return qtt.Tiler.TileFeatures(ctx, layer, t, fn)
+	})
+}
+
+// TileFeaturesLocal adheres to the LocalTiler interface, applying the
+// layer's timeout. ErrUnsupported is returned if the wrapped provider is not
+// a LocalTiler.
+func (qtt *queryTimeoutTiler) TileFeaturesLocal(ctx context.Context, layer string, t Tile, extent int, fn func(f *Feature) error) error {
+	return qtt.query(ctx, layer, func(ctx context.Context) error {
+		return TileFeaturesLocal(ctx, qtt.Tiler, layer, t, extent, fn)
+	})
+}
+
+// AggregateTile adheres to the Aggregator interface, applying the layer's
+// timeout. ErrUnsupported is returned if the wrapped provider is not an
+// Aggregator.
+func (qtt *queryTimeoutTiler) AggregateTile(ctx context.Context, layer string, t Tile, spec AggSpec, fn func(f *Feature) error) error {
+	return qtt.query(ctx, layer, func(ctx context.Context) error {
+		return AggregateTile(ctx, qtt.Tiler, layer, t, spec, fn)
+	})
+}
+
+// TileFeaturesJoin adheres to the SpatialJoiner interface, applying the
+// layer's timeout. ErrUnsupported is returned if the wrapped provider is not
+// a SpatialJoiner.
+func (qtt *queryTimeoutTiler) TileFeaturesJoin(ctx context.Context, layer string, t Tile, join JoinSpec, fn func(f *Feature) error) error {
+	return qtt.query(ctx, layer, func(ctx context.Context) error {
+		return TileFeaturesJoin(ctx, qtt.Tiler, layer, t, join, fn)
+	})
+}
+
+// Unwrap adheres to the Unwrapper interface, so the wrapped provider's other
+// optional interfaces, which do not stream features, can be found with As.
+// Those calls are not given the layer's timeout.
+func (qtt *queryTimeoutTiler) Unwrap() Tiler {
+	return qtt.Tiler
+}
+
+// query calls fn with a context with the layer's deadline, if it has one
+func (qtt *queryTimeoutTiler) query(ctx context.Context, layer string, fn func(ctx context.Context) error) error {
+	timeout, ok := qtt.layers[layer]
+	if !ok {
+		timeout = qtt.def
+	}
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+
+	qctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := fn(qctx)
+	// only the layer's deadline is reported as a timeout, not the caller's
+	if err != nil && errors.Is(qctx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		return ErrQueryTimeout{Layer: layer, Timeout: timeout}
+	}
+	return err
+}
+
+// withConfigQueryTimeouts wraps the Tiler with the query timeouts of the
+// provider's config, see ConfigKeyQueryTimeout
+func withConfigQueryTimeouts(t Tiler, name string, config dict.Dicter) (Tiler, error) {
+	if config == nil {
+		return t, nil
+	}
+
+	def, err := configQueryTimeout(config)
+	if err != nil {
+		return nil, ErrInvalidConfig{Name: name, Err: err}
+	}
+
+	layers, err := config.MapSlice("layers")
+	if err != nil {
+		// the provider validates its own layers
+		return WithQueryTimeouts(t, def, nil), nil
+	}
+
+	timeouts := make(map[string]time.Duration)
+	for _, l := range layers {
+		lname, err := l.String("name", nil)
+		if err != nil {
+			continue
+		}
+		if _, ok := l.Interface(ConfigKeyQueryTimeout); !ok {
+			continue
+		}
+		if timeouts[lname], err = configQueryTimeout(l); err != nil {
+			return nil, ErrInvalidConfig{Name: name, Err: fmt.Errorf("layer (%v): %w", lname, err)}
+		}
+	}
+	return WithQueryTimeouts(t, def, timeouts), nil
+}
+
+// configQueryTimeout returns the config's query_timeout, 0 if it is not set
+func configQueryTimeout(config dict.Dicter) (time.Duration, error) {
+	var s string
+	s, err := config.String(ConfigKeyQueryTimeout, &s)
+	if err != nil || s == "" {
+		return 0, err
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("%v: %w", ConfigKeyQueryTimeout, err)
+	}
+	return d, nil
+}
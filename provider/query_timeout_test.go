@@ -0,0 +1,163 @@
+package provider_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-spatial/tegola/dict"
+	"github.com/go-spatial/tegola/provider"
+)
+
+// delayTiler is a Tiler whose layers take the given time to query, or until
+// the context is done
+type delayTiler struct {
+	featuresTiler
+	delays map[string]time.Duration
+}
+
+func (dt delayTiler) TileFeatures(ctx context.Context, layer string, t provider.Tile, fn func(f *provider.Feature) error) error {
+	select {
+	case <-time.After(dt.delays[layer]):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestQueryTimeout(t *testing.T) {
+	err := provider.Register("query_timeout_test", func(d dict.Dicter) (provider.Tiler, error) {
+		return delayTiler{
+			delays: map[string]time.Duration{
+				"labels":   200 * time.Millisecond,
+				"polygons": 20 * time.Millisecond,
+				"roads":    200 * time.Millisecond,
+				"water":    20 * time.Millisecond,
+			},
+		}, nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("register, expected nil got %v", err)
+	}
+
+	tiler, err := provider.For("query_timeout_test", dict.Dict{
+		"query_timeout": "50ms",
+		"layers": []map[string]interface{}{
+			{"name": "labels", "query_timeout": "10ms"},
+			{"name": "polygons", "query_timeout": "5s"},
+			{"name": "roads"},
+			{"name": "water"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("for, expected nil got %v", err)
+	}
+
+	type tcase struct {
+		layer string
+		err   error
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		err := tiler.TileFeatures(context.Background(), tc.layer, provider.NewTile(0, 0, 0, 0, 3857), func(*provider.Feature) error { return nil })
+		if tc.err == nil {
+			if err != nil {
+				t.Errorf("error, expected nil got %v", err)
+			}
+			return
+		}
+		if !errors.Is(err, tc.err) {
+			t.Errorf("error, expected %v got %v", tc.err, err)
+		}
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("error, expected to wrap %v got %v", context.DeadlineExceeded, err)
+		}
+	}
+
+	tests := map[string]tcase{
+		"layer timeout exceeded": {
+			layer: "labels",
+			err:   provider.ErrQueryTimeout{Layer: "labels", Timeout: 10 * time.Millisecond},
+		},
+		"layer timeout": {
+			layer: "polygons",
+		},
+		"default timeout exceeded": {
+			layer: "roads",
+			err:   provider.ErrQueryTimeout{Layer: "roads", Timeout: 50 * time.Millisecond},
+		},
+		"default timeout": {
+			layer: "water",
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := provider.For("query_timeout_test", dict.Dict{
+			"layers": []map[string]interface{}{
+				{"name": "labels", "query_timeout": "soon"},
+			},
+		})
+		var cerr provider.ErrInvalidConfig
+		if !errors.As(err, &cerr) {
+			t.Errorf("error, expected %T got %v", cerr, err)
+		}
+	})
+}
+
+// localDelayTiler is a delayTiler which is also a LocalTiler, and a
+// TileETagger tagging tiles with the layer name
+type localDelayTiler struct {
+	delayTiler
+}
+
+func (ldt localDelayTiler) TileFeaturesLocal(ctx context.Context, layer string, t provider.Tile, extent int, fn func(f *provider.Feature) error) error {
+	return ldt.delayTiler.TileFeatures(ctx, layer, t, fn)
+}
+
+func (ldt localDelayTiler) TileETag(ctx context.Context, layer string, t provider.Tile) (string, error) {
+	return layer, nil
+}
+
+func TestQueryTimeoutOptionalInterfaces(t *testing.T) {
+	err := provider.Register("query_timeout_local_test", func(d dict.Dicter) (provider.Tiler, error) {
+		return localDelayTiler{delayTiler{
+			delays: map[string]time.Duration{"roads": 200 * time.Millisecond},
+		}}, nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("register, expected nil got %v", err)
+	}
+
+	tiler, err := provider.For("query_timeout_local_test", dict.Dict{"query_timeout": "10ms"})
+	if err != nil {
+		t.Fatalf("for, expected nil got %v", err)
+	}
+	if _, ok := provider.As[provider.LocalTiler](tiler); !ok {
+		t.Errorf("as, expected the provider to be a LocalTiler")
+	}
+	if _, ok := provider.As[provider.TileETagger](tiler); !ok {
+		t.Errorf("as, expected the provider to be a TileETagger")
+	}
+
+	tile := provider.NewTile(0, 0, 0, 0, 3857)
+	tag, err := provider.TileETag(context.Background(), tiler, "roads", tile)
+	if err != nil {
+		t.Fatalf("etag, expected nil got %v", err)
+	}
+	if tag != "roads" {
+		t.Errorf("etag, expected roads got %v", tag)
+	}
+
+	// local features are given the layer's timeout
+	err = provider.TileFeaturesLocal(context.Background(), tiler, "roads", tile, 4096, func(*provider.Feature) error { return nil })
+	expected := provider.ErrQueryTimeout{Layer: "roads", Timeout: 10 * time.Millisecond}
+	if !errors.Is(err, expected) {
+		t.Errorf("local error, expected %v got %v", expected, err)
+	}
+}
@@ -0,0 +1,79 @@
+package provider
+
+// ProviderType is the kind of provider registered under a name, exported so
+// functions observing registrations can be declared outside the package
+type ProviderType = providerType
+
+// RegisterObserverFunc is given the name and type of a provider as it is
+// registered or deregistered. The type is TypeStd for Register and
+// Deregister, and TypeMvt for MVTRegister and MVTDeregister.
+type RegisterObserverFunc func(name string, pt ProviderType)
+
+var (
+	registerObservers   []RegisterObserverFunc
+	deregisterObservers []RegisterObserverFunc
+)
+
+// OnRegister adds an observer which is called whenever a provider is
+// registered with Register or MVTRegister, i.e. to build a catalog of the
+// available drivers. Observers are called synchronously, in the order they
+// were added, after the provider has been registered. Observers are only
+// notified and can not prevent the registration.
+//
+// Providers generally register in their init functions, so observers added
+// later are not called for those providers; use Drivers and MVTDrivers to
+// list the providers already registered.
+func OnRegister(fn RegisterObserverFunc) {
+	registerObservers = append(registerObservers, fn)
+}
+
+// OnDeregister adds an observer which is called whenever a provider is
+// removed with Deregister or MVTDeregister. As with OnRegister, observers
+// are called synchronously in the order they were added.
+func OnDeregister(fn RegisterObserverFunc) {
+	deregisterObservers = append(deregisterObservers, fn)
+}
+
+// Deregister removes the standard provider registered under the name, so
+// it is no longer available to For. The provider's cleanup function is not
+// called. It is a no-op if no standard provider is registered under the name.
+func Deregister(name string) {
+	p, ok := providers[name]
+	if !ok || p.init == nil {
+		return
+	}
+
+	p.init, p.cleanup = nil, nil
+	setProvider(name, p)
+	notifyObservers(deregisterObservers, name, TypeStd)
+}
+
+// MVTDeregister removes the MVT provider registered under the name, so it is
+// no longer available to MVTFor. The provider's cleanup function is not
+// called. It is a no-op if no MVT provider is registered under the name.
+func MVTDeregister(name string) {
+	p, ok := providers[name]
+	if !ok || p.mvtInit == nil {
+		return
+	}
+
+	p.mvtInit, p.mvtCleanup = nil, nil
+	setProvider(name, p)
+	notifyObservers(deregisterObservers, name, TypeMvt)
+}
+
+// setProvider stores the provider's functions, removing the name once
+// neither a standard nor a MVT provider is registered under it
+func setProvider(name string, p pfns) {
+	if p.providerType() == 0 {
+		delete(providers, name)
+		return
+	}
+	providers[name] = p
+}
+
+func notifyObservers(observers []RegisterObserverFunc, name string, pt providerType) {
+	for _, fn := range observers {
+		fn(name, pt)
+	}
+}
@@ -0,0 +1,63 @@
+package provider_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-spatial/tegola/dict"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestRegisterObservers(t *testing.T) {
+	var events []string
+	observer := func(prefix string) provider.RegisterObserverFunc {
+		return func(name string, pt provider.ProviderType) {
+			// ignore providers registered by other tests
+			if strings.HasPrefix(name, "observer_test") {
+				events = append(events, fmt.Sprintf("%v %v %v", prefix, name, pt))
+			}
+		}
+	}
+	provider.OnRegister(observer("register a"))
+	provider.OnRegister(observer("register b"))
+	provider.OnDeregister(observer("deregister"))
+
+	initStd := func(dict.Dicter) (provider.Tiler, error) { return featuresTiler{}, nil }
+	initMVT := func(dict.Dicter) (provider.MVTTiler, error) { return bytesMVTTiler{}, nil }
+
+	if err := provider.Register("observer_test", initStd, nil); err != nil {
+		t.Fatalf("register, expected nil got %v", err)
+	}
+	if err := provider.MVTRegister("observer_test", initMVT, nil); err != nil {
+		t.Fatalf("mvt register, expected nil got %v", err)
+	}
+	// failed registrations are not observed
+	if err := provider.Register("observer_test", initStd, nil); err == nil {
+		t.Fatalf("duplicate register, expected error got nil")
+	}
+
+	provider.Deregister("observer_test")
+	if _, pt := provider.IsRegistered("observer_test"); pt != provider.TypeMvt {
+		t.Errorf("registered type, expected %v got %v", provider.TypeMvt, pt)
+	}
+	provider.MVTDeregister("observer_test")
+	if registered, _ := provider.IsRegistered("observer_test"); registered {
+		t.Errorf("registered, expected false got true")
+	}
+	// deregistering an unknown provider is not observed
+	provider.Deregister("observer_test")
+
+	expected := []string{
+		"register a observer_test std",
+		"register b observer_test std",
+		"register a observer_test mvt",
+		"register b observer_test mvt",
+		"deregister observer_test std",
+		"deregister observer_test mvt",
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("events, expected %v got %v", expected, events)
+	}
+}
@@ -0,0 +1,156 @@
+package provider
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-spatial/tegola/dict"
+	"github.com/go-spatial/tegola/internal/log"
+)
+
+var (
+	instances     = make(map[string]*liveTiler)
+	instancesLock sync.Mutex
+)
+
+// ForNamed returns a configured provider of the given type, like For, which
+// is tracked under name so it can be replaced by Reload. The returned Tiler
+// delegates each call to the current instance of the provider. If a provider
+// is already tracked under name, it is no longer tracked and can not be
+// reloaded.
+func ForNamed(name, typ string, config dict.Dicter) (Tiler, error) {
+	tiler, err := For(typ, config)
+	if err != nil {
+		return nil, err
+	}
+	return trackNamed(name, typ, tiler), nil
+}
+
+// trackNamed tracks the initialized provider under name, see ForNamed
+func trackNamed(name, typ string, tiler Tiler) Tiler {
+	lt := &liveTiler{
+		typ: typ,
+		cur: &liveInstance{Tiler: tiler},
+	}
+
+	instancesLock.Lock()
+	instances[name] = lt
+	instancesLock.Unlock()
+
+	return lt
+}
+
+// Named returns the provider tracked under name by ForNamed, InitAll or
+// Warmup, and if one is tracked
+func Named(name string) (Tiler, bool) {
+	instancesLock.Lock()
+	defer instancesLock.Unlock()
+
+	lt, ok := instances[name]
+	if !ok {
+		return nil, false
+	}
+	return lt, true
+}
+
+// Reload initializes a new instance of the named provider, created with
+// ForNamed, and swaps it in for the current instance. If newConfig has a
+// "type" it is used as the provider type, otherwise the type is unchanged.
+// If the new instance fails to initialize the current instance is kept.
+//
+// Calls made after Reload returns use the new instance. Calls already in
+// flight complete using the old instance; each call uses a single instance
+// from start to finish. Once they have drained the old instance is closed,
+// if it has a Close method, in the background. Calls made through the
+// helpers of the optional interfaces, i.e. TileETag, are counted in flight
+// too.
+func Reload(name string, newConfig dict.Dicter) error {
+	instancesLock.Lock()
+	lt, ok := instances[name]
+	instancesLock.Unlock()
+	if !ok {
+		return ErrUnknownProvider{Name: name}
+	}
+
+	typ, err := newConfig.String("type", &lt.typ)
+	if err != nil {
+		return err
+	}
+
+	tiler, err := For(typ, newConfig)
+	if err != nil {
+		return err
+	}
+
+	old := lt.swap(typ, &liveInstance{Tiler: tiler})
+	log.Infof("provider (%v) reloaded", name)
+
+	go func() {
+		old.inflight.Wait()
+		old.close()
+		log.Infof("provider (%v) old instance closed", name)
+	}()
+	return nil
+}
+
+// liveInstance is an instance of a provider and the calls in flight on it
+type liveInstance struct {
+	Tiler
+	inflight sync.WaitGroup
+}
+
+func (li *liveInstance) close() {
+	if c, ok := As[interface{ Close() error }](li.Tiler); ok {
+		if err := c.Close(); err != nil {
+			log.Errorf("closing provider: %v", err)
+		}
+		return
+	}
+	if c, ok := As[interface{ Close() }](li.Tiler); ok {
+		c.Close()
+	}
+}
+
+// liveTiler delegates to the current instance of a provider
+type liveTiler struct {
+	// lock guards typ and cur. Calls are counted in flight while holding the
+	// read lock, so once swap has the write lock no more calls can start on
+	// the old instance and waiting on it is safe.
+	lock sync.RWMutex
+	typ  string
+	cur  *liveInstance
+}
+
+func (lt *liveTiler) acquire() *liveInstance {
+	lt.lock.RLock()
+	defer lt.lock.RUnlock()
+	li := lt.cur
+	li.inflight.Add(1)
+	return li
+}
+
+func (lt *liveTiler) swap(typ string, li *liveInstance) (old *liveInstance) {
+	lt.lock.Lock()
+	defer lt.lock.Unlock()
+	old, lt.cur, lt.typ = lt.cur, li, typ
+	return old
+}
+
+func (lt *liveTiler) Layers() ([]LayerInfo, error) {
+	li := lt.acquire()
+	defer li.inflight.Done()
+	return li.Layers()
+}
+
+func (lt *liveTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	li := lt.acquire()
+	defer li.inflight.Done()
+	return li.TileFeatures(ctx, layer, t, fn)
+}
+
+// hold adheres to the holder interface, counting the use of the current
+// instance in flight until release is called
+func (lt *liveTiler) hold() (Tiler, func()) {
+	li := lt.acquire()
+	return li.Tiler, li.inflight.Done
+}
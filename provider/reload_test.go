@@ -0,0 +1,265 @@
+package provider_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-spatial/tegola/dict"
+	"github.com/go-spatial/tegola/provider"
+)
+
+// versionTiler reports its version as the feature ID and ETag, and blocks
+// TileFeatures until release is closed. If etagRelease is set TileETag
+// signals etagStarted and blocks until etagRelease is closed.
+type versionTiler struct {
+	featuresTiler
+	version uint64
+	release chan struct{}
+	closed  chan struct{}
+
+	etagStarted chan struct{}
+	etagRelease chan struct{}
+}
+
+func (vt *versionTiler) TileFeatures(ctx context.Context, layer string, t provider.Tile, fn func(f *provider.Feature) error) error {
+	<-vt.release
+	return fn(&provider.Feature{ID: vt.version})
+}
+
+func (vt *versionTiler) TileETag(ctx context.Context, layer string, t provider.Tile) (string, error) {
+	if vt.etagRelease != nil {
+		vt.etagStarted <- struct{}{}
+		<-vt.etagRelease
+	}
+	return strconv.FormatUint(vt.version, 10), nil
+}
+
+func (vt *versionTiler) Close() { close(vt.closed) }
+
+func TestReload(t *testing.T) {
+	var (
+		lock    sync.Mutex
+		created []*versionTiler
+	)
+	err := provider.Register("reload_test", func(d dict.Dicter) (provider.Tiler, error) {
+		version, err := d.Uint("version", nil)
+		if err != nil {
+			return nil, err
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		vt := &versionTiler{version: uint64(version), release: make(chan struct{}), closed: make(chan struct{})}
+		created = append(created, vt)
+		return vt, nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("register, expected nil got %v", err)
+	}
+
+	tiler, err := provider.ForNamed("reloadable", "reload_test", dict.Dict{"version": uint(1)})
+	if err != nil {
+		t.Fatalf("for named, expected nil got %v", err)
+	}
+
+	tile := provider.NewTile(0, 0, 0, 0, 3857)
+	version := make(chan uint64)
+	tileVersion := func() {
+		tiler.TileFeatures(context.Background(), "", tile, func(f *provider.Feature) error {
+			version <- f.ID
+			return nil
+		})
+	}
+
+	// a request in flight on the first instance
+	go tileVersion()
+	// wait for the request to be in flight
+	time.Sleep(10 * time.Millisecond)
+
+	if err = provider.Reload("reloadable", dict.Dict{"version": uint(2)}); err != nil {
+		t.Fatalf("reload, expected nil got %v", err)
+	}
+	// a failed reload keeps the current instance
+	if err = provider.Reload("reloadable", dict.Dict{"version": "three"}); err == nil {
+		t.Errorf("invalid reload, expected error got nil")
+	}
+	if err = provider.Reload("unknown", dict.Dict{}); err == nil {
+		t.Errorf("unknown reload, expected error got nil")
+	}
+
+	first, second := created[0], created[1]
+	close(second.release)
+
+	// new requests use the new instance
+	go tileVersion()
+	if got := <-version; got != 2 {
+		t.Errorf("version after reload, expected 2 got %v", got)
+	}
+
+	select {
+	case <-first.closed:
+		t.Fatalf("closed, expected the old instance to stay open while a request is in flight")
+	default:
+	}
+
+	// the in flight request completes on the old instance
+	close(first.release)
+	if got := <-version; got != 1 {
+		t.Errorf("in flight version, expected 1 got %v", got)
+	}
+
+	select {
+	case <-first.closed:
+	case <-time.After(time.Second):
+		t.Errorf("closed, expected the old instance to be closed once drained")
+	}
+}
+
+func TestReloadOptionalInterfaces(t *testing.T) {
+	err := provider.Register("reload_etag_test", func(d dict.Dicter) (provider.Tiler, error) {
+		version, err := d.Uint("version", nil)
+		if err != nil {
+			return nil, err
+		}
+		return &versionTiler{version: uint64(version), closed: make(chan struct{})}, nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("register, expected nil got %v", err)
+	}
+
+	tiler, err := provider.ForNamed("reloadable_etag", "reload_etag_test", dict.Dict{"version": uint(1)})
+	if err != nil {
+		t.Fatalf("for named, expected nil got %v", err)
+	}
+	// the instance may be closed by Reload, so it is only reached through
+	// the helpers
+	if _, ok := provider.As[provider.TileETagger](tiler); ok {
+		t.Errorf("as, expected the named provider not to be unwrapped")
+	}
+
+	tile := provider.NewTile(0, 0, 0, 0, 3857)
+	err = provider.TileFeaturesLocal(context.Background(), tiler, "", tile, 4096, func(f *provider.Feature) error { return nil })
+	if !errors.Is(err, provider.ErrUnsupported) {
+		t.Errorf("local, expected %v got %v", provider.ErrUnsupported, err)
+	}
+
+	for _, version := range []string{"1", "2"} {
+		if version == "2" {
+			if err = provider.Reload("reloadable_etag", dict.Dict{"version": uint(2)}); err != nil {
+				t.Fatalf("reload, expected nil got %v", err)
+			}
+		}
+		tag, err := provider.TileETag(context.Background(), tiler, "", tile)
+		if err != nil {
+			t.Fatalf("etag, expected nil got %v", err)
+		}
+		if tag != version {
+			t.Errorf("etag, expected %v got %v", version, tag)
+		}
+	}
+}
+
+func TestReloadOptionalInterfaceInFlight(t *testing.T) {
+	var created []*versionTiler
+	err := provider.Register("reload_etag_inflight_test", func(d dict.Dicter) (provider.Tiler, error) {
+		version, err := d.Uint("version", nil)
+		if err != nil {
+			return nil, err
+		}
+		vt := &versionTiler{
+			version:     uint64(version),
+			closed:      make(chan struct{}),
+			etagStarted: make(chan struct{}),
+			etagRelease: make(chan struct{}),
+		}
+		created = append(created, vt)
+		return vt, nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("register, expected nil got %v", err)
+	}
+
+	tiler, err := provider.ForNamed("reloadable_etag_inflight", "reload_etag_inflight_test", dict.Dict{"version": uint(1)})
+	if err != nil {
+		t.Fatalf("for named, expected nil got %v", err)
+	}
+
+	tag := make(chan string)
+	go func() {
+		got, _ := provider.TileETag(context.Background(), tiler, "", provider.NewTile(0, 0, 0, 0, 3857))
+		tag <- got
+	}()
+	first := created[0]
+	<-first.etagStarted
+
+	if err = provider.Reload("reloadable_etag_inflight", dict.Dict{"version": uint(2)}); err != nil {
+		t.Fatalf("reload, expected nil got %v", err)
+	}
+
+	select {
+	case <-first.closed:
+		t.Fatalf("closed, expected the old instance to stay open while an etag is in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(first.etagRelease)
+	if got := <-tag; got != "1" {
+		t.Errorf("in flight etag, expected 1 got %v", got)
+	}
+
+	select {
+	case <-first.closed:
+	case <-time.After(time.Second):
+		t.Errorf("closed, expected the old instance to be closed once drained")
+	}
+}
+
+func TestReloadLazy(t *testing.T) {
+	var created []*versionTiler
+	err := provider.Register("reload_lazy_test", func(d dict.Dicter) (provider.Tiler, error) {
+		version, err := d.Uint("version", nil)
+		if err != nil {
+			return nil, err
+		}
+		vt := &versionTiler{version: uint64(version), closed: make(chan struct{})}
+		created = append(created, vt)
+		return vt, nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("register, expected nil got %v", err)
+	}
+
+	tiler, err := provider.ForNamedLazy("reloadable_lazy", "reload_lazy_test", dict.Dict{"version": uint(1)})
+	if err != nil {
+		t.Fatalf("for named lazy, expected nil got %v", err)
+	}
+	if !provider.LazyPending(tiler) {
+		t.Errorf("pending before use, expected true got false")
+	}
+	if named, ok := provider.Named("reloadable_lazy"); !ok || named != tiler {
+		t.Fatalf("named, expected the lazy provider to be tracked")
+	}
+
+	if err = provider.Reload("reloadable_lazy", dict.Dict{"version": uint(2)}); err != nil {
+		t.Fatalf("reload, expected nil got %v", err)
+	}
+	// the new instance is initialized by Reload, the unused old one is not
+	// initialized to be closed
+	if len(created) != 1 || created[0].version != 2 {
+		t.Fatalf("created, expected only version 2 got %v instances", len(created))
+	}
+	if provider.LazyPending(tiler) {
+		t.Errorf("pending after reload, expected false got true")
+	}
+
+	tag, err := provider.TileETag(context.Background(), tiler, "", provider.NewTile(0, 0, 0, 0, 3857))
+	if err != nil {
+		t.Fatalf("etag, expected nil got %v", err)
+	}
+	if tag != "2" {
+		t.Errorf("etag, expected 2 got %v", tag)
+	}
+}
@@ -0,0 +1,36 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/basic"
+)
+
+// reprojectFeature returns the feature's geometry in the srid. Geometries
+// without an SRID, or already in the srid, are returned as is.
+func reprojectFeature(f *Feature, srid uint64) (geom.Geometry, error) {
+	if f.SRID == 0 || f.SRID == srid {
+		return f.Geometry, nil
+	}
+	// TODO(arolek): support for additional projections
+	g, err := basic.ToWebMercator(f.SRID, f.Geometry)
+	if err != nil {
+		return nil, fmt.Errorf("unable to transform geometry to webmercator from SRID (%v) for feature %v due to error: %w", f.SRID, f.ID, err)
+	}
+	return g, nil
+}
+
+// reprojectFeatureInPlace reprojects the feature's geometry to the srid, as
+// reprojectFeature, and sets the feature's SRID
+func reprojectFeatureInPlace(f *Feature, srid uint64) error {
+	if f.SRID == 0 || f.SRID == srid {
+		return nil
+	}
+	g, err := reprojectFeature(f, srid)
+	if err != nil {
+		return err
+	}
+	f.Geometry, f.SRID = g, srid
+	return nil
+}
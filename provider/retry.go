@@ -0,0 +1,98 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-spatial/tegola/internal/log"
+)
+
+// RetryPolicy determines how often, and how long apart, WithTileRetry
+// attempts a TileFeatures call
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Values less than 1 are treated as 1.
+	MaxAttempts int
+	// Backoff is the wait before the first retry, doubling for each
+	// subsequent retry up to MaxBackoff
+	Backoff time.Duration
+	// MaxBackoff caps the wait between retries, 0 means no cap
+	MaxBackoff time.Duration
+}
+
+// WithTileRetry wraps the Tiler so a failed TileFeatures call is attempted
+// again, according to policy, when retryable reports the error as
+// transient, i.e. a deadlock or a reset connection. A nil retryable retries
+// every error. Context cancellations and deadlines are never retried, and
+// ErrNoFeatures is not considered a failure.
+//
+// As TileFeatures streams features, a failed attempt may already have
+// produced some of the tile's features. To keep the callback from seeing
+// those features twice, every attempt is buffered in memory and the features
+// are only passed to the callback once an attempt succeeds. The callback is
+// therefore not called until the whole tile has been read, and the wrapped
+// Tiler holds all of a tile's features at once. The buffered features are
+// shallow copies of the features passed by the provider.
+func WithTileRetry(t Tiler, policy RetryPolicy, retryable func(error) bool) Tiler {
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+	if retryable == nil {
+		retryable = func(error) bool { return true }
+	}
+	return &retryTiler{
+		Tiler:     t,
+		policy:    policy,
+		retryable: retryable,
+	}
+}
+
+type retryTiler struct {
+	Tiler
+	policy    RetryPolicy
+	retryable func(error) bool
+}
+
+func (rt *retryTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	var (
+		features []Feature
+		err      error
+		backoff  = rt.policy.Backoff
+	)
+	for attempt := 1; ; attempt++ {
+		features = features[:0]
+		err = rt.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+			features = append(features, *f)
+			return nil
+		})
+		if err == nil || errors.Is(err, ErrNoFeatures) {
+			break
+		}
+		if attempt >= rt.policy.MaxAttempts || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || !rt.retryable(err) {
+			return err
+		}
+
+		z, x, y := t.ZXY()
+		log.Warnf("layer (%v) tile %v/%v/%v attempt %v failed: %v, retrying in %v", layer, z, x, y, attempt, err, backoff)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		if backoff *= 2; rt.policy.MaxBackoff > 0 && backoff > rt.policy.MaxBackoff {
+			backoff = rt.policy.MaxBackoff
+		}
+	}
+
+	for i := range features {
+		if err := fn(&features[i]); err != nil {
+			return err
+		}
+	}
+	return err
+}
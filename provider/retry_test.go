@@ -0,0 +1,106 @@
+package provider_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+// flakyTiler streams its features, failing part way through
+// the first failures calls
+type flakyTiler struct {
+	featuresTiler
+	failures int
+	calls    int
+}
+
+var errTransient = errors.New("connection reset")
+
+func (ft *flakyTiler) TileFeatures(ctx context.Context, layer string, t provider.Tile, fn func(f *provider.Feature) error) error {
+	ft.calls++
+	if ft.calls > ft.failures {
+		return ft.featuresTiler.TileFeatures(ctx, layer, t, fn)
+	}
+	// stream a partial tile before failing
+	if err := fn(&ft.features[0]); err != nil {
+		return err
+	}
+	return errTransient
+}
+
+func TestWithTileRetry(t *testing.T) {
+	features := []provider.Feature{
+		{ID: 1, Geometry: geom.Point{1, 1}},
+		{ID: 2, Geometry: geom.Point{2, 2}},
+	}
+
+	type tcase struct {
+		failures  int
+		policy    provider.RetryPolicy
+		retryable func(error) bool
+		calls     int
+		ids       []uint64
+		err       error
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		flaky := &flakyTiler{featuresTiler: featuresTiler{features: features}, failures: tc.failures}
+		got, err := collect(provider.WithTileRetry(flaky, tc.policy, tc.retryable), "", provider.NewTile(0, 0, 0, 0, 3857))
+		if !errors.Is(err, tc.err) {
+			t.Errorf("error, expected %v got %v", tc.err, err)
+		}
+		if flaky.calls != tc.calls {
+			t.Errorf("calls, expected %v got %v", tc.calls, flaky.calls)
+		}
+
+		// features from failed attempts are never passed on
+		var ids []uint64
+		for _, f := range got {
+			ids = append(ids, f.ID)
+		}
+		if len(ids) != len(tc.ids) {
+			t.Fatalf("features, expected %v got %v", tc.ids, ids)
+		}
+		for i := range ids {
+			if ids[i] != tc.ids[i] {
+				t.Errorf("features, expected %v got %v", tc.ids, ids)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"succeeds": {
+			policy: provider.RetryPolicy{MaxAttempts: 3},
+			calls:  1,
+			ids:    []uint64{1, 2},
+		},
+		"retried": {
+			failures: 2,
+			policy:   provider.RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond},
+			calls:    3,
+			ids:      []uint64{1, 2},
+		},
+		"attempts exhausted": {
+			failures: 3,
+			policy:   provider.RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond},
+			calls:    3,
+			err:      errTransient,
+		},
+		"not retryable": {
+			failures:  1,
+			policy:    provider.RetryPolicy{MaxAttempts: 3},
+			retryable: func(err error) bool { return false },
+			calls:     1,
+			err:       errTransient,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
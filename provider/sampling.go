@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"context"
+	"math"
+)
+
+// WithSampling wraps the Tiler so only a fraction of the features are passed
+// to the callback, i.e. to keep low zoom tiles small without clustering. The
+// fraction kept is rate(z) for the tile's zoom z, a rate >= 1 keeps every
+// feature and a rate <= 0 drops every feature.
+//
+// Features are sampled by a hash of their ID, so the same features are kept
+// in every request and in adjacent tiles, and the features kept at a rate are
+// also kept at any higher rate. Features should have unique IDs; features
+// sharing an ID are kept or dropped together.
+func WithSampling(t Tiler, rate func(zoom uint) float64) Tiler {
+	if rate == nil {
+		return t
+	}
+	return &samplingTiler{
+		Tiler: t,
+		rate:  rate,
+	}
+}
+
+type samplingTiler struct {
+	Tiler
+	rate func(zoom uint) float64
+}
+
+func (st *samplingTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	z, _, _ := t.ZXY()
+	rate := st.rate(z)
+	if rate >= 1 {
+		return st.Tiler.TileFeatures(ctx, layer, t, fn)
+	}
+
+	// features whose hash is below the threshold are kept
+	threshold := uint64(0)
+	if rate > 0 {
+		threshold = uint64(math.Ldexp(rate, 64))
+	}
+
+	return st.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		if mix64(f.ID) >= threshold {
+			return nil
+		}
+		return fn(f)
+	})
+}
@@ -0,0 +1,89 @@
+package provider_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithSampling(t *testing.T) {
+	features := make([]provider.Feature, 10000)
+	for i := range features {
+		features[i] = provider.Feature{ID: uint64(i), Geometry: geom.Point{float64(i), 0}}
+	}
+
+	tiler := provider.WithSampling(featuresTiler{features: features}, func(z uint) float64 {
+		// a quarter of the features at z2, half at z3
+		return math.Exp2(float64(z)) / 16
+	})
+
+	sample := func(z, x, y uint) map[uint64]bool {
+		got, err := collect(tiler, "places", provider.NewTile(z, x, y, 0, 3857))
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		ids := make(map[uint64]bool, len(got))
+		for _, f := range got {
+			ids[f.ID] = true
+		}
+		return ids
+	}
+
+	type tcase struct {
+		z        uint
+		expected float64
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		ids := sample(tc.z, 0, 0)
+		if frac := float64(len(ids)) / float64(len(features)); math.Abs(frac-tc.expected) > 0.02 {
+			t.Errorf("fraction, expected %v (±0.02) got %v", tc.expected, frac)
+		}
+
+		// the same features are kept for repeated requests and adjacent tiles
+		for _, again := range []map[uint64]bool{sample(tc.z, 0, 0), sample(tc.z, 1, 0)} {
+			if len(again) != len(ids) {
+				t.Fatalf("repeated sample, expected %v features got %v", len(ids), len(again))
+			}
+			for id := range ids {
+				if !again[id] {
+					t.Fatalf("repeated sample, expected feature %v", id)
+				}
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"none": {
+			z:        0,
+			expected: 1.0 / 16,
+		},
+		"quarter": {
+			z:        2,
+			expected: 0.25,
+		},
+		"half": {
+			z:        3,
+			expected: 0.5,
+		},
+		"all": {
+			z:        4,
+			expected: 1,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+
+	// the features kept at a lower rate are kept at higher rates
+	quarter, half := sample(2, 0, 0), sample(3, 0, 0)
+	for id := range quarter {
+		if !half[id] {
+			t.Errorf("feature %v kept at z2, expected to be kept at z3", id)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package provider
+
+import "context"
+
+// Cursor is an opaque position within the features of a layer. The zero value
+// is the start of the layer. The format of a cursor is provider specific.
+type Cursor string
+
+// LayerScanner is implemented by providers which are able to page through all
+// the features of a layer, independent of any tile. This is intended for bulk
+// export rather than spatial queries.
+type LayerScanner interface {
+	// ScanLayer will stream up to pageSize features of the layer, starting at
+	// the cursor, to the callback function fn. The returned cursor is the start
+	// of the next page, an empty cursor is returned when there are no more
+	// features. If fn returns ErrCanceled, ScanLayer should stop processing.
+	ScanLayer(ctx context.Context, layer string, cursor Cursor, pageSize int, fn func(f *Feature) error) (Cursor, error)
+}
+
+// ScanLayer will page through the features of the layer, if the Tiler
+// implements LayerScanner. Otherwise ErrUnsupported is returned.
+func ScanLayer(ctx context.Context, t Tiler, layer string, cursor Cursor, pageSize int, fn func(f *Feature) error) (Cursor, error) {
+	scanner, release, ok := acquireAs[LayerScanner](t)
+	if !ok {
+		return "", ErrUnsupported
+	}
+	defer release()
+	return scanner.ScanLayer(ctx, layer, cursor, pageSize, fn)
+}
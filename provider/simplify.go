@@ -0,0 +1,225 @@
+package provider
+
+import (
+	"context"
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/internal/log"
+)
+
+// SimplifyTolerance is the tolerance, in pixels, used by a Tiler wrapped with
+// WithSimplify. Vertices closer than this to the simplified line are removed.
+var SimplifyTolerance = 1.0
+
+// WithSimplify wraps the Tiler so feature geometries are simplified with the
+// Douglas-Peucker algorithm before being passed to the callback. The tolerance
+// is SimplifyTolerance pixels at the tile's zoom, derived from the tile's
+// resolution, so geometries are simplified more aggressively at low zooms.
+// Features not in the tile's SRID are reprojected first.
+//
+// Lines keep their end points and polygon rings keep at least 3 points.
+// Parts which collapse (lines shorter than the tolerance, rings with an area
+// smaller than the tolerance squared) are dropped, and features which
+// collapse entirely are not passed to the callback. Points are not changed.
+// Simplification does not check for self intersections it may create.
+func WithSimplify(t Tiler) Tiler {
+	return &simplifyTiler{Tiler: t}
+}
+
+type simplifyTiler struct {
+	Tiler
+}
+
+func (st *simplifyTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	tolerance := simplifyTolerance(t)
+	_, tileSRID := t.Extent()
+
+	return st.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		if f.Geometry == nil || tolerance <= 0 {
+			return fn(f)
+		}
+
+		if err := reprojectFeatureInPlace(f, tileSRID); err != nil {
+			return err
+		}
+
+		g := dpSimplifyGeometry(f.Geometry, tolerance)
+		if g == nil {
+			log.Debugf("layer (%v) feature %v dropped, collapsed when simplified", layer, f.ID)
+			return nil
+		}
+		f.Geometry = g
+		return fn(f)
+	})
+}
+
+// simplifyTolerance returns SimplifyTolerance pixels in the units of the
+// tile's SRID
+func simplifyTolerance(t Tile) float64 {
+	return pixelTolerance(t, SimplifyTolerance)
+}
+
+// pixelTolerance returns px pixels in the units of the tile's SRID. The
+// tile's resolution is at its center latitude, so it is scaled back to the
+// resolution along the tile's (WebMercator) x axis.
+func pixelTolerance(t Tile, px float64) float64 {
+	ext, _ := t.Extent()
+	centerY := (ext.MinY() + ext.MaxY()) / 2
+	lat := math.Atan(math.Sinh(centerY / EarthRadius))
+	return px * t.Resolution() / math.Cos(lat)
+}
+
+// dpSimplifyGeometry returns the geometry simplified with douglasPeucker, nil
+// is returned if the geometry collapses. Unlike simplifyGeometry, which uses
+// maths/simplify, the tolerance is a distance in the geometry's units.
+func dpSimplifyGeometry(g geom.Geometry, tolerance float64) geom.Geometry {
+	switch gg := g.(type) {
+	case geom.LineString:
+		if line := simplifyLine(gg, tolerance); line != nil {
+			return geom.LineString(line)
+		}
+	case geom.MultiLineString:
+		var ml geom.MultiLineString
+		for i := range gg {
+			if line := simplifyLine(gg[i], tolerance); line != nil {
+				ml = append(ml, line)
+			}
+		}
+		if len(ml) > 0 {
+			return ml
+		}
+	case geom.Polygon:
+		if poly := simplifyPolygon(gg, tolerance); poly != nil {
+			return poly
+		}
+	case geom.MultiPolygon:
+		var mp geom.MultiPolygon
+		for i := range gg {
+			if poly := simplifyPolygon(gg[i], tolerance); poly != nil {
+				mp = append(mp, poly)
+			}
+		}
+		if len(mp) > 0 {
+			return mp
+		}
+	case geom.Collection:
+		var col geom.Collection
+		for i := range gg {
+			if g := dpSimplifyGeometry(gg[i], tolerance); g != nil {
+				col = append(col, g)
+			}
+		}
+		if len(col) > 0 {
+			return col
+		}
+	default:
+		return g
+	}
+	return nil
+}
+
+// simplifyLine returns nil if the line is shorter than the tolerance
+func simplifyLine(line [][2]float64, tolerance float64) [][2]float64 {
+	if len(line) < 2 {
+		return nil
+	}
+
+	var length float64
+	for i := 1; i < len(line); i++ {
+		length += math.Hypot(line[i][0]-line[i-1][0], line[i][1]-line[i-1][1])
+	}
+	if length < tolerance {
+		return nil
+	}
+
+	return douglasPeucker(line, tolerance)
+}
+
+// simplifyPolygon drops holes which collapse, nil is returned if the exterior collapses
+func simplifyPolygon(poly geom.Polygon, tolerance float64) geom.Polygon {
+	var simplified geom.Polygon
+	for i := range poly {
+		ring := poly[i]
+		if n := len(ring); n > 1 && ring[0] == ring[n-1] {
+			ring = ring[:n-1]
+		}
+		if len(ring) < 3 || math.Abs(ringSignedArea(ring)) < tolerance*tolerance {
+			if i == 0 {
+				return nil
+			}
+			continue
+		}
+
+		// simplify the closed ring, anchored on its first point
+		closed := append(ring[:len(ring):len(ring)], ring[0])
+		ring = douglasPeucker(closed, tolerance)
+		ring = ring[:len(ring)-1]
+
+		if len(ring) < 3 {
+			// the ring's area is larger than the tolerance, but it is
+			// thin enough to collapse to a line. keep it as a triangle
+			ring = ringTriangle(poly[i])
+		}
+		simplified = append(simplified, ring)
+	}
+	return simplified
+}
+
+// ringTriangle returns three points spread along the ring, which must have
+// at least 3 points
+func ringTriangle(ring [][2]float64) [][2]float64 {
+	mid := len(ring) / 2
+	return [][2]float64{ring[0], ring[mid/2+1], ring[mid+(len(ring)-mid)/2]}
+}
+
+// douglasPeucker simplifies the points, always keeping the first and last
+func douglasPeucker(pts [][2]float64, tolerance float64) [][2]float64 {
+	if len(pts) <= 2 {
+		return pts
+	}
+
+	keep := make([]bool, len(pts))
+	keep[0], keep[len(pts)-1] = true, true
+
+	// an explicit stack of ranges to simplify, to avoid deep recursion
+	stack := [][2]int{{0, len(pts) - 1}}
+	for len(stack) > 0 {
+		r := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		var (
+			dmax float64
+			idx  int
+		)
+		for i := r[0] + 1; i < r[1]; i++ {
+			if d := segmentDistance(pts[i], pts[r[0]], pts[r[1]]); d > dmax {
+				dmax, idx = d, i
+			}
+		}
+		if dmax > tolerance {
+			keep[idx] = true
+			stack = append(stack, [2]int{r[0], idx}, [2]int{idx, r[1]})
+		}
+	}
+
+	simplified := make([][2]float64, 0, len(pts))
+	for i := range pts {
+		if keep[i] {
+			simplified = append(simplified, pts[i])
+		}
+	}
+	return simplified
+}
+
+// segmentDistance returns the distance from pt to the segment a,b
+func segmentDistance(pt, a, b [2]float64) float64 {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	if dx == 0 && dy == 0 {
+		return math.Hypot(pt[0]-a[0], pt[1]-a[1])
+	}
+
+	t := ((pt[0]-a[0])*dx + (pt[1]-a[1])*dy) / (dx*dx + dy*dy)
+	t = math.Max(0, math.Min(1, t))
+	return math.Hypot(pt[0]-(a[0]+t*dx), pt[1]-(a[1]+t*dy))
+}
@@ -0,0 +1,73 @@
+package provider_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithSimplify(t *testing.T) {
+	// a 100km long wave with a 500m amplitude and 1000 vertices, near null island
+	wave := make(geom.LineString, 1000)
+	for i := range wave {
+		x := float64(i) * 100
+		wave[i] = [2]float64{x, 500 * math.Sin(x/5000)}
+	}
+	// a 50m square
+	square := geom.Polygon{{{0, 0}, {50, 0}, {50, 50}, {0, 50}}}
+
+	tiler := provider.WithSimplify(featuresTiler{
+		features: []provider.Feature{
+			{ID: 1, Geometry: wave},
+			{ID: 2, Geometry: square},
+			{ID: 3, Geometry: geom.Point{1, 1}},
+		},
+	})
+
+	vertices := func(z uint) (counts map[uint64]int) {
+		// the tile containing null island's north east corner
+		n := uint(1) << z
+		got, err := collect(tiler, "rivers", provider.NewTile(z, n/2, n/2-1, 0, 3857))
+		if err != nil {
+			t.Fatalf("z%v error, expected nil got %v", z, err)
+		}
+		counts = make(map[uint64]int)
+		for _, f := range got {
+			pts, err := geom.GetCoordinates(f.Geometry)
+			if err != nil {
+				t.Fatalf("z%v coordinates error, expected nil got %v", z, err)
+			}
+			counts[f.ID] = len(pts)
+		}
+		return counts
+	}
+
+	z4, z10, z16 := vertices(4), vertices(10), vertices(16)
+
+	if !(z4[1] < z10[1] && z10[1] < z16[1]) {
+		t.Errorf("line vertices, expected z4 < z10 < z16 got %v, %v, %v", z4[1], z10[1], z16[1])
+	}
+	// at z4 a pixel is ~10km, so the wave is barely more than a straight line
+	if z4[1] > 3 {
+		t.Errorf("z4 line vertices, expected at most 3 got %v", z4[1])
+	}
+	// at z16 a pixel is ~2.4m, so the wave keeps most of its shape
+	if z16[1] < 100 {
+		t.Errorf("z16 line vertices, expected at least 100 got %v", z16[1])
+	}
+
+	// the square collapses at low zooms
+	if _, ok := z4[2]; ok {
+		t.Errorf("z4 square, expected dropped got %v vertices", z4[2])
+	}
+	if z16[2] != 4 {
+		t.Errorf("z16 square vertices, expected 4 got %v", z16[2])
+	}
+
+	// points are never simplified
+	if z4[3] != 1 || z16[3] != 1 {
+		t.Errorf("point, expected kept at z4 and z16 got %v and %v", z4[3], z16[3])
+	}
+}
@@ -0,0 +1,41 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-spatial/tegola/internal/log"
+)
+
+// WithSlowQueryLog wraps the Tiler so TileFeatures calls taking longer than
+// threshold are logged as a warning, with the layer, tile, duration and
+// number of features. The duration includes the time spent in the callback.
+// A threshold <= 0 disables the log.
+func WithSlowQueryLog(t Tiler, threshold time.Duration) Tiler {
+	if threshold <= 0 {
+		return t
+	}
+	return &slowQueryTiler{
+		Tiler:     t,
+		threshold: threshold,
+	}
+}
+
+type slowQueryTiler struct {
+	Tiler
+	threshold time.Duration
+}
+
+func (sqt *slowQueryTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	var count int
+	start := time.Now()
+	err := sqt.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		count++
+		return fn(f)
+	})
+
+	if took := time.Since(start); took > sqt.threshold {
+		log.Warnf("slow query for layer (%v) tile %v took %v with %v features, threshold %v", layer, TileKey(t), took, count, sqt.threshold)
+	}
+	return err
+}
@@ -0,0 +1,73 @@
+package provider_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/internal/log"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithSlowQueryLog(t *testing.T) {
+	var out bytes.Buffer
+	log.SetOutput(&out)
+	defer log.SetOutput(os.Stderr)
+
+	tiler := featuresTiler{
+		features: []provider.Feature{
+			{ID: 1, Geometry: geom.Point{0, 0}},
+			{ID: 2, Geometry: geom.Point{1, 1}},
+		},
+	}
+
+	type tcase struct {
+		delay time.Duration
+		slow  bool
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		out.Reset()
+
+		st := provider.WithSlowQueryLog(tiler, 20*time.Millisecond)
+		err := st.TileFeatures(context.Background(), "roads", provider.NewTile(3, 2, 1, 0, 3857), func(*provider.Feature) error {
+			time.Sleep(tc.delay)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+
+		logged := out.String()
+		if !tc.slow {
+			if logged != "" {
+				t.Errorf("log, expected nothing got %v", logged)
+			}
+			return
+		}
+		for _, s := range []string{"[WARN]", "layer (roads)", "tile 3/2/1", "with 2 features"} {
+			if !strings.Contains(logged, s) {
+				t.Errorf("log, expected to contain %q got %v", s, logged)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"fast": {
+			delay: 0,
+		},
+		"slow": {
+			delay: 15 * time.Millisecond,
+			slow:  true,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
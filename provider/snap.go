@@ -0,0 +1,163 @@
+package provider
+
+import (
+	"context"
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/internal/log"
+)
+
+// WithSnapToGrid wraps the Tiler so the coordinates of features are rounded
+// to the nearest multiple of gridSize, in the units of the tile's SRID.
+// Features not in the tile's SRID are reprojected first. Repeated points
+// created by snapping are removed, and parts of geometries which collapse
+// (lines of zero length, polygons of zero area) are dropped. Features which
+// collapse entirely are not passed to the callback.
+// A gridSize <= 0 disables snapping.
+func WithSnapToGrid(t Tiler, gridSize float64) Tiler {
+	if gridSize <= 0 {
+		return t
+	}
+	return &snapTiler{
+		Tiler:    t,
+		gridSize: gridSize,
+	}
+}
+
+type snapTiler struct {
+	Tiler
+	gridSize float64
+}
+
+func (st *snapTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	_, tileSRID := t.Extent()
+	return st.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		if f.Geometry == nil {
+			return fn(f)
+		}
+
+		if err := reprojectFeatureInPlace(f, tileSRID); err != nil {
+			return err
+		}
+
+		g := snapGeometry(f.Geometry, gridRounder(st.gridSize))
+		if g == nil {
+			log.Debugf("layer (%v) feature %v dropped, collapsed when snapped to grid", layer, f.ID)
+			return nil
+		}
+		f.Geometry = g
+		return fn(f)
+	})
+}
+
+// pointRounder rounds a point, i.e. to a grid
+type pointRounder func(pt [2]float64) [2]float64
+
+// gridRounder rounds points to the nearest multiple of size
+func gridRounder(size float64) pointRounder {
+	return func(pt [2]float64) [2]float64 {
+		return [2]float64{
+			math.Round(pt[0]/size) * size,
+			math.Round(pt[1]/size) * size,
+		}
+	}
+}
+
+// snapGeometry returns the geometry with its points rounded, nil is
+// returned if the geometry collapses
+func snapGeometry(g geom.Geometry, round pointRounder) geom.Geometry {
+	switch gg := g.(type) {
+	case geom.Point:
+		return geom.Point(round(gg))
+	case geom.MultiPoint:
+		mp := make(geom.MultiPoint, len(gg))
+		for i := range gg {
+			mp[i] = round(gg[i])
+		}
+		return mp
+	case geom.LineString:
+		if line := snapLine(gg, round); line != nil {
+			return geom.LineString(line)
+		}
+	case geom.MultiLineString:
+		var ml geom.MultiLineString
+		for i := range gg {
+			if line := snapLine(gg[i], round); line != nil {
+				ml = append(ml, line)
+			}
+		}
+		if len(ml) > 0 {
+			return ml
+		}
+	case geom.Polygon:
+		if poly := snapPolygon(gg, round); poly != nil {
+			return poly
+		}
+	case geom.MultiPolygon:
+		var mp geom.MultiPolygon
+		for i := range gg {
+			if poly := snapPolygon(gg[i], round); poly != nil {
+				mp = append(mp, poly)
+			}
+		}
+		if len(mp) > 0 {
+			return mp
+		}
+	case geom.Collection:
+		var col geom.Collection
+		for i := range gg {
+			if g := snapGeometry(gg[i], round); g != nil {
+				col = append(col, g)
+			}
+		}
+		if len(col) > 0 {
+			return col
+		}
+	default:
+		return g
+	}
+	return nil
+}
+
+// snapPoints rounds the points, removing consecutive duplicates
+func snapPoints(pts [][2]float64, round pointRounder) [][2]float64 {
+	snapped := make([][2]float64, 0, len(pts))
+	for i := range pts {
+		pt := round(pts[i])
+		if len(snapped) > 0 && snapped[len(snapped)-1] == pt {
+			continue
+		}
+		snapped = append(snapped, pt)
+	}
+	return snapped
+}
+
+// snapLine returns nil if the line collapses to a single point
+func snapLine(line [][2]float64, round pointRounder) [][2]float64 {
+	snapped := snapPoints(line, round)
+	if len(snapped) < 2 {
+		return nil
+	}
+	return snapped
+}
+
+// snapPolygon drops holes which collapse, nil is returned if the exterior collapses
+func snapPolygon(poly geom.Polygon, round pointRounder) geom.Polygon {
+	var snapped geom.Polygon
+	for i := range poly {
+		ring := snapPoints(poly[i], round)
+		// rings are not closed, drop a closing point created by snapping
+		if n := len(ring); n > 1 && ring[0] == ring[n-1] {
+			ring = ring[:n-1]
+		}
+		if len(ring) < 3 || ringSignedArea(ring) == 0 {
+			if i == 0 {
+				return nil
+			}
+			continue
+		}
+		snapped = append(snapped, ring)
+	}
+	return snapped
+}
@@ -0,0 +1,41 @@
+package provider_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithSnapToGrid(t *testing.T) {
+	tiler := featuresTiler{
+		features: []provider.Feature{
+			{ID: 1, SRID: 3857, Geometry: geom.Point{12.4, -7.6}},
+			{ID: 2, SRID: 3857, Geometry: geom.LineString{{0.1, 0.1}, {0.2, 0.2}, {9.9, 10.1}}},
+			// collapses to a line when snapped
+			{ID: 3, SRID: 3857, Geometry: geom.Polygon{{{0, 0}, {10, 1}, {20, 2}}}},
+			// the hole collapses, the exterior does not
+			{ID: 4, SRID: 3857, Geometry: geom.Polygon{
+				{{0, 0}, {50, 0}, {50, 50}, {0, 50}},
+				{{20, 20}, {21, 20}, {21, 21}},
+			}},
+			// collapses to a point
+			{ID: 5, SRID: 3857, Geometry: geom.LineString{{1, 1}, {2, 2}}},
+		},
+	}
+
+	features, err := collect(provider.WithSnapToGrid(tiler, 10), "", provider.NewTile(0, 0, 0, 0, 3857))
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+
+	expected := []provider.Feature{
+		{ID: 1, SRID: 3857, Geometry: geom.Point{10, -10}},
+		{ID: 2, SRID: 3857, Geometry: geom.LineString{{0, 0}, {10, 10}}},
+		{ID: 4, SRID: 3857, Geometry: geom.Polygon{{{0, 0}, {50, 0}, {50, 50}, {0, 50}}}},
+	}
+	if !reflect.DeepEqual(features, expected) {
+		t.Errorf("features, expected %v got %v", expected, features)
+	}
+}
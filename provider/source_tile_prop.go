@@ -0,0 +1,35 @@
+package provider
+
+import "context"
+
+// WithSourceTileProp wraps the Tiler so every feature carries the tile it
+// was requested for, as a "z/x/y" string, see TileKey, under key. This keeps
+// track of where features came from once features of several tiles are
+// merged, i.e. when overzooming or deduplicating features across tiles. As
+// it adds a property to every feature it is opt in. An empty key returns t
+// unchanged.
+func WithSourceTileProp(t Tiler, key string) Tiler {
+	if key == "" {
+		return t
+	}
+	return &sourceTilePropTiler{
+		Tiler: t,
+		key:   key,
+	}
+}
+
+type sourceTilePropTiler struct {
+	Tiler
+	key string
+}
+
+func (st *sourceTilePropTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	tileKey := TileKey(t)
+	return st.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		if f.Tags == nil {
+			f.Tags = make(map[string]interface{}, 1)
+		}
+		f.Tags[st.key] = tileKey
+		return fn(f)
+	})
+}
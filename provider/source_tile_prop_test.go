@@ -0,0 +1,60 @@
+package provider_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithSourceTileProp(t *testing.T) {
+	tiler := featuresTiler{
+		features: []provider.Feature{
+			{ID: 1, Geometry: geom.Point{1, 1}, Tags: map[string]interface{}{"name": "a"}},
+			{ID: 2, Geometry: geom.Point{2, 2}},
+		},
+	}
+
+	type tcase struct {
+		tile     provider.Tile
+		expected []map[string]interface{}
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		features, err := collect(provider.WithSourceTileProp(tiler, "source_tile"), "", tc.tile)
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		if len(features) != len(tc.expected) {
+			t.Fatalf("features, expected %v got %v", len(tc.expected), len(features))
+		}
+		for i := range features {
+			if !reflect.DeepEqual(features[i].Tags, tc.expected[i]) {
+				t.Errorf("feature %v tags, expected %v got %v", features[i].ID, tc.expected[i], features[i].Tags)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"z0": {
+			tile: provider.NewTile(0, 0, 0, 0, 3857),
+			expected: []map[string]interface{}{
+				{"name": "a", "source_tile": "0/0/0"},
+				{"source_tile": "0/0/0"},
+			},
+		},
+		"z10": {
+			tile: provider.NewTile(10, 163, 395, 64, 3857),
+			expected: []map[string]interface{}{
+				{"name": "a", "source_tile": "10/163/395"},
+				{"source_tile": "10/163/395"},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
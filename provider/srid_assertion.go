@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/slippy"
+	"github.com/go-spatial/tegola"
+	"github.com/go-spatial/tegola/internal/log"
+)
+
+// sridBounds are the valid coordinate bounds of the SRIDs supported by
+// WithSRIDAssertion
+var sridBounds = map[uint64]*geom.Extent{
+	tegola.WebMercator: geom.NewExtent(
+		[2]float64{-slippy.WebMercatorMax, -slippy.WebMercatorMax},
+		[2]float64{slippy.WebMercatorMax, slippy.WebMercatorMax},
+	),
+	tegola.WGS84: geom.NewExtent(
+		[2]float64{-180, -90},
+		[2]float64{180, 90},
+	),
+}
+
+// WithSRIDAssertion wraps the Tiler so every feature's geometry is checked to
+// be within the valid coordinate bounds of the expected SRID, i.e. ±20037508.34
+// for WebMercator. This catches layers emitting geometries in the wrong SRID,
+// which would otherwise only be noticed when the tile is rendered. A feature
+// outside of the bounds is logged and an ErrSRIDAssertion is returned.
+//
+// Only WebMercator and WGS84 are supported, for other SRIDs t is returned.
+func WithSRIDAssertion(t Tiler, expected uint64) Tiler {
+	bounds, ok := sridBounds[expected]
+	if !ok {
+		return t
+	}
+	return &sridAssertionTiler{
+		Tiler:  t,
+		srid:   expected,
+		bounds: bounds,
+	}
+}
+
+type sridAssertionTiler struct {
+	Tiler
+	srid   uint64
+	bounds *geom.Extent
+}
+
+func (sat *sridAssertionTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	return sat.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		if f.Geometry == nil || geom.IsEmpty(f.Geometry) {
+			return fn(f)
+		}
+
+		ext, err := geom.NewExtentFromGeometry(f.Geometry)
+		if err != nil {
+			return err
+		}
+		if !sat.bounds.Contains(ext) {
+			err := ErrSRIDAssertion{Layer: layer, ID: f.ID, SRID: sat.srid, Extent: ext.Extent()}
+			log.Errorf("%v", err)
+			return err
+		}
+		return fn(f)
+	})
+}
@@ -0,0 +1,77 @@
+package provider_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithSRIDAssertion(t *testing.T) {
+	type tcase struct {
+		srid     uint64
+		features []provider.Feature
+		err      bool
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		tiler := provider.WithSRIDAssertion(featuresTiler{features: tc.features}, tc.srid)
+		got, err := collect(tiler, "roads", provider.NewTile(0, 0, 0, 0, 3857))
+		if !tc.err {
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if len(got) != len(tc.features) {
+				t.Errorf("features, expected %v got %v", len(tc.features), len(got))
+			}
+			return
+		}
+
+		var aerr provider.ErrSRIDAssertion
+		if !errors.As(err, &aerr) {
+			t.Fatalf("error, expected ErrSRIDAssertion got %v", err)
+		}
+		if aerr.Layer != "roads" || aerr.ID != 2 || aerr.SRID != tc.srid {
+			t.Errorf("error, expected layer roads feature 2 srid %v got %+v", tc.srid, aerr)
+		}
+	}
+
+	tests := map[string]tcase{
+		"webmercator in range": {
+			srid: 3857,
+			features: []provider.Feature{
+				{ID: 1, Geometry: geom.Point{-20037508, 20037508}},
+				{ID: 2, Geometry: geom.LineString{{0, 0}, {1000, 1000}}},
+				{ID: 3},
+			},
+		},
+		"webmercator out of range": {
+			srid: 3857,
+			features: []provider.Feature{
+				{ID: 1, Geometry: geom.Point{0, 0}},
+				{ID: 2, Geometry: geom.LineString{{0, 0}, {20037600, 0}}},
+			},
+			err: true,
+		},
+		"wgs84 given webmercator": {
+			srid: 4326,
+			features: []provider.Feature{
+				{ID: 1, Geometry: geom.Point{10, 10}},
+				{ID: 2, Geometry: geom.Point{1113194.9, 1118890.0}},
+			},
+			err: true,
+		},
+		"unsupported srid": {
+			srid: 2163,
+			features: []provider.Feature{
+				{ID: 1, Geometry: geom.Point{1e9, 1e9}},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
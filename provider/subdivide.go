@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/internal/log"
+)
+
+// SubdivideFeatures partitions the features of the parent tile into its four
+// children, as returned by ChildTiles, so the children can be rendered from
+// a single query of the parent. A feature is included in every child whose
+// buffered extent its envelope intersects, features spanning children are
+// included in each of them. The features are not copied, a feature included
+// in more than one child is the same *Feature in each.
+//
+// Envelopes are computed in the parent's SRID, features not in the parent's
+// SRID are reprojected for the comparison only. Features with a nil or empty
+// geometry, or which can not be reprojected, are not included in any child.
+// Every child is in the returned map, even if it has no features.
+func SubdivideFeatures(features []*Feature, parent Tile) map[Tile][]*Feature {
+	children := ChildTiles(parent)
+	_, tileSRID := parent.Extent()
+
+	exts := make([]*geom.Extent, len(children))
+	subdivided := make(map[Tile][]*Feature, len(children))
+	for i, child := range children {
+		exts[i], _ = child.BufferedExtent()
+		subdivided[child] = nil
+	}
+
+	for _, f := range features {
+		if f == nil || f.Geometry == nil {
+			continue
+		}
+
+		g, err := reprojectFeature(f, tileSRID)
+		if err != nil {
+			log.Debugf("feature %v not subdivided: %v", f.ID, err)
+			continue
+		}
+
+		env, err := geom.NewExtentFromGeometry(g)
+		if err != nil || env == nil {
+			continue
+		}
+
+		for i, ext := range exts {
+			// edges are inclusive so points on a boundary are in both tiles
+			if env.MinX() > ext.MaxX() || env.MaxX() < ext.MinX() ||
+				env.MinY() > ext.MaxY() || env.MaxY() < ext.MinY() {
+				continue
+			}
+			subdivided[children[i]] = append(subdivided[children[i]], f)
+		}
+	}
+
+	return subdivided
+}
@@ -0,0 +1,53 @@
+package provider_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestSubdivideFeatures(t *testing.T) {
+	features := []*provider.Feature{
+		{ID: 1, SRID: 3857, Geometry: geom.Point{-1e7, 1e7}},
+		// spans the top two children
+		{ID: 2, SRID: 3857, Geometry: geom.LineString{{-1e7, 1e7}, {1e7, 1e7}}},
+		{ID: 3, SRID: 3857, Geometry: geom.Point{-1e7, -1e7}},
+		// reprojected to the top right child
+		{ID: 4, SRID: 4326, Geometry: geom.Point{90, 45}},
+		// overlaps every child
+		{ID: 5, SRID: 3857, Geometry: geom.Polygon{{{-1e6, -1e6}, {1e6, -1e6}, {1e6, 1e6}, {-1e6, 1e6}}}},
+		{ID: 6, SRID: 3857},
+	}
+
+	subdivided := provider.SubdivideFeatures(features, provider.NewTile(0, 0, 0, 0, 3857))
+
+	got := make(map[string][]uint64, len(subdivided))
+	for tile, fs := range subdivided {
+		ids := []uint64{}
+		for _, f := range fs {
+			ids = append(ids, f.ID)
+		}
+		got[provider.TileKey(tile)] = ids
+	}
+
+	expected := map[string][]uint64{
+		"1/0/0": {1, 2, 5},
+		"1/1/0": {2, 4, 5},
+		"1/0/1": {3, 5},
+		"1/1/1": {5},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("children, expected %v got %v", expected, got)
+	}
+
+	// features spanning children are shared, not copied
+	for tile, fs := range subdivided {
+		for _, f := range fs {
+			if f.ID == 2 && f != features[1] {
+				t.Errorf("tile %v feature 2, expected shared feature got copy", provider.TileKey(tile))
+			}
+		}
+	}
+}
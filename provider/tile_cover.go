@@ -0,0 +1,263 @@
+package provider
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/slippy"
+	"github.com/go-spatial/tegola"
+	"github.com/go-spatial/tegola/basic"
+)
+
+// TilesForGeometry returns the tiles, from minZoom to maxZoom inclusive, whose
+// extent intersects the geometry. Unlike covering the geometry's bounding box,
+// tiles which fall within the bounding box but do not touch the geometry are
+// not returned. The geometry must be in either WebMercator or WGS84.
+//
+// Geometries crossing the antimeridian should use continuous coordinates that
+// extend past ±180° (±20037508.34 in WebMercator), i.e. a line from 179° to
+// 181° rather than to -179°. The tiles are wrapped back into the valid range.
+//
+// The returned tiles have no buffer and are in WebMercator.
+func TilesForGeometry(g geom.Geometry, srid uint64, minZoom, maxZoom uint) ([]Tile, error) {
+	if minZoom > maxZoom {
+		return nil, fmt.Errorf("min zoom (%v) is greater than max zoom (%v)", minZoom, maxZoom)
+	}
+	if maxZoom > tegola.MaxZ {
+		return nil, fmt.Errorf("max zoom (%v) is greater than %v", maxZoom, tegola.MaxZ)
+	}
+	if g == nil || geom.IsEmpty(g) {
+		return nil, nil
+	}
+
+	if srid != tegola.WebMercator {
+		var err error
+		if g, err = basic.ToWebMercator(srid, g); err != nil {
+			return nil, err
+		}
+	}
+
+	ext, err := geom.NewExtentFromGeometry(g)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		tiles []Tile
+		seen  = make(map[[3]uint]struct{})
+	)
+
+	// walk down from the tiles covering the bounding box at minZoom, only
+	// descending into tiles that intersect the geometry
+	var walk func(z uint, x, y int)
+	walk = func(z uint, x, y int) {
+		if !geometryIntersectsExtent(g, unwrappedTileExtent(z, x, y)) {
+			return
+		}
+
+		if z >= minZoom {
+			key := [3]uint{z, wrapTileX(z, x), uint(y)}
+			if _, ok := seen[key]; !ok {
+				seen[key] = struct{}{}
+				tiles = append(tiles, NewTile(key[0], key[1], key[2], 0, tegola.WebMercator))
+			}
+		}
+
+		if z == maxZoom {
+			return
+		}
+		for dx := 0; dx < 2; dx++ {
+			for dy := 0; dy < 2; dy++ {
+				walk(z+1, x*2+dx, y*2+dy)
+			}
+		}
+	}
+
+	minx, miny, maxx, maxy := tileRangeForExtent(0, ext)
+	for x := minx; x <= maxx; x++ {
+		for y := miny; y <= maxy; y++ {
+			walk(0, x, y)
+		}
+	}
+
+	return tiles, nil
+}
+
+// tileRangeForExtent returns the range of tiles covering the WebMercator
+// extent at the zoom. The x values are not wrapped, so extents crossing the
+// antimeridian may return x values outside of the valid range.
+func tileRangeForExtent(z uint, ext *geom.Extent) (minx, miny, maxx, maxy int) {
+	n := math.Exp2(float64(z))
+	res := slippy.WebMercatorMax * 2 / n
+
+	minx = int(math.Floor((ext.MinX() + slippy.WebMercatorMax) / res))
+	maxx = int(math.Floor((ext.MaxX() + slippy.WebMercatorMax) / res))
+	// y is flipped in tile space
+	miny = int(math.Floor((slippy.WebMercatorMax - ext.MaxY()) / res))
+	maxy = int(math.Floor((slippy.WebMercatorMax - ext.MinY()) / res))
+
+	// clamp y values, there is no wrapping over the poles
+	miny = clampInt(miny, 0, int(n)-1)
+	maxy = clampInt(maxy, 0, int(n)-1)
+	// an extent on the edge of the world should not select the tile past it
+	if maxx > minx && float64(maxx)*res-slippy.WebMercatorMax == ext.MaxX() {
+		maxx--
+	}
+	return minx, miny, maxx, maxy
+}
+
+// unwrappedTileExtent returns the WebMercator extent of the tile, x values
+// outside of the valid range give extents to the east or west of the world
+func unwrappedTileExtent(z uint, x, y int) *geom.Extent {
+	res := slippy.WebMercatorMax * 2 / math.Exp2(float64(z))
+	return geom.NewExtent(
+		[2]float64{-slippy.WebMercatorMax + float64(x)*res, slippy.WebMercatorMax - float64(y+1)*res},
+		[2]float64{-slippy.WebMercatorMax + float64(x+1)*res, slippy.WebMercatorMax - float64(y)*res},
+	)
+}
+
+// wrapTileX wraps an x value into the valid range of the zoom
+func wrapTileX(z uint, x int) uint {
+	n := 1 << z
+	x %= n
+	if x < 0 {
+		x += n
+	}
+	return uint(x)
+}
+
+func clampInt(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+// geometryIntersectsExtent reports if the geometry intersects the extent
+func geometryIntersectsExtent(g geom.Geometry, ext *geom.Extent) bool {
+	switch gg := g.(type) {
+	case geom.Point:
+		return ext.ContainsPoint(gg)
+	case geom.MultiPoint:
+		for i := range gg {
+			if ext.ContainsPoint(gg[i]) {
+				return true
+			}
+		}
+	case geom.LineString:
+		return lineIntersectsExtent(gg, ext)
+	case geom.MultiLineString:
+		for i := range gg {
+			if lineIntersectsExtent(gg[i], ext) {
+				return true
+			}
+		}
+	case geom.Polygon:
+		return polygonIntersectsExtent(gg, ext)
+	case geom.MultiPolygon:
+		for i := range gg {
+			if polygonIntersectsExtent(gg[i], ext) {
+				return true
+			}
+		}
+	case geom.Collection:
+		for i := range gg {
+			if geometryIntersectsExtent(gg[i], ext) {
+				return true
+			}
+		}
+	case *geom.Extent:
+		_, ok := ext.Intersect(gg)
+		return ok
+	}
+	return false
+}
+
+func lineIntersectsExtent(line [][2]float64, ext *geom.Extent) bool {
+	if len(line) == 1 {
+		return ext.ContainsPoint(line[0])
+	}
+	for i := 0; i < len(line)-1; i++ {
+		if segmentIntersectsExtent(line[i], line[i+1], ext) {
+			return true
+		}
+	}
+	return false
+}
+
+func polygonIntersectsExtent(poly [][][2]float64, ext *geom.Extent) bool {
+	if len(poly) == 0 {
+		return false
+	}
+	// any edge crossing into the extent
+	for _, ring := range poly {
+		if len(ring) == 0 {
+			continue
+		}
+		if lineIntersectsExtent(ring, ext) || segmentIntersectsExtent(ring[len(ring)-1], ring[0], ext) {
+			return true
+		}
+	}
+	// otherwise the extent is either wholly inside or outside of the polygon
+	center := [2]float64{(ext.MinX() + ext.MaxX()) / 2, (ext.MinY() + ext.MaxY()) / 2}
+	if !pointInRing(center, poly[0]) {
+		return false
+	}
+	for _, hole := range poly[1:] {
+		if pointInRing(center, hole) {
+			return false
+		}
+	}
+	return true
+}
+
+// segmentIntersectsExtent uses the Liang–Barsky algorithm to determine if the
+// segment a,b intersects the extent
+func segmentIntersectsExtent(a, b [2]float64, ext *geom.Extent) bool {
+	t0, t1 := 0.0, 1.0
+	dx, dy := b[0]-a[0], b[1]-a[1]
+
+	clip := func(p, q float64) bool {
+		if p == 0 {
+			return q >= 0
+		}
+		r := q / p
+		if p < 0 {
+			if r > t1 {
+				return false
+			}
+			if r > t0 {
+				t0 = r
+			}
+			return true
+		}
+		if r < t0 {
+			return false
+		}
+		if r < t1 {
+			t1 = r
+		}
+		return true
+	}
+
+	return clip(-dx, a[0]-ext.MinX()) &&
+		clip(dx, ext.MaxX()-a[0]) &&
+		clip(-dy, a[1]-ext.MinY()) &&
+		clip(dy, ext.MaxY()-a[1])
+}
+
+// pointInRing uses ray casting to determine if the point is inside the ring
+func pointInRing(pt [2]float64, ring [][2]float64) bool {
+	var in bool
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		if (ring[i][1] > pt[1]) != (ring[j][1] > pt[1]) &&
+			pt[0] < (ring[j][0]-ring[i][0])*(pt[1]-ring[i][1])/(ring[j][1]-ring[i][1])+ring[i][0] {
+			in = !in
+		}
+	}
+	return in
+}
@@ -0,0 +1,114 @@
+package provider_test
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/slippy"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func zxys(tiles []provider.Tile) (keys [][3]uint) {
+	for _, t := range tiles {
+		z, x, y := t.ZXY()
+		keys = append(keys, [3]uint{z, x, y})
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		for k := 0; k < 3; k++ {
+			if keys[i][k] != keys[j][k] {
+				return keys[i][k] < keys[j][k]
+			}
+		}
+		return false
+	})
+	return keys
+}
+
+func TestTilesForGeometry(t *testing.T) {
+	const max = slippy.WebMercatorMax
+
+	type tcase struct {
+		geom     geom.Geometry
+		srid     uint64
+		minZoom  uint
+		maxZoom  uint
+		expected [][3]uint
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		tiles, err := provider.TilesForGeometry(tc.geom, tc.srid, tc.minZoom, tc.maxZoom)
+		if err != nil {
+			t.Errorf("error, expected nil got %v", err)
+			return
+		}
+		if got := zxys(tiles); !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("tiles, expected %v got %v", tc.expected, got)
+		}
+	}
+
+	tests := map[string]tcase{
+		"point": {
+			geom:     geom.Point{max / 4, max / 4},
+			srid:     3857,
+			minZoom:  0,
+			maxZoom:  2,
+			expected: [][3]uint{{0, 0, 0}, {1, 1, 0}, {2, 2, 1}},
+		},
+		"polygon containing tiles": {
+			// a polygon covering the south west quarter of the world
+			geom:     geom.Polygon{{{-max + 1, -max + 1}, {-1, -max + 1}, {-1, -1}, {-max + 1, -1}}},
+			srid:     3857,
+			minZoom:  1,
+			maxZoom:  1,
+			expected: [][3]uint{{1, 0, 1}},
+		},
+		"antimeridian": {
+			geom:     geom.LineString{{179, 10}, {181, 10}},
+			srid:     4326,
+			minZoom:  1,
+			maxZoom:  1,
+			expected: [][3]uint{{1, 0, 0}, {1, 1, 0}},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
+
+func TestTilesForGeometryVsBBox(t *testing.T) {
+	const (
+		max  = slippy.WebMercatorMax
+		zoom = 4
+	)
+
+	// a diagonal line across the world, which has a bounding box of the whole world
+	line := geom.LineString{{-max * 0.99, -max * 0.98}, {max * 0.99, max * 0.97}}
+
+	tiles, err := provider.TilesForGeometry(line, 3857, zoom, zoom)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+
+	ext, err := geom.NewExtentFromGeometry(line)
+	if err != nil {
+		t.Fatalf("extent error, expected nil got %v", err)
+	}
+	bboxCount := 0
+	for x := slippy.WebX2Tile(zoom, ext.MinX()); x <= slippy.WebX2Tile(zoom, ext.MaxX()); x++ {
+		for y := slippy.WebY2Tile(zoom, ext.MaxY()); y <= slippy.WebY2Tile(zoom, ext.MinY()); y++ {
+			bboxCount++
+		}
+	}
+
+	if bboxCount != 256 {
+		t.Errorf("bbox tile count, expected 256 got %v", bboxCount)
+	}
+	// the line passes through at least one tile per column and at most two
+	if len(tiles) < 16 || len(tiles) > 32 {
+		t.Errorf("geometry tile count, expected between 16 and 32 got %v", len(tiles))
+	}
+}
@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"errors"
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/slippy"
+	"github.com/go-spatial/tegola"
+)
+
+// extentTile is a Tile covering an arbitrary WebMercator extent, rather
+// than a tile of the slippy tile grid
+type extentTile struct {
+	ext    *geom.Extent
+	buffer uint
+}
+
+// NewTileFromExtent returns a Tile whose extent is the WebMercator extent,
+// allowing providers to be queried for an arbitrary region. The buffer is in
+// MVT extent units (1/4096th of the extent's width), the same as NewTile.
+//
+// ZXY returns the smallest tile of the slippy tile grid containing the
+// extent, for providers which use the zoom (i.e. PostGIS's !ZOOM! token).
+func NewTileFromExtent(ext *geom.Extent, buf uint) (Tile, error) {
+	if ext == nil || ext.XSpan() <= 0 || ext.YSpan() <= 0 {
+		return nil, errors.New("extent must have a width and height")
+	}
+	return &extentTile{
+		ext:    ext.Clone(),
+		buffer: buf,
+	}, nil
+}
+
+func (et *extentTile) ZXY() (uint, uint, uint) {
+	for z := uint(tegola.MaxZ); z > 0; z-- {
+		n := int(1) << z
+		res := slippy.WebMercatorMax * 2 / float64(n)
+
+		// the max edges are exclusive, so an extent ending on a tile
+		// boundary is not considered to be in the next tile
+		minx := clampInt(int(math.Floor((et.ext.MinX()+slippy.WebMercatorMax)/res)), 0, n-1)
+		maxx := clampInt(int(math.Ceil((et.ext.MaxX()+slippy.WebMercatorMax)/res))-1, 0, n-1)
+		miny := clampInt(int(math.Floor((slippy.WebMercatorMax-et.ext.MaxY())/res)), 0, n-1)
+		maxy := clampInt(int(math.Ceil((slippy.WebMercatorMax-et.ext.MinY())/res))-1, 0, n-1)
+
+		if minx == maxx && miny == maxy {
+			return z, uint(minx), uint(miny)
+		}
+	}
+	return 0, 0, 0
+}
+
+func (et *extentTile) Extent() (*geom.Extent, uint64) {
+	return et.ext.Clone(), tegola.WebMercator
+}
+
+func (et *extentTile) BufferedExtent() (*geom.Extent, uint64) {
+	return et.ext.ExpandBy(et.ext.XSpan() / slippy.MvtTileDim * float64(et.buffer)), tegola.WebMercator
+}
+
+// Resolution returns the ground resolution, in meters per pixel, at the
+// extent's center latitude, with the extent's width being TileSize pixels.
+func (et *extentTile) Resolution() float64 {
+	centerY := (et.ext.MinY() + et.ext.MaxY()) / 2
+	lat := math.Atan(math.Sinh(centerY / EarthRadius))
+	return math.Cos(lat) * et.ext.XSpan() / TileSize
+}
@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/slippy"
+	"github.com/go-spatial/tegola"
+)
+
+// fractionalTile is a tile of the slippy tile grid whose extent is scaled
+// about its center to a fractional zoom
+type fractionalTile struct {
+	tile_t
+	// scale is 2^(zoom - base zoom), in [1,2)
+	scale float64
+}
+
+// NewFractionalTile returns a Tile for the fractional zoom z, i.e. 14.5 for
+// smooth zoom animations. This is experimental.
+//
+// The tile is the x, y tile of the base integer zoom, floor(z), and ZXY
+// returns the base zoom, so providers query and build features for the base
+// zoom. Only the extent is scaled: Extent and BufferedExtent are the base
+// tile's extent shrunk about its center to the size of a tile at z, and
+// Resolution is scaled to match. The buffer is in MVT extent units of the
+// scaled extent. z is clamped to [0, tegola.MaxZ].
+func NewFractionalTile(z float64, x, y uint, buf, srid uint) Tile {
+	z = math.Max(0, math.Min(z, tegola.MaxZ))
+	base := math.Floor(z)
+	return &fractionalTile{
+		tile_t: tile_t{
+			Tile: slippy.Tile{
+				Z: uint(base),
+				X: x,
+				Y: y,
+			},
+			buffer: buf,
+		},
+		scale: math.Exp2(z - base),
+	}
+}
+
+func (tile *fractionalTile) Extent() (*geom.Extent, uint64) {
+	ext := tile.Extent3857()
+	cx, cy := (ext.MinX()+ext.MaxX())/2, (ext.MinY()+ext.MaxY())/2
+	hw, hh := ext.XSpan()/2/tile.scale, ext.YSpan()/2/tile.scale
+	return &geom.Extent{cx - hw, cy - hh, cx + hw, cy + hh}, tegola.WebMercator
+}
+
+func (tile *fractionalTile) BufferedExtent() (*geom.Extent, uint64) {
+	ext, srid := tile.Extent()
+	return ext.ExpandBy(ext.XSpan() / slippy.MvtTileDim * float64(tile.buffer)), srid
+}
+
+func (tile *fractionalTile) Resolution() float64 {
+	return tile.tile_t.Resolution() / tile.scale
+}
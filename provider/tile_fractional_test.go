@@ -0,0 +1,74 @@
+package provider_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestNewFractionalTile(t *testing.T) {
+	type tcase struct {
+		z     float64
+		zxy   [3]uint
+		scale float64
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		tile := provider.NewFractionalTile(tc.z, 1, 1, 64, 3857)
+		base := provider.NewTile(tc.zxy[0], 1, 1, 64, 3857)
+
+		z, x, y := tile.ZXY()
+		if got := [3]uint{z, x, y}; got != tc.zxy {
+			t.Errorf("zxy, expected %v got %v", tc.zxy, got)
+		}
+
+		ext, srid := tile.Extent()
+		bext, _ := base.Extent()
+		if srid != 3857 {
+			t.Errorf("srid, expected 3857 got %v", srid)
+		}
+		if got, expected := ext.XSpan(), bext.XSpan()/tc.scale; math.Abs(got-expected) > 1e-6 {
+			t.Errorf("width, expected %v got %v", expected, got)
+		}
+		// the extent is scaled about the base tile's center
+		if got, expected := ext.MinX()+ext.MaxX(), bext.MinX()+bext.MaxX(); math.Abs(got-expected) > 1e-6 {
+			t.Errorf("center x, expected %v got %v", expected/2, got/2)
+		}
+		if got, expected := ext.MinY()+ext.MaxY(), bext.MinY()+bext.MaxY(); math.Abs(got-expected) > 1e-6 {
+			t.Errorf("center y, expected %v got %v", expected/2, got/2)
+		}
+
+		// the buffer is in units of the scaled extent
+		buffered, _ := tile.BufferedExtent()
+		if got, expected := buffered.XSpan(), ext.XSpan()*(1+2*64.0/4096); math.Abs(got-expected) > 1e-6 {
+			t.Errorf("buffered width, expected %v got %v", expected, got)
+		}
+		if got, expected := tile.Resolution(), base.Resolution()/tc.scale; math.Abs(got-expected) > 1e-9 {
+			t.Errorf("resolution, expected %v got %v", expected, got)
+		}
+	}
+
+	tests := map[string]tcase{
+		"integer": {
+			z:     14,
+			zxy:   [3]uint{14, 1, 1},
+			scale: 1,
+		},
+		"half": {
+			z:     14.5,
+			zxy:   [3]uint{14, 1, 1},
+			scale: math.Sqrt2,
+		},
+		"clamped": {
+			z:     -1,
+			zxy:   [3]uint{0, 1, 1},
+			scale: 1,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
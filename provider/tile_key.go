@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-spatial/tegola"
+)
+
+// TileKey returns the canonical "z/x/y" string of the tile, to be used for
+// logging, cache keys and metrics labels. Use ParseTileKey to parse it.
+func TileKey(t Tile) string {
+	z, x, y := t.ZXY()
+	return fmt.Sprintf("%v/%v/%v", z, x, y)
+}
+
+// TileKeyBuffered returns the canonical "z/x/y@buffer" string of the tile,
+// for when tiles with different buffers need different keys.
+func TileKeyBuffered(t Tile) string {
+	return fmt.Sprintf("%v@%v", TileKey(t), tileBuffer(t))
+}
+
+// ParseTileKey parses a key returned by TileKey or TileKeyBuffered into a
+// tile with the srid. If the key has a buffer it is used, otherwise the tile
+// is built with buf.
+func ParseTileKey(key string, buf, srid uint) (Tile, error) {
+	zxy := key
+	if at := strings.LastIndex(key, "@"); at != -1 {
+		zxy = key[:at]
+		b, err := strconv.ParseUint(key[at+1:], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("parse tile key (%v): invalid buffer value (%v)", key, key[at+1:])
+		}
+		buf = uint(b)
+	}
+
+	parts := strings.Split(zxy, "/")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("parse tile key (%v): expected z/x/y or z/x/y@buffer", key)
+	}
+
+	z, err := parseTileCoord("Z", parts[0], tegola.MaxZ)
+	if err != nil {
+		return nil, fmt.Errorf("parse tile key (%v): %w", key, err)
+	}
+
+	maxXY := uint64(1)<<z - 1
+
+	x, err := parseTileCoord("X", parts[1], maxXY)
+	if err != nil {
+		return nil, fmt.Errorf("parse tile key (%v): %w", key, err)
+	}
+
+	y, err := parseTileCoord("Y", parts[2], maxXY)
+	if err != nil {
+		return nil, fmt.Errorf("parse tile key (%v): %w", key, err)
+	}
+
+	return NewTile(uint(z), uint(x), uint(y), buf, srid), nil
+}
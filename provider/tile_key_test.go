@@ -0,0 +1,79 @@
+package provider_test
+
+import (
+	"testing"
+
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestTileKey(t *testing.T) {
+	type tcase struct {
+		tile     provider.Tile
+		key      string
+		buffered string
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		if key := provider.TileKey(tc.tile); key != tc.key {
+			t.Errorf("key, expected %v got %v", tc.key, key)
+		}
+		if key := provider.TileKeyBuffered(tc.tile); key != tc.buffered {
+			t.Errorf("buffered key, expected %v got %v", tc.buffered, key)
+		}
+
+		// both keys parse back to the same tile
+		for _, key := range []string{tc.key, tc.buffered} {
+			got, err := provider.ParseTileKey(key, 64, 3857)
+			if err != nil {
+				t.Fatalf("parse %v error, expected nil got %v", key, err)
+			}
+			if provider.TileKeyBuffered(got) != tc.buffered {
+				t.Errorf("parse %v, expected %v got %v", key, tc.buffered, provider.TileKeyBuffered(got))
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"z0": {
+			tile:     provider.NewTile(0, 0, 0, 64, 3857),
+			key:      "0/0/0",
+			buffered: "0/0/0@64",
+		},
+		"z14": {
+			tile:     provider.NewTile(14, 8192, 5460, 64, 3857),
+			key:      "14/8192/5460",
+			buffered: "14/8192/5460@64",
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
+
+func TestParseTileKeyErrors(t *testing.T) {
+	for _, key := range []string{
+		"",
+		"1/2",
+		"1-0-0",
+		"1/0/0/0",
+		"1/2/0",
+		"23/0/0",
+		"a/0/0",
+		"1/0/0@",
+		"1/0/0@-1",
+	} {
+		if _, err := provider.ParseTileKey(key, 0, 3857); err == nil {
+			t.Errorf("parse %q error, expected error got nil", key)
+		}
+	}
+
+	tile, err := provider.ParseTileKey("3/2/1@16", 64, 3857)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if key := provider.TileKeyBuffered(tile); key != "3/2/1@16" {
+		t.Errorf("buffer from key, expected 3/2/1@16 got %v", key)
+	}
+}
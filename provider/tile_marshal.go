@@ -0,0 +1,87 @@
+package provider
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/go-spatial/geom/slippy"
+	"github.com/go-spatial/tegola"
+)
+
+// tileMarshalVersion is the version of the format written by MarshalTile
+const tileMarshalVersion = 1
+
+// MarshalTile encodes the tile's z, x, y, buffer and SRID into a compact
+// binary form, suitable for passing tiles between processes. Use UnmarshalTile
+// to decode it.
+//
+// The encoding is a version byte followed by each value as a uvarint. Later
+// versions will only append values, so UnmarshalTile ignores any values it
+// does not know about.
+func MarshalTile(t Tile) []byte {
+	z, x, y := t.ZXY()
+	_, srid := t.Extent()
+
+	b := make([]byte, 1, 1+5*binary.MaxVarintLen64)
+	b[0] = tileMarshalVersion
+	var scratch [binary.MaxVarintLen64]byte
+	for _, v := range [...]uint64{uint64(z), uint64(x), uint64(y), uint64(tileBuffer(t)), srid} {
+		n := binary.PutUvarint(scratch[:], v)
+		b = append(b, scratch[:n]...)
+	}
+	return b
+}
+
+// UnmarshalTile decodes a tile encoded by MarshalTile
+func UnmarshalTile(b []byte) (Tile, error) {
+	if len(b) == 0 {
+		return nil, errors.New("unmarshal tile: empty")
+	}
+	if b[0] == 0 {
+		return nil, fmt.Errorf("unmarshal tile: invalid version (%v)", b[0])
+	}
+
+	var vals [5]uint64
+	buf := b[1:]
+	for i := range vals {
+		v, n := binary.Uvarint(buf)
+		if n <= 0 {
+			return nil, fmt.Errorf("unmarshal tile: truncated or invalid value at index %v", i)
+		}
+		vals[i], buf = v, buf[n:]
+	}
+
+	z, x, y, buffer, srid := vals[0], vals[1], vals[2], vals[3], vals[4]
+	if z > tegola.MaxZ {
+		return nil, fmt.Errorf("unmarshal tile: zoom (%v) is greater than %v", z, tegola.MaxZ)
+	}
+	if max := uint64(1) << z; x >= max || y >= max {
+		return nil, fmt.Errorf("unmarshal tile: x (%v) or y (%v) out of range for zoom (%v)", x, y, z)
+	}
+
+	return NewTile(uint(z), uint(x), uint(y), uint(buffer), uint(srid)), nil
+}
+
+// tileBuffer returns the tile's buffer in pixels. Tiles not created by
+// NewTile, NewTileFromExtent or NewFractionalTile have their buffer derived
+// from their buffered extent.
+func tileBuffer(t Tile) uint {
+	switch tt := t.(type) {
+	case *tile_t:
+		return tt.buffer
+	case *extentTile:
+		return tt.buffer
+	case *fractionalTile:
+		return tt.buffer
+	}
+
+	z, _, _ := t.ZXY()
+	ext, _ := t.Extent()
+	bext, _ := t.BufferedExtent()
+	if ext == nil || bext == nil {
+		return 0
+	}
+	return uint(math.Round((bext.MaxX() - ext.MaxX()) / slippy.Pixels2Webs(z, 1)))
+}
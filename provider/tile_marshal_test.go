@@ -0,0 +1,112 @@
+package provider_test
+
+import (
+	"testing"
+
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestMarshalTile(t *testing.T) {
+	type tcase struct {
+		tile provider.Tile
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		b := provider.MarshalTile(tc.tile)
+
+		got, err := provider.UnmarshalTile(b)
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+
+		z, x, y := tc.tile.ZXY()
+		gz, gx, gy := got.ZXY()
+		if gz != z || gx != x || gy != y {
+			t.Errorf("zxy, expected %v/%v/%v got %v/%v/%v", z, x, y, gz, gx, gy)
+		}
+
+		ext, srid := tc.tile.BufferedExtent()
+		gext, gsrid := got.BufferedExtent()
+		if gsrid != srid {
+			t.Errorf("srid, expected %v got %v", srid, gsrid)
+		}
+		if *gext != *ext {
+			t.Errorf("buffered extent, expected %v got %v", ext, gext)
+		}
+
+		// re-encoding must be stable
+		if b2 := provider.MarshalTile(got); string(b2) != string(b) {
+			t.Errorf("re-marshal, expected %v got %v", b, b2)
+		}
+	}
+
+	tests := map[string]tcase{
+		"z0": {
+			tile: provider.NewTile(0, 0, 0, 0, 3857),
+		},
+		"buffered": {
+			tile: provider.NewTile(14, 8192, 5460, 64, 3857),
+		},
+		"max zoom": {
+			tile: provider.NewTile(22, 4194303, 4194303, 256, 3857),
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
+
+func TestUnmarshalTile(t *testing.T) {
+	type tcase struct {
+		b        []byte
+		expected [3]uint
+		err      bool
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		tile, err := provider.UnmarshalTile(tc.b)
+		if tc.err {
+			if err == nil {
+				t.Errorf("error, expected error got nil")
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		z, x, y := tile.ZXY()
+		if got := [3]uint{z, x, y}; got != tc.expected {
+			t.Errorf("zxy, expected %v got %v", tc.expected, got)
+		}
+	}
+
+	tests := map[string]tcase{
+		"future version with extra values": {
+			// version 2 with an additional trailing value
+			b:        []byte{2, 1, 1, 0, 64, 0x91, 0x1e, 7},
+			expected: [3]uint{1, 1, 0},
+		},
+		"empty": {
+			err: true,
+		},
+		"version 0": {
+			b:   []byte{0, 1, 1, 0, 64, 0x91, 0x1e},
+			err: true,
+		},
+		"truncated": {
+			b:   []byte{1, 1, 1},
+			err: true,
+		},
+		"out of range": {
+			b:   []byte{1, 1, 2, 0, 64, 0x91, 0x1e},
+			err: true,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
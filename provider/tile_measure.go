@@ -0,0 +1,23 @@
+package provider
+
+import (
+	"github.com/go-spatial/geom"
+)
+
+// TileCentroid returns the centroid of the tile's extent, excluding any
+// buffer, along with the SRID the centroid is in
+func TileCentroid(t Tile) (geom.Point, uint64) {
+	ext, srid := t.Extent()
+	return geom.Point{
+		(ext.MinX() + ext.MaxX()) / 2,
+		(ext.MinY() + ext.MaxY()) / 2,
+	}, srid
+}
+
+// TileArea returns the area of the tile's extent, excluding any buffer, in
+// the square units of the tile's SRID. For WebMercator tiles this is the
+// projected area, not the area on the ground.
+func TileArea(t Tile) float64 {
+	ext, _ := t.Extent()
+	return ext.Area()
+}
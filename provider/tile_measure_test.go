@@ -0,0 +1,59 @@
+package provider_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/slippy"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestTileCentroidArea(t *testing.T) {
+	type tcase struct {
+		tile     provider.Tile
+		centroid geom.Point
+		srid     uint64
+		area     float64
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		centroid, srid := provider.TileCentroid(tc.tile)
+		if math.Abs(centroid[0]-tc.centroid[0]) > 1e-6 || math.Abs(centroid[1]-tc.centroid[1]) > 1e-6 {
+			t.Errorf("centroid, expected %v got %v", tc.centroid, centroid)
+		}
+		if srid != tc.srid {
+			t.Errorf("srid, expected %v got %v", tc.srid, srid)
+		}
+		if area := provider.TileArea(tc.tile); math.Abs(area-tc.area) > tc.area*1e-9 {
+			t.Errorf("area, expected %v got %v", tc.area, area)
+		}
+	}
+
+	world := 2 * slippy.WebMercatorMax
+	tests := map[string]tcase{
+		"z0": {
+			tile:     provider.NewTile(0, 0, 0, 64, 3857),
+			centroid: geom.Point{0, 0},
+			srid:     3857,
+			area:     world * world,
+		},
+		"z1 top left": {
+			tile:     provider.NewTile(1, 0, 0, 64, 3857),
+			centroid: geom.Point{-world / 4, world / 4},
+			srid:     3857,
+			area:     world * world / 4,
+		},
+		"z2 bottom right": {
+			tile:     provider.NewTile(2, 3, 3, 0, 3857),
+			centroid: geom.Point{world * 3 / 8, -world * 3 / 8},
+			srid:     3857,
+			area:     world * world / 16,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
@@ -0,0 +1,75 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-spatial/tegola"
+)
+
+// ParseTilePath parses a tile request path into its parts. The supported shapes are
+//
+//	/maps/:map_name/:z/:x/:y.:format
+//	/maps/:map_name/:layer_name/:z/:x/:y.:format
+//
+// any segments before "maps" (i.e. a URI prefix) are ignored. The returned
+// tile is built with the provided buffer and srid.
+func ParseTilePath(path string, buf, srid uint) (mapName string, layer string, t Tile, format string, err error) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	var zxy []string
+	switch {
+	case len(parts) >= 6 && parts[len(parts)-6] == "maps":
+		mapName, layer = parts[len(parts)-5], parts[len(parts)-4]
+		zxy = parts[len(parts)-3:]
+	case len(parts) >= 5 && parts[len(parts)-5] == "maps":
+		mapName = parts[len(parts)-4]
+		zxy = parts[len(parts)-3:]
+	default:
+		return "", "", nil, "", ErrInvalidTilePath{Path: path, Reason: "expected /maps/:map_name[/:layer_name]/:z/:x/:y.:format"}
+	}
+
+	if mapName == "" {
+		return "", "", nil, "", ErrInvalidTilePath{Path: path, Reason: "missing map name"}
+	}
+
+	// split the format off of the y value
+	dot := strings.LastIndex(zxy[2], ".")
+	if dot == -1 || dot == len(zxy[2])-1 {
+		return "", "", nil, "", ErrInvalidTilePath{Path: path, Reason: "missing extension"}
+	}
+	format = zxy[2][dot+1:]
+	zxy[2] = zxy[2][:dot]
+
+	z, err := parseTileCoord("Z", zxy[0], tegola.MaxZ)
+	if err != nil {
+		return "", "", nil, "", ErrInvalidTilePath{Path: path, Reason: err.Error()}
+	}
+
+	maxXY := uint64(1)<<z - 1
+
+	x, err := parseTileCoord("X", zxy[1], maxXY)
+	if err != nil {
+		return "", "", nil, "", ErrInvalidTilePath{Path: path, Reason: err.Error()}
+	}
+
+	y, err := parseTileCoord("Y", zxy[2], maxXY)
+	if err != nil {
+		return "", "", nil, "", ErrInvalidTilePath{Path: path, Reason: err.Error()}
+	}
+
+	return mapName, layer, NewTile(uint(z), uint(x), uint(y), buf, srid), format, nil
+}
+
+// parseTileCoord parses a single tile coordinate, checking it is in the range [0,max]
+func parseTileCoord(name, val string, max uint64) (uint64, error) {
+	v, err := strconv.ParseUint(val, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %v value (%v)", name, val)
+	}
+	if v > max {
+		return 0, fmt.Errorf("%v value (%v) out of range [0,%v]", name, val, max)
+	}
+	return v, nil
+}
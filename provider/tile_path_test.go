@@ -0,0 +1,95 @@
+package provider_test
+
+import (
+	"testing"
+
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestParseTilePath(t *testing.T) {
+	type tcase struct {
+		path    string
+		mapName string
+		layer   string
+		zxy     [3]uint
+		format  string
+		err     error
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		mapName, layer, tile, format, err := provider.ParseTilePath(tc.path, 64, 3857)
+		if tc.err != nil {
+			if err == nil || err.Error() != tc.err.Error() {
+				t.Errorf("error, expected %v got %v", tc.err, err)
+			}
+			return
+		}
+		if err != nil {
+			t.Errorf("error, expected nil got %v", err)
+			return
+		}
+		if mapName != tc.mapName {
+			t.Errorf("map name, expected %v got %v", tc.mapName, mapName)
+		}
+		if layer != tc.layer {
+			t.Errorf("layer, expected %v got %v", tc.layer, layer)
+		}
+		if format != tc.format {
+			t.Errorf("format, expected %v got %v", tc.format, format)
+		}
+		z, x, y := tile.ZXY()
+		if [3]uint{z, x, y} != tc.zxy {
+			t.Errorf("zxy, expected %v got %v", tc.zxy, [3]uint{z, x, y})
+		}
+	}
+
+	tests := map[string]tcase{
+		"map": {
+			path:    "/maps/osm/1/0/1.pbf",
+			mapName: "osm",
+			zxy:     [3]uint{1, 0, 1},
+			format:  "pbf",
+		},
+		"map layer": {
+			path:    "/maps/osm/roads/14/8192/5461.pbf",
+			mapName: "osm",
+			layer:   "roads",
+			zxy:     [3]uint{14, 8192, 5461},
+			format:  "pbf",
+		},
+		"uri prefix": {
+			path:    "/tegola/maps/osm/0/0/0.mvt",
+			mapName: "osm",
+			zxy:     [3]uint{0, 0, 0},
+			format:  "mvt",
+		},
+		"missing extension": {
+			path: "/maps/osm/1/0/1",
+			err:  provider.ErrInvalidTilePath{Path: "/maps/osm/1/0/1", Reason: "missing extension"},
+		},
+		"non numeric x": {
+			path: "/maps/osm/1/a/1.pbf",
+			err:  provider.ErrInvalidTilePath{Path: "/maps/osm/1/a/1.pbf", Reason: "invalid X value (a)"},
+		},
+		"z out of range": {
+			path: "/maps/osm/23/0/0.pbf",
+			err:  provider.ErrInvalidTilePath{Path: "/maps/osm/23/0/0.pbf", Reason: "Z value (23) out of range [0,22]"},
+		},
+		"y out of range": {
+			path: "/maps/osm/1/0/2.pbf",
+			err:  provider.ErrInvalidTilePath{Path: "/maps/osm/1/0/2.pbf", Reason: "Y value (2) out of range [0,1]"},
+		},
+		"not a map path": {
+			path: "/capabilities/osm.json",
+			err: provider.ErrInvalidTilePath{
+				Path:   "/capabilities/osm.json",
+				Reason: "expected /maps/:map_name[/:layer_name]/:z/:x/:y.:format",
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
@@ -0,0 +1,159 @@
+package provider
+
+import (
+	"context"
+	"math/bits"
+	"sync"
+
+	vectorTile "github.com/go-spatial/geom/encoding/mvt/vector_tile"
+	"github.com/golang/protobuf/proto"
+)
+
+// TileSizeSink receives the encoded size of each layer of the tiles encoded
+// by an MVTTiler wrapped with WithTileSizeMetrics, i.e. to export them as a
+// histogram to a metrics system. Implementations must be safe for concurrent
+// use. TileSizeHistogram is an in memory implementation.
+type TileSizeSink interface {
+	ObserveTileSize(layer string, z uint, size int)
+}
+
+// WithTileSizeMetrics wraps the MVTTiler so the encoded size, in bytes, of
+// each layer of every tile returned by MVTForLayers is recorded in sink,
+// keyed by the layer's MVT name and the tile's zoom. A tile of one layer is
+// recorded as its full length; the tiles of several layers are decoded so
+// the size of each layer's message within the tile is recorded. Tiles are
+// returned unchanged, and failing requests are not recorded.
+func WithTileSizeMetrics(mt MVTTiler, sink TileSizeSink) MVTTiler {
+	if sink == nil {
+		return mt
+	}
+	return &tileSizeTiler{
+		MVTTiler: mt,
+		sink:     sink,
+	}
+}
+
+type tileSizeTiler struct {
+	MVTTiler
+	sink TileSizeSink
+}
+
+func (tst *tileSizeTiler) MVTForLayers(ctx context.Context, t Tile, layers []Layer) ([]byte, error) {
+	b, err := tst.MVTTiler.MVTForLayers(ctx, t, layers)
+	if err != nil {
+		return b, err
+	}
+
+	z, _, _ := t.ZXY()
+	if len(layers) == 1 {
+		name := layers[0].MVTName
+		if name == "" {
+			name = layers[0].Name
+		}
+		tst.sink.ObserveTileSize(name, z, len(b))
+		return b, nil
+	}
+
+	var vtile vectorTile.Tile
+	if err := proto.Unmarshal(b, &vtile); err != nil {
+		// the tile is still returned, it is only not measured
+		return b, nil
+	}
+	for _, l := range vtile.Layers {
+		tst.sink.ObserveTileSize(l.GetName(), z, proto.Size(l))
+	}
+	return b, nil
+}
+
+// tileSizeBuckets is the number of buckets of a TileSizeHistogram, the last
+// holding sizes of 1<<(tileSizeBuckets-2) bytes (8MB) or more
+const tileSizeBuckets = 25
+
+type tileSizeKey struct {
+	layer string
+	z     uint
+}
+
+// TileSizeHistogram is a TileSizeSink which counts tile sizes into buckets
+// of powers of two per layer and zoom, for estimating p50 / p99 tile sizes.
+// The zero value is ready to use.
+type TileSizeHistogram struct {
+	mu     sync.Mutex
+	counts map[tileSizeKey]*[tileSizeBuckets]uint64
+}
+
+// ObserveTileSize adheres to the TileSizeSink interface
+func (h *TileSizeHistogram) ObserveTileSize(layer string, z uint, size int) {
+	b := 0
+	if size > 0 {
+		b = bits.Len(uint(size - 1))
+	}
+	if b >= tileSizeBuckets {
+		b = tileSizeBuckets - 1
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.counts == nil {
+		h.counts = make(map[tileSizeKey]*[tileSizeBuckets]uint64)
+	}
+	key := tileSizeKey{layer: layer, z: z}
+	if h.counts[key] == nil {
+		h.counts[key] = new([tileSizeBuckets]uint64)
+	}
+	h.counts[key][b]++
+}
+
+// TileSizeBucket is the number of tiles whose size is at most UpperBound
+// bytes, and greater than the previous bucket's UpperBound
+type TileSizeBucket struct {
+	UpperBound int
+	Count      uint64
+}
+
+// Buckets returns the non empty buckets of the layer at the zoom, ordered by
+// size. The last bucket's UpperBound is -1 as it has no upper bound.
+func (h *TileSizeHistogram) Buckets(layer string, z uint) []TileSizeBucket {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	counts := h.counts[tileSizeKey{layer: layer, z: z}]
+	if counts == nil {
+		return nil
+	}
+	var buckets []TileSizeBucket
+	for i, c := range counts {
+		if c == 0 {
+			continue
+		}
+		upper := 1 << i
+		if i == tileSizeBuckets-1 {
+			upper = -1
+		}
+		buckets = append(buckets, TileSizeBucket{UpperBound: upper, Count: c})
+	}
+	return buckets
+}
+
+// Quantile returns the upper bound of the bucket holding the q quantile of
+// the tile sizes of the layer at the zoom, i.e. 0.99 for the p99 size. 0 is
+// returned if no tiles have been observed.
+func (h *TileSizeHistogram) Quantile(layer string, z uint, q float64) int {
+	buckets := h.Buckets(layer, z)
+	var total uint64
+	for _, b := range buckets {
+		total += b.Count
+	}
+	if total == 0 {
+		return 0
+	}
+
+	var seen uint64
+	for _, b := range buckets {
+		seen += b.Count
+		if float64(seen) >= q*float64(total) {
+			return b.UpperBound
+		}
+	}
+	return buckets[len(buckets)-1].UpperBound
+}
@@ -0,0 +1,76 @@
+package provider_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	vectorTile "github.com/go-spatial/geom/encoding/mvt/vector_tile"
+	"github.com/go-spatial/tegola/provider"
+	"github.com/golang/protobuf/proto"
+)
+
+func TestWithTileSizeMetrics(t *testing.T) {
+	water, buildings := sizedLayer("water", 100), sizedLayer("buildings", 1000)
+	b, err := proto.Marshal(&vectorTile.Tile{
+		Layers: []*vectorTile.Tile_Layer{water, buildings},
+	})
+	if err != nil {
+		t.Fatalf("marshal, expected nil got %v", err)
+	}
+
+	var hist provider.TileSizeHistogram
+	mt := provider.WithTileSizeMetrics(bytesMVTTiler{b: b}, &hist)
+
+	tile := provider.NewTile(5, 1, 1, 0, 3857)
+	got, err := mt.MVTForLayers(context.Background(), tile, []provider.Layer{{Name: "water"}, {Name: "buildings"}})
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if !reflect.DeepEqual(got, b) {
+		t.Errorf("tile, expected unchanged")
+	}
+
+	// each layer's size within the tile
+	if got, expected := hist.Buckets("water", 5), []provider.TileSizeBucket{{UpperBound: 128, Count: 1}}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("water buckets, expected %v got %v (size %v)", expected, got, proto.Size(water))
+	}
+	if got, expected := hist.Buckets("buildings", 5), []provider.TileSizeBucket{{UpperBound: 1024, Count: 1}}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("buildings buckets, expected %v got %v (size %v)", expected, got, proto.Size(buildings))
+	}
+
+	// a single layer is recorded as the length of the tile, under its MVT name
+	for i := 0; i < 99; i++ {
+		if _, err = mt.MVTForLayers(context.Background(), tile, []provider.Layer{{Name: "water_src", MVTName: "water"}}); err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+	}
+	if got, expected := hist.Buckets("water", 5), []provider.TileSizeBucket{{UpperBound: 128, Count: 1}, {UpperBound: 2048, Count: 99}}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("water buckets, expected %v got %v (size %v)", expected, got, len(b))
+	}
+
+	type tcase struct {
+		layer    string
+		z        uint
+		q        float64
+		expected int
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		if got := hist.Quantile(tc.layer, tc.z, tc.q); got != tc.expected {
+			t.Errorf("quantile, expected %v got %v", tc.expected, got)
+		}
+	}
+
+	tests := map[string]tcase{
+		"p1":         {layer: "water", z: 5, q: 0.01, expected: 128},
+		"p50":        {layer: "water", z: 5, q: 0.5, expected: 2048},
+		"p99":        {layer: "water", z: 5, q: 0.99, expected: 2048},
+		"other zoom": {layer: "water", z: 6, q: 0.5, expected: 0},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/go-spatial/tegola"
+)
+
+// ParentTile returns the ancestor of the tile levels zooms up, with the same
+// buffer and SRID. An error is returned if the ancestor would be above z0.
+func ParentTile(t Tile, levels uint) (Tile, error) {
+	z, x, y := t.ZXY()
+	if levels > z {
+		return nil, fmt.Errorf("tile %v/%v/%v has no parent %v levels up", z, x, y, levels)
+	}
+	_, srid := t.Extent()
+	return NewTile(z-levels, x>>levels, y>>levels, tileBuffer(t), uint(srid)), nil
+}
+
+// ChildTiles returns the four tiles one zoom down covering the tile, with the
+// same buffer and SRID. They are ordered top left, top right, bottom left and
+// bottom right.
+func ChildTiles(t Tile) []Tile {
+	z, x, y := t.ZXY()
+	_, srid := t.Extent()
+	buf := tileBuffer(t)
+	return []Tile{
+		NewTile(z+1, x*2, y*2, buf, uint(srid)),
+		NewTile(z+1, x*2+1, y*2, buf, uint(srid)),
+		NewTile(z+1, x*2, y*2+1, buf, uint(srid)),
+		NewTile(z+1, x*2+1, y*2+1, buf, uint(srid)),
+	}
+}
+
+// Descendants returns the tiles below the tile, from one zoom down to
+// targetZoom inclusive, with the same buffer and SRID. They are ordered by
+// zoom, then by row and column. The number of tiles grows fourfold with each
+// zoom, so a z10 tile has 4 descendants at z11 and 16 at z12. An error is
+// returned if targetZoom is above the tile's zoom or beyond tegola.MaxZ.
+func Descendants(t Tile, targetZoom uint) ([]Tile, error) {
+	z, x, y := t.ZXY()
+	if targetZoom < z {
+		return nil, fmt.Errorf("target zoom (%v) is above tile %v/%v/%v", targetZoom, z, x, y)
+	}
+	if targetZoom > tegola.MaxZ {
+		return nil, fmt.Errorf("target zoom (%v) is greater than %v", targetZoom, tegola.MaxZ)
+	}
+
+	var (
+		_, srid = t.Extent()
+		buf     = tileBuffer(t)
+		tiles   []Tile
+	)
+	for dz := uint(1); z+dz <= targetZoom; dz++ {
+		// the descendants dz levels down are a square of 2^dz tiles a side
+		n := uint(1) << dz
+		for ty := y << dz; ty < y<<dz+n; ty++ {
+			for tx := x << dz; tx < x<<dz+n; tx++ {
+				tiles = append(tiles, NewTile(z+dz, tx, ty, buf, uint(srid)))
+			}
+		}
+	}
+	return tiles, nil
+}
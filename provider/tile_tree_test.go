@@ -0,0 +1,152 @@
+package provider_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestParentTile(t *testing.T) {
+	type tcase struct {
+		tile     provider.Tile
+		levels   uint
+		expected [3]uint
+		err      bool
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		parent, err := provider.ParentTile(tc.tile, tc.levels)
+		if tc.err {
+			if err == nil {
+				t.Errorf("error, expected error got nil")
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+
+		z, x, y := parent.ZXY()
+		if got := [3]uint{z, x, y}; got != tc.expected {
+			t.Errorf("parent, expected %v got %v", tc.expected, got)
+		}
+		// the buffer is kept
+		if got, expected := provider.MarshalTile(parent), provider.MarshalTile(provider.NewTile(z, x, y, 64, 3857)); !reflect.DeepEqual(got, expected) {
+			t.Errorf("parent buffer, expected %v got %v", expected, got)
+		}
+	}
+
+	tests := map[string]tcase{
+		"one level": {
+			tile:     provider.NewTile(15, 16385, 10923, 64, 3857),
+			levels:   1,
+			expected: [3]uint{14, 8192, 5461},
+		},
+		"to z0": {
+			tile:     provider.NewTile(3, 7, 5, 64, 3857),
+			levels:   3,
+			expected: [3]uint{0, 0, 0},
+		},
+		"zero levels": {
+			tile:     provider.NewTile(3, 7, 5, 64, 3857),
+			expected: [3]uint{3, 7, 5},
+		},
+		"above z0": {
+			tile:   provider.NewTile(3, 7, 5, 64, 3857),
+			levels: 4,
+			err:    true,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
+
+func TestChildTiles(t *testing.T) {
+	tile := provider.NewTile(1, 1, 0, 0, 3857)
+	expected := [][3]uint{{2, 2, 0}, {2, 2, 1}, {2, 3, 0}, {2, 3, 1}}
+	if got := zxys(provider.ChildTiles(tile)); !reflect.DeepEqual(got, expected) {
+		t.Errorf("children, expected %v got %v", expected, got)
+	}
+
+	for _, child := range provider.ChildTiles(tile) {
+		parent, err := provider.ParentTile(child, 1)
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+		if z, x, y := parent.ZXY(); z != 1 || x != 1 || y != 0 {
+			t.Errorf("child parent, expected 1/1/0 got %v/%v/%v", z, x, y)
+		}
+	}
+}
+
+func TestDescendants(t *testing.T) {
+	type tcase struct {
+		tile       provider.Tile
+		targetZoom uint
+		counts     map[uint]int
+		err        bool
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		tiles, err := provider.Descendants(tc.tile, tc.targetZoom)
+		if tc.err {
+			if err == nil {
+				t.Errorf("error, expected error got nil")
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+
+		counts := make(map[uint]int)
+		tz, tx, ty := tc.tile.ZXY()
+		for _, tile := range tiles {
+			z, x, y := tile.ZXY()
+			counts[z]++
+
+			// every descendant is under the tile
+			parent, err := provider.ParentTile(tile, z-tz)
+			if err != nil {
+				t.Fatalf("error, expected nil got %v", err)
+			}
+			if pz, px, py := parent.ZXY(); pz != tz || px != tx || py != ty {
+				t.Errorf("parent, expected %v/%v/%v got %v/%v/%v", tz, tx, ty, pz, px, py)
+			}
+			// the buffer is kept
+			if got, expected := provider.MarshalTile(tile), provider.MarshalTile(provider.NewTile(z, x, y, 64, 3857)); !reflect.DeepEqual(got, expected) {
+				t.Errorf("descendant buffer, expected %v got %v", expected, got)
+			}
+		}
+		if !reflect.DeepEqual(counts, tc.counts) {
+			t.Errorf("counts, expected %v got %v", tc.counts, counts)
+		}
+	}
+
+	tests := map[string]tcase{
+		"z10 to z12": {
+			tile:       provider.NewTile(10, 163, 395, 64, 3857),
+			targetZoom: 12,
+			counts:     map[uint]int{11: 4, 12: 16},
+		},
+		"same zoom": {
+			tile:       provider.NewTile(10, 163, 395, 64, 3857),
+			targetZoom: 10,
+			counts:     map[uint]int{},
+		},
+		"above tile": {
+			tile:       provider.NewTile(10, 163, 395, 64, 3857),
+			targetZoom: 9,
+			err:        true,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
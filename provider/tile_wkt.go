@@ -0,0 +1,235 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola"
+	"github.com/go-spatial/tegola/basic"
+)
+
+// TileFromWKT returns a Tile whose extent is the envelope of the WKT geometry,
+// in the srid, as with NewTileFromExtent. A POLYGON describing a box gives a
+// tile with exactly that extent. The PostGIS BOX(minx miny, maxx maxy) form
+// and an EWKT SRID=n; prefix, which overrides srid, are also accepted.
+// The srid must be WebMercator or WGS84.
+//
+// Only the coordinates of the geometry are used, so the geometry is not
+// otherwise validated (i.e. that a polygon's rings are closed).
+func TileFromWKT(wkt string, srid, buf uint) (Tile, error) {
+	text := strings.TrimSpace(wkt)
+	if upper := strings.ToUpper(text); strings.HasPrefix(upper, "SRID=") {
+		semi := strings.Index(text, ";")
+		if semi == -1 {
+			return nil, ErrInvalidWKT{WKT: wkt, Reason: "missing ; after SRID"}
+		}
+		s, err := strconv.ParseUint(strings.TrimSpace(text[5:semi]), 10, 32)
+		if err != nil {
+			return nil, ErrInvalidWKT{WKT: wkt, Reason: fmt.Sprintf("invalid SRID (%v)", text[5:semi])}
+		}
+		srid, text = uint(s), text[semi+1:]
+	}
+
+	p := wktParser{text: text}
+	if err := p.parseGeometry(); err != nil {
+		return nil, ErrInvalidWKT{WKT: wkt, Reason: err.Error()}
+	}
+	if tok := p.next(); tok != "" {
+		return nil, ErrInvalidWKT{WKT: wkt, Reason: fmt.Sprintf("unexpected %q after geometry", tok)}
+	}
+	if len(p.points) == 0 {
+		return nil, ErrInvalidWKT{WKT: wkt, Reason: "geometry is empty"}
+	}
+
+	var pts geom.Geometry = geom.MultiPoint(p.points)
+	if srid != tegola.WebMercator {
+		var err error
+		if pts, err = basic.ToWebMercator(uint64(srid), pts); err != nil {
+			return nil, err
+		}
+	}
+
+	ext, err := geom.NewExtentFromGeometry(pts)
+	if err != nil {
+		return nil, err
+	}
+	return NewTileFromExtent(ext, buf)
+}
+
+// wktParser collects the coordinates of a WKT geometry, checking the text is
+// well formed
+type wktParser struct {
+	text   string
+	pos    int
+	points [][2]float64
+}
+
+// wktDepth is the number of nested parentheses around the coordinates of
+// each geometry type
+var wktDepth = map[string]int{
+	"POINT":           1,
+	"LINESTRING":      1,
+	"POLYGON":         2,
+	"MULTIPOINT":      1,
+	"MULTILINESTRING": 2,
+	"MULTIPOLYGON":    3,
+	"BOX":             1,
+}
+
+// next returns the next token: a word or number, a parenthesis or a comma.
+// An empty string is returned at the end of the text.
+func (p *wktParser) next() string {
+	for p.pos < len(p.text) && strings.ContainsRune(" \t\r\n", rune(p.text[p.pos])) {
+		p.pos++
+	}
+	if p.pos == len(p.text) {
+		return ""
+	}
+
+	start := p.pos
+	if strings.ContainsRune("(),", rune(p.text[p.pos])) {
+		p.pos++
+		return p.text[start:p.pos]
+	}
+	for p.pos < len(p.text) && !strings.ContainsRune(" \t\r\n(),", rune(p.text[p.pos])) {
+		p.pos++
+	}
+	return p.text[start:p.pos]
+}
+
+// peek returns the next token without consuming it
+func (p *wktParser) peek() string {
+	pos := p.pos
+	tok := p.next()
+	p.pos = pos
+	return tok
+}
+
+func (p *wktParser) expect(tok string) error {
+	if got := p.next(); got != tok {
+		if got == "" {
+			return fmt.Errorf("expected %q got end of text", tok)
+		}
+		return fmt.Errorf("expected %q got %q", tok, got)
+	}
+	return nil
+}
+
+func (p *wktParser) parseGeometry() error {
+	typ := strings.ToUpper(p.next())
+	switch dim := strings.ToUpper(p.peek()); dim {
+	case "Z", "M", "ZM":
+		p.next()
+	}
+
+	if strings.ToUpper(p.peek()) == "EMPTY" {
+		p.next()
+		if _, ok := wktDepth[typ]; !ok && typ != "GEOMETRYCOLLECTION" {
+			return fmt.Errorf("unknown geometry type (%v)", typ)
+		}
+		return nil
+	}
+
+	if typ == "GEOMETRYCOLLECTION" {
+		if err := p.expect("("); err != nil {
+			return err
+		}
+		for {
+			if err := p.parseGeometry(); err != nil {
+				return err
+			}
+			if p.peek() != "," {
+				break
+			}
+			p.next()
+		}
+		return p.expect(")")
+	}
+
+	depth, ok := wktDepth[typ]
+	if !ok {
+		if typ == "" {
+			return fmt.Errorf("missing geometry type")
+		}
+		return fmt.Errorf("unknown geometry type (%v)", typ)
+	}
+
+	start := len(p.points)
+	// the points of a MULTIPOINT may or may not be in parentheses
+	if typ == "MULTIPOINT" && p.multiPointParens() {
+		depth = 2
+	}
+	if err := p.parseList(depth); err != nil {
+		return err
+	}
+
+	n := len(p.points) - start
+	switch {
+	case typ == "POINT" && n != 1:
+		return fmt.Errorf("POINT must have one coordinate, got %v", n)
+	case typ == "BOX" && n != 2:
+		return fmt.Errorf("BOX must have two coordinates, got %v", n)
+	}
+	return nil
+}
+
+// multiPointParens reports if the next MULTIPOINT is of the form ((x y), ...)
+func (p *wktParser) multiPointParens() bool {
+	pos := p.pos
+	defer func() { p.pos = pos }()
+	return p.next() == "(" && p.next() == "("
+}
+
+// parseList parses a parenthesized, comma separated list. At depth 1 the
+// items are coordinates, otherwise they are lists of depth-1.
+func (p *wktParser) parseList(depth int) error {
+	if err := p.expect("("); err != nil {
+		return err
+	}
+	for {
+		var err error
+		if depth == 1 {
+			err = p.parseCoordinate()
+		} else {
+			err = p.parseList(depth - 1)
+		}
+		if err != nil {
+			return err
+		}
+
+		switch tok := p.next(); tok {
+		case ",":
+			continue
+		case ")":
+			return nil
+		case "":
+			return fmt.Errorf("expected \",\" or \")\" got end of text")
+		default:
+			return fmt.Errorf("expected \",\" or \")\" got %q", tok)
+		}
+	}
+}
+
+// parseCoordinate parses 2 to 4 numbers, keeping x and y
+func (p *wktParser) parseCoordinate() error {
+	var vals []float64
+	for {
+		tok := p.peek()
+		if tok == "" || tok == "," || tok == ")" || tok == "(" {
+			break
+		}
+		p.next()
+		v, err := strconv.ParseFloat(tok, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number (%v)", tok)
+		}
+		vals = append(vals, v)
+	}
+	if len(vals) < 2 || len(vals) > 4 {
+		return fmt.Errorf("coordinate must have 2 to 4 values, got %v", len(vals))
+	}
+	p.points = append(p.points, [2]float64{vals[0], vals[1]})
+	return nil
+}
@@ -0,0 +1,133 @@
+package provider_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom/slippy"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestTileFromWKT(t *testing.T) {
+	type tcase struct {
+		wkt      string
+		srid     uint
+		buf      uint
+		expected [4]float64
+		zxy      [3]uint
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		tile, err := provider.TileFromWKT(tc.wkt, tc.srid, tc.buf)
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+
+		ext, srid := tile.Extent()
+		if srid != 3857 {
+			t.Errorf("srid, expected 3857 got %v", srid)
+		}
+		got := ext.Extent()
+		for i := range got {
+			if math.Abs(got[i]-tc.expected[i]) > 0.01 {
+				t.Errorf("extent, expected %v got %v", tc.expected, got)
+				break
+			}
+		}
+
+		if z, x, y := tile.ZXY(); [3]uint{z, x, y} != tc.zxy {
+			t.Errorf("zxy, expected %v got %v", tc.zxy, [3]uint{z, x, y})
+		}
+	}
+
+	const max = slippy.WebMercatorMax
+
+	tests := map[string]tcase{
+		"polygon box": {
+			wkt:      "POLYGON((1000 2000, 3000 2000, 3000 4000, 1000 4000, 1000 2000))",
+			srid:     3857,
+			expected: [4]float64{1000, 2000, 3000, 4000},
+			zxy:      [3]uint{13, 4096, 4095},
+		},
+		"box": {
+			wkt:      "BOX(1000 2000, 3000 4000)",
+			srid:     3857,
+			expected: [4]float64{1000, 2000, 3000, 4000},
+			zxy:      [3]uint{13, 4096, 4095},
+		},
+		"line envelope": {
+			wkt:      "LINESTRING Z (3000 2000 1, 1000 4000 2)",
+			srid:     3857,
+			expected: [4]float64{1000, 2000, 3000, 4000},
+			zxy:      [3]uint{13, 4096, 4095},
+		},
+		"collection envelope": {
+			wkt:      "GEOMETRYCOLLECTION(POINT(1000 2000), MULTIPOINT((3000 4000)), POLYGON EMPTY)",
+			srid:     3857,
+			expected: [4]float64{1000, 2000, 3000, 4000},
+			zxy:      [3]uint{13, 4096, 4095},
+		},
+		"wgs84 south east quarter": {
+			wkt:      "polygon((0 -85.0511287798, 180 -85.0511287798, 180 0, 0 0))",
+			srid:     4326,
+			expected: [4]float64{0, -max, max, 0},
+			zxy:      [3]uint{1, 1, 1},
+		},
+		"ewkt srid": {
+			wkt:      "SRID=4326;MULTIPOLYGON(((0 -85.0511287798, 180 -85.0511287798, 180 0, 0 0)))",
+			srid:     3857,
+			expected: [4]float64{0, -max, max, 0},
+			zxy:      [3]uint{1, 1, 1},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
+
+func TestTileFromWKTBuffer(t *testing.T) {
+	tile, err := provider.TileFromWKT("BOX(0 0, 4096 4096)", 3857, 64)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+
+	ext, _ := tile.BufferedExtent()
+	if expected := [4]float64{-64, -64, 4160, 4160}; ext.Extent() != expected {
+		t.Errorf("buffered extent, expected %v got %v", expected, ext.Extent())
+	}
+	if key := provider.TileKeyBuffered(tile); key != "13/4096/4095@64" {
+		t.Errorf("key, expected 13/4096/4095@64 got %v", key)
+	}
+}
+
+func TestTileFromWKTErrors(t *testing.T) {
+	for _, wkt := range []string{
+		"",
+		"POLYGON",
+		"POLYGON EMPTY",
+		"POLYGON((0 0, 1 0, 1 1)",
+		"POLYGON(0 0, 1 0, 1 1)",
+		"POLYGON((0 0, 1 a, 1 1))",
+		"POLYGON((0 0, 1, 1 1))",
+		"POLYGON((0 0, 1 0, 1 1))) extra",
+		"CIRCLE((0 0, 1 0))",
+		"POINT(0 0, 1 1)",
+		"BOX(0 0)",
+		"BOX(0 0, 0 10)",
+		"SRID=abc;POINT(0 0)",
+	} {
+		_, err := provider.TileFromWKT(wkt, 3857, 0)
+		if err == nil {
+			t.Errorf("wkt %q, expected error got nil", wkt)
+			continue
+		}
+	}
+
+	var werr provider.ErrInvalidWKT
+	if _, err := provider.TileFromWKT("LINESTRING(0 0,", 3857, 0); !errors.As(err, &werr) {
+		t.Errorf("error, expected ErrInvalidWKT got %v", err)
+	}
+}
@@ -0,0 +1,176 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola"
+	"github.com/go-spatial/tegola/basic"
+	"github.com/go-spatial/tegola/mapbox/tilejson"
+)
+
+// Extenter is implemented by providers which know the extent of their data,
+// i.e. from the extent of their tables, so clients can be pointed at it
+type Extenter interface {
+	// Extent returns the extent of the data of all layers, in the SRID
+	// returned, which must be WebMercator or WGS84
+	Extent() (ext *geom.Extent, srid uint64, err error)
+}
+
+// TileJSONOpts configures the TileJSON document built by TileJSON
+type TileJSONOpts struct {
+	// Tiles are the tile endpoints, i.e.
+	// "https://example.com/maps/osm/{z}/{x}/{y}.pbf". At least one is
+	// required.
+	Tiles []string
+	// Name, Description and Attribution are omitted when empty
+	Name        string
+	Description string
+	Attribution string
+	// MinZoom and MaxZoom are the zoom range of the tiles. A MaxZoom of 0
+	// defaults to tegola.MaxZ.
+	MinZoom uint
+	MaxZoom uint
+	// Bounds, in WGS84 as left, bottom, right, top, overrides the extent of
+	// the provider
+	Bounds *[4]float64
+	// Center, in WGS84 as longitude, latitude, zoom, overrides the center of
+	// the bounds at MinZoom
+	Center *[3]float64
+	// Version is the version of the tileset, defaults to "1.0.0"
+	Version string
+}
+
+// TileJSON returns a TileJSON document, see https://github.com/mapbox/tilejson-spec,
+// describing the tiles of the provider, from which clients, i.e. Mapbox GL,
+// bootstrap. The document has a vector layer for each layer returned by
+// Layers, with the zoom range of the opts.
+//
+// The bounds are the opts' Bounds if set, otherwise the extent of the data if
+// the Tiler implements Extenter, otherwise the whole world. Bounds are
+// clamped to the latitudes WebMercator covers.
+func TileJSON(t Tiler, opts TileJSONOpts) ([]byte, error) {
+	if len(opts.Tiles) == 0 {
+		return nil, fmt.Errorf("tilejson requires at least 1 tile endpoint")
+	}
+	if opts.MaxZoom == 0 {
+		opts.MaxZoom = tegola.MaxZ
+	}
+	if opts.MinZoom > opts.MaxZoom {
+		return nil, fmt.Errorf("tilejson min zoom (%v) is greater than max zoom (%v)", opts.MinZoom, opts.MaxZoom)
+	}
+	if opts.Version == "" {
+		opts.Version = "1.0.0"
+	}
+
+	layers, err := t.Layers()
+	if err != nil {
+		return nil, err
+	}
+
+	bounds, err := tileJSONBounds(t, opts.Bounds)
+	if err != nil {
+		return nil, err
+	}
+	center := [3]float64{
+		(bounds[0] + bounds[2]) / 2,
+		(bounds[1] + bounds[3]) / 2,
+		float64(opts.MinZoom),
+	}
+	if opts.Center != nil {
+		center = *opts.Center
+	}
+
+	optional := func(s string) *string {
+		if s == "" {
+			return nil
+		}
+		return &s
+	}
+	doc := tilejson.TileJSON{
+		Attribution:  optional(opts.Attribution),
+		Bounds:       bounds,
+		Center:       center,
+		Format:       "pbf",
+		MinZoom:      opts.MinZoom,
+		MaxZoom:      opts.MaxZoom,
+		Name:         optional(opts.Name),
+		Description:  optional(opts.Description),
+		Scheme:       tilejson.SchemeXYZ,
+		TileJSON:     tilejson.Version,
+		Tiles:        opts.Tiles,
+		Grids:        make([]string, 0),
+		Data:         make([]string, 0),
+		Version:      opts.Version,
+		VectorLayers: make([]tilejson.VectorLayer, 0, len(layers)),
+	}
+
+	for _, l := range layers {
+		layer := tilejson.VectorLayer{
+			Version: 2,
+			Extent:  tegola.DefaultExtent,
+			ID:      l.Name(),
+			Name:    l.Name(),
+			MinZoom: opts.MinZoom,
+			MaxZoom: opts.MaxZoom,
+			Tiles:   opts.Tiles,
+		}
+		switch l.GeomType().(type) {
+		case geom.Point, geom.MultiPoint:
+			layer.GeometryType = tilejson.GeomTypePoint
+		case geom.Line, geom.LineString, geom.MultiLineString:
+			layer.GeometryType = tilejson.GeomTypeLine
+		case geom.Polygon, geom.MultiPolygon:
+			layer.GeometryType = tilejson.GeomTypePolygon
+		default:
+			layer.GeometryType = tilejson.GeomTypeUnknown
+		}
+		doc.VectorLayers = append(doc.VectorLayers, layer)
+	}
+
+	return json.Marshal(doc)
+}
+
+// tileJSONBounds returns the bounds in WGS84, clamped to WebMercator's
+// latitudes
+func tileJSONBounds(t Tiler, override *[4]float64) ([4]float64, error) {
+	const maxLat = 85.0511287798066
+
+	bounds := [4]float64{-180, -maxLat, 180, maxLat}
+	switch {
+	case override != nil:
+		bounds = *override
+	default:
+		e, release, ok := acquireAs[Extenter](t)
+		if !ok {
+			break
+		}
+		ext, srid, err := e.Extent()
+		release()
+		if err != nil {
+			return bounds, err
+		}
+		if ext == nil {
+			break
+		}
+		switch srid {
+		case tegola.WGS84:
+			bounds = [4]float64{ext.MinX(), ext.MinY(), ext.MaxX(), ext.MaxY()}
+		case tegola.WebMercator:
+			g, err := basic.FromWebMercator(tegola.WGS84, geom.MultiPoint{{ext.MinX(), ext.MinY()}, {ext.MaxX(), ext.MaxY()}})
+			if err != nil {
+				return bounds, err
+			}
+			pts := g.(geom.MultiPoint)
+			bounds = [4]float64{pts[0][0], pts[0][1], pts[1][0], pts[1][1]}
+		default:
+			return bounds, fmt.Errorf("tilejson unable to transform extent from SRID (%v) to WGS84", srid)
+		}
+	}
+
+	bounds[0], bounds[2] = math.Max(bounds[0], -180), math.Min(bounds[2], 180)
+	bounds[1], bounds[3] = math.Max(bounds[1], -maxLat), math.Min(bounds[3], maxLat)
+	return bounds, nil
+}
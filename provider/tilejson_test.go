@@ -0,0 +1,187 @@
+package provider_test
+
+import (
+	"encoding/json"
+	"math"
+	"regexp"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+// extentTiler is a namedLayersTiler which knows the extent of its data
+type extentTiler struct {
+	namedLayersTiler
+	ext  *geom.Extent
+	srid uint64
+}
+
+func (et extentTiler) Extent() (*geom.Extent, uint64, error) { return et.ext, et.srid, nil }
+
+// validateTileJSON checks the document against the TileJSON 2.1.0 schema,
+// see https://github.com/mapbox/tilejson-spec/tree/master/2.1.0
+func validateTileJSON(t *testing.T, b []byte) map[string]interface{} {
+	t.Helper()
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		t.Fatalf("unmarshal error, expected nil got %v", err)
+	}
+
+	semver := regexp.MustCompile(`^\d+\.\d+\.\d+\w?[\w\d]*$`)
+	if v, ok := doc["tilejson"].(string); !ok || !semver.MatchString(v) {
+		t.Errorf("tilejson, expected a version got %v", doc["tilejson"])
+	}
+	tiles, ok := doc["tiles"].([]interface{})
+	if !ok || len(tiles) == 0 {
+		t.Errorf("tiles, expected at least 1 endpoint got %v", doc["tiles"])
+	}
+	for _, tile := range tiles {
+		if _, ok := tile.(string); !ok {
+			t.Errorf("tile, expected a string got %v", tile)
+		}
+	}
+	for _, key := range []string{"name", "description", "attribution", "version", "template", "legend"} {
+		switch doc[key].(type) {
+		case nil, string:
+		default:
+			t.Errorf("%v, expected a string or null got %v", key, doc[key])
+		}
+	}
+	for _, key := range []string{"grids", "data"} {
+		if _, ok := doc[key].([]interface{}); !ok {
+			t.Errorf("%v, expected an array got %v", key, doc[key])
+		}
+	}
+	if s := doc["scheme"]; s != "xyz" && s != "tms" {
+		t.Errorf("scheme, expected xyz or tms got %v", s)
+	}
+
+	zoom := func(key string) float64 {
+		z, ok := doc[key].(float64)
+		if !ok || z != math.Trunc(z) || z < 0 || z > 30 {
+			t.Errorf("%v, expected an integer between 0 and 30 got %v", key, doc[key])
+		}
+		return z
+	}
+	minZoom, maxZoom := zoom("minzoom"), zoom("maxzoom")
+	if minZoom > maxZoom {
+		t.Errorf("zoom, expected minzoom <= maxzoom got %v > %v", minZoom, maxZoom)
+	}
+
+	numbers := func(key string, n int) []float64 {
+		arr, ok := doc[key].([]interface{})
+		if !ok || len(arr) != n {
+			t.Fatalf("%v, expected %v numbers got %v", key, n, doc[key])
+		}
+		vals := make([]float64, n)
+		for i := range arr {
+			if vals[i], ok = arr[i].(float64); !ok {
+				t.Fatalf("%v, expected %v numbers got %v", key, n, doc[key])
+			}
+		}
+		return vals
+	}
+	bounds := numbers("bounds", 4)
+	if bounds[0] < -180 || bounds[2] > 180 || bounds[1] < -90 || bounds[3] > 90 || bounds[1] > bounds[3] {
+		t.Errorf("bounds, expected WGS84 left, bottom, right, top got %v", bounds)
+	}
+	center := numbers("center", 3)
+	if center[0] < bounds[0] || center[0] > bounds[2] || center[1] < bounds[1] || center[1] > bounds[3] {
+		t.Errorf("center, expected within %v got %v", bounds, center)
+	}
+	if center[2] != math.Trunc(center[2]) || center[2] < minZoom || center[2] > maxZoom {
+		t.Errorf("center zoom, expected an integer between %v and %v got %v", minZoom, maxZoom, center[2])
+	}
+	return doc
+}
+
+func TestTileJSON(t *testing.T) {
+	layers := namedLayersTiler{layersTiler{"roads": nil, "places": nil}}
+
+	type tcase struct {
+		tiler   provider.Tiler
+		opts    provider.TileJSONOpts
+		bounds  [4]float64
+		minZoom float64
+		maxZoom float64
+		err     bool
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		b, err := provider.TileJSON(tc.tiler, tc.opts)
+		if tc.err {
+			if err == nil {
+				t.Errorf("error, expected an error got nil")
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+
+		doc := validateTileJSON(t, b)
+		bounds := doc["bounds"].([]interface{})
+		for i := range tc.bounds {
+			if got := bounds[i].(float64); math.Abs(got-tc.bounds[i]) > 1e-6 {
+				t.Errorf("bounds, expected %v got %v", tc.bounds, bounds)
+				break
+			}
+		}
+		if doc["minzoom"] != tc.minZoom || doc["maxzoom"] != tc.maxZoom {
+			t.Errorf("zoom, expected %v-%v got %v-%v", tc.minZoom, tc.maxZoom, doc["minzoom"], doc["maxzoom"])
+		}
+
+		vls, _ := doc["vector_layers"].([]interface{})
+		if len(vls) != 2 {
+			t.Fatalf("vector layers, expected 2 got %v", len(vls))
+		}
+		for i, name := range []string{"places", "roads"} {
+			vl := vls[i].(map[string]interface{})
+			if vl["id"] != name || vl["geometry_type"] != "point" {
+				t.Errorf("vector layer %v, expected %v point layer got %v", i, name, vl)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"world": {
+			tiler:   layers,
+			opts:    provider.TileJSONOpts{Tiles: []string{"https://example.com/{z}/{x}/{y}.pbf"}, Name: "osm"},
+			bounds:  [4]float64{-180, -85.0511287798066, 180, 85.0511287798066},
+			maxZoom: 22,
+		},
+		"extent": {
+			tiler: extentTiler{
+				namedLayersTiler: layers,
+				ext:              geom.NewExtent([2]float64{0, 0}, [2]float64{20037508.342789244, 20037508.342789244}),
+				srid:             3857,
+			},
+			opts:    provider.TileJSONOpts{Tiles: []string{"https://example.com/{z}/{x}/{y}.pbf"}, MinZoom: 2, MaxZoom: 14},
+			bounds:  [4]float64{0, 0, 180, 85.0511287798066},
+			minZoom: 2,
+			maxZoom: 14,
+		},
+		"bounds override": {
+			tiler:   extentTiler{namedLayersTiler: layers, ext: geom.NewExtent([2]float64{0, 0}, [2]float64{1, 1}), srid: 4326},
+			opts:    provider.TileJSONOpts{Tiles: []string{"https://example.com/{z}/{x}/{y}.pbf"}, Bounds: &[4]float64{2.2, 48.8, 2.5, 48.9}},
+			bounds:  [4]float64{2.2, 48.8, 2.5, 48.9},
+			maxZoom: 22,
+		},
+		"no tiles": {
+			tiler: layers,
+			err:   true,
+		},
+		"inverted zooms": {
+			tiler: layers,
+			opts:  provider.TileJSONOpts{Tiles: []string{"https://example.com/{z}/{x}/{y}.pbf"}, MinZoom: 10, MaxZoom: 5},
+			err:   true,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
@@ -0,0 +1,317 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/go-spatial/geom"
+)
+
+// EncodeTopoJSON encodes the features of the layers for the tile as a
+// TopoJSON topology and writes it to w. Each layer is an object of the
+// topology, a GeometryCollection of the layer's features with their IDs and
+// tags as the id and properties members.
+//
+// Lines and polygon rings are cut where they meet, and the resulting arcs
+// are shared, so a boundary between two adjacent polygons is encoded once.
+// Computing the topology requires every feature of the tile, so all features
+// are held in memory, and is far more CPU intensive than EncodeStream.
+//
+// As with GeoJSON, coordinates are WGS84 and not quantized. WebMercator
+// geometries are reprojected, and geometries without an SRID are assumed to
+// be WGS84 already.
+func EncodeTopoJSON(ctx context.Context, t Tiler, layers []string, tile Tile, w io.Writer) error {
+	type layerFeatures struct {
+		name     string
+		features []Feature
+	}
+
+	var (
+		topo      = newTopology()
+		collected = make([]layerFeatures, len(layers))
+	)
+	for i, layer := range layers {
+		collected[i].name = layer
+		err := t.TileFeatures(ctx, layer, tile, func(f *Feature) error {
+			g, err := f.wgs84Geometry()
+			if err != nil {
+				return fmt.Errorf("layer (%v) %w", layer, err)
+			}
+			if g != nil && !geom.IsEmpty(g) {
+				if err = topo.addJunctions(g); err != nil {
+					return fmt.Errorf("layer (%v) feature %v: %w", layer, f.ID, err)
+				}
+			}
+			ff := *f
+			ff.Geometry = g
+			collected[i].features = append(collected[i].features, ff)
+			return nil
+		})
+		if err != nil && !errors.Is(err, ErrNoFeatures) {
+			return err
+		}
+	}
+
+	objects := make(map[string]*topoLayer, len(collected))
+	for _, lf := range collected {
+		tl := &topoLayer{
+			Type:       "GeometryCollection",
+			Geometries: make([]*topoObject, 0, len(lf.features)),
+		}
+		for i := range lf.features {
+			f := &lf.features[i]
+			obj, err := topo.object(f.Geometry)
+			if err != nil {
+				return fmt.Errorf("layer (%v) feature %v: %w", lf.name, f.ID, err)
+			}
+			obj.ID, obj.Properties = f.ID, f.Tags
+			tl.Geometries = append(tl.Geometries, obj)
+		}
+		objects[lf.name] = tl
+	}
+
+	return json.NewEncoder(w).Encode(struct {
+		Type    string                `json:"type"`
+		Objects map[string]*topoLayer `json:"objects"`
+		Arcs    [][][2]float64        `json:"arcs"`
+	}{
+		Type:    "Topology",
+		Objects: objects,
+		Arcs:    topo.arcs,
+	})
+}
+
+// topoLayer is the TopoJSON object of a layer
+type topoLayer struct {
+	Type       string        `json:"type"`
+	Geometries []*topoObject `json:"geometries"`
+}
+
+// topoObject is a TopoJSON geometry object. Arcs is used for lines and
+// polygons, Coordinates for points and Geometries for collections. A nil
+// Type encodes a null geometry.
+type topoObject struct {
+	Type        interface{}            `json:"type"`
+	ID          interface{}            `json:"id,omitempty"`
+	Properties  map[string]interface{} `json:"properties,omitempty"`
+	Arcs        interface{}            `json:"arcs,omitempty"`
+	Coordinates interface{}            `json:"coordinates,omitempty"`
+	Geometries  []*topoObject          `json:"geometries,omitempty"`
+}
+
+// topology cuts lines and rings into arcs at junctions, the points where
+// lines meet or diverge, and deduplicates the arcs
+type topology struct {
+	// neighbors is the pair of points first seen either side of a point
+	neighbors map[[2]float64][2][2]float64
+	junctions map[[2]float64]bool
+
+	arcs [][][2]float64
+	// arcIdx is the index of each arc, keyed by its coordinates
+	arcIdx map[string]int
+}
+
+func newTopology() *topology {
+	return &topology{
+		neighbors: make(map[[2]float64][2][2]float64),
+		junctions: make(map[[2]float64]bool),
+		arcIdx:    make(map[string]int),
+	}
+}
+
+// addJunctions finds the junctions of the lines and rings of the geometry.
+// All geometries must be added before any objects are created.
+func (topo *topology) addJunctions(g geom.Geometry) error {
+	switch gg := g.(type) {
+	case geom.Pointer, geom.MultiPointer:
+	case geom.LineStringer:
+		topo.addLine(gg.Verticies())
+	case geom.MultiLineStringer:
+		for _, line := range gg.LineStrings() {
+			topo.addLine(line)
+		}
+	case geom.Polygoner:
+		for _, ring := range gg.LinearRings() {
+			topo.addRing(ring)
+		}
+	case geom.MultiPolygoner:
+		for _, poly := range gg.Polygons() {
+			for _, ring := range poly {
+				topo.addRing(ring)
+			}
+		}
+	case geom.Collectioner:
+		for _, cg := range gg.Geometries() {
+			if err := topo.addJunctions(cg); err != nil {
+				return err
+			}
+		}
+	default:
+		return geom.ErrUnknownGeometry{Geom: g}
+	}
+	return nil
+}
+
+// addLine marks the end points of the line as junctions
+func (topo *topology) addLine(line [][2]float64) {
+	if len(line) == 0 {
+		return
+	}
+	topo.junctions[line[0]] = true
+	topo.junctions[line[len(line)-1]] = true
+	for i := 1; i < len(line)-1; i++ {
+		topo.addPoint(line[i], line[i-1], line[i+1])
+	}
+}
+
+// addRing adds the points of the unclosed ring
+func (topo *topology) addRing(ring [][2]float64) {
+	n := len(ring)
+	if n > 1 && ring[0] == ring[n-1] {
+		n--
+	}
+	for i := 0; i < n; i++ {
+		topo.addPoint(ring[i], ring[(i+n-1)%n], ring[(i+1)%n])
+	}
+}
+
+// addPoint marks pt as a junction if it has been seen with other neighbors
+func (topo *topology) addPoint(pt, prev, next [2]float64) {
+	// the direction of the line does not matter
+	if next[0] < prev[0] || (next[0] == prev[0] && next[1] < prev[1]) {
+		prev, next = next, prev
+	}
+	pair := [2][2]float64{prev, next}
+
+	seen, ok := topo.neighbors[pt]
+	if !ok {
+		topo.neighbors[pt] = pair
+		return
+	}
+	if seen != pair {
+		topo.junctions[pt] = true
+	}
+}
+
+// object returns the TopoJSON object of the geometry
+func (topo *topology) object(g geom.Geometry) (*topoObject, error) {
+	if g == nil || geom.IsEmpty(g) {
+		return &topoObject{}, nil
+	}
+
+	switch gg := g.(type) {
+	case geom.Pointer:
+		return &topoObject{Type: "Point", Coordinates: gg.XY()}, nil
+	case geom.MultiPointer:
+		return &topoObject{Type: "MultiPoint", Coordinates: gg.Points()}, nil
+	case geom.LineStringer:
+		return &topoObject{Type: "LineString", Arcs: topo.cutLine(gg.Verticies())}, nil
+	case geom.MultiLineStringer:
+		lines := gg.LineStrings()
+		arcs := make([][]int, len(lines))
+		for i := range lines {
+			arcs[i] = topo.cutLine(lines[i])
+		}
+		return &topoObject{Type: "MultiLineString", Arcs: arcs}, nil
+	case geom.Polygoner:
+		return &topoObject{Type: "Polygon", Arcs: topo.cutRings(gg.LinearRings())}, nil
+	case geom.MultiPolygoner:
+		polys := gg.Polygons()
+		arcs := make([][][]int, len(polys))
+		for i := range polys {
+			arcs[i] = topo.cutRings(polys[i])
+		}
+		return &topoObject{Type: "MultiPolygon", Arcs: arcs}, nil
+	case geom.Collectioner:
+		col := &topoObject{Type: "GeometryCollection", Geometries: []*topoObject{}}
+		for _, cg := range gg.Geometries() {
+			if cg == nil || geom.IsEmpty(cg) {
+				continue
+			}
+			obj, err := topo.object(cg)
+			if err != nil {
+				return nil, err
+			}
+			col.Geometries = append(col.Geometries, obj)
+		}
+		return col, nil
+	default:
+		return nil, geom.ErrUnknownGeometry{Geom: g}
+	}
+}
+
+// cutLine returns the arcs of the line, cut at each junction
+func (topo *topology) cutLine(line [][2]float64) []int {
+	var (
+		arcs  []int
+		start int
+	)
+	for i := 1; i < len(line); i++ {
+		if i == len(line)-1 || topo.junctions[line[i]] {
+			arcs = append(arcs, topo.arc(line[start:i+1]))
+			start = i
+		}
+	}
+	return arcs
+}
+
+func (topo *topology) cutRings(rings [][][2]float64) [][]int {
+	arcs := make([][]int, 0, len(rings))
+	for _, ring := range rings {
+		if len(ring) == 0 {
+			continue
+		}
+		arcs = append(arcs, topo.cutRing(ring))
+	}
+	return arcs
+}
+
+// cutRing returns the arcs of the ring, which is rotated to start at a
+// junction so the ring's arcs are only cut at junctions
+func (topo *topology) cutRing(ring [][2]float64) []int {
+	n := len(ring)
+	if n > 1 && ring[0] == ring[n-1] {
+		n--
+	}
+
+	start := 0
+	for i := 0; i < n; i++ {
+		if topo.junctions[ring[i]] {
+			start = i
+			break
+		}
+	}
+
+	closed := make([][2]float64, 0, n+1)
+	closed = append(closed, ring[start:n]...)
+	closed = append(closed, ring[:start]...)
+	closed = append(closed, ring[start])
+	return topo.cutLine(closed)
+}
+
+// arc returns the index of the arc, adding it if it has not been seen.
+// An arc which has been seen reversed is referenced as the one's
+// complement of the reversed arc's index, as TopoJSON requires.
+func (topo *topology) arc(pts [][2]float64) int {
+	key := fmt.Sprint(pts)
+	if i, ok := topo.arcIdx[key]; ok {
+		return i
+	}
+
+	reversed := make([][2]float64, len(pts))
+	for i := range pts {
+		reversed[len(pts)-1-i] = pts[i]
+	}
+	if i, ok := topo.arcIdx[fmt.Sprint(reversed)]; ok {
+		return ^i
+	}
+
+	arc := make([][2]float64, len(pts))
+	copy(arc, pts)
+	topo.arcs = append(topo.arcs, arc)
+	topo.arcIdx[key] = len(topo.arcs) - 1
+	return len(topo.arcs) - 1
+}
@@ -0,0 +1,106 @@
+package provider_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+// layersTiler returns the features of the requested layer
+type layersTiler map[string][]provider.Feature
+
+func (lt layersTiler) Layers() ([]provider.LayerInfo, error) { return nil, nil }
+
+func (lt layersTiler) TileFeatures(ctx context.Context, layer string, t provider.Tile, fn func(f *provider.Feature) error) error {
+	return featuresTiler{features: lt[layer]}.TileFeatures(ctx, layer, t, fn)
+}
+
+func TestEncodeTopoJSON(t *testing.T) {
+	tiler := layersTiler{
+		"parcels": {
+			// two squares sharing the edge from (1,0) to (1,1)
+			{ID: 1, Geometry: geom.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 1}}}, Tags: map[string]interface{}{"name": "a"}},
+			{ID: 2, Geometry: geom.Polygon{{{1, 0}, {2, 0}, {2, 1}, {1, 1}}}, Tags: map[string]interface{}{"name": "b"}},
+		},
+		"places": {
+			{ID: 3, Geometry: geom.Point{0.5, 0.5}},
+			{ID: 4},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := provider.EncodeTopoJSON(context.Background(), tiler, []string{"parcels", "places"}, provider.NewTile(0, 0, 0, 0, 3857), &buf)
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+
+	type object struct {
+		Type        interface{}            `json:"type"`
+		ID          uint64                 `json:"id"`
+		Properties  map[string]interface{} `json:"properties"`
+		Arcs        [][]int                `json:"arcs"`
+		Coordinates [2]float64             `json:"coordinates"`
+	}
+	var topo struct {
+		Type    string `json:"type"`
+		Objects map[string]struct {
+			Type       string   `json:"type"`
+			Geometries []object `json:"geometries"`
+		} `json:"objects"`
+		Arcs [][][2]float64 `json:"arcs"`
+	}
+	if err = json.Unmarshal(buf.Bytes(), &topo); err != nil {
+		t.Fatalf("unmarshal, expected nil got %v", err)
+	}
+
+	if topo.Type != "Topology" {
+		t.Errorf("type, expected Topology got %v", topo.Type)
+	}
+
+	parcels := topo.Objects["parcels"].Geometries
+	if len(parcels) != 2 {
+		t.Fatalf("parcels, expected 2 got %v", len(parcels))
+	}
+	if parcels[0].ID != 1 || parcels[0].Properties["name"] != "a" {
+		t.Errorf("parcel, expected id 1 name a got %v %v", parcels[0].ID, parcels[0].Properties)
+	}
+
+	// the shared edge is a single arc, referenced reversed by the second square
+	if len(topo.Arcs) != 3 {
+		t.Fatalf("arcs, expected 3 got %v", topo.Arcs)
+	}
+	a, b := parcels[0].Arcs, parcels[1].Arcs
+	if len(a) != 1 || len(b) != 1 || len(a[0]) != 2 || len(b[0]) != 2 {
+		t.Fatalf("parcel arcs, expected a single ring of 2 arcs got %v and %v", a, b)
+	}
+	shared := -1
+	for _, i := range a[0] {
+		for _, j := range b[0] {
+			if i == ^j {
+				shared = i
+			}
+		}
+	}
+	if shared < 0 {
+		t.Fatalf("parcel arcs, expected a shared arc got %v and %v", a, b)
+	}
+	if expected := [][2]float64{{1, 0}, {1, 1}}; !reflect.DeepEqual(topo.Arcs[shared], expected) {
+		t.Errorf("shared arc, expected %v got %v", expected, topo.Arcs[shared])
+	}
+
+	places := topo.Objects["places"].Geometries
+	if len(places) != 2 {
+		t.Fatalf("places, expected 2 got %v", len(places))
+	}
+	if places[0].Type != "Point" || places[0].Coordinates != [2]float64{0.5, 0.5} {
+		t.Errorf("point, expected Point [0.5 0.5] got %v %v", places[0].Type, places[0].Coordinates)
+	}
+	if places[1].Type != nil {
+		t.Errorf("null geometry type, expected nil got %v", places[1].Type)
+	}
+}
@@ -0,0 +1,173 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/internal/log"
+)
+
+// WithTopologySimplify wraps the Tiler so polygon features are simplified
+// with the Douglas-Peucker algorithm without creating gaps or overlaps
+// between adjacent polygons. The tolerance is in pixels at the tile's zoom,
+// as SimplifyTolerance is for WithSimplify.
+//
+// The polygons of a tile are cut into arcs where their boundaries meet, as
+// for EncodeTopoJSON, and each arc is simplified once. The arcs' end points
+// are kept, so a boundary shared by two polygons is simplified the same way
+// for both and stays coincident. This requires every feature of the tile, so
+// the features are held in memory and passed to the callback once the
+// provider has returned them all, in the order they were returned.
+//
+// Features which are not polygons are passed on unchanged, as are polygons
+// within geometry collections. Features not in the tile's SRID are
+// reprojected first. Holes which collapse are dropped, and features whose
+// exterior ring collapses are not passed to the callback. A tolerance <= 0
+// returns t.
+func WithTopologySimplify(t Tiler, tolerance float64) Tiler {
+	if tolerance <= 0 {
+		return t
+	}
+	return &topoSimplifyTiler{
+		Tiler:     t,
+		tolerance: tolerance,
+	}
+}
+
+type topoSimplifyTiler struct {
+	Tiler
+	tolerance float64
+}
+
+func (tst *topoSimplifyTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	var (
+		_, tileSRID = t.Extent()
+		topo        = newTopology()
+		features    []Feature
+	)
+	err := tst.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		ff := *f
+		switch f.Geometry.(type) {
+		case geom.Polygon, geom.MultiPolygon:
+			if err := reprojectFeatureInPlace(&ff, tileSRID); err != nil {
+				return err
+			}
+			if err := topo.addJunctions(ff.Geometry); err != nil {
+				return err
+			}
+		}
+		features = append(features, ff)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	arcs := topoArcSimplifier{
+		topo:      topo,
+		tolerance: pixelTolerance(t, tst.tolerance),
+		arcs:      make(map[int][][2]float64),
+	}
+	for i := range features {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		f := &features[i]
+		switch g := f.Geometry.(type) {
+		case geom.Polygon:
+			poly := arcs.polygon(g)
+			if poly == nil {
+				log.Debugf("layer (%v) feature %v dropped, collapsed when simplified", layer, f.ID)
+				continue
+			}
+			f.Geometry = poly
+		case geom.MultiPolygon:
+			var mp geom.MultiPolygon
+			for j := range g {
+				if poly := arcs.polygon(g[j]); poly != nil {
+					mp = append(mp, poly)
+				}
+			}
+			if len(mp) == 0 {
+				log.Debugf("layer (%v) feature %v dropped, collapsed when simplified", layer, f.ID)
+				continue
+			}
+			f.Geometry = mp
+		}
+
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// topoArcSimplifier simplifies the arcs of a topology, each arc once
+type topoArcSimplifier struct {
+	topo      *topology
+	tolerance float64
+	// arcs is the simplified arcs, by index in the topology
+	arcs map[int][][2]float64
+}
+
+// polygon returns the polygon rebuilt from its simplified arcs. Holes which
+// collapse are dropped, nil is returned if the exterior collapses.
+func (tas topoArcSimplifier) polygon(poly geom.Polygon) geom.Polygon {
+	var simplified geom.Polygon
+	for i, ring := range poly {
+		if len(ring) < 3 {
+			if i == 0 {
+				return nil
+			}
+			continue
+		}
+
+		var rebuilt [][2]float64
+		for _, idx := range tas.topo.cutRing(ring) {
+			arc := tas.arc(idx)
+			if len(rebuilt) > 0 {
+				// arcs share their end points
+				arc = arc[1:]
+			}
+			rebuilt = append(rebuilt, arc...)
+		}
+		// the ring is closed, rings are returned unclosed
+		if n := len(rebuilt); n > 1 && rebuilt[0] == rebuilt[n-1] {
+			rebuilt = rebuilt[:n-1]
+		}
+
+		if len(rebuilt) < 3 {
+			if i == 0 {
+				return nil
+			}
+			continue
+		}
+		simplified = append(simplified, rebuilt)
+	}
+	return simplified
+}
+
+// arc returns the simplified arc of the index, which is the one's complement
+// of the reversed arc's index for reversed arcs
+func (tas topoArcSimplifier) arc(idx int) [][2]float64 {
+	i := idx
+	if i < 0 {
+		i = ^i
+	}
+
+	arc, ok := tas.arcs[i]
+	if !ok {
+		arc = douglasPeucker(tas.topo.arcs[i], tas.tolerance)
+		tas.arcs[i] = arc
+	}
+	if idx >= 0 {
+		return arc
+	}
+
+	reversed := make([][2]float64, len(arc))
+	for j := range arc {
+		reversed[len(arc)-1-j] = arc[j]
+	}
+	return reversed
+}
@@ -0,0 +1,81 @@
+package provider_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithTopologySimplify(t *testing.T) {
+	// a 100km long border with a 500m wave and 200 vertices, near null island
+	border := make([][2]float64, 200)
+	for i := range border {
+		y := float64(i) * 500
+		border[i] = [2]float64{100000 + 500*math.Sin(y/5000), y}
+	}
+	top := border[len(border)-1]
+
+	// the west polygon follows the border north, the east polygon south
+	west := [][2]float64{{0, 0}}
+	west = append(west, border...)
+	west = append(west, [2]float64{0, top[1]})
+	east := [][2]float64{{200000, top[1]}, {200000, 0}}
+	for i := range border {
+		east = append(east, border[len(border)-1-i])
+	}
+
+	tiler := provider.WithTopologySimplify(featuresTiler{
+		features: []provider.Feature{
+			{ID: 1, Geometry: geom.Polygon{west}},
+			{ID: 2, Geometry: geom.Polygon{east}},
+			{ID: 3, Geometry: geom.Point{1, 1}},
+		},
+	}, 1)
+
+	// the z4 tile containing null island's north east corner, a pixel is ~10km
+	got, err := collect(tiler, "countries", provider.NewTile(4, 8, 7, 0, 3857))
+	if err != nil {
+		t.Fatalf("error, expected nil got %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("features, expected 3 got %v", len(got))
+	}
+	if _, ok := got[2].Geometry.(geom.Point); !ok {
+		t.Errorf("feature 3 geometry, expected a point got %T", got[2].Geometry)
+	}
+
+	// the vertices of each polygon along the border
+	borderVertices := func(g geom.Geometry) map[[2]float64]bool {
+		poly, ok := g.(geom.Polygon)
+		if !ok || len(poly) != 1 {
+			t.Fatalf("geometry, expected a polygon with 1 ring got %v", g)
+		}
+		pts := make(map[[2]float64]bool)
+		for _, pt := range poly[0] {
+			if pt[0] > 90000 && pt[0] < 110000 {
+				pts[pt] = true
+			}
+		}
+		return pts
+	}
+	westPts, eastPts := borderVertices(got[0].Geometry), borderVertices(got[1].Geometry)
+
+	if len(westPts) >= len(border) {
+		t.Errorf("border vertices, expected fewer than %v got %v", len(border), len(westPts))
+	}
+	if len(westPts) != len(eastPts) {
+		t.Fatalf("border vertices, expected %v got %v", len(westPts), len(eastPts))
+	}
+	for pt := range westPts {
+		if !eastPts[pt] {
+			t.Errorf("east border vertex %v, expected it got none", pt)
+		}
+	}
+
+	tiler = provider.WithTopologySimplify(featuresTiler{}, 0)
+	if _, ok := tiler.(featuresTiler); !ok {
+		t.Errorf("zero tolerance, expected the tiler got %T", tiler)
+	}
+}
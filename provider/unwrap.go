@@ -0,0 +1,72 @@
+package provider
+
+// Unwrapper is implemented by Tilers which wrap another Tiler without
+// changing its features, i.e. to swap it on Reload or to initialize it
+// lazily, so the optional interfaces of the wrapped Tiler, such as
+// TileETagger, can be found with As. Decorators which change the features,
+// i.e. WithSimplify, must not implement it, as calling the wrapped Tiler's
+// optional interfaces would bypass them.
+type Unwrapper interface {
+	Unwrap() Tiler
+}
+
+// As returns the first Tiler implementing T in the chain of t and the Tilers
+// it wraps, see Unwrapper, and if one does. T is usually one of the optional
+// interfaces, i.e. As[TileETagger](t). As does not look through the
+// providers tracked by ForNamed, as their instance may be closed by Reload
+// once it is returned; use the helpers of the optional interfaces, i.e.
+// TileETag, which hold the instance for the duration of the call.
+func As[T any](t Tiler) (T, bool) {
+	for t != nil {
+		if v, ok := t.(T); ok {
+			return v, true
+		}
+		u, ok := t.(Unwrapper)
+		if !ok {
+			break
+		}
+		t = u.Unwrap()
+	}
+	var zero T
+	return zero, false
+}
+
+// holder is implemented by Tilers which delegate to an instance that may be
+// replaced and closed, i.e. the providers tracked by ForNamed. hold returns
+// the current instance along with a function which must be called once it is
+// no longer in use.
+type holder interface {
+	hold() (t Tiler, release func())
+}
+
+// acquireAs is As, also looking through holders. The returned release
+// function must be called once v is no longer in use, and is nil if ok is
+// false.
+func acquireAs[T any](t Tiler) (v T, release func(), ok bool) {
+	var releases []func()
+	release = func() {
+		for i := len(releases) - 1; i >= 0; i-- {
+			releases[i]()
+		}
+	}
+
+	for t != nil {
+		if v, ok := t.(T); ok {
+			return v, release, true
+		}
+		switch w := t.(type) {
+		case holder:
+			var r func()
+			t, r = w.hold()
+			releases = append(releases, r)
+		case Unwrapper:
+			t = w.Unwrap()
+		default:
+			t = nil
+		}
+	}
+
+	release()
+	var zero T
+	return zero, nil, false
+}
@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/internal/convert"
+	"github.com/go-spatial/tegola/maths/simplify"
+)
+
+// vertexCount returns the number of vertices that make up the geometry
+func vertexCount(g geom.Geometry) int {
+	if g == nil {
+		return 0
+	}
+	pts, err := geom.GetCoordinates(g)
+	if err != nil {
+		return 0
+	}
+	return len(pts)
+}
+
+// simplifyGeometry applies the DouglasPeucker simplification routine to the
+// supplied geometry. A nil geometry is returned if the geometry collapsed.
+func simplifyGeometry(g geom.Geometry, tolerance float64) (geom.Geometry, error) {
+	// TODO: remove this geom conversion step once the simplify function uses geom types
+	tg, err := convert.ToTegola(g)
+	if err != nil {
+		return nil, err
+	}
+
+	sg := simplify.SimplifyGeometry(tg, tolerance)
+	if sg == nil {
+		return nil, nil
+	}
+
+	g, err = convert.ToGeom(sg)
+	if err != nil {
+		return nil, err
+	}
+	if vertexCount(g) == 0 {
+		return nil, nil
+	}
+	return g, nil
+}
@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"context"
+	"math"
+)
+
+// WithValueMapping wraps the Tiler so that feature property values are
+// translated according to mappings, keyed by property key then by value,
+// i.e. coded values to labels:
+//
+//	map[string]map[interface{}]interface{}{
+//		"highway": {1: "motorway", 2: "trunk"},
+//	}
+//
+// Values without a mapping, and properties of keys without mappings, are left
+// untouched. Integers are matched regardless of their type, so a mapping for
+// 1 matches an int32 or uint64 value of 1 as returned by a provider, as are
+// floating point numbers holding integers. Only strings, booleans and numbers
+// are mapped.
+func WithValueMapping(t Tiler, mappings map[string]map[interface{}]interface{}) Tiler {
+	lookup := make(map[string]map[interface{}]interface{}, len(mappings))
+	for key, mapping := range mappings {
+		if len(mapping) == 0 {
+			continue
+		}
+		values := make(map[interface{}]interface{}, len(mapping))
+		for from, to := range mapping {
+			if from, ok := mappingKey(from); ok {
+				values[from] = to
+			}
+		}
+		lookup[key] = values
+	}
+	if len(lookup) == 0 {
+		return t
+	}
+	return &valueMappingTiler{
+		Tiler:    t,
+		mappings: lookup,
+	}
+}
+
+type valueMappingTiler struct {
+	Tiler
+	mappings map[string]map[interface{}]interface{}
+}
+
+func (vmt *valueMappingTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	return vmt.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		f.Tags = vmt.mapValues(f.Tags)
+		return fn(f)
+	})
+}
+
+// mapValues returns the tags with their values mapped. The tags are copied
+// before being modified as they may be shared with other features.
+func (vmt *valueMappingTiler) mapValues(tags map[string]interface{}) map[string]interface{} {
+	var mapped map[string]interface{}
+	for k, v := range tags {
+		values, ok := vmt.mappings[k]
+		if !ok {
+			continue
+		}
+		from, ok := mappingKey(v)
+		if !ok {
+			continue
+		}
+		to, ok := values[from]
+		if !ok {
+			continue
+		}
+
+		if mapped == nil {
+			mapped = make(map[string]interface{}, len(tags))
+			for k, v := range tags {
+				mapped[k] = v
+			}
+		}
+		mapped[k] = to
+	}
+	if mapped == nil {
+		return tags
+	}
+	return mapped
+}
+
+// mappingKey returns the value as a key of a mapping. Integers, and floating
+// point numbers holding integers, are int64 or uint64 if they overflow an
+// int64. Values which are not strings, booleans or numbers can not be keys.
+func mappingKey(v interface{}) (interface{}, bool) {
+	switch v := v.(type) {
+	case string, bool:
+		return v, true
+	case float32:
+		return mappingFloatKey(float64(v)), true
+	case float64:
+		return mappingFloatKey(v), true
+	case uint:
+		if uint64(v) > math.MaxInt64 {
+			return uint64(v), true
+		}
+	case uint64:
+		if v > math.MaxInt64 {
+			return v, true
+		}
+	}
+	if n, ok := arrowInt(v); ok {
+		return n, true
+	}
+	return nil, false
+}
+
+func mappingFloatKey(v float64) interface{} {
+	if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+		return int64(v)
+	}
+	return v
+}
@@ -0,0 +1,76 @@
+package provider_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithValueMapping(t *testing.T) {
+	mappings := map[string]map[interface{}]interface{}{
+		"highway": {1: "motorway", 2: "trunk"},
+		"oneway":  {"yes": true, "no": false},
+	}
+
+	type tcase struct {
+		tags     map[string]interface{}
+		expected map[string]interface{}
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		tiler := featuresTiler{
+			features: []provider.Feature{{ID: 1, Geometry: geom.Point{1, 1}, Tags: tc.tags}},
+		}
+
+		features, err := collect(provider.WithValueMapping(tiler, mappings), "", provider.NewTile(0, 0, 0, 0, 3857))
+		if err != nil {
+			t.Errorf("error, expected nil got %v", err)
+			return
+		}
+		if !reflect.DeepEqual(features[0].Tags, tc.expected) {
+			t.Errorf("tags, expected %v got %v", tc.expected, features[0].Tags)
+		}
+	}
+
+	tests := map[string]tcase{
+		"integer code": {
+			tags:     map[string]interface{}{"highway": 1, "name": "A1"},
+			expected: map[string]interface{}{"highway": "motorway", "name": "A1"},
+		},
+		"unmapped code": {
+			tags:     map[string]interface{}{"highway": 7, "name": "A1"},
+			expected: map[string]interface{}{"highway": 7, "name": "A1"},
+		},
+		"integer types": {
+			tags:     map[string]interface{}{"highway": int32(2)},
+			expected: map[string]interface{}{"highway": "trunk"},
+		},
+		"integral float": {
+			tags:     map[string]interface{}{"highway": float64(1)},
+			expected: map[string]interface{}{"highway": "motorway"},
+		},
+		"fractional float": {
+			tags:     map[string]interface{}{"highway": 1.5},
+			expected: map[string]interface{}{"highway": 1.5},
+		},
+		"string code": {
+			tags:     map[string]interface{}{"oneway": "yes", "highway": uint64(2)},
+			expected: map[string]interface{}{"oneway": true, "highway": "trunk"},
+		},
+		"unmapped key": {
+			tags:     map[string]interface{}{"lanes": 1},
+			expected: map[string]interface{}{"lanes": 1},
+		},
+		"non scalar": {
+			tags:     map[string]interface{}{"highway": []interface{}{1}},
+			expected: map[string]interface{}{"highway": []interface{}{1}},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
@@ -0,0 +1,104 @@
+package provider
+
+import (
+	"context"
+	"math"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/internal/log"
+)
+
+// LimitMode determines how a feature exceeding a limit is handled
+type LimitMode uint8
+
+const (
+	// LimitModeDrop skips the feature
+	LimitModeDrop LimitMode = iota
+	// LimitModeSimplify simplifies the feature until it is under the limit.
+	// if the feature can not be simplified under the limit it is dropped.
+	LimitModeSimplify
+)
+
+func (m LimitMode) String() string {
+	switch m {
+	case LimitModeDrop:
+		return "drop"
+	case LimitModeSimplify:
+		return "simplify"
+	default:
+		return "unknown"
+	}
+}
+
+// maxSimplifyPasses is the number of times the simplification tolerance is
+// doubled before giving up on getting a feature under the vertex limit
+const maxSimplifyPasses = 16
+
+// WithVertexLimit wraps the Tiler so that features with more than maxVertices
+// vertices are handled according to mode before being passed to the callback.
+// A maxVertices <= 0 disables the limit.
+func WithVertexLimit(t Tiler, maxVertices int, mode LimitMode) Tiler {
+	if maxVertices <= 0 {
+		return t
+	}
+	return &vertexLimitTiler{
+		Tiler: t,
+		max:   maxVertices,
+		mode:  mode,
+	}
+}
+
+type vertexLimitTiler struct {
+	Tiler
+	max  int
+	mode LimitMode
+}
+
+func (vlt *vertexLimitTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	return vlt.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		count := vertexCount(f.Geometry)
+		if count <= vlt.max {
+			return fn(f)
+		}
+
+		if vlt.mode == LimitModeSimplify {
+			if g := vlt.simplify(f.Geometry); g != nil {
+				log.Infof("layer (%v) feature %v simplified from %v to %v vertices", layer, f.ID, count, vertexCount(g))
+				f.Geometry = g
+				return fn(f)
+			}
+		}
+
+		log.Infof("layer (%v) feature %v dropped, %v vertices exceeds the limit of %v", layer, f.ID, count, vlt.max)
+		return nil
+	})
+}
+
+// simplify progressively increases the simplification tolerance until the
+// geometry is under the vertex limit. nil is returned if that is not possible.
+func (vlt *vertexLimitTiler) simplify(g geom.Geometry) geom.Geometry {
+	ext, err := geom.NewExtentFromGeometry(g)
+	if err != nil {
+		return nil
+	}
+
+	// start with a tolerance that would leave roughly max vertices
+	// along the longest side of the geometry's extent
+	tolerance := math.Max(ext.XSpan(), ext.YSpan()) / float64(vlt.max)
+	if tolerance == 0 {
+		return nil
+	}
+
+	for i := 0; i < maxSimplifyPasses; i++ {
+		sg, err := simplifyGeometry(g, tolerance)
+		if err != nil || sg == nil {
+			return nil
+		}
+		if vertexCount(sg) <= vlt.max {
+			return sg
+		}
+		tolerance *= 2
+	}
+
+	return nil
+}
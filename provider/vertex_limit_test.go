@@ -0,0 +1,69 @@
+package provider_test
+
+import (
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/tegola/provider"
+)
+
+func TestWithVertexLimit(t *testing.T) {
+	// a slightly wiggly line of 100 vertices
+	var wiggly geom.LineString
+	for i := 0; i < 100; i++ {
+		wiggly = append(wiggly, [2]float64{float64(i * 10), float64(i % 2)})
+	}
+
+	tiler := featuresTiler{
+		features: []provider.Feature{
+			{ID: 1, Geometry: geom.LineString{{0, 0}, {10, 10}}},
+			{ID: 2, Geometry: wiggly},
+		},
+	}
+
+	type tcase struct {
+		mode        provider.LimitMode
+		expectedIDs []uint64
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		features, err := collect(provider.WithVertexLimit(tiler, 10, tc.mode), "", provider.NewTile(0, 0, 0, 0, 3857))
+		if err != nil {
+			t.Errorf("error, expected nil got %v", err)
+			return
+		}
+		if len(features) != len(tc.expectedIDs) {
+			t.Errorf("feature count, expected %v got %v", len(tc.expectedIDs), len(features))
+			return
+		}
+		for i, f := range features {
+			if f.ID != tc.expectedIDs[i] {
+				t.Errorf("feature %v id, expected %v got %v", i, tc.expectedIDs[i], f.ID)
+			}
+			pts, err := geom.GetCoordinates(f.Geometry)
+			if err != nil {
+				t.Errorf("get coordinates, expected nil got %v", err)
+				continue
+			}
+			if len(pts) > 10 {
+				t.Errorf("feature %v vertices, expected <= 10 got %v", f.ID, len(pts))
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"drop": {
+			mode:        provider.LimitModeDrop,
+			expectedIDs: []uint64{1},
+		},
+		"simplify": {
+			mode:        provider.LimitModeSimplify,
+			expectedIDs: []uint64{1, 2},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-spatial/tegola/dict"
+	"github.com/go-spatial/tegola/internal/log"
+)
+
+// Warmer is implemented by providers with a connection pool which can be
+// primed before serving, so the first tile requests after a cold start do not
+// pay for opening connections. Warmup should return once n connections are
+// open and idle, or with ctx's error once ctx is done.
+type Warmer interface {
+	Warmup(ctx context.Context, n int) error
+}
+
+// Warmup initializes the provider of the config's "type", primes its
+// connection pool to targetConns connections if it implements Warmer, and
+// tracks it under name as ForNamed does, see Named. Warmup only returns once
+// the pool is warm, or ctx is done. Providers which do not implement Warmer
+// are only initialized.
+//
+// If warming up fails, i.e. the backend is unreachable, the provider is
+// closed and not tracked, so a failed warmup leaves nothing behind.
+func Warmup(ctx context.Context, name string, config dict.Dicter, targetConns int) error {
+	typ, err := config.String("type", nil)
+	if err != nil {
+		return err
+	}
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+
+	tiler, err := For(typ, config)
+	if err != nil {
+		return err
+	}
+
+	if w, ok := As[Warmer](tiler); ok && targetConns > 0 {
+		if err = w.Warmup(ctx, targetConns); err != nil {
+			(&liveInstance{Tiler: tiler}).close()
+			return fmt.Errorf("provider (%v) warmup: %w", name, err)
+		}
+		log.Infof("provider (%v) warmed up with %v connections", name, targetConns)
+	}
+
+	trackNamed(name, typ, tiler)
+	return nil
+}
@@ -0,0 +1,109 @@
+package provider_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-spatial/tegola/dict"
+	"github.com/go-spatial/tegola/provider"
+)
+
+// poolTiler is a Tiler with a connection pool to a host, the host
+// "unreachable" refuses connections and "slow" never answers
+type poolTiler struct {
+	featuresTiler
+	host   string
+	conns  *int32
+	closed *int32
+}
+
+func (pt poolTiler) Warmup(ctx context.Context, n int) error {
+	switch pt.host {
+	case "unreachable":
+		return errors.New("dial tcp: connection refused")
+	case "slow":
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	atomic.StoreInt32(pt.conns, int32(n))
+	return nil
+}
+
+func (pt poolTiler) Close() { atomic.AddInt32(pt.closed, 1) }
+
+func TestWarmup(t *testing.T) {
+	var conns, closed int32
+	err := provider.Register("warmup_test", func(d dict.Dicter) (provider.Tiler, error) {
+		host, err := d.String("host", nil)
+		if err != nil {
+			return nil, err
+		}
+		return poolTiler{host: host, conns: &conns, closed: &closed}, nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("register, expected nil got %v", err)
+	}
+
+	type tcase struct {
+		host    string
+		timeout time.Duration
+		conns   int32
+		err     string
+		tracked bool
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		atomic.StoreInt32(&conns, 0)
+		atomic.StoreInt32(&closed, 0)
+
+		ctx, cancel := context.WithTimeout(context.Background(), tc.timeout)
+		defer cancel()
+
+		name := "warmup_" + tc.host
+		err := provider.Warmup(ctx, name, dict.Dict{"type": "warmup_test", "host": tc.host}, 10)
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("error, expected %v got %v", tc.err, err)
+			}
+			if atomic.LoadInt32(&closed) != 1 {
+				t.Errorf("closed, expected the provider to be closed")
+			}
+		} else if err != nil {
+			t.Fatalf("error, expected nil got %v", err)
+		}
+
+		if got := atomic.LoadInt32(&conns); got != tc.conns {
+			t.Errorf("conns, expected %v got %v", tc.conns, got)
+		}
+		if _, ok := provider.Named(name); ok != tc.tracked {
+			t.Errorf("tracked, expected %v got %v", tc.tracked, ok)
+		}
+	}
+
+	tests := map[string]tcase{
+		"warm": {
+			host:    "db",
+			timeout: time.Second,
+			conns:   10,
+			tracked: true,
+		},
+		"unreachable": {
+			host:    "unreachable",
+			timeout: time.Second,
+			err:     "provider (warmup_unreachable) warmup: dial tcp: connection refused",
+		},
+		"timeout": {
+			host:    "slow",
+			timeout: 10 * time.Millisecond,
+			err:     "provider (warmup_slow) warmup: context deadline exceeded",
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/go-spatial/geom"
+)
+
+// WithWindingNormalize wraps the Tiler so the rings of polygon features are
+// wound as required by the MVT spec. The spec requires exterior rings to have
+// a positive area and interior rings (holes) a negative area in tile space,
+// where the y axis points down. As tile space has its y axis flipped in
+// relation to the feature's SRID, which negates the area of a ring, in SRID
+// space this means exterior rings have a negative area (clockwise) and holes
+// a positive area (counter-clockwise).
+func WithWindingNormalize(t Tiler) Tiler {
+	return &windingTiler{Tiler: t}
+}
+
+type windingTiler struct {
+	Tiler
+}
+
+func (wt *windingTiler) TileFeatures(ctx context.Context, layer string, t Tile, fn func(f *Feature) error) error {
+	return wt.Tiler.TileFeatures(ctx, layer, t, func(f *Feature) error {
+		f.Geometry = normalizeWinding(f.Geometry)
+		return fn(f)
+	})
+}
+
+// normalizeWinding returns the geometry with all the polygon rings wound
+// clockwise for exteriors and counter-clockwise for holes, in SRID space.
+func normalizeWinding(g geom.Geometry) geom.Geometry {
+	switch gg := g.(type) {
+	case geom.Polygon:
+		return normalizePolygonWinding(gg)
+	case geom.MultiPolygon:
+		mp := make(geom.MultiPolygon, len(gg))
+		for i := range gg {
+			mp[i] = normalizePolygonWinding(gg[i])
+		}
+		return mp
+	case geom.Collection:
+		col := make(geom.Collection, len(gg))
+		for i := range gg {
+			col[i] = normalizeWinding(gg[i])
+		}
+		return col
+	default:
+		return g
+	}
+}
+
+func normalizePolygonWinding(p geom.Polygon) geom.Polygon {
+	np := make(geom.Polygon, len(p))
+	for i := range p {
+		area := ringSignedArea(p[i])
+		// the first ring is the exterior and should have a negative area,
+		// all following rings are holes and should have a positive area
+		if (i == 0 && area > 0) || (i != 0 && area < 0) {
+			np[i] = reverseRing(p[i])
+			continue
+		}
+		np[i] = p[i]
+	}
+	return np
+}
+
+// ringSignedArea returns the signed area of the ring using the shoelace
+// formula. The area is positive for counter-clockwise rings in a y-up
+// coordinate system. The ring does not need to be closed.
+func ringSignedArea(ring [][2]float64) float64 {
+	if len(ring) < 3 {
+		return 0
+	}
+	var sum float64
+	for i := range ring {
+		j := (i + 1) % len(ring)
+		sum += ring[i][0]*ring[j][1] - ring[j][0]*ring[i][1]
+	}
+	return sum / 2
+}
+
+// reverseRing returns a copy of the ring with its points in reverse order
+func reverseRing(ring [][2]float64) [][2]float64 {
+	r := make([][2]float64, len(ring))
+	for i := range ring {
+		r[len(ring)-1-i] = ring[i]
+	}
+	return r
+}
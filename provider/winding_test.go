@@ -0,0 +1,99 @@
+package provider_test
+
+import (
+	"testing"
+
+	"github.com/go-spatial/geom"
+	"github.com/go-spatial/geom/encoding/mvt"
+	"github.com/go-spatial/tegola/provider"
+)
+
+// signedArea is the area of the ring using the surveyor's formula, as the MVT
+// spec defines it, positive for exterior rings in tile space
+func signedArea(ring [][2]float64) float64 {
+	var sum float64
+	for i := range ring {
+		j := (i + 1) % len(ring)
+		sum += ring[i][0]*ring[j][1] - ring[j][0]*ring[i][1]
+	}
+	return sum / 2
+}
+
+func TestWithWindingNormalize(t *testing.T) {
+	type tcase struct {
+		geom geom.Polygon
+	}
+
+	tile := provider.NewTile(0, 0, 0, 0, 3857)
+	tileExt, _ := tile.Extent()
+
+	fn := func(t *testing.T, tc tcase) {
+		tiler := featuresTiler{
+			features: []provider.Feature{{ID: 1, Geometry: tc.geom}},
+		}
+
+		features, err := collect(provider.WithWindingNormalize(tiler), "", tile)
+		if err != nil {
+			t.Errorf("error, expected nil got %v", err)
+			return
+		}
+		if len(features) != 1 {
+			t.Errorf("feature count, expected 1 got %v", len(features))
+			return
+		}
+
+		// the winding is checked in tile space, as the geometry is encoded
+		poly, ok := mvt.PrepareGeo(features[0].Geometry, tileExt, 4096).(geom.Polygon)
+		if !ok {
+			t.Errorf("geometry type, expected geom.Polygon got %T", features[0].Geometry)
+			return
+		}
+		if len(poly) != len(tc.geom) {
+			t.Errorf("ring count, expected %v got %v", len(tc.geom), len(poly))
+			return
+		}
+		// in tile space the exterior has a positive area and the holes a
+		// negative area
+		if a := signedArea(poly[0]); a <= 0 {
+			t.Errorf("exterior ring area, expected positive got %v", a)
+		}
+		for i := 1; i < len(poly); i++ {
+			if a := signedArea(poly[i]); a >= 0 {
+				t.Errorf("hole %v area, expected negative got %v", i, a)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		// rings in WebMercator, wound counter-clockwise or clockwise in SRID space
+		"counter-clockwise exterior and hole": {
+			geom: geom.Polygon{
+				{{0, 0}, {1e6, 0}, {1e6, 1e6}, {0, 1e6}},
+				{{2e5, 2e5}, {8e5, 2e5}, {8e5, 8e5}, {2e5, 8e5}},
+			},
+		},
+		"clockwise exterior and hole": {
+			geom: geom.Polygon{
+				{{0, 0}, {0, 1e6}, {1e6, 1e6}, {1e6, 0}},
+				{{2e5, 2e5}, {2e5, 8e5}, {8e5, 8e5}, {8e5, 2e5}},
+			},
+		},
+		"counter-clockwise exterior and clockwise hole": {
+			geom: geom.Polygon{
+				{{0, 0}, {1e6, 0}, {1e6, 1e6}, {0, 1e6}},
+				{{2e5, 2e5}, {2e5, 8e5}, {8e5, 8e5}, {8e5, 2e5}},
+			},
+		},
+		"clockwise exterior and counter-clockwise hole": {
+			geom: geom.Polygon{
+				{{0, 0}, {0, 1e6}, {1e6, 1e6}, {1e6, 0}},
+				{{2e5, 2e5}, {8e5, 2e5}, {8e5, 8e5}, {2e5, 8e5}},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
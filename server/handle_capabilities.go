@@ -57,7 +57,7 @@ func (req HandleCapabilities) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		// build the map details
 		cMap := CapabilitiesMap{
 			Name:        m.Name,
-			Attribution: m.Attribution,
+			Attribution: m.CombinedAttribution(),
 			Bounds:      m.Bounds,
 			Center:      m.Center,
 			Tiles: []string{
@@ -50,8 +50,11 @@ func (req HandleMapCapabilities) ServeHTTP(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	// include the attribution required by the map's layer providers
+	attribution := m.CombinedAttribution()
+
 	tileJSON := tilejson.TileJSON{
-		Attribution: &m.Attribution,
+		Attribution: &attribution,
 		Bounds:      m.Bounds.Extent(),
 		Center:      m.Center,
 		Format:      "pbf",
@@ -119,7 +122,7 @@ func (req HandleMapCapabilities) ServeHTTP(w http.ResponseWriter, r *http.Reques
 		//	build our vector layer details
 		layer := tilejson.VectorLayer{
 			Version: 2,
-			Extent:  4096,
+			Extent:  int(m.LayerMVTExtent(m.Layers[i])),
 			ID:      m.Layers[i].MVTName(),
 			Name:    m.Layers[i].MVTName(),
 			MinZoom: m.Layers[i].MinZoom,
@@ -129,7 +132,7 @@ func (req HandleMapCapabilities) ServeHTTP(w http.ResponseWriter, r *http.Reques
 			},
 		}
 
-		switch m.Layers[i].GeomType.(type) {
+		switch m.Layers[i].GeometryType().(type) {
 		case geom.Point, geom.MultiPoint:
 			layer.GeometryType = tilejson.GeomTypePoint
 		case geom.Line, geom.LineString, geom.MultiLineString:
@@ -210,3 +210,45 @@ func TestHandleMapCapabilitiesCORS(t *testing.T) {
 		t.Run(name, func(t *testing.T) { CORSTest(t, tc) })
 	}
 }
+
+func TestHandleMapCapabilitiesLayerExtent(t *testing.T) {
+	layer := testLayer1
+	layer.TileExtent = 512
+
+	// the handler reads the maps of the default atlas
+	orig, err := atlas.GetMap(testMapName)
+	if err != nil {
+		t.Fatalf("map, expected nil got %v", err)
+	}
+	defer atlas.AddMap(orig)
+
+	m := atlas.NewWebMercatorMap(testMapName)
+	m.Layers = append(m.Layers, layer, testLayer2)
+	atlas.AddMap(m)
+
+	r, err := http.NewRequest("GET", "http://localhost:8080/capabilities/test-map.json", nil)
+	if err != nil {
+		t.Fatalf("request error, expected nil got %v", err)
+	}
+	w := httptest.NewRecorder()
+	server.NewRouter(nil).ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status code, expected %v got %v", http.StatusOK, w.Code)
+	}
+
+	var tileJSON tilejson.TileJSON
+	if err = json.NewDecoder(w.Body).Decode(&tileJSON); err != nil {
+		t.Fatalf("decode, expected nil got %v", err)
+	}
+
+	// layers without their own extent use the map's
+	expected := map[string]int{testLayer1.MVTName(): 512, testLayer2.MVTName(): 4096}
+	if len(tileJSON.VectorLayers) != len(expected) {
+		t.Fatalf("vector layers, expected %v got %v", len(expected), len(tileJSON.VectorLayers))
+	}
+	for _, vl := range tileJSON.VectorLayers {
+		if vl.Extent != expected[vl.ID] {
+			t.Errorf("layer (%v) extent, expected %v got %v", vl.ID, expected[vl.ID], vl.Extent)
+		}
+	}
+}
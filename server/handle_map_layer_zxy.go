@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -14,6 +15,7 @@ import (
 	"github.com/go-spatial/tegola/atlas"
 	"github.com/go-spatial/tegola/internal/log"
 	"github.com/go-spatial/tegola/maths"
+	"github.com/go-spatial/tegola/provider"
 )
 
 type HandleMapLayerZXY struct {
@@ -154,6 +156,23 @@ func (req HandleMapLayerZXY) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		m = m.AddDebugLayers()
 	}
 
+	// if the providers can tell us the tile's version, we can skip
+	// encoding the tile when the client already has it
+	etag, err := m.TileETag(r.Context(), tile)
+	switch {
+	case err == nil:
+		etag = `"` + etag + `"`
+		w.Header().Set("ETag", etag)
+		if etagMatch(r.Header.Get("If-None-Match"), etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	case errors.Is(err, context.Canceled):
+		return
+	case !errors.Is(err, provider.ErrUnsupported):
+		log.Warnf("unable to compute tile etag for map (%v) tile %v/%v/%v: %v", req.mapName, req.z, req.x, req.y, err)
+	}
+
 	pbyte, err := m.Encode(r.Context(), tile)
 	if err != nil {
 		switch err {
@@ -180,3 +199,19 @@ func (req HandleMapLayerZXY) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		log.Infof("tile z:%v, x:%v, y:%v is rather large - %vKb", req.z, req.x, req.y, len(pbyte)/1024)
 	}
 }
+
+// etagMatch reports if the etag is listed in the If-None-Match header value.
+// Weak comparison is used, as a tile with the same tag is always equivalent.
+func etagMatch(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -1,14 +1,19 @@
 package server_test
 
 import (
+	"context"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/go-spatial/geom/encoding/mvt/vector_tile"
 	"github.com/go-spatial/tegola/atlas"
+	"github.com/go-spatial/tegola/provider"
+	"github.com/go-spatial/tegola/provider/test"
+	"github.com/go-spatial/tegola/server"
 	"github.com/golang/protobuf/proto"
 )
 
@@ -223,3 +228,117 @@ func TestHandleMapLayerCORS(t *testing.T) {
 		t.Run(name, func(t *testing.T) { CORSTest(t, tc) })
 	}
 }
+
+// etagTileProvider is a test.TileProvider which reports a version tag
+type etagTileProvider struct {
+	test.TileProvider
+	version string
+}
+
+func (tp *etagTileProvider) TileETag(ctx context.Context, layer string, t provider.Tile) (string, error) {
+	return tp.version, nil
+}
+
+func TestHandleMapLayerZXYETag(t *testing.T) {
+	const uri = "/maps/test-map/test-layer/4/2/3.pbf"
+
+	etagLayer := testLayer1
+	etagLayer.Provider = &etagTileProvider{version: "1"}
+
+	type tcase struct {
+		atlas        *atlas.Atlas
+		ifNoneMatch  string
+		expectedCode int
+		expectETag   bool
+	}
+
+	// fetch the current tag of the tile
+	w, _, err := doRequest(newTestMapWithLayers(etagLayer), "GET", uri, nil)
+	if err != nil {
+		t.Fatalf("request error, expected nil got %v", err)
+	}
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Fatalf("etag, expected a value got none")
+	}
+
+	fn := func(t *testing.T, tc tcase) {
+		r, err := http.NewRequest("GET", uri, nil)
+		if err != nil {
+			t.Fatalf("request error, expected nil got %v", err)
+		}
+		if tc.ifNoneMatch != "" {
+			r.Header.Set("If-None-Match", tc.ifNoneMatch)
+		}
+
+		w := httptest.NewRecorder()
+		server.NewRouter(tc.atlas).ServeHTTP(w, r)
+
+		if w.Code != tc.expectedCode {
+			t.Errorf("status code, expected %v got %v", tc.expectedCode, w.Code)
+		}
+		if got := w.Header().Get("ETag"); (got != "") != tc.expectETag {
+			t.Errorf("etag header, expected %v got %q", tc.expectETag, got)
+		}
+		if tc.expectedCode == http.StatusNotModified && w.Body.Len() != 0 {
+			t.Errorf("body, expected empty got %v bytes", w.Body.Len())
+		}
+	}
+
+	tests := map[string]tcase{
+		"no if-none-match": {
+			atlas:        newTestMapWithLayers(etagLayer),
+			expectedCode: http.StatusOK,
+			expectETag:   true,
+		},
+		"matching": {
+			atlas:        newTestMapWithLayers(etagLayer),
+			ifNoneMatch:  etag,
+			expectedCode: http.StatusNotModified,
+			expectETag:   true,
+		},
+		"matching weak in list": {
+			atlas:        newTestMapWithLayers(etagLayer),
+			ifNoneMatch:  `"abc", W/` + etag,
+			expectedCode: http.StatusNotModified,
+			expectETag:   true,
+		},
+		"changed": {
+			atlas: newTestMapWithLayers(atlas.Layer{
+				Name:              etagLayer.Name,
+				ProviderLayerName: etagLayer.ProviderLayerName,
+				MinZoom:           etagLayer.MinZoom,
+				MaxZoom:           etagLayer.MaxZoom,
+				GeomType:          etagLayer.GeomType,
+				Provider:          &etagTileProvider{version: "2"},
+			}),
+			ifNoneMatch:  etag,
+			expectedCode: http.StatusOK,
+			expectETag:   true,
+		},
+		"layer extent changed": {
+			atlas: newTestMapWithLayers(atlas.Layer{
+				Name:              etagLayer.Name,
+				ProviderLayerName: etagLayer.ProviderLayerName,
+				MinZoom:           etagLayer.MinZoom,
+				MaxZoom:           etagLayer.MaxZoom,
+				GeomType:          etagLayer.GeomType,
+				Provider:          etagLayer.Provider,
+				TileExtent:        512,
+			}),
+			ifNoneMatch:  etag,
+			expectedCode: http.StatusOK,
+			expectETag:   true,
+		},
+		"unsupported": {
+			atlas:        newTestMapWithLayers(testLayer1),
+			ifNoneMatch:  etag,
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) { fn(t, tc) })
+	}
+}
@@ -104,7 +104,7 @@ func (req HandleMapStyle) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 
 		// chose our paint type based on the geometry type
-		switch l.GeomType.(type) {
+		switch l.GeometryType().(type) {
 		case geom.Point, geom.MultiPoint:
 			layer.Type = style.LayerTypeCircle
 			layer.Paint = &style.LayerPaint{